@@ -3,21 +3,27 @@ module github.com/dasch-swiss/dasch-service-platform
 go 1.16
 
 require (
-	github.com/99designs/gqlgen v0.13.0 // indirect
+	github.com/99designs/gqlgen v0.13.0
 	github.com/EventStore/EventStore-Client-Go v0.0.0-20210219122213-700926402daf // indirect
 	github.com/bazelbuild/buildtools v0.0.0-20210408102303-2b0a1af1a898 // indirect
 	github.com/dgraph-io/badger/v3 v3.2011.1
-	github.com/gofrs/uuid v3.3.0+incompatible // indirect
-	github.com/golang-jwt/jwt v3.2.1+incompatible // indirect
+	github.com/gofrs/uuid v3.3.0+incompatible
+	github.com/golang-jwt/jwt v3.2.1+incompatible
+	github.com/golang/protobuf v1.4.3
 	github.com/google/uuid v1.2.0
 	github.com/gorilla/context v1.1.1
 	github.com/gorilla/handlers v1.5.1 // indirect
 	github.com/gorilla/mux v1.8.0
+	github.com/nats-io/nats.go v1.11.0
 	github.com/ory/dockertest/v3 v3.6.3 // indirect
 	github.com/prometheus/client_golang v1.9.0
-	github.com/snabb/sitemap v1.0.0 // indirect
+	github.com/santhosh-tekuri/jsonschema/v5 v5.0.0
+	github.com/segmentio/kafka-go v0.4.16
+	github.com/snabb/sitemap v1.0.0
 	github.com/stretchr/testify v1.7.0
 	github.com/urfave/negroni v1.0.0
-	github.com/vektah/gqlparser/v2 v2.1.0 // indirect
+	github.com/vektah/gqlparser/v2 v2.1.0
+	google.golang.org/grpc v1.35.0
 	google.golang.org/protobuf v1.25.0
+	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776
 )
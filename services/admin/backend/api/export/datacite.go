@@ -0,0 +1,150 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package export
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+)
+
+// DataCite namespaces and content type.
+const (
+	DataCiteNamespace      = "http://datacite.org/schema/kernel-4"
+	DataCiteSchemaLocation = "http://schema.datacite.org/meta/kernel-4.4/metadata.xsd"
+	DataCiteContentType    = "application/xml"
+)
+
+// unavailable is the DataCite standard value for information which is not available.
+const unavailable = ":unav"
+
+// DataCiteOptions are the values of the DataCite export which are not part of the project.
+type DataCiteOptions struct {
+	// DOIPrefix is the prefix of the DOIs registered by the publisher, e.g. 10.5072.
+	DOIPrefix string
+	// Publisher is the name of the organisation publishing the metadata.
+	Publisher string
+}
+
+// Resource is the root element of the DataCite Metadata Schema 4.4, see https://schema.datacite.org/meta/kernel-4.4/.
+type Resource struct {
+	XMLName              xml.Name              `xml:"http://datacite.org/schema/kernel-4 resource"`
+	XSI                  string                `xml:"xmlns:xsi,attr"`
+	SchemaLocation       string                `xml:"xsi:schemaLocation,attr"`
+	Identifier           Identifier            `xml:"identifier"`
+	Creators             []Creator             `xml:"creators>creator"`
+	Titles               []Title               `xml:"titles>title"`
+	Publisher            string                `xml:"publisher"`
+	PublicationYear      string                `xml:"publicationYear"`
+	ResourceType         ResourceType          `xml:"resourceType"`
+	Subjects             *Subjects             `xml:"subjects"`
+	AlternateIdentifiers []AlternateIdentifier `xml:"alternateIdentifiers>alternateIdentifier"`
+	Dates                []Date                `xml:"dates>date,omitempty"`
+	Descriptions         []Description         `xml:"descriptions>description"`
+}
+
+// Identifier is the DOI of the resource.
+type Identifier struct {
+	IdentifierType string `xml:"identifierType,attr"`
+	Value          string `xml:",chardata"`
+}
+
+// Creator is the person or organisation responsible for the resource.
+type Creator struct {
+	CreatorName CreatorName `xml:"creatorName"`
+}
+
+// CreatorName is the name of a creator, nameType is either Personal or Organizational.
+type CreatorName struct {
+	NameType string `xml:"nameType,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+// Title is a title of the resource, the main title has no titleType.
+type Title struct {
+	TitleType string `xml:"titleType,attr,omitempty"`
+	Value     string `xml:",chardata"`
+}
+
+// ResourceType describes the resource, resourceTypeGeneral is one of the types of the DataCite vocabulary.
+type ResourceType struct {
+	ResourceTypeGeneral string `xml:"resourceTypeGeneral,attr"`
+	Value               string `xml:",chardata"`
+}
+
+// Subjects are the keywords describing the resource, the element is omitted if there are none.
+type Subjects struct {
+	Subjects []string `xml:"subject"`
+}
+
+// AlternateIdentifier is an identifier of the resource other than the DOI.
+type AlternateIdentifier struct {
+	AlternateIdentifierType string `xml:"alternateIdentifierType,attr"`
+	Value                   string `xml:",chardata"`
+}
+
+// Date is a date relevant to the resource, e.g. Created or Updated.
+type Date struct {
+	DateType string `xml:"dateType,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// Description is a description of the resource, e.g. an Abstract.
+type Description struct {
+	DescriptionType string `xml:"descriptionType,attr"`
+	Value           string `xml:",chardata"`
+}
+
+// NewDataCiteResource converts the project aggregate into a DataCite resource, published in the year the project was created.
+// The DOI is derived from the id of the project, so that it is stable once it has been registered.
+// The creators of a project are not known to the admin service, hence they are reported as unavailable.
+// The keywords of the project are its subjects.
+func NewDataCiteResource(p *project.Aggregate, opts DataCiteOptions) Resource {
+	res := Resource{
+		XSI:            "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: DataCiteNamespace + " " + DataCiteSchemaLocation,
+		Identifier:     Identifier{IdentifierType: "DOI", Value: opts.DOIPrefix + "/" + p.ID().String()},
+		Creators:       []Creator{{CreatorName: CreatorName{NameType: "Organizational", Value: unavailable}}},
+		Titles: []Title{
+			{Value: p.LongName().String()},
+			{TitleType: "AlternativeTitle", Value: p.ShortName().String()},
+		},
+		Publisher:       opts.Publisher,
+		PublicationYear: strconv.Itoa(p.CreatedAt().Time().UTC().Year()),
+		ResourceType:    ResourceType{ResourceTypeGeneral: "Collection", Value: "Research Project"},
+		AlternateIdentifiers: []AlternateIdentifier{
+			{AlternateIdentifierType: "URL", Value: string(presenter.ProjectIRI(p))},
+			{AlternateIdentifierType: "ShortCode", Value: p.ShortCode().String()},
+		},
+		Descriptions: []Description{{DescriptionType: "Abstract", Value: p.Description().String()}},
+	}
+
+	if len(p.Keywords()) > 0 {
+		res.Subjects = &Subjects{Subjects: p.Keywords()}
+	}
+	if created := p.CreatedAt().Time(); !created.IsZero() {
+		res.Dates = append(res.Dates, Date{DateType: "Created", Value: created.UTC().Format(dateLayout)})
+	}
+	if changed := p.ChangedAt().Time(); !changed.IsZero() {
+		res.Dates = append(res.Dates, Date{DateType: "Updated", Value: changed.UTC().Format(dateLayout)})
+	}
+
+	return res
+}
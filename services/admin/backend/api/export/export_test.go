@@ -0,0 +1,134 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package export_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var options = export.DataCiteOptions{DOIPrefix: "10.5072", Publisher: "DaSCH"}
+
+func newTestProject(t *testing.T) *project.Aggregate {
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Letters & manuscripts of <the> Bernoullis and Euler")
+	createdBy, _ := valueobject.NewIdentifier()
+	return project.NewAggregate(id, sc, sn, ln, desc, event.Actor{ID: createdBy, Type: event.ActorUser})
+}
+
+// newMigratedTestProject creates a project migrated from DSP-API, which has keywords.
+func newMigratedTestProject(t *testing.T) *project.Aggregate {
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Letters & manuscripts of <the> Bernoullis and Euler")
+	return project.NewMigratedAggregate(id, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", sc, sn, ln, desc, []string{"mathematics", "letters"}, valueobject.NewTimestamp())
+}
+
+func TestNewResearchProject(t *testing.T) {
+	p := newTestProject(t)
+
+	b, err := json.Marshal(export.NewResearchProject(p))
+	assert.Nil(t, err)
+
+	// the export is valid according to the bundled JSON schema
+	schema, err := jsonschema.Compile(filepath.Join("testdata", "schemaorg-researchproject.schema.json"))
+	assert.Nil(t, err)
+	var doc interface{}
+	assert.Nil(t, json.Unmarshal(b, &doc))
+	assert.Nil(t, schema.Validate(doc))
+
+	res := doc.(map[string]interface{})
+	assert.Equal(t, "ResearchProject", res["@type"])
+	assert.Equal(t, "http://rdfh.ch/projects/"+p.ID().String(), res["@id"])
+	assert.Equal(t, "Bernoulli-Euler Online", res["name"])
+	assert.Equal(t, "beol", res["alternateName"])
+	assert.Equal(t, p.CreatedAt().Time().UTC().Format("2006-01-02"), res["foundingDate"])
+}
+
+func TestNewDataCiteResource(t *testing.T) {
+	p := newTestProject(t)
+
+	b, err := xml.MarshalIndent(export.NewDataCiteResource(p, options), "", "  ")
+	assert.Nil(t, err)
+
+	var res export.Resource
+	assert.Nil(t, xml.Unmarshal(b, &res))
+	assert.Equal(t, export.DataCiteNamespace, res.XMLName.Space)
+	assert.Equal(t, "10.5072/"+p.ID().String(), res.Identifier.Value)
+	assert.Equal(t, "Bernoulli-Euler Online", res.Titles[0].Value)
+	assert.Equal(t, "Letters & manuscripts of <the> Bernoullis and Euler", res.Descriptions[0].Value)
+	assert.Equal(t, []export.Date{{DateType: "Created", Value: p.CreatedAt().Time().UTC().Format("2006-01-02")}}, res.Dates)
+
+	// the export is valid according to the bundled XSD
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint is required to validate against the XSD")
+	}
+	f := filepath.Join(t.TempDir(), "datacite.xml")
+	assert.Nil(t, os.WriteFile(f, append([]byte(xml.Header), b...), 0o600))
+	out, err := exec.Command(xmllint, "--noout", "--nonet", "--schema", filepath.Join("testdata", "datacite-kernel-4.4.xsd"), f).CombinedOutput()
+	assert.Nil(t, err, string(out))
+}
+
+func TestExport_Keywords(t *testing.T) {
+	p := newMigratedTestProject(t)
+
+	// the keywords are the keywords of the research project
+	b, err := json.Marshal(export.NewResearchProject(p))
+	assert.Nil(t, err)
+	schema, err := jsonschema.Compile(filepath.Join("testdata", "schemaorg-researchproject.schema.json"))
+	assert.Nil(t, err)
+	var doc interface{}
+	assert.Nil(t, json.Unmarshal(b, &doc))
+	assert.Nil(t, schema.Validate(doc))
+	assert.Equal(t, []interface{}{"mathematics", "letters"}, doc.(map[string]interface{})["keywords"])
+	// migrated projects keep the IRI they had in DSP-API
+	assert.Equal(t, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", doc.(map[string]interface{})["@id"])
+
+	// and the subjects of the DataCite resource
+	b, err = xml.MarshalIndent(export.NewDataCiteResource(p, options), "", "  ")
+	assert.Nil(t, err)
+	var res export.Resource
+	assert.Nil(t, xml.Unmarshal(b, &res))
+	assert.Equal(t, []string{"mathematics", "letters"}, res.Subjects.Subjects)
+
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint is required to validate against the XSD")
+	}
+	f := filepath.Join(t.TempDir(), "datacite.xml")
+	assert.Nil(t, os.WriteFile(f, append([]byte(xml.Header), b...), 0o600))
+	out, err := exec.Command(xmllint, "--noout", "--nonet", "--schema", filepath.Join("testdata", "datacite-kernel-4.4.xsd"), f).CombinedOutput()
+	assert.Nil(t, err, string(out))
+}
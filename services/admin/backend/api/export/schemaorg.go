@@ -0,0 +1,77 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package export converts projects into the metadata formats expected from research data repositories.
+package export
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+)
+
+// SchemaOrgContentType is the content type of the schema.org export.
+const SchemaOrgContentType = "application/ld+json"
+
+// ResearchProject is the schema.org ResearchProject describing a project, see https://schema.org/ResearchProject.
+type ResearchProject struct {
+	Context       string          `json:"@context"`
+	Type          string          `json:"@type"`
+	ID            string          `json:"@id"`
+	Identifier    []PropertyValue `json:"identifier"`
+	Name          string          `json:"name"`
+	AlternateName string          `json:"alternateName"`
+	Description   string          `json:"description"`
+	Keywords      []string        `json:"keywords,omitempty"`
+	URL           string          `json:"url"`
+	FoundingDate  string          `json:"foundingDate,omitempty"`
+}
+
+// PropertyValue is a schema.org PropertyValue, used for the identifiers of the project.
+type PropertyValue struct {
+	Type       string `json:"@type"`
+	PropertyID string `json:"propertyID"`
+	Value      string `json:"value"`
+}
+
+// NewResearchProject converts the project aggregate into a schema.org ResearchProject.
+// The project is identified by its stable IRI, the founding date is the date the project was created.
+func NewResearchProject(p *project.Aggregate) ResearchProject {
+	iri := string(presenter.ProjectIRI(p))
+
+	res := ResearchProject{
+		Context: "https://schema.org",
+		Type:    "ResearchProject",
+		ID:      iri,
+		Identifier: []PropertyValue{
+			{Type: "PropertyValue", PropertyID: "uuid", Value: p.ID().String()},
+			{Type: "PropertyValue", PropertyID: "shortCode", Value: p.ShortCode().String()},
+		},
+		Name:          p.LongName().String(),
+		AlternateName: p.ShortName().String(),
+		Description:   p.Description().String(),
+		Keywords:      p.Keywords(),
+		URL:           iri,
+	}
+	if !p.CreatedAt().Time().IsZero() {
+		res.FoundingDate = p.CreatedAt().Time().UTC().Format(dateLayout)
+	}
+
+	return res
+}
+
+// dateLayout is the ISO 8601 layout of dates used by both schema.org and DataCite.
+const dateLayout = "2006-01-02"
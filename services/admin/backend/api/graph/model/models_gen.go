@@ -0,0 +1,219 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// A reference to the user or service account which has made a change.
+type Actor struct {
+	ID string `json:"id"`
+	// The type of the actor, null for changes recorded before the type of the actor was stored.
+	Type *ActorType `json:"type"`
+}
+
+// Information about a page of a connection.
+type PageInfo struct {
+	HasNextPage bool `json:"hasNextPage"`
+	// The cursor to pass as 'after' to get the next page.
+	EndCursor *string `json:"endCursor"`
+}
+
+// A project of the DaSCH Service Platform.
+type Project struct {
+	ID          string `json:"id"`
+	ShortCode   string `json:"shortCode"`
+	ShortName   string `json:"shortName"`
+	LongName    string `json:"longName"`
+	Description string `json:"description"`
+	// The keywords of the project, empty unless it has been migrated from DSP-API.
+	Keywords  []string  `json:"keywords"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy"`
+	// The user or service account that created the project, null for projects migrated from DSP-API.
+	Creator   *Actor     `json:"creator"`
+	ChangedAt *time.Time `json:"changedAt"`
+	ChangedBy *string    `json:"changedBy"`
+	Changer   *Actor     `json:"changer"`
+	DeletedAt *time.Time `json:"deletedAt"`
+	DeletedBy *string    `json:"deletedBy"`
+	Deleter   *Actor     `json:"deleter"`
+	// All events recorded for the project, oldest first.
+	History []*ProjectEvent `json:"history"`
+}
+
+// A page of projects.
+type ProjectConnection struct {
+	Nodes    []*Project `json:"nodes"`
+	PageInfo *PageInfo  `json:"pageInfo"`
+	// The number of projects matching the query, over all pages.
+	TotalCount int `json:"totalCount"`
+}
+
+// An event recorded for a project. Only the fields set by the event are provided.
+type ProjectEvent struct {
+	// The type of the event, e.g. ProjectCreated.
+	Type string `json:"type"`
+	// When the event happened.
+	At time.Time `json:"at"`
+	By string    `json:"by"`
+	// The user or service account that caused the event.
+	Actor       *Actor  `json:"actor"`
+	ShortCode   *string `json:"shortCode"`
+	ShortName   *string `json:"shortName"`
+	LongName    *string `json:"longName"`
+	Description *string `json:"description"`
+}
+
+// Filters of the project list. Names match if they contain the provided value, ignoring case.
+type ProjectFilter struct {
+	Status    *ProjectStatus `json:"status"`
+	ShortName *string        `json:"shortName"`
+	LongName  *string        `json:"longName"`
+	// Matches the short or the long name.
+	Name *string `json:"name"`
+}
+
+// The values of a project. All fields must be provided.
+type ProjectInput struct {
+	ShortCode   string `json:"shortCode"`
+	ShortName   string `json:"shortName"`
+	LongName    string `json:"longName"`
+	Description string `json:"description"`
+}
+
+// The kind of principal which has made a change.
+type ActorType string
+
+const (
+	// A person authenticated with an access token.
+	ActorTypeUser ActorType = "USER"
+	// A service account authenticated with an api key.
+	ActorTypeServiceAccount ActorType = "SERVICE_ACCOUNT"
+)
+
+var AllActorType = []ActorType{
+	ActorTypeUser,
+	ActorTypeServiceAccount,
+}
+
+func (e ActorType) IsValid() bool {
+	switch e {
+	case ActorTypeUser, ActorTypeServiceAccount:
+		return true
+	}
+	return false
+}
+
+func (e ActorType) String() string {
+	return string(e)
+}
+
+func (e *ActorType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ActorType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ActorType", str)
+	}
+	return nil
+}
+
+func (e ActorType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProjectSortField string
+
+const (
+	ProjectSortFieldShortCode ProjectSortField = "SHORT_CODE"
+	ProjectSortFieldShortName ProjectSortField = "SHORT_NAME"
+	ProjectSortFieldCreatedAt ProjectSortField = "CREATED_AT"
+	ProjectSortFieldChangedAt ProjectSortField = "CHANGED_AT"
+)
+
+var AllProjectSortField = []ProjectSortField{
+	ProjectSortFieldShortCode,
+	ProjectSortFieldShortName,
+	ProjectSortFieldCreatedAt,
+	ProjectSortFieldChangedAt,
+}
+
+func (e ProjectSortField) IsValid() bool {
+	switch e {
+	case ProjectSortFieldShortCode, ProjectSortFieldShortName, ProjectSortFieldCreatedAt, ProjectSortFieldChangedAt:
+		return true
+	}
+	return false
+}
+
+func (e ProjectSortField) String() string {
+	return string(e)
+}
+
+func (e *ProjectSortField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProjectSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProjectSortField", str)
+	}
+	return nil
+}
+
+func (e ProjectSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProjectStatus string
+
+const (
+	ProjectStatusActive  ProjectStatus = "ACTIVE"
+	ProjectStatusDeleted ProjectStatus = "DELETED"
+	ProjectStatusAll     ProjectStatus = "ALL"
+)
+
+var AllProjectStatus = []ProjectStatus{
+	ProjectStatusActive,
+	ProjectStatusDeleted,
+	ProjectStatusAll,
+}
+
+func (e ProjectStatus) IsValid() bool {
+	switch e {
+	case ProjectStatusActive, ProjectStatusDeleted, ProjectStatusAll:
+		return true
+	}
+	return false
+}
+
+func (e ProjectStatus) String() string {
+	return string(e)
+}
+
+func (e *ProjectStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProjectStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProjectStatus", str)
+	}
+	return nil
+}
+
+func (e ProjectStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,189 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package graph
+
+//go:generate go run github.com/99designs/gqlgen
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/graph/model"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// maxFirst is the maximum number of projects returned by a single query.
+const maxFirst = 100
+
+//ErrInvalidFirst the requested number of projects is out of range
+var ErrInvalidFirst = errors.New("first must be between 1 and 100")
+
+// Resolver resolves the GraphQL queries and mutations by calling the project use case.
+// The principal is expected in the context of every request, see middleware.Authenticator.
+type Resolver struct {
+	Service project.UseCase
+}
+
+// sortFields maps the GraphQL sort fields to the ones of the project use case.
+var sortFields = map[model.ProjectSortField]project.SortField{
+	model.ProjectSortFieldShortCode: project.SortByShortCode,
+	model.ProjectSortFieldShortName: project.SortByShortName,
+	model.ProjectSortFieldCreatedAt: project.SortByCreatedAt,
+	model.ProjectSortFieldChangedAt: project.SortByChangedAt,
+}
+
+// statuses maps the GraphQL project statuses to the ones of the project use case.
+var statuses = map[model.ProjectStatus]project.Status{
+	model.ProjectStatusActive:  project.StatusActive,
+	model.ProjectStatusDeleted: project.StatusDeleted,
+	model.ProjectStatusAll:     project.StatusAll,
+}
+
+// authenticated returns the principal of the request.
+func authenticated(ctx context.Context) (*principal.Principal, error) {
+	user, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, principal.ErrNotAuthenticated
+	}
+	return user, nil
+}
+
+// parseID converts the id argument of a query to an identifier.
+func parseID(id string) (valueobject.Identifier, error) {
+	uuid, err := valueobject.IdentifierFromBytes([]byte(id))
+	if err != nil {
+		return valueobject.Identifier{}, projectEntity.ErrInvalidUUID
+	}
+	return uuid, nil
+}
+
+// parseFirst returns the number of projects to return, given the first argument of a query.
+func parseFirst(first *int) (int, error) {
+	if first == nil {
+		return 0, nil
+	}
+	if *first < 1 || *first > maxFirst {
+		return 0, ErrInvalidFirst
+	}
+	return *first, nil
+}
+
+// parseInput converts the project input to value objects.
+// The returned error lists every invalid field of the input.
+func parseInput(input model.ProjectInput) (valueobject.ShortCode, valueobject.ShortName, valueobject.LongName, valueobject.Description, error) {
+	v := valueobject.Validation{}
+	sc := v.ShortCode("shortCode", input.ShortCode)
+	sn := v.ShortName("shortName", input.ShortName)
+	ln := v.LongName("longName", input.LongName)
+	desc := v.Description("description", input.Description)
+	return sc, sn, ln, desc, v.Err()
+}
+
+// newProject converts the project aggregate to its GraphQL model. Values which have not been set yet are null.
+func newProject(p *projectEntity.Aggregate) *model.Project {
+	res := &model.Project{
+		ID:          p.ID().String(),
+		ShortCode:   p.ShortCode().String(),
+		ShortName:   p.ShortName().String(),
+		LongName:    p.LongName().String(),
+		Description: p.Description().String(),
+		Keywords:    append([]string{}, p.Keywords()...),
+		CreatedAt:   p.CreatedAt().Time(),
+		CreatedBy:   p.CreatedBy().String(),
+		Creator:     newActor(p.Creator()),
+	}
+
+	if !p.ChangedAt().Time().IsZero() {
+		changedAt, changedBy := p.ChangedAt().Time(), p.ChangedBy().String()
+		res.ChangedAt, res.ChangedBy, res.Changer = &changedAt, &changedBy, newActor(p.Changer())
+	}
+
+	if !p.DeletedAt().Time().IsZero() {
+		deletedAt, deletedBy := p.DeletedAt().Time(), p.DeletedBy().String()
+		res.DeletedAt, res.DeletedBy, res.Deleter = &deletedAt, &deletedBy, newActor(p.Deleter())
+	}
+
+	return res
+}
+
+// actorTypes maps the actor types to their GraphQL enum values.
+var actorTypes = map[event.ActorType]model.ActorType{
+	event.ActorUser:           model.ActorTypeUser,
+	event.ActorServiceAccount: model.ActorTypeServiceAccount,
+}
+
+// newActor converts the actor to its GraphQL model, or returns nil if it has not been set.
+// The type is nil for changes recorded before the type of the actor was stored.
+func newActor(a event.Actor) *model.Actor {
+	if a.ID == (valueobject.Identifier{}) {
+		return nil
+	}
+	res := &model.Actor{ID: a.ID.String()}
+	if t, ok := actorTypes[a.Type]; ok {
+		res.Type = &t
+	}
+	return res
+}
+
+// newProjectEvent converts the project event to its GraphQL model. It returns nil for events of other aggregates.
+func newProjectEvent(ev event.Event) *model.ProjectEvent {
+	str := func(s string) *string { return &s }
+
+	switch e := ev.(type) {
+	case *event.ProjectCreated:
+		return &model.ProjectEvent{
+			Type:        "ProjectCreated",
+			At:          e.CreatedAt.Time(),
+			By:          e.CreatedBy.String(),
+			Actor:       newActor(event.Actor{ID: e.CreatedBy, Type: e.CreatedByType}),
+			ShortCode:   str(e.ShortCode.String()),
+			ShortName:   str(e.ShortName.String()),
+			LongName:    str(e.LongName.String()),
+			Description: str(e.Description.String()),
+		}
+	case *event.ProjectChanged:
+		return &model.ProjectEvent{
+			Type:        "ProjectChanged",
+			At:          e.ChangedAt.Time(),
+			By:          e.ChangedBy.String(),
+			Actor:       newActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}),
+			ShortCode:   str(e.ShortCode.String()),
+			ShortName:   str(e.ShortName.String()),
+			LongName:    str(e.LongName.String()),
+			Description: str(e.Description.String()),
+		}
+	case *event.ProjectShortCodeChanged:
+		return &model.ProjectEvent{Type: "ProjectShortCodeChanged", At: e.ChangedAt.Time(), By: e.ChangedBy.String(), Actor: newActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}), ShortCode: str(e.ShortCode.String())}
+	case *event.ProjectShortNameChanged:
+		return &model.ProjectEvent{Type: "ProjectShortNameChanged", At: e.ChangedAt.Time(), By: e.ChangedBy.String(), Actor: newActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}), ShortName: str(e.ShortName.String())}
+	case *event.ProjectLongNameChanged:
+		return &model.ProjectEvent{Type: "ProjectLongNameChanged", At: e.ChangedAt.Time(), By: e.ChangedBy.String(), Actor: newActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}), LongName: str(e.LongName.String())}
+	case *event.ProjectDescriptionChanged:
+		return &model.ProjectEvent{Type: "ProjectDescriptionChanged", At: e.ChangedAt.Time(), By: e.ChangedBy.String(), Actor: newActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}), Description: str(e.Description.String())}
+	case *event.ProjectDeleted:
+		return &model.ProjectEvent{Type: "ProjectDeleted", At: e.DeletedAt.Time(), By: e.DeletedBy.String(), Actor: newActor(event.Actor{ID: e.DeletedBy, Type: e.DeletedByType})}
+	case *event.ProjectRestored:
+		return &model.ProjectEvent{Type: "ProjectRestored", At: e.RestoredAt.Time(), By: e.RestoredBy.String(), Actor: newActor(event.Actor{ID: e.RestoredBy, Type: e.RestoredByType})}
+	}
+
+	return nil
+}
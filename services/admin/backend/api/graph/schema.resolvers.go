@@ -0,0 +1,257 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/graph/model"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+)
+
+func (r *mutationResolver) CreateProject(ctx context.Context, input model.ProjectInput) (*model.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanCreate(user) {
+		return nil, projectEntity.ErrUserDoesNotHaveCreateProjectsPermission
+	}
+
+	sc, sn, ln, desc, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := r.Service.CreateProject(ctx, sc, sn, ln, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.Service.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProject(p), nil
+}
+
+func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input model.ProjectInput) (*model.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uuid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanUpdate(user, uuid) {
+		return nil, projectEntity.ErrUserDoesNotHaveUpdateProjectPermission
+	}
+
+	sc, sn, ln, desc, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.Service.UpdateProject(ctx, uuid, sc, sn, ln, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProject(p), nil
+}
+
+func (r *mutationResolver) DeleteProject(ctx context.Context, id string) (*model.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanDelete(user) {
+		return nil, projectEntity.ErrUserDoesNotHaveDeleteProjectPermission
+	}
+
+	uuid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.Service.DeleteProject(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProject(p), nil
+}
+
+func (r *projectResolver) History(ctx context.Context, obj *model.Project) ([]*model.ProjectEvent, error) {
+	uuid, err := parseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := r.Service.GetProjectHistory(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []*model.ProjectEvent{}
+	for _, ev := range events {
+		if e := newProjectEvent(ev); e != nil {
+			history = append(history, e)
+		}
+	}
+
+	return history, nil
+}
+
+func (r *queryResolver) Project(ctx context.Context, id string) (*model.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uuid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanRead(user, uuid) {
+		return nil, projectEntity.ErrUserDoesNotHaveReadProjectPermission
+	}
+
+	p, err := r.Service.GetProject(ctx, uuid)
+	if err == projectEntity.ErrProjectNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newProject(p), nil
+}
+
+func (r *queryResolver) Projects(ctx context.Context, first *int, after *string, sort *model.ProjectSortField, descending *bool, filter *model.ProjectFilter) (*model.ProjectConnection, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanList(user) {
+		return nil, projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission
+	}
+
+	limit, err := parseFirst(first)
+	if err != nil {
+		return nil, err
+	}
+
+	q := project.Query{Limit: limit}
+	if after != nil {
+		q.After = *after
+	}
+	if sort != nil {
+		q.Sort = sortFields[*sort]
+	}
+	if descending != nil {
+		q.Descending = *descending
+	}
+	if filter != nil {
+		if filter.Status != nil {
+			q.Status = statuses[*filter.Status]
+		}
+		if filter.ShortName != nil {
+			q.ShortName = *filter.ShortName
+		}
+		if filter.LongName != nil {
+			q.LongName = *filter.LongName
+		}
+		if filter.Name != nil {
+			q.Name = *filter.Name
+		}
+	}
+
+	// project admins only get the projects they have access to
+	if projectEntity.IsRestrictedToOwnProjects(user) {
+		q.ProjectIDs = append([]string{}, user.Projects...)
+	}
+
+	result, err := r.Service.QueryProjects(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.ProjectConnection{
+		Nodes:      []*model.Project{},
+		PageInfo:   &model.PageInfo{HasNextPage: result.NextCursor != ""},
+		TotalCount: result.Total,
+	}
+	if result.NextCursor != "" {
+		res.PageInfo.EndCursor = &result.NextCursor
+	}
+	for i := range result.Projects {
+		res.Nodes = append(res.Nodes, newProject(&result.Projects[i]))
+	}
+
+	return res, nil
+}
+
+func (r *queryResolver) SearchProjects(ctx context.Context, query string, first *int) ([]*model.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanList(user) {
+		return nil, projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, projectEntity.ErrNoSearchQuery
+	}
+
+	limit, err := parseFirst(first)
+	if err != nil {
+		return nil, err
+	}
+
+	// project admins only get the projects they have access to, so the limit is applied after filtering
+	filter := projectEntity.IsRestrictedToOwnProjects(user)
+	searchLimit := limit
+	if filter {
+		searchLimit = 0
+	}
+
+	projects, err := r.Service.SearchProjects(ctx, query, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.Project{}
+	for i := range projects {
+		if filter && !user.HasProject(projects[i].ID().String()) {
+			continue
+		}
+		if limit > 0 && len(res) == limit {
+			break
+		}
+		res = append(res, newProject(&projects[i]))
+	}
+
+	return res, nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Project returns ProjectResolver implementation.
+func (r *Resolver) Project() ProjectResolver { return &projectResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type projectResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
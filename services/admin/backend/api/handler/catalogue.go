@@ -0,0 +1,177 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+)
+
+// defaultCatalogueLimit is the number of projects returned per page if no limit is requested.
+const defaultCatalogueLimit = 20
+
+// maxCatalogueLimit is the maximum number of projects returned per page.
+const maxCatalogueLimit = 100
+
+// catalogueMaxAge is the number of seconds clients and proxies may cache responses of the catalogue.
+const catalogueMaxAge = 300
+
+// ErrInvalidLimit is returned if the limit query parameter is not a positive number.
+var ErrInvalidLimit = errors.New("limit must be a number between 1 and " + strconv.Itoa(maxCatalogueLimit))
+
+// ErrInvalidOffset is returned if the offset query parameter is not a positive number.
+var ErrInvalidOffset = errors.New("offset must be a positive number")
+
+// listPublicProjects gets a page of the public project catalogue.
+// Only active projects are listed and only their public metadata is returned.
+// The page can be selected with the `limit` and `offset` query parameters.
+func listPublicProjects(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		limit, offset, err := parsePage(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		// deleted projects are never part of the catalogue
+		projects, err := service.ListProjects(ctx, false)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		res := presenter.PublicProjectList{
+			Projects: []presenter.PublicProject{},
+			Total:    len(projects),
+			Limit:    limit,
+			Offset:   offset,
+		}
+
+		for i := offset; i < len(projects) && i < offset+limit; i++ {
+			res.Projects = append(res.Projects, presenter.NewPublicProject(&projects[i]))
+		}
+
+		writeCacheable(w, r, res)
+	}
+}
+
+// getPublicProject gets the public metadata of the project with the provided UUID.
+// Deleted projects are reported as not found.
+func getPublicProject(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		uuid, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, projectEntity.ErrInvalidUUID)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		p, err := service.GetProject(ctx, uuid)
+		if err == projectEntity.ErrProjectHasBeenDeleted || err == nil && (p == nil || p.ID() != uuid || !p.DeletedAt().Time().IsZero()) {
+			err = projectEntity.ErrProjectNotFound
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeCacheable(w, r, presenter.NewPublicProject(p))
+	}
+}
+
+// parsePage reads the `limit` and `offset` query parameters of the request.
+func parsePage(r *http.Request) (int, int, error) {
+	limit := defaultCatalogueLimit
+	offset := 0
+
+	if l := r.URL.Query().Get("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v < 1 || v > maxCatalogueLimit {
+			return 0, 0, problem.InvalidParameter("limit", ErrInvalidLimit)
+		}
+		limit = v
+	}
+
+	if o := r.URL.Query().Get("offset"); o != "" {
+		v, err := strconv.Atoi(o)
+		if err != nil || v < 0 {
+			return 0, 0, problem.InvalidParameter("offset", ErrInvalidOffset)
+		}
+		offset = v
+	}
+
+	return limit, offset, nil
+}
+
+// writeCacheable writes the JSON encoded value together with caching headers.
+// The ETag is derived from the response body, so that clients revalidating with
+// `If-None-Match` get a `304 Not Modified` as long as the content did not change.
+func writeCacheable(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(catalogueMaxAge))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Accept")
+
+	for _, match := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if m := strings.TrimSpace(match); m == etag || m == "W/"+etag || m == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
+
+// MakePublicProjectHandlers make url handlers for the public, read-only project catalogue.
+// The catalogue does not require authentication and must therefore not be registered on an authenticated router.
+func MakePublicProjectHandlers(r *mux.Router, service project.UseCase) {
+
+	r.HandleFunc("/v1/public/projects", listPublicProjects(service)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/v1/public/projects/{id}", getPublicProject(service)).Methods("GET", "OPTIONS")
+}
@@ -0,0 +1,119 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogue_ListPublicProjects(t *testing.T) {
+	service := newStubProjectService(t, 3)
+	service.projects[1].DeleteProject(service.projects[1].ID(), service.projects[1].Creator())
+
+	r := mux.NewRouter()
+	handler.MakePublicProjectHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/public/projects?limit=1&offset=1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Cache-Control"), "public")
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+
+	var res presenter.PublicProjectList
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, 1, res.Limit)
+	assert.Equal(t, 1, res.Offset)
+	assert.Len(t, res.Projects, 1)
+	assert.Equal(t, service.projects[2].ID(), res.Projects[0].ID)
+}
+
+func TestCatalogue_ListPublicProjects_OnlyWhitelistedFields(t *testing.T) {
+	service := newStubProjectService(t, 1)
+
+	r := mux.NewRouter()
+	handler.MakePublicProjectHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/public/projects", nil))
+
+	var res struct {
+		Projects []map[string]interface{} `json:"projects"`
+	}
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Len(t, res.Projects, 1)
+
+	var keys []string
+	for k := range res.Projects[0] {
+		keys = append(keys, k)
+	}
+	assert.ElementsMatch(t, []string{"id", "shortCode", "shortName", "longName", "description", "keywords"}, keys)
+}
+
+func TestCatalogue_ListPublicProjects_InvalidPage(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakePublicProjectHandlers(r, newStubProjectService(t, 1))
+
+	for _, query := range []string{"limit=0", "limit=abc", "limit=1000", "offset=-1"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/public/projects?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestCatalogue_GetPublicProject_NotModified(t *testing.T) {
+	service := newStubProjectService(t, 1)
+
+	r := mux.NewRouter()
+	handler.MakePublicProjectHandlers(r, service)
+
+	path := "/v1/public/projects/" + service.projects[0].ID().String()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestCatalogue_GetPublicProject_Deleted(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	service.projects[0].DeleteProject(service.projects[0].ID(), service.projects[0].Creator())
+
+	r := mux.NewRouter()
+	handler.MakePublicProjectHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/public/projects/"+service.projects[0].ID().String(), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
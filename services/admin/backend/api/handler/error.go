@@ -0,0 +1,45 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+)
+
+// ErrInvalidIncludeDeleted is returned if the includeDeleted query parameter is not a boolean.
+var ErrInvalidIncludeDeleted = errors.New("includeDeleted must be either true or false")
+
+// ErrInvalidExportFormat is returned if the format query parameter of an export is missing or unknown.
+var ErrInvalidExportFormat = errors.New("format must be either schema.org or datacite")
+
+// ErrInvalidDryRun is returned if the dryRun query parameter is not a boolean.
+var ErrInvalidDryRun = errors.New("dryRun must be either true or false")
+
+// ErrStreamingUnsupported is returned if the connection of a request cannot stream a response.
+var ErrStreamingUnsupported = errors.New("the connection does not support streaming")
+
+// ErrProjectionNotFound is returned if a maintenance request names a projection which does not exist.
+var ErrProjectionNotFound = errors.New("there is no projection with this name")
+
+// writeError writes the error as application/problem+json response, see problem.FromError for the status codes.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, problem.FromError(err))
+}
@@ -0,0 +1,161 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/server"
+	"github.com/gorilla/mux"
+)
+
+// eventStreamBuffer is the number of events buffered for each client of an event stream.
+const eventStreamBuffer = 100
+
+// eventStreamHeartbeat is the interval in which a comment is sent to keep idle event streams open.
+const eventStreamHeartbeat = 15 * time.Second
+
+// eventStreamWriteTimeout is the time allowed for writing each event, which replaces the timeout of the server for event streams.
+const eventStreamWriteTimeout = 10 * time.Second
+
+// watchedEvent is a project event together with its id.
+type watchedEvent struct {
+	id string
+	ev event.Event
+}
+
+// streamProjectEvents streams the projects being created, changed or deleted as server-sent events, until the client disconnects.
+// Only the events of the projects the user may read are sent. Each event carries the state of the project after it.
+// A client reconnecting with the `Last-Event-ID` header receives the events recorded after the last one it received.
+func streamProjectEvents(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
+			return
+		}
+
+		// ensure the user has the required role for the action
+		if !projectEntity.CanList(user) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission)
+			return
+		}
+		restricted := projectEntity.IsRestrictedToOwnProjects(user)
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, r, ErrStreamingUnsupported)
+			return
+		}
+
+		// the events are handed over to this goroutine, a slow client holds back the subscription
+		ctx := r.Context()
+		events := make(chan watchedEvent, eventStreamBuffer)
+		err := service.WatchProjectsSince(ctx, r.Header.Get("Last-Event-ID"), func(id string, ev event.Event) {
+			select {
+			case events <- watchedEvent{id, ev}:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		// the stream outlives the timeout of the server, a client which stops reading is still disconnected
+		if err := server.ExtendDeadline(r, eventStreamWriteTimeout); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		// proxies must not buffer the stream
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(eventStreamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+				if err := server.ExtendDeadline(r, eventStreamWriteTimeout); err != nil {
+					return
+				}
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			case e := <-events:
+				res := presenter.NewProjectEvent(e.ev)
+				if res == nil || (restricted && !user.HasProject(res.ProjectID.String())) {
+					continue
+				}
+
+				p, err := service.GetProject(ctx, res.ProjectID)
+				if err != nil {
+					// the notification is still sent, the client can get the project itself
+					log.Println(err.Error())
+				} else {
+					state := presenter.NewProjectV2(p)
+					res.Project = &state
+				}
+
+				if err := server.ExtendDeadline(r, eventStreamWriteTimeout); err != nil {
+					return
+				}
+				if err := writeServerSentEvent(w, e.id, res.Type, res); err != nil {
+					log.Println(err.Error())
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeServerSentEvent writes the data as JSON encoded server-sent event with the id and the name.
+func writeServerSentEvent(w http.ResponseWriter, id string, name string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, name, b)
+	return err
+}
+
+// MakeProjectEventHandlers makes the url handler of the stream of project events.
+// Browsers can keep several admin views in sync by listening to it.
+func MakeProjectEventHandlers(r *mux.Router, service project.UseCase) {
+
+	r.HandleFunc("/v1/events/projects", streamProjectEvents(service)).Methods("GET", "OPTIONS")
+}
@@ -0,0 +1,148 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// serverSentEvent is an event read from an event stream.
+type serverSentEvent struct {
+	id   string
+	name string
+	data presenter.ProjectEvent
+}
+
+// newEventServer serves the project event stream to the principal.
+func newEventServer(service *stubProjectService, p *principal.Principal) *httptest.Server {
+	r := mux.NewRouter()
+	handler.MakeProjectEventHandlers(r, service)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.ServeHTTP(w, req.WithContext(principal.NewContext(req.Context(), p)))
+	}))
+}
+
+// readEvent reads the next event from the stream, skipping comments.
+func readEvent(t *testing.T, stream *bufio.Reader) serverSentEvent {
+	var res serverSentEvent
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && res.id != "":
+			return res
+		case strings.HasPrefix(line, "id: "):
+			res.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			res.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			assert.Nil(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &res.data))
+		}
+	}
+}
+
+func TestStreamProjectEvents(t *testing.T) {
+	service := newStubProjectService(t, 2)
+	first, second := service.projects[0].ID(), service.projects[1].ID()
+	service.record(&event.ProjectShortNameChanged{ID: first})
+	service.record(&event.ProjectLongNameChanged{ID: second})
+
+	srv := newEventServer(service, systemAdmin())
+	defer srv.Close()
+
+	// resuming after the first event replays the second one
+	req, _ := http.NewRequest("GET", srv.URL+"/v1/events/projects", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	stream := bufio.NewReader(res.Body)
+	ev := readEvent(t, stream)
+	assert.Equal(t, "2", ev.id)
+	assert.Equal(t, presenter.ProjectEventChanged, ev.name)
+	assert.Equal(t, "ProjectLongNameChanged", ev.data.Event)
+	assert.Equal(t, second, ev.data.ProjectID)
+	assert.Equal(t, second, ev.data.Project.ID)
+
+	// events recorded later on are pushed
+	service.record(&event.ProjectDeleted{ID: first})
+	ev = readEvent(t, stream)
+	assert.Equal(t, "3", ev.id)
+	assert.Equal(t, presenter.ProjectEventDeleted, ev.name)
+	assert.Equal(t, first, ev.data.ProjectID)
+}
+
+func TestStreamProjectEvents_OwnProjectsOnly(t *testing.T) {
+	service := newStubProjectService(t, 2)
+	first, second := service.projects[0].ID(), service.projects[1].ID()
+	service.record(&event.ProjectShortNameChanged{ID: second})
+	service.record(&event.ProjectShortNameChanged{ID: first})
+
+	srv := newEventServer(service, &principal.Principal{IsProjectAdmin: true, Projects: []string{first.String()}})
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL+"/v1/events/projects", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	// the event of the other project is skipped
+	ev := readEvent(t, bufio.NewReader(res.Body))
+	assert.Equal(t, "2", ev.id)
+	assert.Equal(t, first, ev.data.ProjectID)
+}
+
+func TestStreamProjectEvents_Errors(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeProjectEventHandlers(r, newStubProjectService(t, 1))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/events/projects", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/events/projects", &principal.Principal{}))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	req := newAuthenticatedRequest("GET", "/v1/events/projects", systemAdmin())
+	req.Header.Set("Last-Event-ID", "not an id")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_event_id")
+}
@@ -0,0 +1,114 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+)
+
+// export formats of a project.
+const (
+	exportFormatSchemaOrg = "schema.org"
+	exportFormatDataCite  = "datacite"
+)
+
+// exportProject exports the metadata of the project with the provided UUID in the format of the `format` query parameter.
+func exportProject(service project.UseCase, opts export.DataCiteOptions) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
+			return
+		}
+
+		uuid, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, projectEntity.ErrInvalidUUID)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format != exportFormatSchemaOrg && format != exportFormatDataCite {
+			writeError(w, r, problem.InvalidParameter("format", ErrInvalidExportFormat))
+			return
+		}
+
+		// ensure the user has the required role for the action
+		if !projectEntity.CanRead(user, uuid) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveReadProjectPermission)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		p, err := service.GetProject(ctx, uuid)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if p == nil {
+			writeError(w, r, projectEntity.ErrNoProjectDataReturned)
+			return
+		}
+
+		if format == exportFormatDataCite {
+			w.Header().Set("Content-Type", export.DataCiteContentType)
+			err = writeXML(w, export.NewDataCiteResource(p, opts))
+		} else {
+			w.Header().Set("Content-Type", export.SchemaOrgContentType)
+			err = json.NewEncoder(w).Encode(export.NewResearchProject(p))
+		}
+		if err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// writeXML writes the XML encoded value, including the XML declaration.
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(v)
+}
+
+// MakeProjectExportHandlers make url handlers for exporting the metadata of projects in the formats of research data repositories.
+// The router is expected to authenticate every request, see middleware.Authenticator.
+func MakeProjectExportHandlers(r *mux.Router, service project.UseCase, opts export.DataCiteOptions) {
+
+	r.HandleFunc("/v1/projects/{id}/export", exportProject(service, opts)).Methods("GET", "OPTIONS")
+}
@@ -0,0 +1,93 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportProject(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	id := service.projects[0].ID().String()
+
+	r := mux.NewRouter()
+	handler.MakeProjectExportHandlers(r, service, export.DataCiteOptions{DOIPrefix: "10.5072", Publisher: "DaSCH"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects/"+id+"/export?format=schema.org", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, export.SchemaOrgContentType, w.Header().Get("Content-Type"))
+
+	var researchProject export.ResearchProject
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&researchProject))
+	assert.Equal(t, "ResearchProject", researchProject.Type)
+	assert.Equal(t, "http://rdfh.ch/projects/"+id, researchProject.ID)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects/"+id+"/export?format=datacite", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, export.DataCiteContentType, w.Header().Get("Content-Type"))
+
+	var resource export.Resource
+	assert.Nil(t, xml.NewDecoder(w.Body).Decode(&resource))
+	assert.Equal(t, "10.5072/"+id, resource.Identifier.Value)
+	assert.Equal(t, "DaSCH", resource.Publisher)
+}
+
+func TestExportProject_Errors(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	id := service.projects[0].ID().String()
+
+	r := mux.NewRouter()
+	handler.MakeProjectExportHandlers(r, service, export.DataCiteOptions{DOIPrefix: "10.5072", Publisher: "DaSCH"})
+
+	otherAdmin := &principal.Principal{IsProjectAdmin: true, Projects: []string{"b9d7a6e4-dcd6-43ff-a928-f55e9e8097f8"}}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		code   string
+	}{
+		{"missing format", newAuthenticatedRequest("GET", "/v1/projects/"+id+"/export", systemAdmin()), http.StatusBadRequest, problem.CodeInvalidParameter},
+		{"unknown format", newAuthenticatedRequest("GET", "/v1/projects/"+id+"/export?format=marc", systemAdmin()), http.StatusBadRequest, problem.CodeInvalidParameter},
+		{"permission denied", newAuthenticatedRequest("GET", "/v1/projects/"+id+"/export?format=datacite", otherAdmin), http.StatusForbidden, problem.CodePermissionDenied},
+		{"not authenticated", httptest.NewRequest("GET", "/v1/projects/"+id+"/export?format=datacite", nil), http.StatusUnauthorized, problem.CodeNotAuthenticated},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, tt.req)
+		assert.Equal(t, tt.status, w.Code, tt.name)
+
+		var res problem.Problem
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&res), tt.name)
+		assert.Equal(t, tt.code, res.Code, tt.name)
+	}
+}
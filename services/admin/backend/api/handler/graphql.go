@@ -0,0 +1,43 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	gqlhandler "github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/graph"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/gorilla/mux"
+)
+
+// maxQueryComplexity limits the number of fields a single GraphQL query may resolve.
+const maxQueryComplexity = 500
+
+// MakeGraphQLHandler makes the url handler of the GraphQL endpoint, which queries and mutates projects.
+// The router is expected to authenticate every request, see middleware.Authenticator.
+func MakeGraphQLHandler(r *mux.Router, service project.UseCase) {
+
+	srv := gqlhandler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{Service: service}}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(maxQueryComplexity))
+
+	r.Handle("/v1/graphql", srv).Methods("GET", "POST", "OPTIONS")
+}
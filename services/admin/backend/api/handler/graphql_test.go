@@ -0,0 +1,209 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// graphQLResponse is the body of a response of the GraphQL endpoint.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// postGraphQL sends the query with the provided variables to the GraphQL endpoint on behalf of the principal.
+func postGraphQL(t *testing.T, r http.Handler, p *principal.Principal, query string, variables map[string]interface{}) graphQLResponse {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("POST", "/v1/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if p != nil {
+		req = req.WithContext(principal.NewContext(req.Context(), p))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res graphQLResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestGraphQL_ProjectWithHistory(t *testing.T) {
+	service := newStubProjectService(t, 2)
+
+	r := mux.NewRouter()
+	handler.MakeGraphQLHandler(r, service)
+
+	id := service.projects[1].ID().String()
+	res := postGraphQL(t, r, systemAdmin(), `query($id: ID!) {
+		project(id: $id) { id shortCode shortName changedAt creator { id type } changer { id } history { type by actor { id type } shortName } }
+	}`, map[string]interface{}{"id": id})
+	assert.Empty(t, res.Errors)
+
+	var data struct {
+		Project struct {
+			ID        string
+			ShortCode string
+			ShortName string
+			ChangedAt *string
+			Creator   struct {
+				ID   string
+				Type *string
+			}
+			Changer *struct{ ID string }
+			History []struct {
+				Type  string
+				By    string
+				Actor struct {
+					ID   string
+					Type *string
+				}
+				ShortName *string
+			}
+		}
+	}
+	assert.Nil(t, json.Unmarshal(res.Data, &data))
+	assert.Equal(t, id, data.Project.ID)
+	assert.Equal(t, "0001", data.Project.ShortCode)
+	assert.Nil(t, data.Project.ChangedAt)
+	assert.Equal(t, service.projects[1].CreatedBy().String(), data.Project.Creator.ID)
+	assert.Equal(t, "USER", *data.Project.Creator.Type)
+	assert.Nil(t, data.Project.Changer)
+	assert.Len(t, data.Project.History, 1)
+	assert.Equal(t, "ProjectCreated", data.Project.History[0].Type)
+	assert.Equal(t, service.projects[1].CreatedBy().String(), data.Project.History[0].By)
+	assert.Equal(t, service.projects[1].CreatedBy().String(), data.Project.History[0].Actor.ID)
+	assert.Equal(t, "USER", *data.Project.History[0].Actor.Type)
+	assert.Equal(t, "project 1", *data.Project.History[0].ShortName)
+}
+
+func TestGraphQL_Keywords(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Bernoullis and Euler")
+	service.projects = append(service.projects, project.NewMigratedAggregate(id, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", sc, sn, ln, desc, []string{"mathematics", "letters"}, valueobject.NewTimestamp()))
+
+	r := mux.NewRouter()
+	handler.MakeGraphQLHandler(r, service)
+
+	res := postGraphQL(t, r, systemAdmin(), `{ projects(first: 10) { nodes { shortCode keywords } } }`, nil)
+	assert.Empty(t, res.Errors)
+
+	var data struct {
+		Projects struct {
+			Nodes []struct {
+				ShortCode string
+				Keywords  []string
+			}
+		}
+	}
+	assert.Nil(t, json.Unmarshal(res.Data, &data))
+	assert.Len(t, data.Projects.Nodes, 2)
+
+	// the keywords of the projects which have not been migrated are empty
+	assert.Equal(t, []string{}, data.Projects.Nodes[0].Keywords)
+	assert.Equal(t, "0801", data.Projects.Nodes[1].ShortCode)
+	assert.Equal(t, []string{"mathematics", "letters"}, data.Projects.Nodes[1].Keywords)
+}
+
+func TestGraphQL_Projects(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeGraphQLHandler(r, newStubProjectService(t, 3))
+
+	res := postGraphQL(t, r, systemAdmin(), `{
+		projects(first: 10, sort: CREATED_AT, descending: true) { totalCount nodes { shortCode } pageInfo { hasNextPage endCursor } }
+	}`, nil)
+	assert.Empty(t, res.Errors)
+
+	var data struct {
+		Projects struct {
+			TotalCount int
+			Nodes      []struct{ ShortCode string }
+			PageInfo   struct {
+				HasNextPage bool
+				EndCursor   *string
+			}
+		}
+	}
+	assert.Nil(t, json.Unmarshal(res.Data, &data))
+	assert.Equal(t, 3, data.Projects.TotalCount)
+	assert.Len(t, data.Projects.Nodes, 3)
+	assert.False(t, data.Projects.PageInfo.HasNextPage)
+	assert.Nil(t, data.Projects.PageInfo.EndCursor)
+
+	res = postGraphQL(t, r, systemAdmin(), `{ projects(first: 1000) { totalCount } }`, nil)
+	assert.Len(t, res.Errors, 1)
+}
+
+func TestGraphQL_CreateProject(t *testing.T) {
+	service := newStubProjectService(t, 0)
+
+	r := mux.NewRouter()
+	handler.MakeGraphQLHandler(r, service)
+
+	mutation := `mutation($input: ProjectInput!) { createProject(input: $input) { id shortCode } }`
+	input := map[string]interface{}{"input": map[string]interface{}{
+		"shortCode":   "0801",
+		"shortName":   "beol",
+		"longName":    "Bernoulli-Euler Online",
+		"description": "Letters of the Bernoulli family",
+	}}
+
+	// users without the permission to create projects are rejected
+	res := postGraphQL(t, r, &principal.Principal{IsProjectAdmin: true}, mutation, input)
+	assert.Len(t, res.Errors, 1)
+	assert.Empty(t, service.projects)
+
+	res = postGraphQL(t, r, systemAdmin(), mutation, input)
+	assert.Empty(t, res.Errors)
+	assert.Len(t, service.projects, 1)
+	assert.Contains(t, string(res.Data), `"shortCode":"0801"`)
+}
+
+func TestGraphQL_NotAuthenticated(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeGraphQLHandler(r, newStubProjectService(t, 1))
+
+	res := postGraphQL(t, r, nil, `{ projects { totalCount } }`, nil)
+	assert.Len(t, res.Errors, 1)
+	assert.Equal(t, principal.ErrNotAuthenticated.Error(), res.Errors[0].Message)
+}
@@ -0,0 +1,191 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+)
+
+// media types of the files accepted by the project import.
+const (
+	mediaTypeCSV       = "text/csv"
+	mediaTypeNDJSON    = "application/x-ndjson"
+	mediaTypeJSONLines = "application/jsonl"
+)
+
+// maxImportBodySize is the maximum size of an imported file in bytes.
+const maxImportBodySize = 10 << 20
+
+// importColumns are the columns of an imported CSV file, which must be named in its header row.
+var importColumns = []string{"shortCode", "shortName", "longName", "description"}
+
+// importProjects validates the projects of a CSV or JSON lines file and creates the valid ones.
+// With `?dryRun=true`, only the outcome of every row is reported and no project is created.
+func importProjects(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
+			return
+		}
+
+		// ensure the user has the required role for the action
+		if !projectEntity.CanCreate(user) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveCreateProjectsPermission)
+			return
+		}
+
+		dryRun := false
+		if d := r.URL.Query().Get("dryRun"); d != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(d); err != nil {
+				writeError(w, r, problem.InvalidParameter("dryRun", ErrInvalidDryRun))
+				return
+			}
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		var parse func(io.Reader) ([]project.ImportRow, error)
+		switch mediaType {
+		case mediaTypeCSV:
+			parse = parseImportCSV
+		case mediaTypeNDJSON, mediaTypeJSONLines:
+			parse = parseImportJSONLines
+		default:
+			writeError(w, r, problem.UnsupportedMediaType(mediaTypeCSV, mediaTypeNDJSON))
+			return
+		}
+
+		rows, err := parse(http.MaxBytesReader(w, r.Body, maxImportBodySize))
+		if err != nil {
+			writeError(w, r, problem.MalformedBody(err))
+			return
+		}
+
+		// every row is saved on its own, so large imports get more time than a single project
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(60)*time.Second)
+		defer cancel()
+
+		results, err := service.ImportProjects(ctx, rows, dryRun)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(presenter.NewImportReport(dryRun, results)); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// parseImportCSV reads the rows of a CSV file whose header row names the columns, in any order.
+// The line of a row is its record number, the header being line 1.
+func parseImportCSV(r io.Reader) ([]project.ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		// a byte order mark written by spreadsheet applications is not part of the first column name
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("the header row does not contain the column %s", name)
+		}
+	}
+
+	var rows []project.ImportRow
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, project.ImportRow{
+			Line:        line,
+			ShortCode:   record[index["shortCode"]],
+			ShortName:   record[index["shortName"]],
+			LongName:    record[index["longName"]],
+			Description: record[index["description"]],
+		})
+	}
+}
+
+// parseImportJSONLines reads the rows of a file containing a project per line, structured like the RequestBody.
+// Blank lines are skipped.
+func parseImportJSONLines(r io.Reader) ([]project.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportBodySize)
+
+	var rows []project.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		var input RequestBody
+		if err := json.Unmarshal(b, &input); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rows = append(rows, project.ImportRow{
+			Line:        line,
+			ShortCode:   input.ShortCode,
+			ShortName:   input.ShortName,
+			LongName:    input.LongName,
+			Description: input.Description,
+		})
+	}
+
+	return rows, scanner.Err()
+}
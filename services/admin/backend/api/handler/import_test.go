@@ -0,0 +1,146 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// importCSV contains a valid row, a row with invalid values and a row using the short code of the first stub project.
+const importCSV = "description,shortCode,shortName,longName\n" +
+	"the first project,0A01,first,first project\n" +
+	"an invalid project,XYZ,,invalid project\n" +
+	"\"a project, taken\",0000,taken,taken project\n"
+
+// newImportRequest creates an authenticated request of a system admin importing the body of the media type.
+func newImportRequest(target string, contentType string, body string) *http.Request {
+	req := httptest.NewRequest("POST", target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req.WithContext(principal.NewContext(req.Context(), systemAdmin()))
+}
+
+// importProjects serves the import request and decodes the report.
+func importProjects(t *testing.T, r *mux.Router, req *http.Request) presenter.ImportReport {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res presenter.ImportReport
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	return res
+}
+
+func TestProject_Import_DryRun(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	res := importProjects(t, r, newImportRequest("/v1/projects:import?dryRun=true", "text/csv; charset=utf-8", importCSV))
+
+	assert.True(t, res.DryRun)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, 2, res.Valid)
+	assert.Equal(t, 1, res.Invalid)
+	assert.Equal(t, 0, res.Created)
+
+	assert.Equal(t, 2, res.Rows[0].Line)
+	assert.Equal(t, "0A01", res.Rows[0].ShortCode)
+	assert.Equal(t, "valid", res.Rows[0].Status)
+	assert.Nil(t, res.Rows[0].Error)
+
+	assert.Equal(t, "invalid", res.Rows[1].Status)
+	assert.Equal(t, "validation_failed", res.Rows[1].Error.Code)
+	assert.Len(t, res.Rows[1].Error.Errors, 2)
+
+	assert.Len(t, service.projects, 1)
+}
+
+func TestProject_Import_Commit(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	res := importProjects(t, r, newImportRequest("/v1/projects:import", "text/csv", importCSV))
+
+	assert.False(t, res.DryRun)
+	assert.Equal(t, 1, res.Created)
+	assert.Equal(t, "created", res.Rows[0].Status)
+	assert.NotNil(t, res.Rows[0].ID)
+	assert.Equal(t, "invalid", res.Rows[2].Status)
+	assert.Equal(t, "short_code_already_exists", res.Rows[2].Error.Code)
+	assert.Len(t, service.projects, 2)
+}
+
+func TestProject_Import_JSONLines(t *testing.T) {
+	service := newStubProjectService(t, 0)
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	body := `{"shortCode":"0A01","shortName":"first","longName":"first project","description":"the first project"}` + "\n\n" +
+		`{"shortCode":"0A02","shortName":"second","longName":"second project","description":"the second project"}` + "\n"
+	res := importProjects(t, r, newImportRequest("/v1/projects:import", "application/x-ndjson", body))
+
+	assert.Equal(t, 2, res.Created)
+	assert.Equal(t, 1, res.Rows[0].Line)
+	assert.Equal(t, 3, res.Rows[1].Line)
+	assert.Equal(t, "0A02", service.projects[1].ShortCode().String())
+}
+
+func TestProject_Import_Errors(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, newStubProjectService(t, 0))
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		status      int
+		code        string
+	}{
+		{"unsupported media type", "/v1/projects:import", "application/json", `[]`, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"invalid dry run", "/v1/projects:import?dryRun=maybe", "text/csv", importCSV, http.StatusBadRequest, "invalid_parameter"},
+		{"missing column", "/v1/projects:import", "text/csv", "shortCode,shortName,longName\n0A01,a,b\n", http.StatusBadRequest, "malformed_body"},
+		{"malformed line", "/v1/projects:import", "application/x-ndjson", "{\"shortCode\":\n", http.StatusBadRequest, "malformed_body"},
+		{"no rows", "/v1/projects:import", "text/csv", "shortCode,shortName,longName,description\n", http.StatusBadRequest, "missing_import_rows"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newImportRequest(tt.target, tt.contentType, tt.body))
+		assert.Equal(t, tt.status, w.Code, tt.name)
+		assert.Contains(t, w.Body.String(), `"code":"`+tt.code+`"`, tt.name)
+	}
+
+	// only users allowed to create projects can import them
+	req := httptest.NewRequest("POST", "/v1/projects:import", strings.NewReader(importCSV))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req.WithContext(principal.NewContext(req.Context(), &principal.Principal{ID: "3018c9db-7a65-44e7-b31a-0d547a10b75c"})))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,241 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+)
+
+// RebuildFunc rebuilds derived state, such as a projection or the snapshots, from the events in the event store.
+// It returns the number of items processed.
+type RebuildFunc func(ctx context.Context) (int, error)
+
+// RebuildResult is the response of a successful rebuild.
+type RebuildResult struct {
+	Name      string `json:"name"`
+	Processed int    `json:"processed"`
+	Duration  string `json:"duration"`
+}
+
+// HealthCheck returns an error if a dependency of the service is unavailable.
+type HealthCheck func(ctx context.Context) error
+
+// Health is the response of the health check.
+type Health struct {
+	// Status is either ok or unavailable.
+	Status string `json:"status"`
+	// Checks contains the status of each dependency by name.
+	Checks map[string]string `json:"checks"`
+}
+
+// VerifyFunc verifies the hash chain of every project stream, or of the stream of the project if an id is provided.
+type VerifyFunc func(ctx context.Context, id *valueobject.Identifier) (integrity.Report, error)
+
+// rebuildTimeout is the time a rebuild may take before it is cancelled.
+const rebuildTimeout = 5 * time.Minute
+
+// rebuildProjection rebuilds the projection named in the url by replaying all events.
+func rebuildProjection(projections map[string]RebuildFunc) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeMaintenance(w, r) {
+			return
+		}
+
+		name := mux.Vars(r)["name"]
+		rebuild, ok := projections[name]
+		if !ok {
+			problem.Write(w, r, problem.New(http.StatusNotFound, "projection_not_found", ErrProjectionNotFound.Error()))
+			return
+		}
+
+		runRebuild(w, r, name, rebuild)
+	}
+}
+
+// rebuildSnapshots stores a fresh snapshot of every aggregate.
+func rebuildSnapshots(rebuild RebuildFunc) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeMaintenance(w, r) {
+			return
+		}
+
+		runRebuild(w, r, "snapshots", rebuild)
+	}
+}
+
+// runRebuild runs the rebuild and writes its result.
+func runRebuild(w http.ResponseWriter, r *http.Request, name string, rebuild RebuildFunc) {
+	ctx, cancel := context.WithTimeout(r.Context(), rebuildTimeout)
+	defer cancel()
+
+	start := time.Now()
+	n, err := rebuild(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res := RebuildResult{Name: name, Processed: n, Duration: time.Since(start).Round(time.Millisecond).String()}
+	log.Printf("rebuilt %s: %d processed in %s", name, res.Processed, res.Duration)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// listProjections lists the names of the projections which can be rebuilt.
+func listProjections(projections map[string]RebuildFunc) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeMaintenance(w, r) {
+			return
+		}
+
+		names := []string{}
+		for name := range projections {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(names); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// getHealth runs the health checks and reports the status of every dependency.
+// The status code is 503 if any dependency is unavailable, so that it can be used as readiness probe.
+func getHealth(checks map[string]HealthCheck) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		res := Health{Status: "ok", Checks: map[string]string{}}
+		for name, check := range checks {
+			if err := check(ctx); err != nil {
+				log.Printf("health check %s failed: %v", name, err)
+				res.Status = "unavailable"
+				res.Checks[name] = "unavailable"
+				continue
+			}
+			res.Checks[name] = "ok"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if res.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// verifyIntegrity verifies that the events, of one project or of all projects, have not been altered since they were stored.
+// The response is 200 whenever the verification could be completed, the outcome is the status of the report.
+func verifyIntegrity(verify VerifyFunc) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeMaintenance(w, r) {
+			return
+		}
+
+		var id *valueobject.Identifier
+		if s := r.URL.Query().Get("project"); s != "" {
+			uuid, err := valueobject.IdentifierFromBytes([]byte(s))
+			if err != nil {
+				writeError(w, r, projectEntity.ErrInvalidUUID)
+				return
+			}
+			id = &uuid
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), rebuildTimeout)
+		defer cancel()
+
+		report, err := verify(ctx, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if report.Status != "ok" {
+			log.Printf("integrity verification found %d breaks", len(report.Breaks))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// authorizeMaintenance ensures that the authenticated caller is a system admin.
+// If false is returned, the response has already been written.
+func authorizeMaintenance(w http.ResponseWriter, r *http.Request) bool {
+	user, ok := principal.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, principal.ErrNotAuthenticated)
+		return false
+	}
+
+	if !user.IsSystemAdmin {
+		writeError(w, r, principal.ErrNotSystemAdmin)
+		return false
+	}
+
+	return true
+}
+
+// MakeHealthHandler makes the url handler of the health check, which runs the checks by name.
+// The health check is public and must therefore not be registered on an authenticated router.
+func MakeHealthHandler(r *mux.Router, checks map[string]HealthCheck) {
+
+	r.HandleFunc("/v1/health", getHealth(checks)).Methods("GET", "OPTIONS")
+}
+
+// MakeMaintenanceHandlers make url handlers for the maintenance of the derived state of the service.
+// projections maps the name of each projection to the function rebuilding it.
+// The router is expected to authenticate every request, see middleware.Authenticator.
+func MakeMaintenanceHandlers(r *mux.Router, projections map[string]RebuildFunc, snapshots RebuildFunc) {
+
+	r.HandleFunc("/v1/projections", listProjections(projections)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/v1/projections/{name}:rebuild", rebuildProjection(projections)).Methods("POST", "OPTIONS")
+
+	r.HandleFunc("/v1/snapshots:rebuild", rebuildSnapshots(snapshots)).Methods("POST", "OPTIONS")
+}
+
+// MakeIntegrityHandler makes the url handler verifying the hash chains of the project events.
+// The router is expected to authenticate every request, see middleware.Authenticator.
+func MakeIntegrityHandler(r *mux.Router, verify VerifyFunc) {
+
+	r.HandleFunc("/v1/integrity", verifyIntegrity(verify)).Methods("GET", "OPTIONS")
+}
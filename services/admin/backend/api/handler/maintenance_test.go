@@ -0,0 +1,180 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenance_Rebuild(t *testing.T) {
+	rebuilt := 0
+	projections := map[string]handler.RebuildFunc{
+		"search": func(ctx context.Context) (int, error) {
+			rebuilt++
+			return 42, nil
+		},
+	}
+	snapshots := func(ctx context.Context) (int, error) { return 3, nil }
+
+	r := mux.NewRouter()
+	handler.MakeMaintenanceHandlers(r, projections, snapshots)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("POST", "/v1/projections/search:rebuild", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res handler.RebuildResult
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, "search", res.Name)
+	assert.Equal(t, 42, res.Processed)
+	assert.Equal(t, 1, rebuilt)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("POST", "/v1/snapshots:rebuild", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, "snapshots", res.Name)
+	assert.Equal(t, 3, res.Processed)
+
+	var names []string
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projections", systemAdmin()))
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&names))
+	assert.Equal(t, []string{"search"}, names)
+}
+
+func TestMaintenance_Errors(t *testing.T) {
+	projections := map[string]handler.RebuildFunc{
+		"search": func(ctx context.Context) (int, error) { return 0, errors.New("event store unavailable") },
+	}
+
+	r := mux.NewRouter()
+	handler.MakeMaintenanceHandlers(r, projections, nil)
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		code   string
+	}{
+		{"not authenticated", httptest.NewRequest("POST", "/v1/snapshots:rebuild", nil), http.StatusUnauthorized, "not_authenticated"},
+		{"project admin", newAuthenticatedRequest("POST", "/v1/projections/search:rebuild", &principal.Principal{IsProjectAdmin: true}), http.StatusForbidden, "permission_denied"},
+		{"unknown projection", newAuthenticatedRequest("POST", "/v1/projections/sitemap:rebuild", systemAdmin()), http.StatusNotFound, "projection_not_found"},
+		{"failed rebuild", newAuthenticatedRequest("POST", "/v1/projections/search:rebuild", systemAdmin()), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, tt.req)
+		assert.Equal(t, tt.status, w.Code, tt.name)
+
+		var res problem.Problem
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&res), tt.name)
+		assert.Equal(t, tt.code, res.Code, tt.name)
+	}
+}
+
+func TestIntegrity(t *testing.T) {
+	var verified *valueobject.Identifier
+	r := mux.NewRouter()
+	handler.MakeIntegrityHandler(r, func(ctx context.Context, id *valueobject.Identifier) (integrity.Report, error) {
+		verified = id
+		if id != nil && id.String() == "00000000-0000-0000-0000-000000000000" {
+			return integrity.Report{}, projectEntity.ErrProjectNotFound
+		}
+		return integrity.Report{Status: "broken", Streams: 1, Events: 2, Breaks: []integrity.Break{
+			{Stream: "Project-1", Revision: 1, EventID: "2", Reason: "the hash does not match the event"},
+		}}, nil
+	})
+
+	// a broken chain is the outcome of a successful verification
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/integrity", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, verified)
+
+	var res integrity.Report
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, "broken", res.Status)
+	assert.Len(t, res.Breaks, 1)
+
+	id, _ := valueobject.NewIdentifier()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/integrity?project="+id.String(), systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, id, *verified)
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		code   string
+	}{
+		{"project admin", newAuthenticatedRequest("GET", "/v1/integrity", &principal.Principal{IsProjectAdmin: true}), http.StatusForbidden, "permission_denied"},
+		{"invalid id", newAuthenticatedRequest("GET", "/v1/integrity?project=1", systemAdmin()), http.StatusBadRequest, "invalid_uuid"},
+		{"unknown project", newAuthenticatedRequest("GET", "/v1/integrity?project=00000000-0000-0000-0000-000000000000", systemAdmin()), http.StatusNotFound, "project_not_found"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, tt.req)
+		assert.Equal(t, tt.status, w.Code, tt.name)
+
+		var res problem.Problem
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&res), tt.name)
+		assert.Equal(t, tt.code, res.Code, tt.name)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	var unavailable error
+	r := mux.NewRouter()
+	handler.MakeHealthHandler(r, map[string]handler.HealthCheck{
+		"eventStore": func(ctx context.Context) error { return unavailable },
+	})
+
+	// no credentials are needed
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res handler.Health
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, handler.Health{Status: "ok", Checks: map[string]string{"eventStore": "ok"}}, res)
+
+	unavailable = errors.New("connection refused")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, "unavailable", res.Status)
+	assert.Equal(t, "unavailable", res.Checks["eventStore"])
+}
@@ -0,0 +1,84 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// media types of the project representations.
+const (
+	mediaTypeJSON     = "application/json"
+	mediaTypeJSONLD   = "application/ld+json"
+	mediaTypeTurtle   = "text/turtle"
+	mediaTypeNTriples = "application/n-triples"
+)
+
+// negotiate returns the offered media type preferred by the Accept header of the request.
+// Without an Accept header the first offer is returned. Among equally preferred media types the earlier offer wins.
+// An empty string is returned if none of the offers is acceptable.
+func negotiate(r *http.Request, offers ...string) string {
+	header := r.Header.Get("Accept")
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := quality(header, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// quality returns the q-value of the most specific media range of the Accept header matching the media type.
+func quality(header string, mediaType string) float64 {
+	q, specificity := 0.0, -1
+	for _, mediaRange := range strings.Split(header, ",") {
+		params := strings.Split(mediaRange, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+
+		s := -1
+		switch {
+		case name == mediaType:
+			s = 2
+		case strings.HasSuffix(name, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(name, "*")):
+			s = 1
+		case name == "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+
+		v := 1.0
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+					v = f
+				}
+			}
+		}
+		q, specificity = v, s
+	}
+	return q
+}
@@ -0,0 +1,31 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/oai"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/gorilla/mux"
+)
+
+// MakeOAIHandler makes the url handler of the OAI-PMH provider, which harvesters use to collect the metadata of the projects.
+// Harvesting does not require credentials.
+func MakeOAIHandler(r *mux.Router, service project.UseCase, repository oai.Repository) {
+
+	r.Handle("/v1/oai", oai.NewProvider(service, repository)).Methods("GET", "POST", "OPTIONS")
+}
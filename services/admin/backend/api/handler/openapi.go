@@ -0,0 +1,709 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/oai"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/openapi"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/gorilla/mux"
+)
+
+// OpenAPISpec returns the OpenAPI 3 document describing every REST route of the admin api.
+// Routes registered by the Make*Handlers functions must be added here as well, which is verified by the tests.
+func OpenAPISpec() *openapi.Document {
+	d := openapi.NewDocument(openapi.Info{
+		Title:       "DSP Admin API",
+		Description: "Manages the projects, service accounts and webhooks of the DaSCH Service Platform.",
+		Version:     "v1",
+	})
+
+	d.Components.SecuritySchemes["bearerAuth"] = openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "Access token issued by the identity provider.",
+	}
+	d.Components.SecuritySchemes["apiKey"] = openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        "X-API-Key",
+		Description: "API key of a service account.",
+	}
+	d.Security = []openapi.SecurityRequirement{{"bearerAuth": {}}, {"apiKey": {}}}
+
+	public := &[]openapi.SecurityRequirement{}
+
+	project := d.Ref(presenter.Project{})
+	serviceAccount := d.Ref(presenter.ServiceAccount{})
+	webhook := d.Ref(presenter.Webhook{})
+	deliveries := &openapi.Schema{Type: "array", Items: d.Ref(presenter.WebhookDelivery{})}
+	problemSchema := d.Ref(problem.Problem{})
+
+	// projects, the v2 api only differs in their representation
+	addProjectOperations(d, "/v1", "", project, problemSchema)
+	addProjectOperations(d, "/v2", "V2", d.Ref(presenter.ProjectV2{}), problemSchema)
+	d.Components.Schemas["Actor"].Properties["type"].Enum = []string{string(event.ActorUser), string(event.ActorServiceAccount)}
+
+	d.AddOperation("/v1/projects/{id}/export", http.MethodGet, &openapi.Operation{
+		OperationID: "exportProject",
+		Summary:     "Export the metadata of a project",
+		Description: "Exports the project as schema.org ResearchProject JSON-LD or as DataCite Metadata Schema 4.4 XML.",
+		Tags:        []string{"projects"},
+		Parameters: []openapi.Parameter{
+			pathParameter("id", "Id of the project."),
+			requiredQueryParameter("format", "Format of the export.", &openapi.Schema{Type: "string", Enum: []string{exportFormatSchemaOrg, exportFormatDataCite}}),
+		},
+		Responses: responses(
+			map[string]*openapi.Response{
+				strconv.Itoa(http.StatusOK): {
+					Description: "The metadata of the project.",
+					Content: map[string]openapi.MediaType{
+						export.SchemaOrgContentType: {Schema: &openapi.Schema{Type: "object"}},
+						export.DataCiteContentType:  {Schema: &openapi.Schema{Type: "string"}},
+					},
+				},
+			},
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusInternalServerError),
+		),
+	})
+
+	// project events
+	d.AddOperation("/v1/events/projects", http.MethodGet, &openapi.Operation{
+		OperationID: "streamProjectEvents",
+		Summary:     "Stream the changes of the projects",
+		Description: "Streams server-sent events named created, changed or deleted, whose data is the JSON encoded project event including the state of the project. " +
+			"Only events of projects the caller may read are sent.",
+		Tags: []string{"projects"},
+		Parameters: []openapi.Parameter{
+			{Name: "Last-Event-ID", In: "header", Description: "Id of the last event received, to resume the stream after it.", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: responses(
+			map[string]*openapi.Response{
+				strconv.Itoa(http.StatusOK): {
+					Description: "The stream of project events.",
+					Content:     map[string]openapi.MediaType{"text/event-stream": {Schema: &openapi.Schema{Type: "string"}}},
+				},
+			},
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+
+	// public catalogue
+	d.AddOperation("/v1/public/projects", http.MethodGet, &openapi.Operation{
+		OperationID: "listPublicProjects",
+		Summary:     "List the public project catalogue",
+		Tags:        []string{"catalogue"},
+		Parameters: []openapi.Parameter{
+			queryParameter("limit", "Maximum number of projects returned.", integer(1, maxCatalogueLimit)),
+			queryParameter("offset", "Number of projects to skip.", integer(0, 0)),
+		},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "A page of the catalogue.", d.Ref(presenter.PublicProjectList{})),
+			notModifiedResponse(),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusInternalServerError),
+		),
+		Security: public,
+	})
+	d.AddOperation("/v1/public/projects/{id}", http.MethodGet, &openapi.Operation{
+		OperationID: "getPublicProject",
+		Summary:     "Get a project of the public catalogue",
+		Tags:        []string{"catalogue"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the project.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The project.", d.Ref(presenter.PublicProject{})),
+			notModifiedResponse(),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		),
+		Security: public,
+	})
+
+	// graphql
+	graphQLRequest := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"query":         {Type: "string"},
+			"operationName": {Type: "string"},
+			"variables":     {Type: "object"},
+		},
+		Required: []string{"query"},
+	}
+	graphQLResponse := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"data":   {Type: "object"},
+			"errors": {Type: "array", Items: &openapi.Schema{Type: "object"}},
+		},
+	}
+	d.AddOperation("/v1/graphql", http.MethodGet, &openapi.Operation{
+		OperationID: "queryGraphQL",
+		Summary:     "Run a GraphQL query",
+		Tags:        []string{"graphql"},
+		Parameters: []openapi.Parameter{
+			requiredQueryParameter("query", "GraphQL query.", &openapi.Schema{Type: "string"}),
+			queryParameter("operationName", "Name of the operation to run.", &openapi.Schema{Type: "string"}),
+			queryParameter("variables", "JSON encoded variables.", &openapi.Schema{Type: "string"}),
+		},
+		Responses: responses(jsonResponse(http.StatusOK, "The GraphQL response.", graphQLResponse)),
+	})
+	d.AddOperation("/v1/graphql", http.MethodPost, &openapi.Operation{
+		OperationID: "postGraphQL",
+		Summary:     "Run a GraphQL query or mutation",
+		Tags:        []string{"graphql"},
+		RequestBody: jsonBody(graphQLRequest),
+		Responses:   responses(jsonResponse(http.StatusOK, "The GraphQL response.", graphQLResponse)),
+	})
+
+	// oai-pmh
+	oaiResponse := map[string]*openapi.Response{
+		strconv.Itoa(http.StatusOK): {
+			Description: "The OAI-PMH response. Protocol errors are reported within the response.",
+			Content:     map[string]openapi.MediaType{"text/xml": {Schema: &openapi.Schema{Type: "string"}}},
+		},
+	}
+	oaiVerbs := []string{oai.VerbIdentify, oai.VerbListMetadataFormats, oai.VerbListSets, oai.VerbListIdentifiers, oai.VerbListRecords, oai.VerbGetRecord}
+	d.AddOperation("/v1/oai", http.MethodGet, &openapi.Operation{
+		OperationID: "harvestOAI",
+		Summary:     "Harvest the project metadata with OAI-PMH 2.0",
+		Tags:        []string{"oai-pmh"},
+		Parameters: []openapi.Parameter{
+			requiredQueryParameter("verb", "OAI-PMH verb.", &openapi.Schema{Type: "string", Enum: oaiVerbs}),
+			queryParameter("identifier", "OAI identifier of a project.", &openapi.Schema{Type: "string"}),
+			queryParameter("metadataPrefix", "Metadata format of the records.", &openapi.Schema{Type: "string", Enum: []string{oai.PrefixDublinCore, oai.PrefixDataCite}}),
+			queryParameter("from", "Lower bound of the datestamps.", &openapi.Schema{Type: "string"}),
+			queryParameter("until", "Upper bound of the datestamps.", &openapi.Schema{Type: "string"}),
+			queryParameter("set", "Set of the records, sets are not supported.", &openapi.Schema{Type: "string"}),
+			queryParameter("resumptionToken", "Token of the next page of an incomplete list.", &openapi.Schema{Type: "string"}),
+		},
+		Responses: responses(oaiResponse, errorResponses(problemSchema, http.StatusInternalServerError)),
+		Security:  public,
+	})
+	d.AddOperation("/v1/oai", http.MethodPost, &openapi.Operation{
+		OperationID: "postOAI",
+		Summary:     "Harvest the project metadata with OAI-PMH 2.0, with the arguments in the body",
+		Tags:        []string{"oai-pmh"},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{"application/x-www-form-urlencoded": {Schema: &openapi.Schema{
+				Type: "object",
+				Properties: map[string]*openapi.Schema{
+					"verb":            {Type: "string", Enum: oaiVerbs},
+					"identifier":      {Type: "string"},
+					"metadataPrefix":  {Type: "string"},
+					"from":            {Type: "string"},
+					"until":           {Type: "string"},
+					"set":             {Type: "string"},
+					"resumptionToken": {Type: "string"},
+				},
+				Required: []string{"verb"},
+			}}},
+		},
+		Responses: responses(oaiResponse, errorResponses(problemSchema, http.StatusInternalServerError)),
+		Security:  public,
+	})
+
+	// service accounts
+	d.AddOperation("/v1/service-accounts", http.MethodPost, &openapi.Operation{
+		OperationID: "createServiceAccount",
+		Summary:     "Create a service account",
+		Tags:        []string{"service accounts"},
+		RequestBody: jsonBody(d.Ref(ServiceAccountRequestBody{})),
+		Responses: responses(
+			jsonResponse(http.StatusCreated, "The created service account.", serviceAccount),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/service-accounts", http.MethodGet, &openapi.Operation{
+		OperationID: "listServiceAccounts",
+		Summary:     "List service accounts",
+		Tags:        []string{"service accounts"},
+		Parameters: []openapi.Parameter{
+			queryParameter("includeDeleted", "Include deleted service accounts.", &openapi.Schema{Type: "boolean"}),
+		},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The service accounts.", &openapi.Schema{Type: "array", Items: serviceAccount}),
+			errorResponses(problemSchema, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/service-accounts/{id}", http.MethodGet, &openapi.Operation{
+		OperationID: "getServiceAccount",
+		Summary:     "Get a service account",
+		Tags:        []string{"service accounts"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the service account.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The service account.", serviceAccount),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/service-accounts/{id}", http.MethodDelete, &openapi.Operation{
+		OperationID: "deleteServiceAccount",
+		Summary:     "Delete a service account and revoke all its api keys",
+		Tags:        []string{"service accounts"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the service account.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The deleted service account.", serviceAccount),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/service-accounts/{id}/keys", http.MethodPost, &openapi.Operation{
+		OperationID: "issueAPIKey",
+		Summary:     "Issue an api key",
+		Description: "The key itself is only returned in this response and cannot be retrieved later.",
+		Tags:        []string{"service accounts"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the service account.")},
+		RequestBody: jsonBody(d.Ref(APIKeyRequestBody{})),
+		Responses: responses(
+			jsonResponse(http.StatusCreated, "The issued api key.", d.Ref(presenter.APIKey{})),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusUnprocessableEntity, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/service-accounts/{id}/keys/{keyId}", http.MethodDelete, &openapi.Operation{
+		OperationID: "revokeAPIKey",
+		Summary:     "Revoke an api key",
+		Tags:        []string{"service accounts"},
+		Parameters: []openapi.Parameter{
+			pathParameter("id", "Id of the service account."),
+			pathParameter("keyId", "Id of the api key."),
+		},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The service account.", serviceAccount),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusGone, http.StatusInternalServerError),
+		),
+	})
+
+	// webhooks
+	d.AddOperation("/v1/webhooks", http.MethodPost, &openapi.Operation{
+		OperationID: "createWebhook",
+		Summary:     "Create a webhook",
+		Description: "The secret used to sign the payloads is only returned in this response and cannot be retrieved later.",
+		Tags:        []string{"webhooks"},
+		RequestBody: jsonBody(d.Ref(WebhookRequestBody{})),
+		Responses: responses(
+			jsonResponse(http.StatusCreated, "The created webhook.", webhook),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/webhooks", http.MethodGet, &openapi.Operation{
+		OperationID: "listWebhooks",
+		Summary:     "List webhooks",
+		Tags:        []string{"webhooks"},
+		Parameters: []openapi.Parameter{
+			queryParameter("includeDeleted", "Include deleted webhooks.", &openapi.Schema{Type: "boolean"}),
+		},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The webhooks.", &openapi.Schema{Type: "array", Items: webhook}),
+			errorResponses(problemSchema, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/webhooks/dead-letters", http.MethodGet, &openapi.Operation{
+		OperationID: "listWebhookDeadLetters",
+		Summary:     "List the dead-lettered deliveries to all webhooks",
+		Tags:        []string{"webhooks"},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The dead-lettered deliveries, oldest first.", deliveries),
+			errorResponses(problemSchema, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/webhooks/{id}", http.MethodGet, &openapi.Operation{
+		OperationID: "getWebhook",
+		Summary:     "Get a webhook",
+		Tags:        []string{"webhooks"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the webhook.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The webhook.", webhook),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/webhooks/{id}", http.MethodDelete, &openapi.Operation{
+		OperationID: "deleteWebhook",
+		Summary:     "Delete a webhook and stop all deliveries to it",
+		Tags:        []string{"webhooks"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the webhook.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The deleted webhook.", webhook),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/webhooks/{id}/deliveries", http.MethodGet, &openapi.Operation{
+		OperationID: "listWebhookDeliveries",
+		Summary:     "List the deliveries to a webhook",
+		Tags:        []string{"webhooks"},
+		Parameters: []openapi.Parameter{
+			pathParameter("id", "Id of the webhook."),
+			queryParameter("status", "Only return deliveries with this status.", &openapi.Schema{Type: "string", Enum: []string{"pending", "delivered", "dead_lettered"}}),
+		},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The deliveries, oldest first.", deliveries),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver", http.MethodPost, &openapi.Operation{
+		OperationID: "redeliverWebhookDelivery",
+		Summary:     "Attempt a delivery again",
+		Description: "Delivered and dead-lettered deliveries are attempted again the next time the outbox is checked, starting with a fresh attempt count.",
+		Tags:        []string{"webhooks"},
+		Parameters: []openapi.Parameter{
+			pathParameter("id", "Id of the webhook."),
+			pathParameter("deliveryId", "Id of the delivery."),
+		},
+		Responses: responses(
+			jsonResponse(http.StatusAccepted, "The delivery, which is pending again.", d.Ref(presenter.WebhookDelivery{})),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		),
+	})
+
+	// maintenance
+	rebuildResult := d.Ref(RebuildResult{})
+	d.AddOperation("/v1/projections", http.MethodGet, &openapi.Operation{
+		OperationID: "listProjections",
+		Summary:     "List the projections which can be rebuilt",
+		Description: "Requires a system admin.",
+		Tags:        []string{"maintenance"},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The names of the projections.", &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}),
+			errorResponses(problemSchema, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/projections/{name}:rebuild", http.MethodPost, &openapi.Operation{
+		OperationID: "rebuildProjection",
+		Summary:     "Rebuild a projection",
+		Description: "Replaces the projection with one built by replaying all events. Requires a system admin.",
+		Tags:        []string{"maintenance"},
+		Parameters:  []openapi.Parameter{pathParameter("name", "Name of the projection.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The number of events replayed.", rebuildResult),
+			errorResponses(problemSchema, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation("/v1/snapshots:rebuild", http.MethodPost, &openapi.Operation{
+		OperationID: "rebuildSnapshots",
+		Summary:     "Rebuild the snapshots of all projects",
+		Description: "Stores a fresh snapshot of every project, so that loading a project replays only the events recorded later on. Requires a system admin.",
+		Tags:        []string{"maintenance"},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The number of snapshots stored.", rebuildResult),
+			errorResponses(problemSchema, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+
+	d.AddOperation("/v1/integrity", http.MethodGet, &openapi.Operation{
+		OperationID: "verifyIntegrity",
+		Summary:     "Verify that the project events have not been altered",
+		Description: "Walks the hash chain of every project stream, or of one project, and verifies the checkpoints over all project events. " +
+			"Every break is reported, the status of the report is broken if any was found. Requires a system admin.",
+		Tags:       []string{"maintenance"},
+		Parameters: []openapi.Parameter{queryParameter("project", "Only verify the events of the project with this id.", &openapi.Schema{Type: "string", Format: "uuid"})},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The outcome of the verification.", d.Ref(integrity.Report{})),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+		),
+	})
+
+	// privacy
+	d.AddOperation("/v1/users/{id}:forget", http.MethodPost, &openapi.Operation{
+		OperationID: "forgetUser",
+		Summary:     "Erase the personal data of a user",
+		Description: "Destroys the keys encrypting the user in the project events, e.g. to fulfil a request for erasure. " +
+			"The events are kept and the user is read as a placeholder from then on. Erasing is idempotent. Requires a system admin.",
+		Tags:       []string{"privacy"},
+		Parameters: []openapi.Parameter{pathParameter("id", "Id of the user or service account.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "Whether personal data of the user had been recorded.", d.Ref(ForgetResult{})),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+
+	d.AddOperation("/v1/health", http.MethodGet, &openapi.Operation{
+		OperationID: "getHealth",
+		Summary:     "Check the health of the service",
+		Description: "Reports whether the service and its dependencies, such as the event store, are available.",
+		Tags:        []string{"maintenance"},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The service is available.", d.Ref(Health{})),
+			jsonResponse(http.StatusServiceUnavailable, "A dependency of the service is unavailable.", d.Ref(Health{})),
+		),
+		Security: public,
+	})
+
+	// the document itself
+	d.AddOperation("/v1/openapi.json", http.MethodGet, &openapi.Operation{
+		OperationID: "getOpenAPISpec",
+		Summary:     "Get this OpenAPI document",
+		Tags:        []string{"meta"},
+		Responses:   responses(jsonResponse(http.StatusOK, "The OpenAPI document.", &openapi.Schema{Type: "object"})),
+		Security:    public,
+	})
+
+	// mutating requests of authenticated clients can be retried safely, see middleware.Idempotency
+	for _, item := range d.Paths {
+		for method, op := range item {
+			if op.Security == nil && method != "get" {
+				idempotent(op, problemSchema)
+			}
+		}
+	}
+
+	return d
+}
+
+// addProjectOperations adds the operations of the projects of a version of the api, whose paths start with the prefix
+// and whose operation ids end with the suffix, see makeProjectHandlers. The project schema is the representation of the version.
+func addProjectOperations(d *openapi.Document, prefix string, suffix string, project *openapi.Schema, problemSchema *openapi.Schema) {
+	projects := &openapi.Schema{Type: "array", Items: project}
+
+	d.AddOperation(prefix+"/projects", http.MethodPost, &openapi.Operation{
+		OperationID: "createProject" + suffix,
+		Summary:     "Create a project",
+		Tags:        []string{"projects"},
+		RequestBody: jsonBody(d.Ref(RequestBody{})),
+		Responses: responses(
+			jsonResponse(http.StatusCreated, "The created project.", project),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation(prefix+"/projects:import", http.MethodPost, &openapi.Operation{
+		OperationID: "importProjects" + suffix,
+		Summary:     "Import projects from a CSV or JSON lines file",
+		Description: "Every row is validated like the body of createProject and must use a short code which is not taken yet. " +
+			"Valid rows are created even if other rows are invalid. In dry-run mode, only the outcome of every row is reported.",
+		Tags: []string{"projects"},
+		Parameters: []openapi.Parameter{
+			queryParameter("dryRun", "Validate the rows without creating any project.", &openapi.Schema{Type: "boolean"}),
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			mediaTypeCSV:       {Schema: &openapi.Schema{Type: "string", Description: "A header row naming the columns shortCode, shortName, longName and description, followed by a project per row."}},
+			mediaTypeNDJSON:    {Schema: d.Ref(RequestBody{})},
+			mediaTypeJSONLines: {Schema: d.Ref(RequestBody{})},
+		}},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The outcome of every row.", d.Ref(presenter.ImportReport{})),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation(prefix+"/projects", http.MethodGet, &openapi.Operation{
+		OperationID: "listProjects" + suffix,
+		Summary:     "List projects",
+		Description: "Returns a page of projects. The cursor of the next page is provided in the X-Next-Cursor and Link headers.",
+		Tags:        []string{"projects"},
+		Parameters: []openapi.Parameter{
+			queryParameter("limit", "Maximum number of projects returned.", integer(1, maxCatalogueLimit)),
+			queryParameter("after", "Cursor returned with the previous page.", &openapi.Schema{Type: "string"}),
+			queryParameter("sort", "Field to sort by, prefixed with - to sort in descending order.", &openapi.Schema{
+				Type: "string",
+				Enum: []string{"shortCode", "-shortCode", "shortName", "-shortName", "createdAt", "-createdAt", "changedAt", "-changedAt"},
+			}),
+			queryParameter("status", "Only return projects with this status.", &openapi.Schema{Type: "string", Enum: []string{"active", "deleted", "all"}}),
+			queryParameter("shortName", "Only return projects with this short name.", &openapi.Schema{Type: "string"}),
+			queryParameter("longName", "Only return projects whose long name contains this value.", &openapi.Schema{Type: "string"}),
+			queryParameter("name", "Only return projects whose short or long name contains this value.", &openapi.Schema{Type: "string"}),
+			queryParameter("includeDeleted", "Include deleted projects, same as status=all.", &openapi.Schema{Type: "boolean"}),
+		},
+		Responses: responses(
+			map[string]*openapi.Response{"200": {
+				Description: "A page of projects.",
+				Headers: map[string]openapi.Header{
+					"X-Total-Count": {Description: "Number of projects matching the query.", Schema: &openapi.Schema{Type: "integer"}},
+					"X-Next-Cursor": {Description: "Cursor of the next page, if any.", Schema: &openapi.Schema{Type: "string"}},
+					"Link":          {Description: "Link to the next page, if any.", Schema: &openapi.Schema{Type: "string"}},
+				},
+				Content: jsonContent(projects),
+			}},
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation(prefix+"/projects/search", http.MethodGet, &openapi.Operation{
+		OperationID: "searchProjects" + suffix,
+		Summary:     "Search projects",
+		Description: "Returns the projects matching the full-text query, best matches first. The short code, short name, long name, description and keywords of the projects are searched, allowing for prefixes and typos.",
+		Tags:        []string{"projects"},
+		Parameters: []openapi.Parameter{
+			requiredQueryParameter("q", "Full-text query.", &openapi.Schema{Type: "string"}),
+			queryParameter("limit", "Maximum number of projects returned.", integer(1, maxCatalogueLimit)),
+		},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The matching projects.", projects),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation(prefix+"/projects/{id}", http.MethodGet, &openapi.Operation{
+		OperationID: "getProject" + suffix,
+		Summary:     "Get a project",
+		Tags:        []string{"projects"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the project.")},
+		Responses: responses(
+			negotiatedResponse(http.StatusOK, "The project, as JSON or as RDF using the admin ontology depending on the Accept header.", project),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusNotAcceptable, http.StatusGone, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation(prefix+"/projects/{id}", http.MethodPut, &openapi.Operation{
+		OperationID: "updateProject" + suffix,
+		Summary:     "Update a project",
+		Tags:        []string{"projects"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the project.")},
+		RequestBody: jsonBody(d.Ref(RequestBody{})),
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The updated project.", project),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusUnprocessableEntity, http.StatusInternalServerError),
+		),
+	})
+	d.AddOperation(prefix+"/projects/{id}", http.MethodDelete, &openapi.Operation{
+		OperationID: "deleteProject" + suffix,
+		Summary:     "Delete a project",
+		Tags:        []string{"projects"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the project.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The deleted project.", project),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusInternalServerError),
+		),
+	})
+
+	d.AddOperation(prefix+"/projects/{id}:restore", http.MethodPost, &openapi.Operation{
+		OperationID: "restoreProject" + suffix,
+		Summary:     "Restore a deleted project",
+		Description: "Requires the same permission as deleting the project. The project keeps its short code while it is deleted, so it can always be restored.",
+		Tags:        []string{"projects"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the project.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The restored project.", project),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		),
+	})
+
+	d.AddOperation(prefix+"/projects/{id}/events", http.MethodGet, &openapi.Operation{
+		OperationID: "getProjectEvents" + suffix,
+		Summary:     "Get the events of a project",
+		Description: "Returns every event recorded for the project, oldest first, including the events of a deleted project.",
+		Tags:        []string{"projects"},
+		Parameters:  []openapi.Parameter{pathParameter("id", "Id of the project.")},
+		Responses: responses(
+			jsonResponse(http.StatusOK, "The events of the project.", &openapi.Schema{Type: "array", Items: d.Ref(presenter.ProjectEvent{})}),
+			errorResponses(problemSchema, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError),
+		),
+	})
+}
+
+// MakeOpenAPIHandler makes the url handler serving the OpenAPI document of the api.
+// The document is public and must therefore not be registered on an authenticated router.
+func MakeOpenAPIHandler(r *mux.Router) {
+	spec := OpenAPISpec()
+
+	r.HandleFunc("/v1/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeCacheable(w, r, spec)
+	}).Methods("GET", "OPTIONS")
+}
+
+// idempotent adds the Idempotency-Key header and the problems of reused keys to a mutating operation.
+func idempotent(op *openapi.Operation, problemSchema *openapi.Schema) {
+	op.Parameters = append(op.Parameters, openapi.Parameter{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Unique key of the request chosen by the client, at most 255 characters, for request bodies of at most 10 MiB. Retries with the same key replay the first response instead of being processed again.",
+		Schema:      &openapi.Schema{Type: "string"},
+	})
+	op.Responses = responses(op.Responses, errorResponses(problemSchema, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity))
+}
+
+// responses merges the responses of an operation.
+func responses(all ...map[string]*openapi.Response) map[string]*openapi.Response {
+	res := map[string]*openapi.Response{}
+	for _, r := range all {
+		for status, response := range r {
+			res[status] = response
+		}
+	}
+	return res
+}
+
+// jsonResponse describes a response with a JSON body.
+func jsonResponse(status int, description string, schema *openapi.Schema) map[string]*openapi.Response {
+	return map[string]*openapi.Response{
+		strconv.Itoa(status): {Description: description, Content: jsonContent(schema)},
+	}
+}
+
+// negotiatedResponse describes a response with a JSON body, which is also available in the RDF serializations, see writeProject.
+func negotiatedResponse(status int, description string, schema *openapi.Schema) map[string]*openapi.Response {
+	res := jsonResponse(status, description, schema)
+	content := res[strconv.Itoa(status)].Content
+	content[mediaTypeJSONLD] = openapi.MediaType{Schema: &openapi.Schema{Type: "object"}}
+	content[mediaTypeTurtle] = openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+	content[mediaTypeNTriples] = openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+	return res
+}
+
+// notModifiedResponse describes the response to a conditional request of a cacheable resource, see writeCacheable.
+func notModifiedResponse() map[string]*openapi.Response {
+	return map[string]*openapi.Response{
+		strconv.Itoa(http.StatusNotModified): {Description: "The resource did not change since the ETag provided in If-None-Match."},
+	}
+}
+
+// errorResponses describes the error responses with the provided status codes, whose body is a problem, see problem.Write.
+func errorResponses(schema *openapi.Schema, statuses ...int) map[string]*openapi.Response {
+	res := map[string]*openapi.Response{}
+	for _, status := range statuses {
+		res[strconv.Itoa(status)] = &openapi.Response{
+			Description: http.StatusText(status),
+			Content:     map[string]openapi.MediaType{problem.ContentType: {Schema: schema}},
+		}
+	}
+	return res
+}
+
+// jsonBody describes a required JSON request body.
+func jsonBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: jsonContent(schema)}
+}
+
+// jsonContent describes JSON content.
+func jsonContent(schema *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: schema}}
+}
+
+// pathParameter describes an id in the path.
+func pathParameter(name string, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "path", Description: description, Required: true, Schema: &openapi.Schema{Type: "string", Format: "uuid"}}
+}
+
+// queryParameter describes an optional query parameter.
+func queryParameter(name string, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// requiredQueryParameter describes a required query parameter.
+func requiredQueryParameter(name string, description string, schema *openapi.Schema) openapi.Parameter {
+	p := queryParameter(name, description, schema)
+	p.Required = true
+	return p
+}
+
+// integer describes an integer within the bounds, a maximum lower than the minimum means no upper bound.
+func integer(min int, max int) *openapi.Schema {
+	s := &openapi.Schema{Type: "integer", Minimum: &min}
+	if max >= min {
+		s.Maximum = &max
+	}
+	return s
+}
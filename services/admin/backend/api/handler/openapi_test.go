@@ -0,0 +1,165 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/oai"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/openapi"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// newAPIRouter creates a router with all REST routes of the api, registered like main does.
+func newAPIRouter(t *testing.T) *mux.Router {
+	service := newStubProjectService(t, 1)
+
+	r := mux.NewRouter()
+	handler.MakeRoutes(r, handler.Routes{
+		Projects: service,
+		DataCite: export.DataCiteOptions{DOIPrefix: "10.5072", Publisher: "DaSCH"},
+		OAI:      oai.Repository{BaseURL: "http://localhost:8080/v1/oai", Namespace: "admin.dasch.swiss"},
+	})
+	return r
+}
+
+func TestOpenAPISpec_DescribesAllRoutes(t *testing.T) {
+	spec := handler.OpenAPISpec()
+	routes := map[string]bool{}
+
+	err := newAPIRouter(t).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		// the subrouter of the authenticated routes is no route of its own
+		if route.GetHandler() == nil {
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, m := range methods {
+			if m == http.MethodOptions {
+				continue
+			}
+			routes[m+" "+path] = true
+			assert.NotNil(t, spec.Operation(path, m), "route %s %s is missing from the OpenAPI spec", m, path)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+
+	// and the spec must not describe routes that do not exist
+	for path, item := range spec.Paths {
+		for m := range item {
+			assert.True(t, routes[strings.ToUpper(m)+" "+path], "the OpenAPI spec describes the unknown route %s %s", m, path)
+		}
+	}
+}
+
+func TestOpenAPISpec_Schemas(t *testing.T) {
+	spec := handler.OpenAPISpec()
+
+	// the v1 representation of a project is unchanged
+	project := spec.Components.Schemas["Project"]
+	assert.NotNil(t, project)
+	assert.Equal(t, "uuid", project.Properties["id"].Format)
+	assert.Contains(t, project.Required, "shortCode")
+	assert.Equal(t, "string", project.Properties["deletedBy"].Type)
+	assert.False(t, project.Properties["deletedAt"].Nullable)
+
+	project = spec.Components.Schemas["ProjectV2"]
+	assert.NotNil(t, project)
+	assert.Equal(t, "uuid", project.Properties["id"].Format)
+	assert.Contains(t, project.Required, "shortCode")
+	assert.Equal(t, "date-time", project.Properties["deletedAt"].Format)
+	assert.True(t, project.Properties["deletedAt"].Nullable)
+	assert.True(t, project.Properties["deletedBy"].Nullable)
+	assert.Equal(t, "#/components/schemas/Actor", project.Properties["deletedBy"].AllOf[0].Ref)
+	assert.Equal(t, []string{"user", "serviceAccount"}, spec.Components.Schemas["Actor"].Properties["type"].Enum)
+	assert.False(t, project.Properties["shortName"].Nullable)
+
+	body := spec.Components.Schemas["RequestBody"]
+	assert.NotNil(t, body)
+	assert.ElementsMatch(t, []string{"shortCode", "shortName", "longName", "description"}, body.Required)
+
+	// the key is only returned when issued
+	assert.NotContains(t, spec.Components.Schemas["APIKey"].Required, "key")
+
+	create := spec.Operation("/v1/projects", http.MethodPost)
+	assert.Equal(t, "#/components/schemas/RequestBody", create.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Project", create.Responses["201"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Problem", create.Responses["409"].Content["application/problem+json"].Schema.Ref)
+
+	createV2 := spec.Operation("/v2/projects", http.MethodPost)
+	assert.Equal(t, "createProjectV2", createV2.OperationID)
+	assert.Equal(t, "#/components/schemas/ProjectV2", createV2.Responses["201"].Content["application/json"].Schema.Ref)
+
+	// migrated projects are found by their keywords as well
+	assert.Contains(t, spec.Operation("/v1/projects/search", http.MethodGet).Description, "keywords")
+
+	// the public catalogue does not require authentication
+	assert.Empty(t, *spec.Operation("/v1/public/projects", http.MethodGet).Security)
+	assert.Nil(t, create.Security)
+}
+
+func TestOpenAPISpec_IdempotencyKey(t *testing.T) {
+	spec := handler.OpenAPISpec()
+
+	hasKey := func(op *openapi.Operation) bool {
+		for _, p := range op.Parameters {
+			if p.Name == "Idempotency-Key" && p.In == "header" {
+				return true
+			}
+		}
+		return false
+	}
+
+	create := spec.Operation("/v1/projects", http.MethodPost)
+	assert.True(t, hasKey(create))
+	assert.NotNil(t, create.Responses["422"])
+	assert.True(t, hasKey(spec.Operation("/v1/projects/{id}", http.MethodDelete)))
+
+	// safe and public operations are not affected
+	assert.False(t, hasKey(spec.Operation("/v1/projects", http.MethodGet)))
+	assert.False(t, hasKey(spec.Operation("/v1/oai", http.MethodPost)))
+}
+
+func TestOpenAPI_Serve(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeOpenAPIHandler(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var res map[string]interface{}
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, "3.0.3", res["openapi"])
+	assert.Contains(t, res["paths"], "/v1/projects/{id}")
+}
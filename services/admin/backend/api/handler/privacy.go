@@ -0,0 +1,78 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+)
+
+// ForgetFunc erases the personal data of a user or service account from the events.
+// It returns false if no personal data of the actor had been recorded.
+type ForgetFunc func(ctx context.Context, id valueobject.Identifier) (bool, error)
+
+// ForgetResult is the response of a successful erasure.
+type ForgetResult struct {
+	ID valueobject.Identifier `json:"id"`
+	// Forgotten is false if no personal data of the actor had been recorded, which is not an error.
+	Forgotten bool `json:"forgotten"`
+}
+
+// forgetUser erases the personal data of the user in the url, e.g. to fulfil a request for erasure under the GDPR.
+// Erasing is idempotent, and the events themselves are kept: the user is replaced by a placeholder when they are read.
+func forgetUser(forget ForgetFunc) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeMaintenance(w, r) {
+			return
+		}
+
+		id, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, projectEntity.ErrInvalidUUID)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), rebuildTimeout)
+		defer cancel()
+
+		forgotten, err := forget(ctx, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		log.Printf("forgot the personal data of actor %s", id)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(ForgetResult{ID: id, Forgotten: forgotten}); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// MakePrivacyHandlers make url handlers for the erasure of personal data.
+// The router is expected to authenticate every request, see middleware.Authenticator.
+func MakePrivacyHandlers(r *mux.Router, forget ForgetFunc) {
+
+	r.HandleFunc("/v1/users/{id}:forget", forgetUser(forget)).Methods("POST", "OPTIONS")
+}
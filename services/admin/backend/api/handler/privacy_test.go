@@ -0,0 +1,88 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivacy_Forget(t *testing.T) {
+	forgotten := map[valueobject.Identifier]bool{}
+	r := mux.NewRouter()
+	handler.MakePrivacyHandlers(r, func(ctx context.Context, id valueobject.Identifier) (bool, error) {
+		if id.String() == "00000000-0000-0000-0000-000000000000" {
+			return false, errors.New("event store unavailable")
+		}
+		if forgotten[id] {
+			return false, nil
+		}
+		forgotten[id] = true
+		return true, nil
+	})
+
+	id, _ := valueobject.NewIdentifier()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("POST", "/v1/users/"+id.String()+":forget", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res handler.ForgetResult
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, id, res.ID)
+	assert.True(t, res.Forgotten)
+
+	// forgetting again succeeds, there is just nothing left to erase
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("POST", "/v1/users/"+id.String()+":forget", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.False(t, res.Forgotten)
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		code   string
+	}{
+		{"not authenticated", httptest.NewRequest("POST", "/v1/users/"+id.String()+":forget", nil), http.StatusUnauthorized, "not_authenticated"},
+		{"project admin", newAuthenticatedRequest("POST", "/v1/users/"+id.String()+":forget", &principal.Principal{IsProjectAdmin: true}), http.StatusForbidden, "permission_denied"},
+		{"invalid id", newAuthenticatedRequest("POST", "/v1/users/1:forget", systemAdmin()), http.StatusBadRequest, "invalid_uuid"},
+		{"failed erasure", newAuthenticatedRequest("POST", "/v1/users/00000000-0000-0000-0000-000000000000:forget", systemAdmin()), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, tt.req)
+		assert.Equal(t, tt.status, w.Code, tt.name)
+
+		var res problem.Problem
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&res), tt.name)
+		assert.Equal(t, tt.code, res.Code, tt.name)
+	}
+}
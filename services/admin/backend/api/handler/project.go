@@ -22,10 +22,14 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/middleware"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rdf"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
 	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
@@ -40,118 +44,74 @@ type RequestBody struct {
 	Description string `json:"description"`
 }
 
+// values converts the fields of the request body to value objects.
+// A valueobject.ValidationError listing every invalid field is returned if any of them is invalid.
+func (b RequestBody) values() (valueobject.ShortCode, valueobject.ShortName, valueobject.LongName, valueobject.Description, error) {
+	v := valueobject.Validation{}
+	sc := v.ShortCode("shortCode", b.ShortCode)
+	sn := v.ShortName("shortName", b.ShortName)
+	ln := v.LongName("longName", b.LongName)
+	desc := v.Description("description", b.Description)
+	return sc, sn, ln, desc, v.Err()
+}
+
 // createProject creates a project with the provided RequestBody.
-func createProject(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+func createProject(service project.UseCase, present projectPresenter) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		// check JWT token to make sure user is authenticated
-		// an object containing the users info is returned by ExtractTokenMetadata
-		user, tokenErr := middleware.ExtractTokenMetadata(r)
-		if tokenErr != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(tokenErr.Error()))
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
 			return
 		}
 
 		// ensure the user has the required role for the action
-		if !user.IsSystemAdmin {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(projectEntity.ErrUserDoesNotHaveCreateProjectsPermission.Error()))
+		if !projectEntity.CanCreate(user) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveCreateProjectsPermission)
 			return
 		}
 
 		var input RequestBody
 		err := json.NewDecoder(r.Body).Decode(&input)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			writeError(w, r, problem.MalformedBody(err))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
 		defer cancel()
 
-		// convert input strings to value objects
-		sc, err := valueobject.NewShortCode(input.ShortCode)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
-			return
-		}
-
-		sn, err := valueobject.NewShortName(input.ShortName)
+		// convert input strings to value objects, reporting all invalid fields at once
+		sc, sn, ln, desc, err := input.values()
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
-			return
-		}
-
-		ln, err := valueobject.NewLongName(input.LongName)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
-			return
-		}
-
-		desc, err := valueobject.NewDescription(input.Description)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			writeError(w, r, err)
 			return
 		}
 
 		id, err := service.CreateProject(ctx, sc, sn, ln, desc)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			writeError(w, r, err)
 			return
 		}
 
 		// get the project
 		p, err := service.GetProject(ctx, id)
-		if err != nil && err == projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		if err != nil && err == projectEntity.ErrProjectHasBeenDeleted {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		if err != nil && err != projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(projectEntity.ErrServerNotResponding.Error()))
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
 		if p == nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(projectEntity.ErrNoProjectDataReturned.Error()))
+			writeError(w, r, projectEntity.ErrNoProjectDataReturned)
 			return
 		}
 
-		res := &presenter.Project{
-			ID:          id,
-			ShortCode:   p.ShortCode().String(),
-			ShortName:   p.ShortName().String(),
-			LongName:    p.LongName().String(),
-			Description: p.Description().String(),
-			CreatedAt:   p.CreatedAt().String(),
-			CreatedBy:   p.CreatedBy().String(),
-			ChangedAt:   p.ChangedAt().String(),
-			ChangedBy:   p.ChangedBy().String(),
-			DeletedAt:   p.DeletedAt().String(),
-			DeletedBy:   p.DeletedBy().String(),
-		}
-
-		// replace null-values with "null"
-		*res = res.NullifyJsonProps()
+		res := present(p)
 
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(res); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
-			return
+			log.Println(err.Error())
 		}
 	}
 }
@@ -161,442 +121,504 @@ func createProject(service project.UseCase) func(w http.ResponseWriter, r *http.
 // All fields of the RequestBody must be provided.
 // At least one of the values of the provided RequestBody must differ from the current value of the corresponding project field.
 // If a value of a field is identical to what it already is, the update will not be performed for that field.
-func updateProject(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+func updateProject(service project.UseCase, present projectPresenter) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		// get variables from request url
-		vars := mux.Vars(r)
-
-		// create empty Identifier
-		uuid := valueobject.Identifier{}
-
-		// create byte array from the provided id string
-		b := []byte(vars["id"])
-
-		// assign the value of the Identifier
-		uuid.UnmarshalText(b)
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
+			return
+		}
 
-		// check JWT token to make sure user is authenticated
-		// an object containing the users info is returned by ExtractTokenMetadata
-		user, tokenErr := middleware.ExtractTokenMetadata(r)
-		if tokenErr != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(tokenErr.Error()))
+		uuid, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, projectEntity.ErrInvalidUUID)
 			return
 		}
 
 		// ensure the user has the required role for the action
-		if !user.IsSystemAdmin && !checkRoles("Role:" + uuid.String() + ":Update", user.Roles) {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(projectEntity.ErrUserDoesNotHaveUpdateProjectPermission.Error()))
+		if !projectEntity.CanUpdate(user, uuid) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveUpdateProjectPermission)
 			return
 		}
 
 		var input RequestBody
-		err := json.NewDecoder(r.Body).Decode(&input)
+		err = json.NewDecoder(r.Body).Decode(&input)
 		if err != nil {
-			log.Println(err.Error())
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			writeError(w, r, problem.MalformedBody(err))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
 		defer cancel()
 
 		// get the project
 		p, err := service.GetProject(ctx, uuid)
-		if err != nil && err == projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		if err != nil && err == projectEntity.ErrProjectHasBeenDeleted {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		if err != nil && err != projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(projectEntity.ErrServerNotResponding.Error()))
-			return
-		}
-		if p == nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(projectEntity.ErrNoProjectDataReturned.Error()))
-			return
-		}
-
-		// convert input strings to value objects
-		sc, err := valueobject.NewShortCode(input.ShortCode)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
-			return
-		}
-
-		sn, err := valueobject.NewShortName(input.ShortName)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			writeError(w, r, err)
 			return
 		}
-
-		ln, err := valueobject.NewLongName(input.LongName)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+		if p == nil {
+			writeError(w, r, projectEntity.ErrNoProjectDataReturned)
 			return
 		}
 
-		desc, err := valueobject.NewDescription(input.Description)
+		// convert input strings to value objects, reporting all invalid fields at once
+		sc, sn, ln, desc, err := input.values()
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			writeError(w, r, err)
 			return
 		}
 
 		// update the project
 		up, err := service.UpdateProject(ctx, uuid, sc, sn, ln, desc)
-		if err != nil && err == projectEntity.ErrProjectHasBeenDeleted {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		if err != nil && err == projectEntity.ErrNoPropertiesChanged {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			w.Write([]byte(err.Error()))
-			return
-		}
 		if err != nil {
-			log.Println(err.Error())
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(projectEntity.ErrServerNotResponding.Error()))
+			writeError(w, r, err)
 			return
 		}
 
-		res := &presenter.Project{
-			ID:          up.ID(),
-			ShortCode:   up.ShortCode().String(),
-			ShortName:   up.ShortName().String(),
-			LongName:    up.LongName().String(),
-			Description: up.Description().String(),
-			CreatedAt:   up.CreatedAt().String(),
-			CreatedBy:   up.CreatedBy().String(),
-			ChangedAt:   up.ChangedAt().String(),
-			ChangedBy:   up.ChangedBy().String(),
-			DeletedAt:   up.DeletedAt().String(),
-			DeletedBy:   up.DeletedBy().String(),
-		}
-
-		// replace null-values with "null"
-		*res = res.NullifyJsonProps()
+		res := present(up)
 
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(res); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
-			return
+			log.Println(err.Error())
 		}
 	}
 }
 
+// projectMediaTypes are the media types a single project can be represented in, the first one being the default.
+var projectMediaTypes = []string{mediaTypeJSON, mediaTypeJSONLD, mediaTypeTurtle, mediaTypeNTriples}
+
 // getProject gets a project with the provided UUID.
-func getProject(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+func getProject(service project.UseCase, present projectPresenter) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		// get variables from request url
-		vars := mux.Vars(r)
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
+			return
+		}
 
-		uuid, err := valueobject.IdentifierFromBytes([]byte(vars["id"]))
+		uuid, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			writeError(w, r, projectEntity.ErrInvalidUUID)
 			return
 		}
 
-		// check JWT token to make sure user is authenticated
-		// an object containing the users info is returned by ExtractTokenMetadata
-		user, tokenErr := middleware.ExtractTokenMetadata(r)
-		if tokenErr != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(tokenErr.Error()))
+		// the representation depends on the Accept header
+		w.Header().Set("Vary", "Accept")
+		mediaType := negotiate(r, projectMediaTypes...)
+		if mediaType == "" {
+			writeError(w, r, problem.NotAcceptable(projectMediaTypes...))
 			return
 		}
 
 		// ensure the user has the required role for the action
-		if user.Roles == nil || (!user.IsSystemAdmin && !checkRoles("Role:" + uuid.String() + ":Read", user.Roles)) {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(projectEntity.ErrUserDoesNotHaveReadProjectPermission.Error()))
+		if !projectEntity.CanRead(user, uuid) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveReadProjectPermission)
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
 		defer cancel()
 
 		// get the project
 		p, err := service.GetProject(ctx, uuid)
-		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if p == nil {
+			writeError(w, r, projectEntity.ErrNoProjectDataReturned)
+			return
+		}
 
-		if err != nil && err == projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
+		writeProject(w, mediaType, p, present)
+	}
+}
+
+// writeProject writes the project in the negotiated media type.
+// Besides JSON, the project can be represented as RDF using the admin ontology, see presenter.NewProjectGraph.
+func writeProject(w http.ResponseWriter, mediaType string, p *projectEntity.Aggregate, present projectPresenter) {
+	w.Header().Set("Content-Type", mediaType)
+
+	var err error
+	switch mediaType {
+	case mediaTypeJSONLD:
+		err = rdf.WriteJSONLD(w, presenter.NewProjectGraph(p), presenter.RDFPrefixes...)
+	case mediaTypeTurtle:
+		err = rdf.WriteTurtle(w, presenter.NewProjectGraph(p), presenter.RDFPrefixes...)
+	case mediaTypeNTriples:
+		err = rdf.WriteNTriples(w, presenter.NewProjectGraph(p))
+	default:
+		err = json.NewEncoder(w).Encode(present(p))
+	}
+	if err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// deleteProject deletes a project with the provided UUID.
+func deleteProject(service project.UseCase, present projectPresenter) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
 			return
 		}
 
-		if err != nil && err != projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
+		// ensure the user has the required role for the action
+		if !projectEntity.CanDelete(user) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveDeleteProjectPermission)
 			return
 		}
-		if p == nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(projectEntity.ErrNoProjectDataReturned.Error()))
+
+		uuid, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, projectEntity.ErrInvalidUUID)
 			return
 		}
 
-		res := &presenter.Project{
-			ID:          p.ID(),
-			ShortCode:   p.ShortCode().String(),
-			ShortName:   p.ShortName().String(),
-			LongName:    p.LongName().String(),
-			Description: p.Description().String(),
-			CreatedAt:   p.CreatedAt().String(),
-			CreatedBy:   p.CreatedBy().String(),
-			ChangedAt:   p.ChangedAt().String(),
-			ChangedBy:   p.ChangedBy().String(),
-			DeletedAt:   p.DeletedAt().String(),
-			DeletedBy:   p.DeletedBy().String(),
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		// delete the project
+		p, err := service.DeleteProject(ctx, uuid)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if p == nil {
+			writeError(w, r, projectEntity.ErrNoProjectDataReturned)
+			return
 		}
 
-		// replace null-values with "null"
-		*res = res.NullifyJsonProps()
+		res := present(p)
 
+		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(res); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
+			log.Println(err.Error())
 		}
 	}
 }
 
-// deleteProject deletes a project with the provided UUID.
-func deleteProject(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+// restoreProject restores a deleted project with the provided UUID.
+func restoreProject(service project.UseCase, present projectPresenter) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		// check JWT token to make sure user is authenticated
-		// an object containing the users info is returned by ExtractTokenMetadata
-		user, tokenErr := middleware.ExtractTokenMetadata(r)
-		if tokenErr != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(tokenErr.Error()))
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
 			return
 		}
 
-		// ensure the user has the required role for the action
-		if !user.IsSystemAdmin {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(projectEntity.ErrUserDoesNotHaveDeleteProjectPermission.Error()))
+		// restoring a project requires the same role as deleting it
+		if !projectEntity.CanDelete(user) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveDeleteProjectPermission)
 			return
 		}
 
-		// get variables from request url
-		vars := mux.Vars(r)
-
-		// create empty Identifier
-		uuid := valueobject.Identifier{}
-
-		// create byte array from the provided id string
-		b := []byte(vars["id"])
-
-		// assign the value of the Identifier
-		uuid.UnmarshalText(b)
+		uuid, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, projectEntity.ErrInvalidUUID)
+			return
+		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
 		defer cancel()
 
-		// delete the project
-		p, err := service.DeleteProject(ctx, uuid)
-		w.Header().Set("Content-Type", "application/json")
-
-		if err != nil && err == projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
+		// restore the project
+		p, err := service.RestoreProject(ctx, uuid)
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		if err != nil && err == projectEntity.ErrProjectHasBeenDeleted {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(present(p)); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// getProjectEvents gets the events of the project with the provided UUID, oldest first.
+// Only the events are returned, without the state of the project after each of them.
+func getProjectEvents(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
 			return
 		}
 
+		uuid, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(projectEntity.ErrServerNotResponding.Error()))
+			writeError(w, r, projectEntity.ErrInvalidUUID)
 			return
 		}
-		if p == nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(projectEntity.ErrNoProjectDataReturned.Error()))
+
+		// ensure the user has the required role for the action
+		if !projectEntity.CanRead(user, uuid) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveReadProjectPermission)
 			return
 		}
 
-		res := &presenter.Project{
-			ID:          p.ID(),
-			ShortCode:   p.ShortCode().String(),
-			ShortName:   p.ShortName().String(),
-			LongName:    p.LongName().String(),
-			Description: p.Description().String(),
-			CreatedAt:   p.CreatedAt().String(),
-			CreatedBy:   p.CreatedBy().String(),
-			ChangedAt:   p.ChangedAt().String(),
-			ChangedBy:   p.ChangedBy().String(),
-			DeletedAt:   p.DeletedAt().String(),
-			DeletedBy:   p.DeletedBy().String(),
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		events, err := service.GetProjectHistory(ctx, uuid)
+		if err != nil {
+			writeError(w, r, err)
+			return
 		}
 
-		// replace null-values with "null"
-		*res = res.NullifyJsonProps()
+		res := []presenter.ProjectEvent{}
+		for _, ev := range events {
+			if e := presenter.NewProjectEvent(ev); e != nil {
+				res = append(res, *e)
+			}
+		}
 
+		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(res); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
+			log.Println(err.Error())
 		}
 	}
 }
 
-// listProjects gets a list of all projects.
-// By default, this only returns active projects.
-// ReturnDeletedProjects can be provided in the request body to also return projects marked as deleted.
-func listProjects(service project.UseCase) func(w http.ResponseWriter, r *http.Request) {
+// listProjects gets a page of projects.
+// By default, all active projects are returned sorted by short code.
+// The following query parameters are supported:
+//
+//	limit           the maximum number of projects returned, all projects are returned if omitted
+//	after           the cursor of the page to return, as found in the X-Next-Cursor header of the previous page
+//	sort            shortCode, shortName, createdAt or changedAt, prefixed with "-" to sort descending
+//	status          active, deleted or all
+//	includeDeleted  if true, projects marked as deleted are returned as well (same as status=all)
+//	shortName       only returns projects whose short name contains the value
+//	longName        only returns projects whose long name contains the value
+//	name            only returns projects whose short or long name contains the value
+//
+// The total number of matching projects is returned in the X-Total-Count header.
+func listProjects(service project.UseCase, present projectPresenter) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		// check JWT token to make sure user is authenticated
-		// an object containing the users info is returned by ExtractTokenMetadata
-		user, tokenErr := middleware.ExtractTokenMetadata(r)
-		if tokenErr != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(tokenErr.Error()))
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
 			return
 		}
 
-		log.Print(user)
-
 		// ensure the user has the required role for the action
-		if !user.IsSystemAdmin && !user.IsProjectAdmin {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission.Error()))
+		if !projectEntity.CanList(user) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission)
 			return
 		}
 
-		var input struct {
-			ReturnDeletedProjects bool `json:"returnDeletedProjects"`
-		}
-		err := json.NewDecoder(r.Body).Decode(&input)
+		q, err := parseProjectQuery(r)
 		if err != nil {
-			input.ReturnDeletedProjects = false // default to false if decoding fails (likely because it wasn't provided)
+			writeError(w, r, err)
+			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+		// if user is not a system admin and is a project admin, only the projects the user has access to are returned
+		if projectEntity.IsRestrictedToOwnProjects(user) {
+			q.ProjectIDs = append([]string{}, user.Projects...)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
 		defer cancel()
 
-		// get all projects
-		projects, err := service.ListProjects(ctx, input.ReturnDeletedProjects)
+		// get the requested page of projects
+		result, err := service.QueryProjects(ctx, q)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 
-		if err != nil && err == projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(err.Error()))
-			return
+		w.Header().Set("X-Total-Count", strconv.Itoa(result.Total))
+		if result.NextCursor != "" {
+			next := *r.URL
+			values := next.Query()
+			values.Set("after", result.NextCursor)
+			next.RawQuery = values.Encode()
+
+			w.Header().Set("X-Next-Cursor", result.NextCursor)
+			w.Header().Set("Link", "<"+next.RequestURI()+`>; rel="next"`)
 		}
 
-		if err != nil && err != projectEntity.ErrProjectNotFound {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(projectEntity.ErrServerNotResponding.Error()))
-			return
+		res := []interface{}{}
+		for i := range result.Projects {
+			res = append(res, present(&result.Projects[i]))
+		}
+
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			log.Println(err.Error())
 		}
-		if projects == nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(projectEntity.ErrNoProjectDataReturned.Error()))
+	}
+}
+
+// searchProjects gets the projects matching the full-text query provided in the `q` query parameter, best matches first.
+// Short code, short name, long name, description and keywords are searched, allowing for prefixes and typos.
+// The number of projects returned can be restricted with the `limit` query parameter.
+func searchProjects(service project.UseCase, present projectPresenter) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// get the authenticated user from the request context
+		user, ok := principal.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, principal.ErrNotAuthenticated)
 			return
 		}
 
-		// if user is not a system admin and is a project admin, filter `projects` to only contain the projects the user has access to
-		if !user.IsSystemAdmin && user.IsProjectAdmin {
+		// ensure the user has the required role for the action
+		if !projectEntity.CanList(user) {
+			writeError(w, r, projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission)
+			return
+		}
 
-			var filteredProjects []projectEntity.Aggregate
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			writeError(w, r, projectEntity.ErrNoSearchQuery)
+			return
+		}
 
-			for i := range projects { // for each projects in entire projects list
-				for _, atp := range user.Projects { // for each projects user has access to
-					if projects[i].ID().String() == atp { // compare project id to atp id
-						filteredProjects = append(filteredProjects, projects[i]) // add to filtered array
-					}
-				}
+		limit := 0
+		if l := r.URL.Query().Get("limit"); l != "" {
+			v, err := strconv.Atoi(l)
+			if err != nil || v < 1 || v > maxCatalogueLimit {
+				writeError(w, r, problem.InvalidParameter("limit", ErrInvalidLimit))
+				return
 			}
+			limit = v
+		}
 
-			// set the filteredProjects as the list of projects to be returned
-			projects = filteredProjects
+		// project admins only get the projects they have access to, so the limit is applied after filtering
+		filter := projectEntity.IsRestrictedToOwnProjects(user)
+		searchLimit := limit
+		if filter {
+			searchLimit = 0
 		}
 
-		var res []presenter.Project
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
 
-		for _, p := range projects {
+		projects, err := service.SearchProjects(ctx, query, searchLimit)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
 
-			projToAppend := presenter.Project{
-				ID:          p.ID(),
-				ShortCode:   p.ShortCode().String(),
-				ShortName:   p.ShortName().String(),
-				LongName:    p.LongName().String(),
-				Description: p.Description().String(),
-				CreatedAt:   p.CreatedAt().String(),
-				CreatedBy:   p.CreatedBy().String(),
-				ChangedAt:   p.ChangedAt().String(),
-				ChangedBy:   p.ChangedBy().String(),
-				DeletedAt:   p.DeletedAt().String(),
-				DeletedBy:   p.DeletedBy().String(),
-			}
+		w.Header().Set("Content-Type", "application/json")
 
-			// replace null-values with "null"
-			projToAppend = projToAppend.NullifyJsonProps()
+		res := []interface{}{}
 
-			res = append(res, projToAppend)
+		for i, p := range projects {
+			if filter && !user.HasProject(p.ID().String()) {
+				continue
+			}
+			if limit > 0 && len(res) == limit {
+				break
+			}
 
+			res = append(res, present(&projects[i]))
 		}
 
 		if err := json.NewEncoder(w).Encode(res); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
+			log.Println(err.Error())
 		}
 	}
 }
 
-// checkRoles checks if a specified role is in the list of the roles of the user
-func checkRoles(role string, usersRoleList[]interface{}) bool {
+// parseProjectQuery reads the query parameters of a request listing projects.
+func parseProjectQuery(r *http.Request) (project.Query, error) {
+	values := r.URL.Query()
 
-	// if user has no roles, return false
-	if len(role) == 0 {
-		return false
+	q := project.Query{
+		After:     values.Get("after"),
+		Status:    project.Status(values.Get("status")),
+		ShortName: values.Get("shortName"),
+		LongName:  values.Get("longName"),
+		Name:      values.Get("name"),
 	}
 
-	for _, r := range usersRoleList { // for each role the user has
-		if r == role { // check if the user has the role
-			return true
+	if l := values.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxCatalogueLimit {
+			return project.Query{}, problem.InvalidParameter("limit", ErrInvalidLimit)
+		}
+		q.Limit = limit
+	}
+
+	if s := values.Get("sort"); s != "" {
+		q.Descending = strings.HasPrefix(s, "-")
+		q.Sort = project.SortField(strings.TrimPrefix(s, "-"))
+	}
+
+	if d := values.Get("includeDeleted"); d != "" {
+		includeDeleted, err := strconv.ParseBool(d)
+		if err != nil {
+			return project.Query{}, problem.InvalidParameter("includeDeleted", ErrInvalidIncludeDeleted)
+		}
+		if includeDeleted && q.Status == "" {
+			q.Status = project.StatusAll
 		}
 	}
 
-	return false
+	return q, nil
 }
 
-// MakeProjectHandlers make url handlers for creating, updating, deleting, and getting projects
+// projectPresenter converts the project into its representation in a version of the api.
+type projectPresenter func(p *projectEntity.Aggregate) interface{}
+
+// presentProjectV1 represents the project as presenter.Project, which the v1 api has always returned.
+func presentProjectV1(p *projectEntity.Aggregate) interface{} {
+	return presenter.NewProject(p)
+}
+
+// presentProjectV2 represents the project as presenter.ProjectV2, with RFC 3339 timestamps and typed actors.
+func presentProjectV2(p *projectEntity.Aggregate) interface{} {
+	return presenter.NewProjectV2(p)
+}
+
+// MakeProjectHandlers make url handlers for creating, updating, deleting, restoring, and getting projects
+// The routes are served by the v1 and the v2 api, which only differ in the representation of the projects, see projectPresenter.
+// The router is expected to authenticate every request, see middleware.Authenticator.
 func MakeProjectHandlers(r *mux.Router, service project.UseCase) {
+	makeProjectHandlers(r, "/v1", service, presentProjectV1)
+	makeProjectHandlers(r, "/v2", service, presentProjectV2)
+}
+
+// makeProjectHandlers makes the url handlers of the projects of a version of the api, whose paths start with the prefix.
+func makeProjectHandlers(r *mux.Router, prefix string, service project.UseCase, present projectPresenter) {
+
+	r.HandleFunc(prefix+"/projects", createProject(service, present)).Methods("POST", "OPTIONS")
+
+	r.HandleFunc(prefix+"/projects:import", importProjects(service)).Methods("POST", "OPTIONS")
+
+	// must be registered before the routes matching any project id
+	r.HandleFunc(prefix+"/projects/search", searchProjects(service, present)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc(prefix+"/projects/{id}:restore", restoreProject(service, present)).Methods("POST", "OPTIONS")
 
-	r.HandleFunc("/v1/projects", createProject(service)).Methods("POST", "OPTIONS")
+	r.HandleFunc(prefix+"/projects/{id}", updateProject(service, present)).Methods("PUT", "OPTIONS")
 
-	r.HandleFunc("/v1/projects/{id}", updateProject(service)).Methods("PUT", "OPTIONS")
+	r.HandleFunc(prefix+"/projects/{id}", deleteProject(service, present)).Methods("DELETE", "OPTIONS")
 
-	r.HandleFunc("/v1/projects/{id}", deleteProject(service)).Methods("DELETE", "OPTIONS")
+	r.HandleFunc(prefix+"/projects/{id}", getProject(service, present)).Methods("GET", "OPTIONS")
 
-	r.HandleFunc("/v1/projects/{id}", getProject(service)).Methods("GET", "OPTIONS")
+	r.HandleFunc(prefix+"/projects/{id}/events", getProjectEvents(service)).Methods("GET", "OPTIONS")
 
-	r.HandleFunc("/v1/projects", listProjects(service)).Methods("GET", "OPTIONS")
+	r.HandleFunc(prefix+"/projects", listProjects(service, present)).Methods("GET", "OPTIONS")
 }
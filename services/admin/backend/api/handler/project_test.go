@@ -0,0 +1,311 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProject_ListProjects(t *testing.T) {
+	service := newStubProjectService(t, 2)
+
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects?sort=-createdAt&limit=10", systemAdmin()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+
+	var res []presenter.Project
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Len(t, res, 2)
+}
+
+func TestProject_ListProjects_InvalidQuery(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, newStubProjectService(t, 1))
+
+	for _, query := range []string{"limit=0", "limit=abc", "includeDeleted=maybe"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects?"+query, systemAdmin()))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestProject_ListProjects_NotAuthenticated(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, newStubProjectService(t, 1))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/projects", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+// newAuthenticatedRequest creates a request carrying the principal in its context, as done by the authentication middleware.
+func newAuthenticatedRequest(method string, target string, p *principal.Principal) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	return req.WithContext(principal.NewContext(req.Context(), p))
+}
+
+// systemAdmin returns a principal of a system admin.
+func systemAdmin() *principal.Principal {
+	return &principal.Principal{
+		ID:            "3018c9db-7a65-44e7-b31a-0d547a10b75b",
+		IsSystemAdmin: true,
+	}
+}
+
+func TestProject_SearchProjects(t *testing.T) {
+	service := newStubProjectService(t, 2)
+
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects/search?q=project+1", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res []presenter.Project
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Len(t, res, 1)
+	assert.Equal(t, service.projects[1].ID(), res[0].ID)
+
+	// project admins only find the projects they have access to
+	admin := &principal.Principal{IsProjectAdmin: true, Projects: []string{service.projects[0].ID().String()}}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects/search?q=project+1", admin))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Empty(t, res)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects/search", systemAdmin()))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProject_Errors(t *testing.T) {
+	service := newStubProjectService(t, 2)
+	deleted := service.projects[1]
+	assert.Nil(t, deleted.DeleteProject(deleted.ID(), event.Actor{}))
+
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	projectAdmin := &principal.Principal{IsProjectAdmin: true, Projects: []string{service.projects[0].ID().String()}}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		code   string
+		field  string
+	}{
+		{"permission denied", newAuthenticatedRequest("GET", "/v1/projects/"+service.projects[0].ID().String(), projectAdmin), http.StatusForbidden, "permission_denied", ""},
+		{"not found", newAuthenticatedRequest("GET", "/v1/projects/"+valueobject.Identifier{}.String(), systemAdmin()), http.StatusNotFound, "project_not_found", ""},
+		{"invalid id", newAuthenticatedRequest("GET", "/v1/projects/abc", systemAdmin()), http.StatusBadRequest, "invalid_uuid", "id"},
+		{"deleted", newAuthenticatedRequest("DELETE", "/v1/projects/"+deleted.ID().String(), systemAdmin()), http.StatusGone, "project_deleted", ""},
+		{"malformed body", newAuthenticatedJSONRequest("POST", "/v1/projects", `{"shortCode":`, systemAdmin()), http.StatusBadRequest, "malformed_body", ""},
+		{"invalid field", newAuthenticatedJSONRequest("POST", "/v1/projects", `{"shortCode":"xyz","shortName":"a","longName":"b","description":"c"}`, systemAdmin()), http.StatusUnprocessableEntity, "validation_failed", "shortCode"},
+		{"duplicate short code", newAuthenticatedJSONRequest("POST", "/v1/projects", `{"shortCode":"0000","shortName":"a","longName":"b","description":"c"}`, systemAdmin()), http.StatusConflict, "short_code_already_exists", "shortCode"},
+		{"invalid limit", newAuthenticatedRequest("GET", "/v1/projects?limit=0", systemAdmin()), http.StatusBadRequest, "invalid_parameter", "limit"},
+		{"restore active project", newAuthenticatedRequest("POST", "/v1/projects/"+service.projects[0].ID().String()+":restore", systemAdmin()), http.StatusConflict, "project_not_deleted", ""},
+		{"restore permission denied", newAuthenticatedRequest("POST", "/v1/projects/"+deleted.ID().String()+":restore", projectAdmin), http.StatusForbidden, "permission_denied", ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, tt.req)
+		assert.Equal(t, tt.status, w.Code, tt.name)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), tt.name)
+
+		var res problem.Problem
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&res), tt.name)
+		assert.Equal(t, tt.code, res.Code, tt.name)
+		assert.Equal(t, tt.status, res.Status, tt.name)
+		assert.Equal(t, tt.req.URL.Path, res.Instance, tt.name)
+		if tt.field != "" {
+			assert.Len(t, res.Errors, 1, tt.name)
+			assert.Equal(t, tt.field, res.Errors[0].Field, tt.name)
+		}
+	}
+}
+
+func TestProject_RestoreProject(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	deleted := service.projects[0]
+	assert.Nil(t, deleted.DeleteProject(deleted.ID(), event.Actor{}))
+
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("POST", "/v1/projects/"+deleted.ID().String()+":restore", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res presenter.Project
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, deleted.ID(), res.ID)
+	assert.Equal(t, "null", res.DeletedAt)
+}
+
+func TestProject_Versions(t *testing.T) {
+	service := newStubProjectService(t, 2)
+	p := service.projects[0]
+
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	// the v1 api keeps returning the timestamps and actors as strings
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects/"+p.ID().String(), systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var v1 map[string]interface{}
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&v1))
+	assert.Equal(t, p.CreatedAt().String(), v1["createdAt"])
+	assert.Equal(t, p.CreatedBy().String(), v1["createdBy"])
+	assert.Equal(t, "null", v1["changedAt"])
+	assert.Equal(t, "null", v1["deletedBy"])
+
+	// the v2 api returns RFC 3339 timestamps, typed actors and nulls
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v2/projects/"+p.ID().String(), systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var v2 presenter.ProjectV2
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&v2))
+	assert.True(t, p.CreatedAt().Time().Equal(*v2.CreatedAt))
+	if assert.NotNil(t, v2.CreatedBy) && assert.NotNil(t, v2.CreatedBy.Type) {
+		assert.Equal(t, p.CreatedBy(), v2.CreatedBy.ID)
+		assert.Equal(t, "user", *v2.CreatedBy.Type)
+	}
+	assert.Nil(t, v2.ChangedAt)
+	assert.Nil(t, v2.DeletedBy)
+
+	var list []presenter.ProjectV2
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v2/projects", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Len(t, list, 2)
+	assert.NotNil(t, list[0].CreatedBy)
+}
+
+func TestProject_GetProjectEvents(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	deleted := service.projects[0]
+	assert.Nil(t, deleted.DeleteProject(deleted.ID(), event.Actor{}))
+
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/projects/"+deleted.ID().String()+"/events", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res []presenter.ProjectEvent
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Len(t, res, 2)
+	assert.Equal(t, "ProjectCreated", res[0].Event)
+	assert.Equal(t, presenter.ProjectEventDeleted, res[1].Type)
+	assert.Equal(t, deleted.ID(), res[1].ProjectID)
+}
+
+// newAuthenticatedJSONRequest creates an authenticated request with the JSON body.
+func newAuthenticatedJSONRequest(method string, target string, body string, p *principal.Principal) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req.WithContext(principal.NewContext(req.Context(), p))
+}
+
+func TestProject_Create_ReportsAllInvalidFields(t *testing.T) {
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, newStubProjectService(t, 1))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedJSONRequest("POST", "/v1/projects", `{"shortCode":"xyz","shortName":"a name longer than 15","longName":"long name","description":" "}`, systemAdmin()))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var res problem.Problem
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, problem.CodeValidationFailed, res.Code)
+	assert.Len(t, res.Errors, 3)
+	assert.Equal(t, problem.FieldError{Field: "shortCode", Constraint: "hexadecimal", Detail: "invalid short code, must be two hexadecimal digits and non-empty"}, res.Errors[0])
+	assert.Equal(t, problem.FieldError{Field: "shortName", Constraint: "maxLength", Limit: 15, Detail: "invalid short name, must be within 15 characters and non-empty"}, res.Errors[1])
+	assert.Equal(t, "description", res.Errors[2].Field)
+	assert.Equal(t, "required", res.Errors[2].Constraint)
+}
+
+func TestProject_GetProject_ContentNegotiation(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	id := service.projects[0].ID().String()
+
+	r := mux.NewRouter()
+	handler.MakeProjectHandlers(r, service)
+
+	tests := []struct {
+		accept      string
+		contentType string
+		contains    string
+	}{
+		{"", "application/json", `"id":"` + id + `"`},
+		{"*/*", "application/json", `"id":"` + id + `"`},
+		{"application/ld+json", "application/ld+json", `"@id":"http://rdfh.ch/projects/` + id + `"`},
+		{"text/turtle", "text/turtle", "<http://rdfh.ch/projects/" + id + ">\n    a admin:Project ;"},
+		{"application/n-triples", "application/n-triples", "<http://rdfh.ch/projects/" + id + "> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://ns.dasch.swiss/admin#Project> ."},
+		{"text/html, text/*;q=0.8, application/json;q=0.5", "text/turtle", "@prefix admin: <http://ns.dasch.swiss/admin#> ."},
+		{"application/*, application/json;q=0", "application/ld+json", `"@context"`},
+	}
+
+	for _, tt := range tests {
+		req := newAuthenticatedRequest("GET", "/v1/projects/"+id, systemAdmin())
+		req.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, tt.accept)
+		assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"), tt.accept)
+		assert.Equal(t, "Accept", w.Header().Get("Vary"), tt.accept)
+		assert.Contains(t, w.Body.String(), tt.contains, tt.accept)
+	}
+
+	req := newAuthenticatedRequest("GET", "/v1/projects/"+id, systemAdmin())
+	req.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+
+	var res problem.Problem
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, problem.CodeNotAcceptable, res.Code)
+}
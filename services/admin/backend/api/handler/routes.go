@@ -0,0 +1,66 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/oai"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/webhook"
+	"github.com/gorilla/mux"
+)
+
+// Routes are the services and settings the REST api is served with, see MakeRoutes.
+type Routes struct {
+	Projects        project.UseCase
+	ServiceAccounts serviceaccount.UseCase
+	Webhooks        webhook.UseCase
+	DataCite        export.DataCiteOptions
+	OAI             oai.Repository
+	HealthChecks    map[string]HealthCheck
+	// Projections are the projections system admins can rebuild, by name.
+	Projections      map[string]RebuildFunc
+	RebuildSnapshots RebuildFunc
+	Verify           VerifyFunc
+	Forget           ForgetFunc
+}
+
+// MakeRoutes registers every route of the REST api. The routes which require credentials are registered on a subrouter,
+// after the public ones, which applies the middlewares to every request, the first being expected to authenticate it,
+// see middleware.Authenticator. It is used by main and by the tests of the OpenAPI document, so that no route can be served
+// without being described.
+func MakeRoutes(r *mux.Router, routes Routes, middlewares ...mux.MiddlewareFunc) {
+	MakePublicProjectHandlers(r, routes.Projects)
+	MakeOpenAPIHandler(r)
+	MakeHealthHandler(r, routes.HealthChecks)
+	MakeOAIHandler(r, routes.Projects, routes.OAI)
+
+	api := r.NewRoute().Subrouter()
+	api.Use(middlewares...)
+
+	MakeProjectHandlers(api, routes.Projects)
+	MakeProjectExportHandlers(api, routes.Projects, routes.DataCite)
+	MakeProjectEventHandlers(api, routes.Projects)
+	MakeServiceAccountHandlers(api, routes.ServiceAccounts)
+	MakeWebhookHandlers(api, routes.Webhooks)
+	MakeMaintenanceHandlers(api, routes.Projections, routes.RebuildSnapshots)
+	MakeIntegrityHandler(api, routes.Verify)
+	MakePrivacyHandlers(api, routes.Forget)
+	MakeGraphQLHandler(api, routes.Projects)
+}
@@ -0,0 +1,308 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	serviceAccountEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+)
+
+// ServiceAccountRequestBody provides a reusable struct to use when decoding the JSON request body of a new service account.
+type ServiceAccountRequestBody struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// APIKeyRequestBody provides a reusable struct to use when decoding the JSON request body of a new api key.
+// ExpiresAt is optional and must be provided in RFC 3339 format, e.g. "2022-01-31T00:00:00Z".
+type APIKeyRequestBody struct {
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expiresAt"`
+}
+
+// createServiceAccount creates a service account with the provided ServiceAccountRequestBody.
+func createServiceAccount(service serviceaccount.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeServiceAccountManagement(w, r) {
+			return
+		}
+
+		var input ServiceAccountRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeError(w, r, problem.MalformedBody(err))
+			return
+		}
+
+		// convert input strings to value objects
+		v := valueobject.Validation{}
+		name := v.LongName("name", input.Name)
+		desc := v.Description("description", input.Description)
+		if err := v.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		id, err := service.CreateServiceAccount(ctx, name, desc)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		a, err := service.GetServiceAccount(ctx, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(presenter.NewServiceAccount(a))
+	}
+}
+
+// getServiceAccount gets the service account with the provided UUID.
+func getServiceAccount(service serviceaccount.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeServiceAccountManagement(w, r) {
+			return
+		}
+
+		id, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		a, err := service.GetServiceAccount(ctx, id)
+		if err == nil && a.ID() != id {
+			err = serviceAccountEntity.ErrServiceAccountNotFound
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(presenter.NewServiceAccount(a))
+	}
+}
+
+// listServiceAccounts gets a list of all service accounts.
+// By default, this only returns active service accounts.
+// The query parameter includeDeleted=true can be provided to also return service accounts marked as deleted.
+func listServiceAccounts(service serviceaccount.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeServiceAccountManagement(w, r) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		accounts, err := service.ListServiceAccounts(ctx, r.URL.Query().Get("includeDeleted") == "true")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		res := []presenter.ServiceAccount{}
+		for i := range accounts {
+			res = append(res, presenter.NewServiceAccount(&accounts[i]))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// deleteServiceAccount deletes the service account with the provided UUID, which invalidates all of its api keys.
+func deleteServiceAccount(service serviceaccount.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeServiceAccountManagement(w, r) {
+			return
+		}
+
+		id, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		a, err := service.DeleteServiceAccount(ctx, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(presenter.NewServiceAccount(a))
+	}
+}
+
+// issueAPIKey issues a new api key for the service account with the provided UUID.
+// The plain text key is part of the response and cannot be retrieved later on.
+func issueAPIKey(service serviceaccount.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeServiceAccountManagement(w, r) {
+			return
+		}
+
+		id, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		var input APIKeyRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeError(w, r, problem.MalformedBody(err))
+			return
+		}
+
+		var scopes []serviceAccountEntity.Scope
+		for _, s := range input.Scopes {
+			scope, err := serviceAccountEntity.ParseScope(s)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			scopes = append(scopes, scope)
+		}
+
+		var expiresAt valueobject.Timestamp
+		if input.ExpiresAt != "" {
+			t, err := time.Parse(time.RFC3339, input.ExpiresAt)
+			if err != nil {
+				writeError(w, r, problem.Validation(problem.FieldError{Field: "expiresAt", Detail: err.Error()}))
+				return
+			}
+			expiresAt = valueobject.NewTimestampFromUnix(t.Unix())
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		key, k, err := service.IssueAPIKey(ctx, id, scopes, expiresAt)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		res := presenter.NewAPIKey(*k)
+		res.Key = key
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// revokeAPIKey revokes the api key with the provided key UUID.
+func revokeAPIKey(service serviceaccount.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeServiceAccountManagement(w, r) {
+			return
+		}
+
+		vars := mux.Vars(r)
+
+		id, err := valueobject.IdentifierFromBytes([]byte(vars["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		keyID, err := valueobject.IdentifierFromBytes([]byte(vars["keyId"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("keyId", err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		a, err := service.RevokeAPIKey(ctx, id, keyID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(presenter.NewServiceAccount(a))
+	}
+}
+
+// authorizeServiceAccountManagement ensures that the authenticated caller is a system admin.
+// Service accounts cannot manage service accounts, regardless of their scopes.
+// If false is returned, the response has already been written.
+func authorizeServiceAccountManagement(w http.ResponseWriter, r *http.Request) bool {
+	user, ok := principal.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, principal.ErrNotAuthenticated)
+		return false
+	}
+
+	if !user.IsSystemAdmin || user.IsServiceAccount {
+		writeError(w, r, serviceAccountEntity.ErrUserDoesNotHaveManageServiceAccountsPermission)
+		return false
+	}
+
+	return true
+}
+
+// MakeServiceAccountHandlers make url handlers for managing service accounts and their api keys.
+// The router is expected to authenticate every request, see middleware.Authenticator.
+func MakeServiceAccountHandlers(r *mux.Router, service serviceaccount.UseCase) {
+
+	r.HandleFunc("/v1/service-accounts", createServiceAccount(service)).Methods("POST", "OPTIONS")
+
+	r.HandleFunc("/v1/service-accounts", listServiceAccounts(service)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/v1/service-accounts/{id}", getServiceAccount(service)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/v1/service-accounts/{id}", deleteServiceAccount(service)).Methods("DELETE", "OPTIONS")
+
+	r.HandleFunc("/v1/service-accounts/{id}/keys", issueAPIKey(service)).Methods("POST", "OPTIONS")
+
+	r.HandleFunc("/v1/service-accounts/{id}/keys/{keyId}", revokeAPIKey(service)).Methods("DELETE", "OPTIONS")
+}
@@ -0,0 +1,281 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	projectService "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+//stubProjectService is a project use case working on a fixed list of projects (only visible inside this package)
+type stubProjectService struct {
+	projects []*project.Aggregate
+	mu       sync.Mutex
+	events   []event.Event
+	watchers []func(id string, ev event.Event)
+}
+
+//newStubProjectService creates a stub project use case containing n projects
+func newStubProjectService(t *testing.T, n int) *stubProjectService {
+	s := &stubProjectService{}
+	for i := 0; i < n; i++ {
+		id, _ := valueobject.NewIdentifier()
+		sc, err := valueobject.NewShortCode(fmt.Sprintf("%04X", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sn, _ := valueobject.NewShortName(fmt.Sprintf("project %d", i))
+		ln, _ := valueobject.NewLongName(fmt.Sprintf("long name of project %d", i))
+		desc, _ := valueobject.NewDescription(fmt.Sprintf("description of project %d", i))
+		createdBy, _ := valueobject.NewIdentifier()
+		s.projects = append(s.projects, project.NewAggregate(id, sc, sn, ln, desc, event.Actor{ID: createdBy, Type: event.ActorUser}))
+	}
+	return s
+}
+
+func (s *stubProjectService) GetProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
+	for _, p := range s.projects {
+		if p.ID() == id {
+			return p, nil
+		}
+	}
+	return nil, project.ErrProjectNotFound
+}
+
+func (s *stubProjectService) GetProjectHistory(ctx context.Context, id valueobject.Identifier) ([]event.Event, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p.Events(), nil
+}
+
+func (s *stubProjectService) ListProjects(ctx context.Context, returnDeletedProjects bool) ([]project.Aggregate, error) {
+	var projects []project.Aggregate
+	for _, p := range s.projects {
+		if returnDeletedProjects || p.DeletedAt().Time().IsZero() {
+			projects = append(projects, *p)
+		}
+	}
+	return projects, nil
+}
+
+func (s *stubProjectService) QueryProjects(ctx context.Context, q projectService.Query) (projectService.Result, error) {
+	projects, err := s.ListProjects(ctx, q.Status == projectService.StatusAll)
+	if err != nil {
+		return projectService.Result{}, err
+	}
+	return projectService.Result{Projects: projects, Total: len(projects)}, nil
+}
+
+func (s *stubProjectService) SearchProjects(ctx context.Context, query string, limit int) ([]project.Aggregate, error) {
+	var projects []project.Aggregate
+	for _, p := range s.projects {
+		if p.DeletedAt().Time().IsZero() && strings.Contains(p.ShortName().String(), query) {
+			projects = append(projects, *p)
+		}
+	}
+	return projects, nil
+}
+
+func (s *stubProjectService) CreateProject(ctx context.Context, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (valueobject.Identifier, error) {
+	for _, p := range s.projects {
+		if p.ShortCode().Equals(shortCode) {
+			return valueobject.Identifier{}, project.ErrShortCodeAlreadyExists
+		}
+	}
+	id, _ := valueobject.NewIdentifier()
+	s.projects = append(s.projects, project.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{}))
+	return id, nil
+}
+
+func (s *stubProjectService) UpdateProject(ctx context.Context, id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (*project.Aggregate, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.UpdateProject(id, shortCode, shortName, longName, description, event.Actor{})
+}
+
+func (s *stubProjectService) DeleteProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !p.DeletedAt().Time().IsZero() {
+		return nil, project.ErrProjectHasBeenDeleted
+	}
+	return p, p.DeleteProject(id, event.Actor{})
+}
+
+func (s *stubProjectService) RestoreProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.RestoreProject(event.Actor{})
+}
+
+//ImportProjects validates each row on its own, valid rows are created with CreateProject unless dryRun is set
+func (s *stubProjectService) ImportProjects(ctx context.Context, rows []projectService.ImportRow, dryRun bool) ([]projectService.ImportResult, error) {
+	if len(rows) == 0 {
+		return nil, project.ErrNoImportRows
+	}
+	var results []projectService.ImportResult
+	for _, row := range rows {
+		res := projectService.ImportResult{Line: row.Line, ShortCode: row.ShortCode, Status: projectService.ImportValid}
+		v := valueobject.Validation{}
+		sc := v.ShortCode("shortCode", row.ShortCode)
+		sn := v.ShortName("shortName", row.ShortName)
+		ln := v.LongName("longName", row.LongName)
+		desc := v.Description("description", row.Description)
+		if err := v.Err(); err != nil {
+			res.Status, res.Err = projectService.ImportInvalid, err
+		} else if !dryRun {
+			if id, err := s.CreateProject(ctx, sc, sn, ln, desc); err != nil {
+				res.Status, res.Err = projectService.ImportInvalid, err
+			} else {
+				res.Status, res.ID = projectService.ImportCreated, id
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *stubProjectService) WatchProjects(ctx context.Context, handle func(ev event.Event)) error {
+	return nil
+}
+
+//WatchProjectsSince replays the recorded events after the one with the id, which is the position of an event starting at 1
+func (s *stubProjectService) WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from := len(s.events)
+	if lastEventID != "" {
+		var err error
+		if from, err = strconv.Atoi(lastEventID); err != nil || from < 0 || from > len(s.events) {
+			return project.ErrInvalidEventID
+		}
+	}
+	for i := from; i < len(s.events); i++ {
+		handle(strconv.Itoa(i+1), s.events[i])
+	}
+	s.watchers = append(s.watchers, handle)
+	return nil
+}
+
+//record records the event and passes it to all handlers watching the projects
+func (s *stubProjectService) record(ev event.Event) {
+	s.mu.Lock()
+	s.events = append(s.events, ev)
+	id := strconv.Itoa(len(s.events))
+	watchers := append([]func(id string, ev event.Event){}, s.watchers...)
+	s.mu.Unlock()
+	for _, handle := range watchers {
+		handle(id, ev)
+	}
+}
+
+//stubWebhookService is a webhook use case keeping the webhooks and deliveries in memory (only visible inside this package)
+type stubWebhookService struct {
+	webhooks   []*webhook.Aggregate
+	deliveries []*webhook.Delivery
+}
+
+func (s *stubWebhookService) GetWebhook(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error) {
+	for _, w := range s.webhooks {
+		if w.ID() == id {
+			return w, nil
+		}
+	}
+	return nil, webhook.ErrWebhookNotFound
+}
+
+func (s *stubWebhookService) ListWebhooks(ctx context.Context, returnDeletedWebhooks bool) ([]webhook.Aggregate, error) {
+	var webhooks []webhook.Aggregate
+	for _, w := range s.webhooks {
+		if returnDeletedWebhooks || w.DeletedAt().Time().IsZero() {
+			webhooks = append(webhooks, *w)
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *stubWebhookService) CreateWebhook(ctx context.Context, url string, eventTypes []webhook.EventType, description valueobject.Description) (valueobject.Identifier, error) {
+	if err := webhook.ValidateURL(url); err != nil {
+		return valueobject.Identifier{}, err
+	}
+	if len(eventTypes) == 0 {
+		return valueobject.Identifier{}, webhook.ErrNoEventTypesProvided
+	}
+	id, _ := valueobject.NewIdentifier()
+	s.webhooks = append(s.webhooks, webhook.NewAggregate(id, url, "secret", eventTypes, description, principal.TypedActorFromContext(ctx)))
+	return id, nil
+}
+
+func (s *stubWebhookService) DeleteWebhook(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error) {
+	w, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return w, w.DeleteWebhook(principal.TypedActorFromContext(ctx))
+}
+
+func (s *stubWebhookService) ListDeliveries(ctx context.Context, webhookID valueobject.Identifier, status webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	if webhookID != (valueobject.Identifier{}) {
+		if _, err := s.GetWebhook(ctx, webhookID); err != nil {
+			return nil, err
+		}
+	}
+	var deliveries []webhook.Delivery
+	for _, d := range s.deliveries {
+		if (webhookID == valueobject.Identifier{} || d.WebhookID() == webhookID) && (status == "" || d.Status() == status) {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	return deliveries, nil
+}
+
+func (s *stubWebhookService) Redeliver(ctx context.Context, webhookID valueobject.Identifier, deliveryID valueobject.Identifier) (*webhook.Delivery, error) {
+	for _, d := range s.deliveries {
+		if d.ID() == deliveryID && d.WebhookID() == webhookID {
+			return d, d.Redeliver(principal.TypedActorFromContext(ctx))
+		}
+	}
+	return nil, webhook.ErrDeliveryNotFound
+}
+
+//schedule adds a pending delivery of an event to the webhook
+func (s *stubWebhookService) schedule(webhookID valueobject.Identifier) *webhook.Delivery {
+	id, _ := valueobject.NewIdentifier()
+	d := webhook.NewDelivery(id, webhookID, webhook.EventProjectCreated, strconv.Itoa(len(s.deliveries)+1), []byte(`{}`))
+	s.deliveries = append(s.deliveries, d)
+	return d
+}
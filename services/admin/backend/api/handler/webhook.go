@@ -0,0 +1,322 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	webhookEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+)
+
+// WebhookRequestBody provides a reusable struct to use when decoding the JSON request body of a new webhook.
+// EventTypes lists the project events the webhook is notified about, e.g. "project.created".
+type WebhookRequestBody struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"eventTypes"`
+	Description string   `json:"description"`
+}
+
+// createWebhook creates a webhook with the provided WebhookRequestBody.
+// The secret used to sign the payloads is part of the response and cannot be retrieved later on.
+func createWebhook(service webhook.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeWebhookManagement(w, r) {
+			return
+		}
+
+		var input WebhookRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeError(w, r, problem.MalformedBody(err))
+			return
+		}
+
+		// convert input strings to value objects
+		v := valueobject.Validation{}
+		desc := v.Description("description", input.Description)
+		if err := v.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var eventTypes []webhookEntity.EventType
+		for _, s := range input.EventTypes {
+			t, err := webhookEntity.ParseEventType(s)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			eventTypes = append(eventTypes, t)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		id, err := service.CreateWebhook(ctx, input.URL, eventTypes, desc)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		a, err := service.GetWebhook(ctx, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		res := presenter.NewWebhook(a)
+		res.Secret = a.Secret()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// getWebhook gets the webhook with the provided UUID.
+func getWebhook(service webhook.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeWebhookManagement(w, r) {
+			return
+		}
+
+		id, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		a, err := service.GetWebhook(ctx, id)
+		if err == nil && a.ID() != id {
+			err = webhookEntity.ErrWebhookNotFound
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(presenter.NewWebhook(a))
+	}
+}
+
+// listWebhooks gets a list of all webhooks.
+// By default, this only returns active webhooks.
+// The query parameter includeDeleted=true can be provided to also return webhooks marked as deleted.
+func listWebhooks(service webhook.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeWebhookManagement(w, r) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		webhooks, err := service.ListWebhooks(ctx, r.URL.Query().Get("includeDeleted") == "true")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		res := []presenter.Webhook{}
+		for i := range webhooks {
+			res = append(res, presenter.NewWebhook(&webhooks[i]))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// deleteWebhook deletes the webhook with the provided UUID, which stops all deliveries to it.
+func deleteWebhook(service webhook.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeWebhookManagement(w, r) {
+			return
+		}
+
+		id, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		a, err := service.DeleteWebhook(ctx, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(presenter.NewWebhook(a))
+	}
+}
+
+// listWebhookDeliveries gets a list of the deliveries to the webhook with the provided UUID, oldest first.
+// The query parameter status can be provided to only return deliveries with this status, e.g. status=dead_lettered.
+func listWebhookDeliveries(service webhook.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeWebhookManagement(w, r) {
+			return
+		}
+
+		id, err := valueobject.IdentifierFromBytes([]byte(mux.Vars(r)["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		writeDeliveries(w, r, service, id, r.URL.Query().Get("status"))
+	}
+}
+
+// listDeadLetters gets a list of the dead-lettered deliveries to all webhooks, oldest first.
+func listDeadLetters(service webhook.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeWebhookManagement(w, r) {
+			return
+		}
+
+		writeDeliveries(w, r, service, valueobject.Identifier{}, string(webhookEntity.DeliveryDeadLettered))
+	}
+}
+
+// redeliverWebhookDelivery requests a delivery which is no longer pending to be attempted again.
+func redeliverWebhookDelivery(service webhook.UseCase) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !authorizeWebhookManagement(w, r) {
+			return
+		}
+
+		vars := mux.Vars(r)
+
+		id, err := valueobject.IdentifierFromBytes([]byte(vars["id"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("id", err))
+			return
+		}
+
+		deliveryID, err := valueobject.IdentifierFromBytes([]byte(vars["deliveryId"]))
+		if err != nil {
+			writeError(w, r, problem.InvalidParameter("deliveryId", err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+		defer cancel()
+
+		d, err := service.Redeliver(ctx, id, deliveryID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(presenter.NewWebhookDelivery(d))
+	}
+}
+
+// writeDeliveries writes the deliveries to the webhook, or to all webhooks if no id is provided.
+// If a status is provided, only the deliveries with this status are written.
+func writeDeliveries(w http.ResponseWriter, r *http.Request, service webhook.UseCase, id valueobject.Identifier, s string) {
+	var status webhookEntity.DeliveryStatus
+	if s != "" {
+		var err error
+		if status, err = webhookEntity.ParseDeliveryStatus(s); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	deliveries, err := service.ListDeliveries(ctx, id, status)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res := []presenter.WebhookDelivery{}
+	for i := range deliveries {
+		res = append(res, presenter.NewWebhookDelivery(&deliveries[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// authorizeWebhookManagement ensures that the authenticated caller is a system admin.
+// Service accounts cannot manage webhooks, regardless of their scopes.
+// If false is returned, the response has already been written.
+func authorizeWebhookManagement(w http.ResponseWriter, r *http.Request) bool {
+	user, ok := principal.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, principal.ErrNotAuthenticated)
+		return false
+	}
+
+	if !user.IsSystemAdmin || user.IsServiceAccount {
+		writeError(w, r, webhookEntity.ErrUserDoesNotHaveManageWebhooksPermission)
+		return false
+	}
+
+	return true
+}
+
+// MakeWebhookHandlers make url handlers for managing webhooks and the deliveries to them.
+// The router is expected to authenticate every request, see middleware.Authenticator.
+func MakeWebhookHandlers(r *mux.Router, service webhook.UseCase) {
+
+	r.HandleFunc("/v1/webhooks", createWebhook(service)).Methods("POST", "OPTIONS")
+
+	r.HandleFunc("/v1/webhooks", listWebhooks(service)).Methods("GET", "OPTIONS")
+
+	// registered before /v1/webhooks/{id}, which would match it otherwise
+	r.HandleFunc("/v1/webhooks/dead-letters", listDeadLetters(service)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/v1/webhooks/{id}", getWebhook(service)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/v1/webhooks/{id}", deleteWebhook(service)).Methods("DELETE", "OPTIONS")
+
+	r.HandleFunc("/v1/webhooks/{id}/deliveries", listWebhookDeliveries(service)).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver", redeliverWebhookDelivery(service)).Methods("POST", "OPTIONS")
+}
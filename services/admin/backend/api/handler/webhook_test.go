@@ -0,0 +1,179 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	webhookEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_CreateWebhook(t *testing.T) {
+	service := &stubWebhookService{}
+
+	r := mux.NewRouter()
+	handler.MakeWebhookHandlers(r, service)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedJSONRequest("POST", "/v1/webhooks", `{"url":"https://example.com/hook","eventTypes":["project.created","project.deleted"],"description":"notifies the archive"}`, systemAdmin()))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var res presenter.Webhook
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, "https://example.com/hook", res.URL)
+	assert.Equal(t, []string{"project.created", "project.deleted"}, res.EventTypes)
+	assert.Equal(t, "secret", res.Secret)
+	if assert.NotNil(t, res.CreatedBy) && assert.NotNil(t, res.CreatedBy.Type) {
+		assert.Equal(t, systemAdmin().ID, res.CreatedBy.ID.String())
+		assert.Equal(t, "user", *res.CreatedBy.Type)
+	}
+	assert.Nil(t, res.DeletedBy)
+
+	// the secret is only returned once
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/webhooks/"+res.ID.String(), systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("DELETE", "/v1/webhooks/"+res.ID.String(), systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var list []presenter.Webhook
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/webhooks", systemAdmin()))
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Empty(t, list)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/webhooks?includeDeleted=true", systemAdmin()))
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Len(t, list, 1)
+}
+
+func TestWebhook_Deliveries(t *testing.T) {
+	service := &stubWebhookService{}
+	id := newStubWebhook(t, service)
+
+	pending := service.schedule(id)
+	deadLetter := service.schedule(id)
+	deadLetter.RecordFailure(500, "unexpected status code 500", time.Time{})
+	deadLetter.DeadLetter("gave up after 1 attempts: unexpected status code 500")
+
+	r := mux.NewRouter()
+	handler.MakeWebhookHandlers(r, service)
+
+	var res []presenter.WebhookDelivery
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/webhooks/"+id.String()+"/deliveries", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Len(t, res, 2)
+	assert.Equal(t, "pending", res[0].Status)
+	assert.Nil(t, res[0].LastStatusCode)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("GET", "/v1/webhooks/dead-letters", systemAdmin()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Len(t, res, 1)
+	assert.Equal(t, deadLetter.ID(), res[0].ID)
+	assert.Equal(t, 500, *res[0].LastStatusCode)
+	assert.NotNil(t, res[0].DeadLetteredAt)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("POST", "/v1/webhooks/"+id.String()+"/deliveries/"+deadLetter.ID().String()+"/redeliver", systemAdmin()))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var d presenter.WebhookDelivery
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&d))
+	assert.Equal(t, "pending", d.Status)
+	assert.Equal(t, 0, d.Attempts)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthenticatedRequest("POST", "/v1/webhooks/"+id.String()+"/deliveries/"+pending.ID().String()+"/redeliver", systemAdmin()))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestWebhook_Errors(t *testing.T) {
+	service := &stubWebhookService{}
+	id := newStubWebhook(t, service).String()
+	unknown, _ := valueobject.NewIdentifier()
+
+	r := mux.NewRouter()
+	handler.MakeWebhookHandlers(r, service)
+
+	serviceAccount := &principal.Principal{ID: "b2fbdd3c-5e06-4cb9-9d7d-2f1d1f8b7e61", IsSystemAdmin: true, IsServiceAccount: true}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		code   string
+		field  string
+	}{
+		{"not authenticated", httptest.NewRequest("GET", "/v1/webhooks", nil), http.StatusUnauthorized, "not_authenticated", ""},
+		{"service account", newAuthenticatedRequest("GET", "/v1/webhooks", serviceAccount), http.StatusForbidden, "permission_denied", ""},
+		{"project admin", newAuthenticatedRequest("GET", "/v1/webhooks/dead-letters", &principal.Principal{IsProjectAdmin: true}), http.StatusForbidden, "permission_denied", ""},
+		{"not found", newAuthenticatedRequest("GET", "/v1/webhooks/"+unknown.String(), systemAdmin()), http.StatusNotFound, "webhook_not_found", ""},
+		{"invalid id", newAuthenticatedRequest("GET", "/v1/webhooks/abc", systemAdmin()), http.StatusBadRequest, "invalid_parameter", "id"},
+		{"invalid url", newAuthenticatedJSONRequest("POST", "/v1/webhooks", `{"url":"example.com","eventTypes":["project.created"],"description":"a"}`, systemAdmin()), http.StatusBadRequest, "invalid_url", "url"},
+		{"invalid event type", newAuthenticatedJSONRequest("POST", "/v1/webhooks", `{"url":"https://example.com","eventTypes":["user.created"],"description":"a"}`, systemAdmin()), http.StatusBadRequest, "invalid_event_type", "eventTypes"},
+		{"missing event types", newAuthenticatedJSONRequest("POST", "/v1/webhooks", `{"url":"https://example.com","eventTypes":[],"description":"a"}`, systemAdmin()), http.StatusBadRequest, "missing_event_types", "eventTypes"},
+		{"invalid field", newAuthenticatedJSONRequest("POST", "/v1/webhooks", `{"url":"https://example.com","eventTypes":["project.created"],"description":" "}`, systemAdmin()), http.StatusUnprocessableEntity, "validation_failed", "description"},
+		{"invalid status", newAuthenticatedRequest("GET", "/v1/webhooks/"+id+"/deliveries?status=failed", systemAdmin()), http.StatusBadRequest, "invalid_delivery_status", "status"},
+		{"delivery not found", newAuthenticatedRequest("POST", "/v1/webhooks/"+id+"/deliveries/"+unknown.String()+"/redeliver", systemAdmin()), http.StatusNotFound, "delivery_not_found", ""},
+		{"invalid delivery id", newAuthenticatedRequest("POST", "/v1/webhooks/"+id+"/deliveries/abc/redeliver", systemAdmin()), http.StatusBadRequest, "invalid_parameter", "deliveryId"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, tt.req)
+		assert.Equal(t, tt.status, w.Code, tt.name)
+
+		var res problem.Problem
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&res), tt.name)
+		assert.Equal(t, tt.code, res.Code, tt.name)
+		if tt.field != "" {
+			assert.Len(t, res.Errors, 1, tt.name)
+			assert.Equal(t, tt.field, res.Errors[0].Field, tt.name)
+		}
+	}
+}
+
+// newStubWebhook creates a webhook notified about created projects.
+func newStubWebhook(t *testing.T, service *stubWebhookService) valueobject.Identifier {
+	desc, _ := valueobject.NewDescription("notifies the archive")
+	id, err := service.CreateWebhook(context.Background(), "https://example.com/hook", []webhookEntity.EventType{webhookEntity.EventProjectCreated}, desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
@@ -0,0 +1,134 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package message defines the public schema of the project messages published to message brokers.
+// The schema is versioned independently of the events recorded in the event store,
+// which may change without notice. It is described by the JSON schema in schema/project.v1.json.
+package message
+
+import (
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// attributes shared by all project messages.
+const (
+	SpecVersion = "1.0"
+	Source      = "urn:dasch:admin"
+	ContentType = "application/json"
+)
+
+// types of the project messages. A breaking change of the schema requires a new version suffix.
+const (
+	TypeProjectCreated  = "swiss.dasch.admin.project.created.v1"
+	TypeProjectChanged  = "swiss.dasch.admin.project.changed.v1"
+	TypeProjectDeleted  = "swiss.dasch.admin.project.deleted.v1"
+	TypeProjectRestored = "swiss.dasch.admin.project.restored.v1"
+)
+
+// Message is a project message, structured as a CloudEvents 1.0 event in JSON format.
+// ID is the id of the underlying event and stays the same if the message is published again,
+// so consumers can use it to ignore duplicates. Subject is the id of the project.
+type Message struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Project   `json:"data"`
+}
+
+// Project is the data of a project message.
+// Created and changed messages contain the properties which have been set, deleted and restored messages none of them.
+// Actor is the id of the user or service account causing the message, null if unknown.
+type Project struct {
+	ID          string  `json:"id"`
+	ShortCode   *string `json:"shortCode,omitempty"`
+	ShortName   *string `json:"shortName,omitempty"`
+	LongName    *string `json:"longName,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Actor       *string `json:"actor"`
+}
+
+// NewProjectMessage converts the project event with the provided id into its message.
+// False is returned for events which are not published.
+func NewProjectMessage(id string, ev event.Event) (Message, bool) {
+	var (
+		typ  string
+		at   valueobject.Timestamp
+		by   valueobject.Identifier
+		data Project
+	)
+
+	switch e := ev.(type) {
+	case *event.ProjectCreated:
+		typ, at, by = TypeProjectCreated, e.CreatedAt, e.CreatedBy
+		data = Project{ID: e.ID.String(), ShortCode: str(e.ShortCode), ShortName: str(e.ShortName), LongName: str(e.LongName), Description: str(e.Description)}
+	case *event.ProjectChanged:
+		typ, at, by = TypeProjectChanged, e.ChangedAt, e.ChangedBy
+		data = Project{ID: e.ID.String(), ShortCode: str(e.ShortCode), ShortName: str(e.ShortName), LongName: str(e.LongName), Description: str(e.Description)}
+	case *event.ProjectShortCodeChanged:
+		typ, at, by = TypeProjectChanged, e.ChangedAt, e.ChangedBy
+		data = Project{ID: e.ID.String(), ShortCode: str(e.ShortCode)}
+	case *event.ProjectShortNameChanged:
+		typ, at, by = TypeProjectChanged, e.ChangedAt, e.ChangedBy
+		data = Project{ID: e.ID.String(), ShortName: str(e.ShortName)}
+	case *event.ProjectLongNameChanged:
+		typ, at, by = TypeProjectChanged, e.ChangedAt, e.ChangedBy
+		data = Project{ID: e.ID.String(), LongName: str(e.LongName)}
+	case *event.ProjectDescriptionChanged:
+		typ, at, by = TypeProjectChanged, e.ChangedAt, e.ChangedBy
+		data = Project{ID: e.ID.String(), Description: str(e.Description)}
+	case *event.ProjectDeleted:
+		typ, at, by = TypeProjectDeleted, e.DeletedAt, e.DeletedBy
+		data = Project{ID: e.ID.String()}
+	case *event.ProjectRestored:
+		typ, at, by = TypeProjectRestored, e.RestoredAt, e.RestoredBy
+		data = Project{ID: e.ID.String()}
+	default:
+		return Message{}, false
+	}
+
+	if by != (valueobject.Identifier{}) {
+		actor := by.String()
+		data.Actor = &actor
+	}
+
+	return Message{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          Source,
+		Type:            typ,
+		Subject:         data.ID,
+		Time:            at.Time().UTC(),
+		DataContentType: ContentType,
+		Data:            data,
+	}, true
+}
+
+// str returns a reference to the string value of the value object, or nil if it is empty.
+func str(v interface{ String() string }) *string {
+	s := v.String()
+	if s == "" {
+		return nil
+	}
+	return &s
+}
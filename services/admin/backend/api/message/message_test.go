@@ -0,0 +1,91 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package message_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/message"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProjectMessage(t *testing.T) {
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("081C")
+	sn, _ := valueobject.NewShortName("hdm")
+	ln, _ := valueobject.NewLongName("Historical Dance Manuals")
+	desc, _ := valueobject.NewDescription("Dance manuals of the 17th and 18th century")
+	by, _ := valueobject.NewIdentifier()
+	actor := event.Actor{ID: by, Type: event.ActorUser}
+	p := project.NewAggregate(id, sc, sn, ln, desc, actor)
+	assert.Nil(t, p.ChangeLongName(ln, actor))
+	assert.Nil(t, p.DeleteProject(id, event.Actor{}))
+	assert.Nil(t, p.RestoreProject(actor))
+
+	// every project event is published as a message valid according to the schema
+	schema, err := jsonschema.Compile(filepath.Join("schema", "project.v1.json"))
+	assert.Nil(t, err)
+
+	var messages []message.Message
+	for i, ev := range p.Events() {
+		m, ok := message.NewProjectMessage("C:1/P:"+strconv.Itoa(i+1), ev)
+		assert.True(t, ok)
+		messages = append(messages, m)
+
+		b, err := json.Marshal(m)
+		assert.Nil(t, err)
+		var doc interface{}
+		assert.Nil(t, json.Unmarshal(b, &doc))
+		assert.Nil(t, schema.Validate(doc), string(b))
+	}
+
+	created := messages[0]
+	assert.Equal(t, "1.0", created.SpecVersion)
+	assert.Equal(t, "C:1/P:1", created.ID)
+	assert.Equal(t, message.TypeProjectCreated, created.Type)
+	assert.Equal(t, id.String(), created.Subject)
+	assert.Equal(t, "081C", *created.Data.ShortCode)
+	assert.Equal(t, "Historical Dance Manuals", *created.Data.LongName)
+	assert.Equal(t, by.String(), *created.Data.Actor)
+	assert.Equal(t, time.UTC, created.Time.Location())
+
+	changed := messages[1]
+	assert.Equal(t, message.TypeProjectChanged, changed.Type)
+	assert.Nil(t, changed.Data.ShortCode)
+	assert.Equal(t, "Historical Dance Manuals", *changed.Data.LongName)
+
+	deleted := messages[2]
+	assert.Equal(t, message.TypeProjectDeleted, deleted.Type)
+	assert.Nil(t, deleted.Data.LongName)
+	assert.Nil(t, deleted.Data.Actor)
+
+	restored := messages[3]
+	assert.Equal(t, message.TypeProjectRestored, restored.Type)
+	assert.Equal(t, by.String(), *restored.Data.Actor)
+
+	_, ok := message.NewProjectMessage("C:1/P:4", &event.WebhookCreated{})
+	assert.False(t, ok)
+}
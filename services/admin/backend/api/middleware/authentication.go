@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/serviceaccount"
+)
+
+// Authenticator authenticates requests made either by users holding a JWT issued by Keycloak
+// or by service accounts holding an api key.
+type Authenticator struct {
+	serviceAccounts serviceaccount.UseCase
+}
+
+// NewAuthenticator creates a new authenticator which checks api keys against the provided service accounts.
+func NewAuthenticator(serviceAccounts serviceaccount.UseCase) *Authenticator {
+	return &Authenticator{
+		serviceAccounts: serviceAccounts,
+	}
+}
+
+// Middleware is a mux middleware which authenticates every request once and stores
+// the resulting principal in the request context, where it can be retrieved with principal.FromContext.
+// Requests that cannot be authenticated are rejected before they reach any handler.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(r)
+		if err != nil {
+			// every failure to authenticate is reported as 401, known errors keep their code
+			res, ok := problem.Lookup(err)
+			if !ok || res.Status != http.StatusUnauthorized {
+				res = problem.New(http.StatusUnauthorized, problem.CodeNotAuthenticated, err.Error())
+			}
+			problem.Write(w, r, res)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(principal.NewContext(r.Context(), p)))
+	})
+}
+
+// Authenticate returns the principal of the caller.
+//
+// An api key can be provided in the `X-API-Key` header, or in the `Authorization` header
+// using either the `ApiKey` or the `Bearer` scheme. Every other bearer token is treated as JWT.
+func (a *Authenticator) Authenticate(r *http.Request) (*principal.Principal, error) {
+	key := ExtractAPIKey(r)
+	if key == "" {
+		return ExtractTokenMetadata(r)
+	}
+
+	sa, k, err := a.serviceAccounts.Authenticate(r.Context(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	var scopes []string
+	for _, s := range k.Scopes() {
+		scopes = append(scopes, string(s))
+	}
+
+	return &principal.Principal{
+		ID:               sa.ID().String(),
+		Scopes:           scopes,
+		IsServiceAccount: true,
+	}, nil
+}
+
+// ExtractAPIKey extracts the api key from the request headers.
+// An empty string is returned if the request does not carry an api key.
+func ExtractAPIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	strArr := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(strArr) != 2 {
+		return ""
+	}
+
+	if strings.EqualFold(strArr[0], "ApiKey") || strings.HasPrefix(strArr[1], serviceaccount.KeyPrefix) {
+		return strArr[1]
+	}
+
+	return ""
+}
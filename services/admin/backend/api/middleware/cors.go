@@ -29,7 +29,8 @@ import "net/http"
 func Cors(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, DELETE, PUT")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-API-Key")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag, Link, X-Next-Cursor, X-Total-Count")
 	// w.Header().Set("Content-Type", "application/json")
 	if r.Method == "OPTIONS" {
 		return
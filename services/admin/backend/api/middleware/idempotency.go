@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+)
+
+// IdempotencyKeyHeader is the request header carrying the idempotency key chosen by the client.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed for a retried request.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength is the maximum length of an idempotency key, a UUID fits comfortably.
+const maxIdempotencyKeyLength = 255
+
+// MaxIdempotentBodySize is the maximum size in bytes of the body of a request carrying an idempotency key,
+// which is buffered to fingerprint the request. It allows the largest import file.
+const MaxIdempotentBodySize = 10 << 20
+
+// errIdempotencyKeyTooLong is returned for idempotency keys longer than maxIdempotencyKeyLength.
+var errIdempotencyKeyTooLong = errors.New("the idempotency key must not be longer than 255 characters")
+
+// idempotentResponse is the response stored for an idempotency key.
+type idempotentResponse struct {
+	// fingerprint identifies the request the key has first been used with.
+	fingerprint [sha256.Size]byte
+	// done is false while the first request is being handled.
+	done    bool
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// Idempotency makes retries of mutating requests safe.
+// The response to the first POST, PUT, PATCH or DELETE request carrying an idempotency key is stored per key and principal,
+// and replayed for every retry with the same key until the TTL has passed.
+// Retries with the same key but a different method, path or body are rejected.
+type Idempotency struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	responses map[string]*idempotentResponse
+}
+
+// NewIdempotency creates a new idempotency middleware storing responses in memory for the provided duration.
+// The responses are lost on a restart and not shared between replicas, so retries must reach the same instance.
+func NewIdempotency(ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		ttl:       ttl,
+		responses: map[string]*idempotentResponse{},
+	}
+}
+
+// Middleware is a mux middleware handling the requests carrying an idempotency key.
+// It must run after the Authenticator, so that the keys of different principals do not collide.
+// Responses with a 5xx status code are not stored, so the request can be retried with the same key.
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || !isMutating(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			problem.Write(w, r, problem.InvalidParameter(IdempotencyKeyHeader, errIdempotencyKeyTooLong))
+			return
+		}
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxIdempotentBodySize))
+			// the reader stops at the limit, so a failure after reading that many bytes means the body is too large
+			if err != nil && len(body) == MaxIdempotentBodySize {
+				problem.Write(w, r, problem.New(http.StatusRequestEntityTooLarge, problem.CodeBodyTooLarge, "the body of a request with an idempotency key must not be larger than 10 MiB"))
+				return
+			}
+			if err != nil {
+				problem.Write(w, r, problem.MalformedBody(err))
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		var principalID string
+		if p, ok := principal.FromContext(r.Context()); ok {
+			principalID = p.ID
+		}
+		id := principalID + "\x00" + key
+		fingerprint := sha256.Sum256([]byte(r.Method + " " + r.URL.RequestURI() + "\n" + string(body)))
+
+		res, stored := i.reserve(id, fingerprint)
+		switch {
+		case res.fingerprint != fingerprint:
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, problem.CodeIdempotencyKeyReused, "the idempotency key has already been used for a different request"))
+			return
+		case stored && !res.done:
+			problem.Write(w, r, problem.New(http.StatusConflict, problem.CodeIdempotencyKeyInUse, "a request with the idempotency key is still being processed"))
+			return
+		case stored:
+			replay(w, res)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		completed := false
+		defer func() {
+			if !completed {
+				i.release(id)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+		completed = true
+		i.complete(id, rec)
+	})
+}
+
+// reserve returns the response stored for the id, or stores an empty response for the request with the fingerprint.
+// The second return value reports whether a response has been stored before.
+func (i *Idempotency) reserve(id string, fingerprint [sha256.Size]byte) (*idempotentResponse, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := time.Now()
+	if res, ok := i.responses[id]; ok && (!res.done || now.Before(res.expires)) {
+		return res, true
+	}
+
+	// expired responses are removed whenever a new key is used
+	for k, res := range i.responses {
+		if res.done && !now.Before(res.expires) {
+			delete(i.responses, k)
+		}
+	}
+
+	res := &idempotentResponse{fingerprint: fingerprint}
+	i.responses[id] = res
+	return res, false
+}
+
+// complete stores the recorded response for the id, to be replayed until the TTL has passed.
+func (i *Idempotency) complete(id string, rec *responseRecorder) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	res := i.responses[id]
+	res.status = rec.status
+	if res.status == 0 {
+		res.status = http.StatusOK
+	}
+	res.header = rec.Header().Clone()
+	res.body = rec.body.Bytes()
+	res.expires = time.Now().Add(i.ttl)
+	res.done = true
+}
+
+// release removes the id, so that the request can be retried with the same key.
+func (i *Idempotency) release(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.responses, id)
+}
+
+// replay writes the stored response.
+func replay(w http.ResponseWriter, res *idempotentResponse) {
+	for k, v := range res.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(res.status)
+	_, _ = w.Write(res.body)
+}
+
+// isMutating reports whether requests with the method change the state of a resource.
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// responseRecorder passes the response through to the client and records its status code and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,164 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/middleware"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingHandler responds with the number of requests it has handled and the provided status code.
+func countingHandler(status int) (http.Handler, *int) {
+	n := 0
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"n":%d}`, n)
+	}), &n
+}
+
+// request creates a request of the principal with the idempotency key and body.
+func request(method string, principalID string, key string, body string) *http.Request {
+	r := httptest.NewRequest(method, "/v1/projects", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	return r.WithContext(principal.NewContext(context.Background(), &principal.Principal{ID: principalID}))
+}
+
+// serve serves the request and returns the recorded response.
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestIdempotency_Replay(t *testing.T) {
+	next, n := countingHandler(http.StatusCreated)
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	first := serve(h, request("POST", "alice", "key-1", `{"shortCode":"00F1"}`))
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, "", first.Header().Get(middleware.IdempotentReplayedHeader))
+
+	retry := serve(h, request("POST", "alice", "key-1", `{"shortCode":"00F1"}`))
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, 1, *n)
+}
+
+func TestIdempotency_DifferentPayload(t *testing.T) {
+	next, n := countingHandler(http.StatusCreated)
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	serve(h, request("POST", "alice", "key-1", `{"shortCode":"00F1"}`))
+	w := serve(h, request("POST", "alice", "key-1", `{"shortCode":"00F2"}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"idempotency_key_reused"`)
+	assert.Equal(t, 1, *n)
+}
+
+func TestIdempotency_KeysArePerPrincipal(t *testing.T) {
+	next, n := countingHandler(http.StatusCreated)
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	serve(h, request("POST", "alice", "key-1", `{}`))
+	w := serve(h, request("POST", "bob", "key-1", `{}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "", w.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, 2, *n)
+}
+
+func TestIdempotency_Expiry(t *testing.T) {
+	next, n := countingHandler(http.StatusCreated)
+	h := middleware.NewIdempotency(time.Millisecond).Middleware(next)
+
+	serve(h, request("POST", "alice", "key-1", `{}`))
+	time.Sleep(5 * time.Millisecond)
+	w := serve(h, request("POST", "alice", "key-1", `{"other":true}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, *n)
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	next, n := countingHandler(http.StatusServiceUnavailable)
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	serve(h, request("DELETE", "alice", "key-1", ""))
+	w := serve(h, request("DELETE", "alice", "key-1", ""))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, 2, *n)
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve(h, request("PUT", "alice", "key-1", `{}`)) }()
+	<-started
+
+	w := serve(h, request("PUT", "alice", "key-1", `{}`))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"idempotency_key_in_use"`)
+
+	close(finish)
+	assert.Equal(t, http.StatusNoContent, (<-done).Code)
+	assert.Equal(t, http.StatusNoContent, serve(h, request("PUT", "alice", "key-1", `{}`)).Code)
+}
+
+func TestIdempotency_Ignored(t *testing.T) {
+	next, n := countingHandler(http.StatusOK)
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	// requests without a key and safe requests are always handled
+	serve(h, request("POST", "alice", "", `{}`))
+	serve(h, request("POST", "alice", "", `{}`))
+	serve(h, request("GET", "alice", "key-1", ""))
+	serve(h, request("GET", "alice", "key-1", ""))
+	assert.Equal(t, 4, *n)
+}
+
+func TestIdempotency_KeyTooLong(t *testing.T) {
+	next, n := countingHandler(http.StatusCreated)
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	w := serve(h, request("POST", "alice", strings.Repeat("k", 256), `{}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_parameter"`)
+	assert.Equal(t, 0, *n)
+}
+
+func TestIdempotency_BodyTooLarge(t *testing.T) {
+	next, n := countingHandler(http.StatusCreated)
+	h := middleware.NewIdempotency(time.Hour).Middleware(next)
+
+	w := serve(h, request("POST", "alice", "key-1", strings.Repeat("x", middleware.MaxIdempotentBodySize+1)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"body_too_large"`)
+	assert.Equal(t, 0, *n)
+
+	// a body of the maximum size is accepted
+	w = serve(h, request("POST", "alice", "key-2", strings.Repeat("x", middleware.MaxIdempotentBodySize)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
@@ -1,22 +1,19 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
-	"github.com/golang-jwt/jwt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"strings"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/golang-jwt/jwt"
 )
 
-type UserInfo struct {
-	UserId         string
-	Groups         []interface{}
-	Roles          []interface{}
-	Projects       []string
-	IsSystemAdmin  bool
-	IsProjectAdmin bool
-}
+//ErrInvalidToken the token does not contain the expected claims
+var ErrInvalidToken = errors.New("invalid token provided")
 
 // ExtractToken extracts the JWT token from the header.
 func ExtractToken(r *http.Request) string {
@@ -51,8 +48,8 @@ func VerifyToken(r *http.Request) (*jwt.Token, error) {
 	return token, nil
 }
 
-// ExtractTokenMetadata extracts the data contained within the JWT token and returns an UserInfo object.
-func ExtractTokenMetadata(r *http.Request) (*UserInfo, error) {
+// ExtractTokenMetadata extracts the data contained within the JWT token and returns a Principal.
+func ExtractTokenMetadata(r *http.Request) (*principal.Principal, error) {
 	token, err := VerifyToken(r)
 	if err != nil {
 		return nil, err
@@ -61,17 +58,17 @@ func ExtractTokenMetadata(r *http.Request) (*UserInfo, error) {
 	if ok && token.Valid {
 		userId, ok := claims["sub"].(string)
 		if !ok {
-			return nil, err
+			return nil, ErrInvalidToken
 		}
 
 		groups, ok := claims["groups"].([]interface{})
 		if !ok {
-			return nil, err
+			return nil, ErrInvalidToken
 		}
 
 		roles, ok := claims["roles"].([]interface{})
 		if !ok {
-			return nil, err
+			return nil, ErrInvalidToken
 		}
 
 		var isSysAdmin = false
@@ -98,16 +95,25 @@ func ExtractTokenMetadata(r *http.Request) (*UserInfo, error) {
 			}
 		}
 
-		return &UserInfo{
-			UserId:         userId,
-			Groups:         groups,
-			Roles:          roles,
+		return &principal.Principal{
+			ID:             userId,
+			Groups:         toStrings(groups),
+			Roles:          toStrings(roles),
 			Projects:       projects,
 			IsSystemAdmin:  isSysAdmin,
 			IsProjectAdmin: isProjAdmin,
 		}, nil
 	}
-	return nil, err
+	return nil, ErrInvalidToken
+}
+
+// toStrings converts the values of a JSON array claim to strings.
+func toStrings(values []interface{}) []string {
+	var s []string
+	for _, v := range values {
+		s = append(s, fmt.Sprintf("%v", v))
+	}
+	return s
 }
 
 // getPublicKey returns the public key from the specified file
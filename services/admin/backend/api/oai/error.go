@@ -0,0 +1,55 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package oai
+
+// Error is an error condition of the protocol, reported with a code defined by the protocol.
+type Error struct {
+	Code    string `xml:"code,attr"`
+	Message string `xml:",chardata"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+//ErrBadVerb is returned if the verb is missing, repeated or unknown.
+var ErrBadVerb = &Error{Code: "badVerb", Message: "the verb is missing, repeated or not a verb of OAI-PMH 2.0"}
+
+//ErrBadResumptionToken is returned if the resumption token is malformed.
+var ErrBadResumptionToken = &Error{Code: "badResumptionToken", Message: "the resumption token is invalid"}
+
+//ErrCannotDisseminateFormat is returned if the metadata format is not supported.
+var ErrCannotDisseminateFormat = &Error{Code: "cannotDisseminateFormat", Message: "the metadata format is not supported by the repository"}
+
+//ErrIDDoesNotExist is returned if no project has the identifier.
+var ErrIDDoesNotExist = &Error{Code: "idDoesNotExist", Message: "the identifier is unknown in this repository"}
+
+//ErrNoRecordsMatch is returned if no project matches the arguments of a list request.
+var ErrNoRecordsMatch = &Error{Code: "noRecordsMatch", Message: "no records match the arguments of the request"}
+
+//ErrNoSetHierarchy is returned if sets are requested, since projects are not organised in sets.
+var ErrNoSetHierarchy = &Error{Code: "noSetHierarchy", Message: "the repository does not support sets"}
+
+// codeBadArgument is the code of the errors returned by badArgument.
+const codeBadArgument = "badArgument"
+
+// badArgument returns the error of an illegal, missing or repeated argument.
+func badArgument(message string) *Error {
+	return &Error{Code: codeBadArgument, Message: message}
+}
@@ -0,0 +1,108 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package oai
+
+import (
+	"encoding/xml"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+)
+
+// prefixes of the supported metadata formats.
+const (
+	PrefixDublinCore = "oai_dc"
+	PrefixDataCite   = "datacite"
+)
+
+// format is a supported metadata format, which converts projects into its metadata.
+type format struct {
+	MetadataFormat
+	metadata func(p *project.Aggregate) interface{}
+}
+
+// formats returns the metadata formats supported by the repository. Dublin Core is mandatory for every repository.
+func (r Repository) formats() []format {
+	return []format{
+		{
+			MetadataFormat: MetadataFormat{
+				MetadataPrefix:    PrefixDublinCore,
+				Schema:            "http://www.openarchives.org/OAI/2.0/oai_dc.xsd",
+				MetadataNamespace: "http://www.openarchives.org/OAI/2.0/oai_dc/",
+			},
+			metadata: func(p *project.Aggregate) interface{} { return NewDublinCore(p, r.DataCite.Publisher) },
+		},
+		{
+			MetadataFormat: MetadataFormat{
+				MetadataPrefix:    PrefixDataCite,
+				Schema:            export.DataCiteSchemaLocation,
+				MetadataNamespace: export.DataCiteNamespace,
+			},
+			metadata: func(p *project.Aggregate) interface{} { return export.NewDataCiteResource(p, r.DataCite) },
+		},
+	}
+}
+
+// format returns the metadata format with the prefix.
+func (r Repository) format(prefix string) (format, bool) {
+	for _, f := range r.formats() {
+		if f.MetadataPrefix == prefix {
+			return f, true
+		}
+	}
+	return format{}, false
+}
+
+// DublinCore is the unqualified Dublin Core metadata of a project, see http://www.openarchives.org/OAI/2.0/oai_dc.xsd.
+// The elements are written with the conventional prefixes, hence the namespaces are declared as attributes.
+type DublinCore struct {
+	XMLName        xml.Name `xml:"oai_dc:dc"`
+	OAIDC          string   `xml:"xmlns:oai_dc,attr"`
+	DC             string   `xml:"xmlns:dc,attr"`
+	XSI            string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	Titles         []string `xml:"dc:title"`
+	Subjects       []string `xml:"dc:subject"`
+	Identifiers    []string `xml:"dc:identifier"`
+	Description    string   `xml:"dc:description"`
+	Publisher      string   `xml:"dc:publisher,omitempty"`
+	Date           string   `xml:"dc:date,omitempty"`
+	Type           string   `xml:"dc:type"`
+}
+
+// NewDublinCore converts the project aggregate into Dublin Core. The date is the date the project was created,
+// the keywords of the project are its subjects.
+func NewDublinCore(p *project.Aggregate, publisher string) DublinCore {
+	dc := DublinCore{
+		OAIDC:          "http://www.openarchives.org/OAI/2.0/oai_dc/",
+		DC:             "http://purl.org/dc/elements/1.1/",
+		XSI:            xsiNamespace,
+		SchemaLocation: "http://www.openarchives.org/OAI/2.0/oai_dc/ http://www.openarchives.org/OAI/2.0/oai_dc.xsd",
+		Titles:         []string{p.LongName().String(), p.ShortName().String()},
+		Subjects:       p.Keywords(),
+		Identifiers:    []string{string(presenter.ProjectIRI(p)), p.ShortCode().String()},
+		Description:    p.Description().String(),
+		Publisher:      publisher,
+		Type:           "Collection",
+	}
+	if !p.CreatedAt().Time().IsZero() {
+		dc.Date = p.CreatedAt().Time().UTC().Format("2006-01-02")
+	}
+	return dc
+}
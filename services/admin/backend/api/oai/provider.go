@@ -0,0 +1,421 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package oai
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// DefaultPageSize is the number of headers or records of a page of an incomplete list.
+const DefaultPageSize = 100
+
+// layouts of the datestamps. Datestamps are written with seconds granularity, but days are accepted as well.
+const (
+	granularity     = "YYYY-MM-DDThh:mm:ssZ"
+	secondsLayout   = "2006-01-02T15:04:05Z"
+	dayLayout       = "2006-01-02"
+	deletedPersists = "persistent"
+)
+
+// Repository describes the repository the provider serves.
+type Repository struct {
+	// Name is the human readable name of the repository.
+	Name string
+	// BaseURL is the URL the provider is served at.
+	BaseURL string
+	// AdminEmail is the address of the administrator of the repository.
+	AdminEmail string
+	// Namespace is the namespace of the record identifiers, which are oai:<Namespace>:<project id>.
+	Namespace string
+	// DataCite are the options of the DataCite metadata format. Its publisher is used for Dublin Core as well.
+	DataCite export.DataCiteOptions
+}
+
+// Provider is an OAI-PMH data provider, whose items are the projects.
+// Deleted projects are kept in the event store, so they are reported as deleted records persistently.
+type Provider struct {
+	service    project.UseCase
+	repository Repository
+	// PageSize is the maximum number of headers or records returned by a single list request.
+	PageSize int
+}
+
+// NewProvider creates a provider for the projects of the service.
+func NewProvider(service project.UseCase, repository Repository) *Provider {
+	return &Provider{
+		service:    service,
+		repository: repository,
+		PageSize:   DefaultPageSize,
+	}
+}
+
+// arguments lists the arguments allowed for each verb, the required ones are true.
+// A resumption token is exclusive, it must not be combined with other arguments.
+var arguments = map[string]map[string]bool{
+	VerbIdentify:            {},
+	VerbListMetadataFormats: {"identifier": false},
+	VerbListSets:            {"resumptionToken": false},
+	VerbGetRecord:           {"identifier": true, "metadataPrefix": true},
+	VerbListIdentifiers:     {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+	VerbListRecords:         {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+}
+
+// ServeHTTP answers requests issued with GET or POST. Protocol errors are part of the response,
+// only unexpected errors are reported as problem, see problem.FromError.
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res := &Response{
+		XSI:            xsiNamespace,
+		SchemaLocation: Namespace + " " + SchemaLocation,
+		ResponseDate:   time.Now().UTC().Format(secondsLayout),
+		Request:        Request{BaseURL: p.repository.BaseURL},
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(30)*time.Second)
+	defer cancel()
+
+	err := r.ParseForm()
+	if err == nil {
+		err = p.handle(ctx, r.Form, res)
+	} else {
+		err = badArgument("the arguments cannot be parsed")
+	}
+
+	var oaiErr *Error
+	if errors.As(err, &oaiErr) {
+		res.Errors = []Error{*oaiErr}
+		// the arguments must not be echoed if they are invalid
+		if oaiErr.Code == ErrBadVerb.Code || oaiErr.Code == codeBadArgument {
+			res.Request = Request{BaseURL: p.repository.BaseURL}
+		}
+	} else if err != nil {
+		problem.Write(w, r, problem.FromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		log.Println(err.Error())
+		return
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// handle validates the arguments and answers the verb.
+func (p *Provider) handle(ctx context.Context, args url.Values, res *Response) error {
+	verbs := args["verb"]
+	if len(verbs) != 1 {
+		return ErrBadVerb
+	}
+	verb := verbs[0]
+	allowed, ok := arguments[verb]
+	if !ok {
+		return ErrBadVerb
+	}
+	if err := validateArguments(args, allowed); err != nil {
+		return err
+	}
+
+	res.Request = Request{
+		Verb:            verb,
+		Identifier:      args.Get("identifier"),
+		MetadataPrefix:  args.Get("metadataPrefix"),
+		From:            args.Get("from"),
+		Until:           args.Get("until"),
+		Set:             args.Get("set"),
+		ResumptionToken: args.Get("resumptionToken"),
+		BaseURL:         p.repository.BaseURL,
+	}
+
+	var err error
+	switch verb {
+	case VerbIdentify:
+		res.Identify, err = p.identify(ctx)
+	case VerbListMetadataFormats:
+		res.ListMetadataFormats, err = p.listMetadataFormats(ctx, args.Get("identifier"))
+	case VerbListSets:
+		err = ErrNoSetHierarchy
+	case VerbGetRecord:
+		res.GetRecord, err = p.getRecord(ctx, args.Get("identifier"), args.Get("metadataPrefix"))
+	case VerbListIdentifiers:
+		var records []Record
+		var rt *ResumptionToken
+		records, rt, err = p.list(ctx, args)
+		if err == nil {
+			res.ListIdentifiers = &ListIdentifiers{ResumptionToken: rt}
+			for _, r := range records {
+				res.ListIdentifiers.Headers = append(res.ListIdentifiers.Headers, r.Header)
+			}
+		}
+	case VerbListRecords:
+		var records []Record
+		var rt *ResumptionToken
+		records, rt, err = p.list(ctx, args)
+		if err == nil {
+			res.ListRecords = &ListRecords{Records: records, ResumptionToken: rt}
+		}
+	}
+	return err
+}
+
+// validateArguments ensures that only the allowed arguments are present, each at most once, and the required ones are not missing.
+func validateArguments(args url.Values, allowed map[string]bool) error {
+	for name, values := range args {
+		if name == "verb" {
+			continue
+		}
+		if _, ok := allowed[name]; !ok {
+			return badArgument("the argument " + name + " is not allowed")
+		}
+		if len(values) != 1 {
+			return badArgument("the argument " + name + " must not be repeated")
+		}
+	}
+
+	if _, ok := args["resumptionToken"]; ok {
+		if len(args) != 2 {
+			return badArgument("the argument resumptionToken is exclusive")
+		}
+		return nil
+	}
+
+	for name, required := range allowed {
+		if _, ok := args[name]; required && !ok {
+			return badArgument("the argument " + name + " is missing")
+		}
+	}
+	return nil
+}
+
+// identify describes the repository. The earliest datestamp is the one of the record modified first.
+func (p *Provider) identify(ctx context.Context) (*Identify, error) {
+	projects, err := p.service.ListProjects(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	earliest := time.Now()
+	for _, pr := range projects {
+		if modified := project.ModifiedAt(pr).Time(); modified.Before(earliest) {
+			earliest = modified
+		}
+	}
+
+	return &Identify{
+		RepositoryName:    p.repository.Name,
+		BaseURL:           p.repository.BaseURL,
+		ProtocolVersion:   ProtocolVersion,
+		AdminEmail:        p.repository.AdminEmail,
+		EarliestDatestamp: earliest.UTC().Format(secondsLayout),
+		DeletedRecord:     deletedPersists,
+		Granularity:       granularity,
+	}, nil
+}
+
+// listMetadataFormats lists the formats of the repository. Every project is available in all formats.
+func (p *Provider) listMetadataFormats(ctx context.Context, identifier string) (*ListMetadataFormats, error) {
+	if identifier != "" {
+		if _, err := p.load(ctx, identifier); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &ListMetadataFormats{}
+	for _, f := range p.repository.formats() {
+		res.MetadataFormats = append(res.MetadataFormats, f.MetadataFormat)
+	}
+	return res, nil
+}
+
+// getRecord returns the record of the project in the format.
+func (p *Provider) getRecord(ctx context.Context, identifier string, prefix string) (*GetRecord, error) {
+	f, ok := p.repository.format(prefix)
+	if !ok {
+		return nil, ErrCannotDisseminateFormat
+	}
+
+	pr, err := p.load(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetRecord{Record: p.record(pr, f)}, nil
+}
+
+// list returns a page of the records selected by the arguments or by the resumption token.
+// Projects are listed in the order they were created, so that new projects do not shift the pages.
+func (p *Provider) list(ctx context.Context, args url.Values) ([]Record, *ResumptionToken, error) {
+	if args.Get("set") != "" {
+		return nil, nil, ErrNoSetHierarchy
+	}
+
+	t := token{MetadataPrefix: args.Get("metadataPrefix"), From: args.Get("from"), Until: args.Get("until")}
+	if rt := args.Get("resumptionToken"); rt != "" {
+		var err error
+		if t, err = decodeToken(rt); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	f, ok := p.repository.format(t.MetadataPrefix)
+	if !ok {
+		return nil, nil, ErrCannotDisseminateFormat
+	}
+
+	from, until, err := parseRange(t.From, t.Until)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := p.service.QueryProjects(ctx, project.Query{
+		Limit:         p.PageSize,
+		After:         t.After,
+		Sort:          project.SortByCreatedAt,
+		Status:        project.StatusAll,
+		ModifiedFrom:  from,
+		ModifiedUntil: until,
+	})
+	if errors.Is(err, projectEntity.ErrInvalidCursor) {
+		return nil, nil, ErrBadResumptionToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(res.Projects) == 0 {
+		return nil, nil, ErrNoRecordsMatch
+	}
+
+	var records []Record
+	for i := range res.Projects {
+		records = append(records, p.record(&res.Projects[i], f))
+	}
+
+	// the resumption token is omitted if the list is complete, and empty on the last page of an incomplete list
+	var rt *ResumptionToken
+	if res.NextCursor != "" || t.After != "" {
+		rt = &ResumptionToken{CompleteListSize: res.Total, Cursor: t.Cursor}
+		if res.NextCursor != "" {
+			next := t
+			next.After = res.NextCursor
+			next.Cursor = t.Cursor + len(res.Projects)
+			rt.Value = next.encode()
+		}
+	}
+
+	return records, rt, nil
+}
+
+// record returns the record of the project, deleted projects only consist of the header.
+func (p *Provider) record(pr *projectEntity.Aggregate, f format) Record {
+	h := Header{
+		Identifier: p.identifier(pr.ID()),
+		Datestamp:  project.ModifiedAt(*pr).Time().UTC().Format(secondsLayout),
+	}
+	if !pr.DeletedAt().Time().IsZero() {
+		h.Status = "deleted"
+		return Record{Header: h}
+	}
+	return Record{Header: h, Metadata: &Metadata{Content: f.metadata(pr)}}
+}
+
+// identifier returns the OAI identifier of the project.
+func (p *Provider) identifier(id valueobject.Identifier) string {
+	return "oai:" + p.repository.Namespace + ":" + id.String()
+}
+
+// load returns the project with the OAI identifier, including deleted projects.
+func (p *Provider) load(ctx context.Context, identifier string) (*projectEntity.Aggregate, error) {
+	prefix := "oai:" + p.repository.Namespace + ":"
+	if !strings.HasPrefix(identifier, prefix) {
+		return nil, ErrIDDoesNotExist
+	}
+	id, err := valueobject.IdentifierFromBytes([]byte(strings.TrimPrefix(identifier, prefix)))
+	if err != nil {
+		return nil, ErrIDDoesNotExist
+	}
+
+	pr, err := p.service.GetProject(ctx, id)
+	if errors.Is(err, projectEntity.ErrProjectNotFound) || err == nil && (pr == nil || pr.ID() != id) {
+		return nil, ErrIDDoesNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// parseRange parses the from and until arguments, which must have the same granularity.
+// Both bounds are inclusive, so until covers the whole day or second.
+func parseRange(from string, until string) (time.Time, time.Time, error) {
+	f, fDay, err := parseDatestamp(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	u, uDay, err := parseDatestamp(until)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if from != "" && until != "" {
+		if fDay != uDay {
+			return time.Time{}, time.Time{}, badArgument("the arguments from and until must have the same granularity")
+		}
+		if f.After(u) {
+			return time.Time{}, time.Time{}, badArgument("the argument from must not be after until")
+		}
+	}
+
+	if until != "" {
+		if uDay {
+			u = u.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		} else {
+			u = u.Add(time.Second - time.Nanosecond)
+		}
+	}
+	return f, u, nil
+}
+
+// parseDatestamp parses a datestamp of either granularity and reports whether it is a day.
+func parseDatestamp(s string) (time.Time, bool, error) {
+	if s == "" {
+		return time.Time{}, false, nil
+	}
+	if t, err := time.Parse(secondsLayout, s); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse(dayLayout, s); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, badArgument("the datestamp " + s + " must be formatted as " + granularity + " or YYYY-MM-DD")
+}
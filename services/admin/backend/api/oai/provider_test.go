@@ -0,0 +1,236 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package oai_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/oai"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+var repository = oai.Repository{
+	Name:       "DaSCH Service Platform",
+	BaseURL:    "http://localhost:8080/v1/oai",
+	AdminEmail: "info@dasch.swiss",
+	Namespace:  "admin.dasch.swiss",
+	DataCite:   export.DataCiteOptions{DOIPrefix: "10.5072", Publisher: "DaSCH"},
+}
+
+// newTestProvider creates a provider for a service containing one project per short code, the first one being deleted.
+func newTestProvider(t *testing.T, shortCodes ...string) (*oai.Provider, []valueobject.Identifier) {
+	service := project.NewService(newStubRepository())
+
+	var ids []valueobject.Identifier
+	for _, code := range shortCodes {
+		sc, _ := valueobject.NewShortCode(code)
+		sn, _ := valueobject.NewShortName("name " + code)
+		ln, _ := valueobject.NewLongName("long name " + code)
+		desc, _ := valueobject.NewDescription("description " + code)
+		id, err := service.CreateProject(context.Background(), sc, sn, ln, desc)
+		assert.Nil(t, err)
+		ids = append(ids, id)
+	}
+	_, err := service.DeleteProject(context.Background(), ids[0])
+	assert.Nil(t, err)
+
+	return oai.NewProvider(service, repository), ids
+}
+
+// harvest issues the request and decodes the response.
+func harvest(t *testing.T, p *oai.Provider, args url.Values) (oai.Response, string) {
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/v1/oai?"+args.Encode(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	var res oai.Response
+	assert.Nil(t, xml.Unmarshal([]byte(body), &res), body)
+	return res, body
+}
+
+func TestProvider_Identify(t *testing.T) {
+	p, _ := newTestProvider(t, "0001", "0002")
+
+	res, _ := harvest(t, p, url.Values{"verb": {"Identify"}})
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, "Identify", res.Request.Verb)
+	assert.Equal(t, repository.BaseURL, res.Request.BaseURL)
+	assert.Equal(t, "2.0", res.Identify.ProtocolVersion)
+	assert.Equal(t, "persistent", res.Identify.DeletedRecord)
+	assert.Equal(t, "YYYY-MM-DDThh:mm:ssZ", res.Identify.Granularity)
+	_, err := time.Parse("2006-01-02T15:04:05Z", res.Identify.EarliestDatestamp)
+	assert.Nil(t, err)
+}
+
+func TestProvider_ListMetadataFormats(t *testing.T) {
+	p, ids := newTestProvider(t, "0001", "0002")
+
+	res, _ := harvest(t, p, url.Values{"verb": {"ListMetadataFormats"}, "identifier": {"oai:admin.dasch.swiss:" + ids[1].String()}})
+	assert.Empty(t, res.Errors)
+	assert.Len(t, res.ListMetadataFormats.MetadataFormats, 2)
+	assert.Equal(t, "oai_dc", res.ListMetadataFormats.MetadataFormats[0].MetadataPrefix)
+	assert.Equal(t, "datacite", res.ListMetadataFormats.MetadataFormats[1].MetadataPrefix)
+}
+
+func TestProvider_GetRecord(t *testing.T) {
+	p, ids := newTestProvider(t, "0001", "0002")
+
+	res, body := harvest(t, p, url.Values{"verb": {"GetRecord"}, "metadataPrefix": {"oai_dc"}, "identifier": {"oai:admin.dasch.swiss:" + ids[1].String()}})
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, "oai:admin.dasch.swiss:"+ids[1].String(), res.GetRecord.Record.Header.Identifier)
+	assert.Empty(t, res.GetRecord.Record.Header.Status)
+	assert.Contains(t, body, `<oai_dc:dc xmlns:oai_dc="http://www.openarchives.org/OAI/2.0/oai_dc/" xmlns:dc="http://purl.org/dc/elements/1.1/"`)
+	assert.Contains(t, body, "<dc:title>long name 0002</dc:title>")
+	assert.Contains(t, body, "<dc:identifier>http://rdfh.ch/projects/"+ids[1].String()+"</dc:identifier>")
+	assert.Contains(t, body, "<dc:publisher>DaSCH</dc:publisher>")
+
+	res, body = harvest(t, p, url.Values{"verb": {"GetRecord"}, "metadataPrefix": {"datacite"}, "identifier": {"oai:admin.dasch.swiss:" + ids[1].String()}})
+	assert.Empty(t, res.Errors)
+	assert.Contains(t, body, `<resource xmlns="http://datacite.org/schema/kernel-4"`)
+
+	// deleted projects are reported as deleted records without metadata
+	res, body = harvest(t, p, url.Values{"verb": {"GetRecord"}, "metadataPrefix": {"oai_dc"}, "identifier": {"oai:admin.dasch.swiss:" + ids[0].String()}})
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, "deleted", res.GetRecord.Record.Header.Status)
+	assert.Nil(t, res.GetRecord.Record.Metadata)
+	assert.NotContains(t, body, "<metadata>")
+}
+
+func TestProvider_ListRecords_ResumptionToken(t *testing.T) {
+	p, ids := newTestProvider(t, "0001", "0002", "0003")
+	p.PageSize = 2
+
+	res, _ := harvest(t, p, url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"oai_dc"}})
+	assert.Empty(t, res.Errors)
+	assert.Len(t, res.ListRecords.Records, 2)
+	assert.Equal(t, "oai:admin.dasch.swiss:"+ids[0].String(), res.ListRecords.Records[0].Header.Identifier)
+	assert.Equal(t, "deleted", res.ListRecords.Records[0].Header.Status)
+	assert.Equal(t, 3, res.ListRecords.ResumptionToken.CompleteListSize)
+	assert.Equal(t, 0, res.ListRecords.ResumptionToken.Cursor)
+	assert.NotEmpty(t, res.ListRecords.ResumptionToken.Value)
+
+	res, _ = harvest(t, p, url.Values{"verb": {"ListRecords"}, "resumptionToken": {res.ListRecords.ResumptionToken.Value}})
+	assert.Empty(t, res.Errors)
+	assert.Len(t, res.ListRecords.Records, 1)
+	assert.Equal(t, "oai:admin.dasch.swiss:"+ids[2].String(), res.ListRecords.Records[0].Header.Identifier)
+	assert.Equal(t, 2, res.ListRecords.ResumptionToken.Cursor)
+	assert.Empty(t, res.ListRecords.ResumptionToken.Value)
+
+	// complete lists have no resumption token
+	p.PageSize = 10
+	res, _ = harvest(t, p, url.Values{"verb": {"ListIdentifiers"}, "metadataPrefix": {"datacite"}})
+	assert.Empty(t, res.Errors)
+	assert.Len(t, res.ListIdentifiers.Headers, 3)
+	assert.Nil(t, res.ListIdentifiers.ResumptionToken)
+}
+
+func TestProvider_ListIdentifiers_SelectiveHarvesting(t *testing.T) {
+	p, _ := newTestProvider(t, "0001", "0002")
+
+	today := time.Now().UTC().Format("2006-01-02")
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+
+	res, _ := harvest(t, p, url.Values{"verb": {"ListIdentifiers"}, "metadataPrefix": {"oai_dc"}, "from": {today}, "until": {today}})
+	assert.Empty(t, res.Errors)
+	assert.Len(t, res.ListIdentifiers.Headers, 2)
+
+	res, _ = harvest(t, p, url.Values{"verb": {"ListIdentifiers"}, "metadataPrefix": {"oai_dc"}, "from": {tomorrow}})
+	assert.Equal(t, "noRecordsMatch", res.Errors[0].Code)
+
+	res, _ = harvest(t, p, url.Values{"verb": {"ListIdentifiers"}, "metadataPrefix": {"oai_dc"}, "until": {yesterday + "T23:59:59Z"}})
+	assert.Equal(t, "noRecordsMatch", res.Errors[0].Code)
+}
+
+func TestProvider_Errors(t *testing.T) {
+	p, ids := newTestProvider(t, "0001", "0002")
+	identifier := "oai:admin.dasch.swiss:" + ids[1].String()
+
+	tests := []struct {
+		name string
+		args url.Values
+		code string
+	}{
+		{"missing verb", url.Values{}, "badVerb"},
+		{"unknown verb", url.Values{"verb": {"ListEverything"}}, "badVerb"},
+		{"repeated verb", url.Values{"verb": {"Identify", "Identify"}}, "badVerb"},
+		{"illegal argument", url.Values{"verb": {"Identify"}, "identifier": {identifier}}, "badArgument"},
+		{"missing argument", url.Values{"verb": {"GetRecord"}, "identifier": {identifier}}, "badArgument"},
+		{"exclusive resumption token", url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"oai_dc"}, "resumptionToken": {"abc"}}, "badArgument"},
+		{"invalid date", url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"oai_dc"}, "from": {"yesterday"}}, "badArgument"},
+		{"mixed granularity", url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"oai_dc"}, "from": {"2021-01-01"}, "until": {"2021-02-01T00:00:00Z"}}, "badArgument"},
+		{"from after until", url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"oai_dc"}, "from": {"2021-02-01"}, "until": {"2021-01-01"}}, "badArgument"},
+		{"unknown format", url.Values{"verb": {"GetRecord"}, "metadataPrefix": {"marc21"}, "identifier": {identifier}}, "cannotDisseminateFormat"},
+		{"unknown identifier", url.Values{"verb": {"GetRecord"}, "metadataPrefix": {"oai_dc"}, "identifier": {"oai:admin.dasch.swiss:" + valueobject.Identifier{}.String()}}, "idDoesNotExist"},
+		{"foreign identifier", url.Values{"verb": {"GetRecord"}, "metadataPrefix": {"oai_dc"}, "identifier": {"oai:example.org:1"}}, "idDoesNotExist"},
+		{"sets", url.Values{"verb": {"ListSets"}}, "noSetHierarchy"},
+		{"set", url.Values{"verb": {"ListIdentifiers"}, "metadataPrefix": {"oai_dc"}, "set": {"a"}}, "noSetHierarchy"},
+		{"bad resumption token", url.Values{"verb": {"ListIdentifiers"}, "resumptionToken": {"abc"}}, "badResumptionToken"},
+	}
+
+	for _, tt := range tests {
+		res, _ := harvest(t, p, tt.args)
+		if assert.Len(t, res.Errors, 1, tt.name) {
+			assert.Equal(t, tt.code, res.Errors[0].Code, tt.name)
+		}
+		// the arguments are not echoed for bad verbs and arguments
+		if tt.code == "badVerb" || tt.code == "badArgument" {
+			assert.Empty(t, res.Request.Verb, tt.name)
+		}
+	}
+}
+
+func TestProvider_Post(t *testing.T) {
+	p, _ := newTestProvider(t, "0001", "0002")
+
+	req := httptest.NewRequest("POST", "/v1/oai", strings.NewReader("verb=ListIdentifiers&metadataPrefix=oai_dc"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, req)
+
+	var res oai.Response
+	assert.Nil(t, xml.NewDecoder(w.Body).Decode(&res))
+	assert.Empty(t, res.Errors)
+	assert.Len(t, res.ListIdentifiers.Headers, 2)
+}
+
+func TestNewDublinCore_Keywords(t *testing.T) {
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Bernoullis and Euler")
+	p := projectEntity.NewMigratedAggregate(id, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", sc, sn, ln, desc, []string{"mathematics", "letters"}, valueobject.NewTimestamp())
+
+	b, err := xml.Marshal(oai.NewDublinCore(p, "DaSCH"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "<dc:subject>mathematics</dc:subject><dc:subject>letters</dc:subject>")
+}
@@ -0,0 +1,51 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package oai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// token is the state of an incomplete list, which is handed to the harvester as resumption token.
+// It contains the arguments of the original request, so that the provider does not need to keep any state.
+type token struct {
+	MetadataPrefix string `json:"m"`
+	From           string `json:"f,omitempty"`
+	Until          string `json:"u,omitempty"`
+	// After is the cursor of the project query the next page starts after.
+	After string `json:"a"`
+	// Cursor is the number of records returned by the previous pages.
+	Cursor int `json:"c"`
+}
+
+// encode returns the resumption token.
+func (t token) encode() string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeToken parses the resumption token.
+func decodeToken(s string) (token, error) {
+	var t token
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(b, &t) != nil || t.MetadataPrefix == "" || t.After == "" || t.Cursor < 0 {
+		return token{}, ErrBadResumptionToken
+	}
+	return t, nil
+}
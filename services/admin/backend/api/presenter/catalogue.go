@@ -0,0 +1,54 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package presenter
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// PublicProject contains the publicly available metadata of a project.
+// Only add fields here which may be shown to anonymous users.
+type PublicProject struct {
+	ID          valueobject.Identifier `json:"id"`
+	ShortCode   string                 `json:"shortCode"`
+	ShortName   string                 `json:"shortName"`
+	LongName    string                 `json:"longName"`
+	Description string                 `json:"description"`
+	Keywords    []string               `json:"keywords"`
+}
+
+// PublicProjectList is a page of the public project catalogue.
+type PublicProjectList struct {
+	Projects []PublicProject `json:"projects"`
+	Total    int             `json:"total"`
+	Limit    int             `json:"limit"`
+	Offset   int             `json:"offset"`
+}
+
+// NewPublicProject converts the project aggregate into its public presenter.
+func NewPublicProject(p *project.Aggregate) PublicProject {
+	return PublicProject{
+		ID:          p.ID(),
+		ShortCode:   p.ShortCode().String(),
+		ShortName:   p.ShortName().String(),
+		LongName:    p.LongName().String(),
+		Description: p.Description().String(),
+		Keywords:    keywords(p),
+	}
+}
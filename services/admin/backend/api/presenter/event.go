@@ -0,0 +1,68 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package presenter
+
+import (
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// types of the project events, every change of a project property is reported as changed.
+const (
+	ProjectEventCreated  = "created"
+	ProjectEventChanged  = "changed"
+	ProjectEventDeleted  = "deleted"
+	ProjectEventRestored = "restored"
+)
+
+// ProjectEvent notifies clients that a project has been created, changed, deleted or restored.
+// Event is the name of the domain event, e.g. ProjectShortNameChanged, and Project is the state of the project after it.
+type ProjectEvent struct {
+	Type      string                 `json:"type"`
+	Event     string                 `json:"event"`
+	ProjectID valueobject.Identifier `json:"projectId"`
+	At        *time.Time             `json:"at"`
+	By        *Actor                 `json:"by"`
+	Project   *ProjectV2             `json:"project,omitempty"`
+}
+
+// NewProjectEvent converts the domain event into its presenter, nil is returned for events not concerning a project.
+// The state of the project is not set.
+func NewProjectEvent(ev event.Event) *ProjectEvent {
+	switch e := ev.(type) {
+	case *event.ProjectCreated:
+		return &ProjectEvent{Type: ProjectEventCreated, Event: "ProjectCreated", ProjectID: e.ID, At: timestamp(e.CreatedAt), By: typedActor(event.Actor{ID: e.CreatedBy, Type: e.CreatedByType})}
+	case *event.ProjectChanged:
+		return &ProjectEvent{Type: ProjectEventChanged, Event: "ProjectChanged", ProjectID: e.ID, At: timestamp(e.ChangedAt), By: typedActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectShortCodeChanged:
+		return &ProjectEvent{Type: ProjectEventChanged, Event: "ProjectShortCodeChanged", ProjectID: e.ID, At: timestamp(e.ChangedAt), By: typedActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectShortNameChanged:
+		return &ProjectEvent{Type: ProjectEventChanged, Event: "ProjectShortNameChanged", ProjectID: e.ID, At: timestamp(e.ChangedAt), By: typedActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectLongNameChanged:
+		return &ProjectEvent{Type: ProjectEventChanged, Event: "ProjectLongNameChanged", ProjectID: e.ID, At: timestamp(e.ChangedAt), By: typedActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectDescriptionChanged:
+		return &ProjectEvent{Type: ProjectEventChanged, Event: "ProjectDescriptionChanged", ProjectID: e.ID, At: timestamp(e.ChangedAt), By: typedActor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectDeleted:
+		return &ProjectEvent{Type: ProjectEventDeleted, Event: "ProjectDeleted", ProjectID: e.ID, At: timestamp(e.DeletedAt), By: typedActor(event.Actor{ID: e.DeletedBy, Type: e.DeletedByType})}
+	case *event.ProjectRestored:
+		return &ProjectEvent{Type: ProjectEventRestored, Event: "ProjectRestored", ProjectID: e.ID, At: timestamp(e.RestoredAt), By: typedActor(event.Actor{ID: e.RestoredBy, Type: e.RestoredByType})}
+	}
+	return nil
+}
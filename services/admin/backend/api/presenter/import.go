@@ -0,0 +1,88 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package presenter
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// ImportReport data describing the outcome of a project import.
+// In dry-run mode, no project is created and valid rows are reported with the status `valid`.
+type ImportReport struct {
+	DryRun  bool        `json:"dryRun"`
+	Total   int         `json:"total"`
+	Valid   int         `json:"valid"`
+	Created int         `json:"created"`
+	Invalid int         `json:"invalid"`
+	Failed  int         `json:"failed"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+// ImportRow data describing the outcome of importing a single row.
+// ID is only set for created projects, Error only for invalid and failed rows.
+type ImportRow struct {
+	Line      int                     `json:"line"`
+	ShortCode string                  `json:"shortCode"`
+	Status    string                  `json:"status"`
+	ID        *valueobject.Identifier `json:"id"`
+	Error     *problem.Problem        `json:"error,omitempty"`
+}
+
+// NewImportReport converts the results of an import into its presenter.
+// Errors are reported as problems, so that every row carries a stable code.
+func NewImportReport(dryRun bool, results []project.ImportResult) ImportReport {
+	res := ImportReport{
+		DryRun: dryRun,
+		Total:  len(results),
+		Rows:   []ImportRow{},
+	}
+
+	for _, r := range results {
+		row := ImportRow{
+			Line:      r.Line,
+			ShortCode: r.ShortCode,
+			Status:    string(r.Status),
+		}
+		if r.Status == project.ImportCreated {
+			id := r.ID
+			row.ID = &id
+		}
+		if r.Err != nil {
+			row.Error = problem.FromError(r.Err)
+		}
+
+		switch r.Status {
+		case project.ImportValid:
+			res.Valid++
+		case project.ImportCreated:
+			res.Valid++
+			res.Created++
+		case project.ImportInvalid:
+			res.Invalid++
+		case project.ImportFailed:
+			res.Valid++
+			res.Failed++
+		}
+
+		res.Rows = append(res.Rows, row)
+	}
+
+	return res
+}
@@ -18,16 +18,23 @@
 package presenter
 
 import (
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 )
 
-// Project data used as the result for any project operation.
+// Project data used as the result for any project operation of the v1 api.
+// Timestamps and actors are strings, values which have not been set are the string "null", see NullifyJsonProps.
+// It is kept unchanged for existing clients, ProjectV2 is returned by the v2 api.
 type Project struct {
 	ID          valueobject.Identifier `json:"id"`
 	ShortCode   string                 `json:"shortCode"`
 	ShortName   string                 `json:"shortName"`
 	LongName    string                 `json:"longName"`
 	Description string                 `json:"description"`
+	Keywords    []string               `json:"keywords"`
 	CreatedAt   string                 `json:"createdAt"`
 	CreatedBy   string                 `json:"createdBy"`
 	ChangedAt   string                 `json:"changedAt"`
@@ -55,3 +62,121 @@ func (p *Project) NullifyJsonProps() Project {
 
 	return *p
 }
+
+// NewProject converts the project aggregate into its v1 presenter.
+func NewProject(p *project.Aggregate) Project {
+	res := Project{
+		ID:          p.ID(),
+		ShortCode:   p.ShortCode().String(),
+		ShortName:   p.ShortName().String(),
+		LongName:    p.LongName().String(),
+		Description: p.Description().String(),
+		Keywords:    keywords(p),
+		CreatedAt:   p.CreatedAt().String(),
+		CreatedBy:   p.CreatedBy().String(),
+		ChangedAt:   p.ChangedAt().String(),
+		ChangedBy:   p.ChangedBy().String(),
+		DeletedAt:   p.DeletedAt().String(),
+		DeletedBy:   p.DeletedBy().String(),
+	}
+
+	// replace null-values with "null"
+	return res.NullifyJsonProps()
+}
+
+// NewProjects converts the project aggregates into their v1 presenters.
+func NewProjects(projects []project.Aggregate) []Project {
+	res := []Project{}
+	for i := range projects {
+		res = append(res, NewProject(&projects[i]))
+	}
+	return res
+}
+
+// ProjectV2 is the project data used as the result for any project operation of the v2 api.
+// Timestamps are RFC 3339 in UTC and actors are typed references.
+// Values which have not been set, such as the deletion of an active project, are null.
+type ProjectV2 struct {
+	ID          valueobject.Identifier `json:"id"`
+	ShortCode   string                 `json:"shortCode"`
+	ShortName   string                 `json:"shortName"`
+	LongName    string                 `json:"longName"`
+	Description string                 `json:"description"`
+	Keywords    []string               `json:"keywords"`
+	CreatedAt   *time.Time             `json:"createdAt"`
+	CreatedBy   *Actor                 `json:"createdBy"`
+	ChangedAt   *time.Time             `json:"changedAt"`
+	ChangedBy   *Actor                 `json:"changedBy"`
+	DeletedAt   *time.Time             `json:"deletedAt"`
+	DeletedBy   *Actor                 `json:"deletedBy"`
+}
+
+// Actor references the user or service account which has made a change.
+// The type is null for changes recorded before the type of the actor was stored.
+type Actor struct {
+	ID   valueobject.Identifier `json:"id"`
+	Type *string                `json:"type"`
+}
+
+// NewProjectV2 converts the project aggregate into its v2 presenter.
+func NewProjectV2(p *project.Aggregate) ProjectV2 {
+	return ProjectV2{
+		ID:          p.ID(),
+		ShortCode:   p.ShortCode().String(),
+		ShortName:   p.ShortName().String(),
+		LongName:    p.LongName().String(),
+		Description: p.Description().String(),
+		Keywords:    keywords(p),
+		CreatedAt:   timestamp(p.CreatedAt()),
+		CreatedBy:   typedActor(p.Creator()),
+		ChangedAt:   timestamp(p.ChangedAt()),
+		ChangedBy:   typedActor(p.Changer()),
+		DeletedAt:   timestamp(p.DeletedAt()),
+		DeletedBy:   typedActor(p.Deleter()),
+	}
+}
+
+// NewProjectsV2 converts the project aggregates into their v2 presenters.
+func NewProjectsV2(projects []project.Aggregate) []ProjectV2 {
+	res := []ProjectV2{}
+	for i := range projects {
+		res = append(res, NewProjectV2(&projects[i]))
+	}
+	return res
+}
+
+// keywords returns the keywords of the project, an empty list if it has none.
+// Only projects migrated from DSP-API have keywords.
+func keywords(p *project.Aggregate) []string {
+	return append([]string{}, p.Keywords()...)
+}
+
+// timestamp returns the time of the timestamp in UTC, or nil if it has not been set.
+func timestamp(ts valueobject.Timestamp) *time.Time {
+	if ts.Time().IsZero() {
+		return nil
+	}
+	t := ts.Time().UTC()
+	return &t
+}
+
+// actor returns a reference to the user or service account, or nil if it has not been set.
+func actor(id valueobject.Identifier) *valueobject.Identifier {
+	if id == (valueobject.Identifier{}) {
+		return nil
+	}
+	return &id
+}
+
+// typedActor returns the typed reference to the actor, or nil if it has not been set.
+func typedActor(a event.Actor) *Actor {
+	if a.ID == (valueobject.Identifier{}) {
+		return nil
+	}
+	res := &Actor{ID: a.ID}
+	if a.Type != "" {
+		t := string(a.Type)
+		res.Type = &t
+	}
+	return res
+}
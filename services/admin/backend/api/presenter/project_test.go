@@ -18,40 +18,169 @@
 package presenter_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rdf"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
-func TestProject_NullifyJsonProps(t *testing.T) {
+func newTestProject(t *testing.T) *project.Aggregate {
 	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("ffff")
+	sn, _ := valueobject.NewShortName("short name")
+	ln, _ := valueobject.NewLongName("long name")
+	desc, _ := valueobject.NewDescription("description")
+	createdBy, err := valueobject.IdentifierFromBytes([]byte("90c8c7ba-14c5-49b4-98ea-da479b5bf95e"))
+	assert.Nil(t, err)
+	return project.NewAggregate(id, sc, sn, ln, desc, event.Actor{ID: createdBy, Type: event.ActorUser})
+}
+
+func TestNewProject(t *testing.T) {
+	p := newTestProject(t)
+
+	// the v1 representation is unchanged, values which have not been set are the string "null"
+	res := presenter.NewProject(p)
+	assert.Equal(t, p.ID(), res.ID)
+	assert.Equal(t, "ffff", res.ShortCode)
+	assert.Equal(t, []string{}, res.Keywords)
+	assert.Equal(t, p.CreatedAt().String(), res.CreatedAt)
+	assert.Equal(t, "90c8c7ba-14c5-49b4-98ea-da479b5bf95e", res.CreatedBy)
+	assert.Equal(t, "null", res.ChangedAt)
+	assert.Equal(t, "null", res.ChangedBy)
+	assert.Equal(t, "null", res.DeletedAt)
+	assert.Equal(t, "null", res.DeletedBy)
+
+	assert.Nil(t, p.DeleteProject(p.ID(), p.Creator()))
+	res = presenter.NewProject(p)
+	assert.Equal(t, p.DeletedAt().String(), res.DeletedAt)
+	assert.Equal(t, "90c8c7ba-14c5-49b4-98ea-da479b5bf95e", res.DeletedBy)
+}
+
+func TestNewProjectV2(t *testing.T) {
+	p := newTestProject(t)
 
-	p := presenter.Project{
-		ID:          id,
-		ShortCode:   "ffff",
-		ShortName:   "short name",
-		LongName:    "long name",
-		Description: "description",
-		CreatedAt:   "2021-06-03 10:00:00 +0000 UTC",
-		CreatedBy:   "90c8c7ba-14c5-49b4-98ea-da479b5bf95e",
-		ChangedAt:   "0001-01-01 00:00:00 +0000 UTC",
-		ChangedBy:   "00000000-0000-0000-0000-000000000000",
-		DeletedAt:   "0001-01-01 00:00:00 +0000 UTC",
-		DeletedBy:   "00000000-0000-0000-0000-000000000000",
+	res := presenter.NewProjectV2(p)
+	assert.Equal(t, p.ID(), res.ID)
+	assert.Equal(t, "ffff", res.ShortCode)
+	assert.Equal(t, "short name", res.ShortName)
+	assert.Equal(t, "long name", res.LongName)
+	assert.Equal(t, "description", res.Description)
+	assert.Equal(t, time.UTC, res.CreatedAt.Location())
+	assert.Equal(t, "90c8c7ba-14c5-49b4-98ea-da479b5bf95e", res.CreatedBy.ID.String())
+	assert.Equal(t, "user", *res.CreatedBy.Type)
+	assert.Nil(t, res.ChangedAt)
+	assert.Nil(t, res.ChangedBy)
+	assert.Nil(t, res.DeletedAt)
+	assert.Nil(t, res.DeletedBy)
+}
+
+func TestProjectV2_JSON(t *testing.T) {
+	p := newTestProject(t)
+
+	b, err := json.Marshal(presenter.NewProjectV2(p))
+	assert.Nil(t, err)
+
+	var res map[string]interface{}
+	assert.Nil(t, json.Unmarshal(b, &res))
+
+	// values which have not been set are JSON nulls, not the string "null"
+	for _, field := range []string{"changedAt", "changedBy", "deletedAt", "deletedBy"} {
+		v, ok := res[field]
+		assert.True(t, ok, field)
+		assert.Nil(t, v, field)
 	}
 
-	p = p.NullifyJsonProps()
-
-	assert.Equal(t, p.ID, id)
-	assert.Equal(t, p.ShortCode, "ffff")
-	assert.Equal(t, p.ShortName, "short name")
-	assert.Equal(t, p.LongName, "long name")
-	assert.Equal(t, p.Description, "description")
-	assert.Equal(t, p.CreatedAt, "2021-06-03 10:00:00 +0000 UTC")
-	assert.Equal(t, p.CreatedBy, "90c8c7ba-14c5-49b4-98ea-da479b5bf95e")
-	assert.Equal(t, p.ChangedAt, "null")
-	assert.Equal(t, p.ChangedBy, "null")
-	assert.Equal(t, p.DeletedAt, "null")
-	assert.Equal(t, p.DeletedBy, "null")
+	// projects which have not been migrated from DSP-API have no keywords
+	assert.Equal(t, []interface{}{}, res["keywords"])
+
+	createdAt, err := time.Parse(time.RFC3339, res["createdAt"].(string))
+	assert.Nil(t, err)
+	assert.True(t, createdAt.Equal(p.CreatedAt().Time()))
+	assert.Equal(t, "Z", res["createdAt"].(string)[len(res["createdAt"].(string))-1:])
+	assert.Equal(t, map[string]interface{}{"id": "90c8c7ba-14c5-49b4-98ea-da479b5bf95e", "type": "user"}, res["createdBy"])
+
+	assert.Nil(t, p.DeleteProject(p.ID(), p.Creator()))
+	b, err = json.Marshal(presenter.NewProjectV2(p))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(b, &res))
+	assert.NotNil(t, res["deletedAt"])
+	assert.Equal(t, map[string]interface{}{"id": "90c8c7ba-14c5-49b4-98ea-da479b5bf95e", "type": "user"}, res["deletedBy"])
+}
+
+func TestNewProjectGraph(t *testing.T) {
+	p := newTestProject(t)
+
+	var b bytes.Buffer
+	assert.Nil(t, rdf.WriteNTriples(&b, presenter.NewProjectGraph(p)))
+
+	s := "<http://rdfh.ch/projects/" + p.ID().String() + ">"
+	createdAt := p.CreatedAt().Time().UTC().Format(time.RFC3339Nano)
+	expected := s + " <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://ns.dasch.swiss/admin#Project> .\n" +
+		s + " <http://ns.dasch.swiss/admin#shortCode> \"ffff\" .\n" +
+		s + " <http://ns.dasch.swiss/admin#shortName> \"short name\" .\n" +
+		s + " <http://ns.dasch.swiss/admin#longName> \"long name\" .\n" +
+		s + " <http://ns.dasch.swiss/admin#description> \"description\" .\n" +
+		s + " <http://ns.dasch.swiss/admin#createdAt> \"" + createdAt + "\"^^<http://www.w3.org/2001/XMLSchema#dateTime> .\n" +
+		s + " <http://ns.dasch.swiss/admin#createdBy> <urn:uuid:90c8c7ba-14c5-49b4-98ea-da479b5bf95e> .\n" +
+		"<urn:uuid:90c8c7ba-14c5-49b4-98ea-da479b5bf95e> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://ns.dasch.swiss/admin#User> .\n"
+
+	// values which have not been set are omitted
+	assert.Equal(t, expected, b.String())
+}
+
+func TestProject_Keywords(t *testing.T) {
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Bernoullis and Euler")
+	p := project.NewMigratedAggregate(id, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", sc, sn, ln, desc, []string{"mathematics", "letters"}, valueobject.NewTimestamp())
+
+	assert.Equal(t, []string{"mathematics", "letters"}, presenter.NewProject(p).Keywords)
+	assert.Equal(t, []string{"mathematics", "letters"}, presenter.NewProjectV2(p).Keywords)
+	assert.Equal(t, []string{"mathematics", "letters"}, presenter.NewPublicProject(p).Keywords)
+
+	// the project keeps the IRI it had in DSP-API
+	var b bytes.Buffer
+	assert.Nil(t, rdf.WriteNTriples(&b, presenter.NewProjectGraph(p)))
+	s := "<http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF>"
+	assert.Equal(t, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", string(presenter.ProjectIRI(p)))
+	assert.Contains(t, b.String(), s+" <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://ns.dasch.swiss/admin#Project> .\n")
+	assert.NotContains(t, b.String(), id.String())
+	assert.Contains(t, b.String(), s+" <http://ns.dasch.swiss/admin#keyword> \"mathematics\" .\n")
+	assert.Contains(t, b.String(), s+" <http://ns.dasch.swiss/admin#keyword> \"letters\" .\n")
+}
+
+func TestNewProjectEvent(t *testing.T) {
+	id, _ := valueobject.NewIdentifier()
+	by, _ := valueobject.NewIdentifier()
+	at := valueobject.NewTimestamp()
+
+	res := presenter.NewProjectEvent(&event.ProjectShortNameChanged{ID: id, ChangedAt: at, ChangedBy: by, ChangedByType: event.ActorServiceAccount})
+	assert.Equal(t, presenter.ProjectEventChanged, res.Type)
+	assert.Equal(t, "ProjectShortNameChanged", res.Event)
+	assert.Equal(t, id, res.ProjectID)
+	assert.Equal(t, at.Time().UTC(), *res.At)
+	assert.Equal(t, by, res.By.ID)
+	assert.Equal(t, "serviceAccount", *res.By.Type)
+	assert.Nil(t, res.Project)
+
+	// the type of actors recorded before it was stored is unknown
+	res = presenter.NewProjectEvent(&event.ProjectCreated{ID: id, CreatedAt: at, CreatedBy: by})
+	assert.Equal(t, by, res.By.ID)
+	assert.Nil(t, res.By.Type)
+
+	res = presenter.NewProjectEvent(&event.ProjectDeleted{ID: id, DeletedAt: at})
+	assert.Equal(t, presenter.ProjectEventDeleted, res.Type)
+	assert.Nil(t, res.By)
+
+	res = presenter.NewProjectEvent(&event.ServiceAccountCreated{})
+	assert.Nil(t, res)
 }
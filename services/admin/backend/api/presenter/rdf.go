@@ -0,0 +1,107 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package presenter
+
+import (
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rdf"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// AdminOntology is the namespace of the DaSCH admin ontology.
+const AdminOntology = "http://ns.dasch.swiss/admin#"
+
+// ProjectIRIBase is the base of the stable project IRIs, which are also used by DSP-API.
+const ProjectIRIBase = "http://rdfh.ch/projects/"
+
+// RDFPrefixes are the prefixes used when serializing the RDF representations.
+var RDFPrefixes = []rdf.Prefix{
+	{Name: "admin", Namespace: AdminOntology},
+	{Name: "xsd", Namespace: rdf.XSD},
+}
+
+// ProjectIRI returns the stable IRI of the project. Projects migrated from DSP-API keep the IRI they had there,
+// the IRIs of the other projects are built from their id.
+func ProjectIRI(p *project.Aggregate) rdf.IRI {
+	if p.IRI() != "" {
+		return rdf.IRI(p.IRI())
+	}
+	return rdf.IRI(ProjectIRIBase + p.ID().String())
+}
+
+// NewProjectGraph converts the project aggregate into its RDF representation.
+// Values which have not been set are omitted. Users and service accounts are referenced by their urn:uuid IRI,
+// typed as admin:User or admin:ServiceAccount when the type of the actor is known.
+func NewProjectGraph(p *project.Aggregate) *rdf.Graph {
+	g := &rdf.Graph{}
+	s := ProjectIRI(p)
+
+	g.Add(s, rdf.Type, rdf.IRI(AdminOntology+"Project"))
+	g.Add(s, AdminOntology+"shortCode", rdf.String(p.ShortCode().String()))
+	g.Add(s, AdminOntology+"shortName", rdf.String(p.ShortName().String()))
+	g.Add(s, AdminOntology+"longName", rdf.String(p.LongName().String()))
+	g.Add(s, AdminOntology+"description", rdf.String(p.Description().String()))
+	for _, k := range p.Keywords() {
+		g.Add(s, AdminOntology+"keyword", rdf.String(k))
+	}
+	addTimestamp(g, s, "createdAt", p.CreatedAt())
+	addActor(g, s, "createdBy", p.CreatedBy())
+	addTimestamp(g, s, "changedAt", p.ChangedAt())
+	addActor(g, s, "changedBy", p.ChangedBy())
+	addTimestamp(g, s, "deletedAt", p.DeletedAt())
+	addActor(g, s, "deletedBy", p.DeletedBy())
+	addActorTypes(g, p.Creator(), p.Changer(), p.Deleter())
+
+	return g
+}
+
+// addTimestamp adds the timestamp as xsd:dateTime in UTC if it has been set.
+func addTimestamp(g *rdf.Graph, s rdf.IRI, property string, ts valueobject.Timestamp) {
+	if t := timestamp(ts); t != nil {
+		g.Add(s, rdf.IRI(AdminOntology+property), rdf.Typed(t.Format(time.RFC3339Nano), rdf.XSD+"dateTime"))
+	}
+}
+
+// actorClasses maps the actor types to their classes in the admin ontology.
+var actorClasses = map[event.ActorType]string{
+	event.ActorUser:           "User",
+	event.ActorServiceAccount: "ServiceAccount",
+}
+
+// addActorTypes adds the class of every distinct actor whose type is known.
+func addActorTypes(g *rdf.Graph, actors ...event.Actor) {
+	seen := map[valueobject.Identifier]bool{}
+	for _, a := range actors {
+		class, ok := actorClasses[a.Type]
+		if !ok || a.ID == (valueobject.Identifier{}) || seen[a.ID] {
+			continue
+		}
+		seen[a.ID] = true
+		g.Add(rdf.IRI("urn:uuid:"+a.ID.String()), rdf.Type, rdf.IRI(AdminOntology+class))
+	}
+}
+
+// addActor adds the reference to the user or service account if it has been set.
+func addActor(g *rdf.Graph, s rdf.IRI, property string, id valueobject.Identifier) {
+	if a := actor(id); a != nil {
+		g.Add(s, rdf.IRI(AdminOntology+property), rdf.IRI("urn:uuid:"+a.String()))
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package presenter
+
+import (
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// ServiceAccount data used as the result for any service account operation.
+// Timestamps are RFC 3339 in UTC and actors are typed references. Values which have not been set are null.
+type ServiceAccount struct {
+	ID          valueobject.Identifier `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	CreatedAt   *time.Time             `json:"createdAt"`
+	CreatedBy   *Actor                 `json:"createdBy"`
+	DeletedAt   *time.Time             `json:"deletedAt"`
+	DeletedBy   *Actor                 `json:"deletedBy"`
+	APIKeys     []APIKey               `json:"apiKeys"`
+}
+
+// APIKey data describing a key issued to a service account.
+// Key is only set in the response to issuing a new key, it cannot be retrieved later on.
+type APIKey struct {
+	ID         valueobject.Identifier `json:"id"`
+	Key        string                 `json:"key,omitempty"`
+	Scopes     []string               `json:"scopes"`
+	IssuedAt   *time.Time             `json:"issuedAt"`
+	IssuedBy   *Actor                 `json:"issuedBy"`
+	ExpiresAt  *time.Time             `json:"expiresAt"`
+	LastUsedAt *time.Time             `json:"lastUsedAt"`
+	RevokedAt  *time.Time             `json:"revokedAt"`
+	RevokedBy  *Actor                 `json:"revokedBy"`
+}
+
+// NewServiceAccount converts the service account aggregate into its presenter.
+func NewServiceAccount(a *serviceaccount.Aggregate) ServiceAccount {
+	res := ServiceAccount{
+		ID:          a.ID(),
+		Name:        a.Name().String(),
+		Description: a.Description().String(),
+		CreatedAt:   timestamp(a.CreatedAt()),
+		CreatedBy:   typedActor(a.Creator()),
+		DeletedAt:   timestamp(a.DeletedAt()),
+		DeletedBy:   typedActor(a.Deleter()),
+		APIKeys:     []APIKey{},
+	}
+
+	for _, k := range a.Keys() {
+		res.APIKeys = append(res.APIKeys, NewAPIKey(k))
+	}
+
+	return res
+}
+
+// NewAPIKey converts the api key into its presenter. The hash of the key is never exposed.
+func NewAPIKey(k serviceaccount.APIKey) APIKey {
+	res := APIKey{
+		ID:         k.ID(),
+		Scopes:     []string{},
+		IssuedAt:   timestamp(k.IssuedAt()),
+		IssuedBy:   typedActor(k.Issuer()),
+		ExpiresAt:  timestamp(k.ExpiresAt()),
+		LastUsedAt: timestamp(k.LastUsedAt()),
+		RevokedAt:  timestamp(k.RevokedAt()),
+		RevokedBy:  typedActor(k.Revoker()),
+	}
+
+	for _, s := range k.Scopes() {
+		res.Scopes = append(res.Scopes, string(s))
+	}
+
+	return res
+}
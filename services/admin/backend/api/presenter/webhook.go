@@ -0,0 +1,100 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package presenter
+
+import (
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// Webhook data used as the result for any webhook operation.
+// Secret is only set in the response to creating a webhook, it cannot be retrieved later on.
+// Actors are typed references, like those of a project.
+type Webhook struct {
+	ID          valueobject.Identifier `json:"id"`
+	URL         string                 `json:"url"`
+	Secret      string                 `json:"secret,omitempty"`
+	EventTypes  []string               `json:"eventTypes"`
+	Description string                 `json:"description"`
+	CreatedAt   *time.Time             `json:"createdAt"`
+	CreatedBy   *Actor                 `json:"createdBy"`
+	DeletedAt   *time.Time             `json:"deletedAt"`
+	DeletedBy   *Actor                 `json:"deletedBy"`
+}
+
+// WebhookDelivery data describing a payload in the outbox of a webhook.
+// LastStatusCode is null if no response has been received yet.
+type WebhookDelivery struct {
+	ID             valueobject.Identifier `json:"id"`
+	WebhookID      valueobject.Identifier `json:"webhookId"`
+	EventType      string                 `json:"eventType"`
+	EventID        string                 `json:"eventId"`
+	Status         string                 `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	LastStatusCode *int                   `json:"lastStatusCode"`
+	LastError      string                 `json:"lastError"`
+	ScheduledAt    *time.Time             `json:"scheduledAt"`
+	LastAttemptAt  *time.Time             `json:"lastAttemptAt"`
+	NextAttemptAt  *time.Time             `json:"nextAttemptAt"`
+	DeadLetteredAt *time.Time             `json:"deadLetteredAt"`
+}
+
+// NewWebhook converts the webhook aggregate into its presenter. The secret is never exposed.
+func NewWebhook(a *webhook.Aggregate) Webhook {
+	res := Webhook{
+		ID:          a.ID(),
+		URL:         a.URL(),
+		EventTypes:  []string{},
+		Description: a.Description().String(),
+		CreatedAt:   timestamp(a.CreatedAt()),
+		CreatedBy:   typedActor(a.Creator()),
+		DeletedAt:   timestamp(a.DeletedAt()),
+		DeletedBy:   typedActor(a.Deleter()),
+	}
+
+	for _, t := range a.EventTypes() {
+		res.EventTypes = append(res.EventTypes, string(t))
+	}
+
+	return res
+}
+
+// NewWebhookDelivery converts the delivery aggregate into its presenter. The payload is not part of it.
+func NewWebhookDelivery(d *webhook.Delivery) WebhookDelivery {
+	res := WebhookDelivery{
+		ID:             d.ID(),
+		WebhookID:      d.WebhookID(),
+		EventType:      string(d.EventType()),
+		EventID:        d.SourceEventID(),
+		Status:         string(d.Status()),
+		Attempts:       d.Attempts(),
+		LastError:      d.LastError(),
+		ScheduledAt:    timestamp(d.ScheduledAt()),
+		LastAttemptAt:  timestamp(d.LastAttemptAt()),
+		NextAttemptAt:  timestamp(d.NextAttemptAt()),
+		DeadLetteredAt: timestamp(d.DeadLetteredAt()),
+	}
+
+	if code := d.LastStatusCode(); code != 0 {
+		res.LastStatusCode = &code
+	}
+
+	return res
+}
@@ -0,0 +1,176 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package problem
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// codes of the problems not caused by a domain error.
+const (
+	CodeInternal             = "internal_error"
+	CodeMalformedBody        = "malformed_body"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidParameter     = "invalid_parameter"
+	CodePermissionDenied     = "permission_denied"
+	CodeNotAuthenticated     = "not_authenticated"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeIdempotencyKeyInUse  = "idempotency_key_in_use"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeBodyTooLarge         = "body_too_large"
+)
+
+// mapping describes the problem corresponding to a domain error.
+type mapping struct {
+	err    error
+	status int
+	code   string
+	// field is the field of the request causing the error, if any.
+	field string
+}
+
+// mappings lists the problems corresponding to the domain errors.
+// The codes are part of the api and must not change once released.
+var mappings = []mapping{
+	{principal.ErrNotAuthenticated, http.StatusUnauthorized, CodeNotAuthenticated, ""},
+	{principal.ErrNotSystemAdmin, http.StatusForbidden, CodePermissionDenied, ""},
+
+	{project.ErrProjectNotFound, http.StatusNotFound, "project_not_found", ""},
+	{project.ErrNoProjectDataReturned, http.StatusNotFound, "project_not_found", ""},
+	{project.ErrProjectHasBeenDeleted, http.StatusGone, "project_deleted", ""},
+	{project.ErrProjectNotDeleted, http.StatusConflict, "project_not_deleted", ""},
+	{project.ErrServerNotResponding, http.StatusServiceUnavailable, "server_not_responding", ""},
+	{project.ErrInvalidEntity, http.StatusBadRequest, "invalid_entity", ""},
+	{project.ErrInvalidUUID, http.StatusBadRequest, "invalid_uuid", "id"},
+	{project.ErrNoPropertiesChanged, http.StatusUnprocessableEntity, "no_properties_changed", ""},
+	{project.ErrCannotBeDeleted, http.StatusConflict, "project_cannot_be_deleted", ""},
+	{project.ErrShortCodeAlreadyExists, http.StatusConflict, "short_code_already_exists", "shortCode"},
+	{project.ErrUserDoesNotHaveCreateProjectsPermission, http.StatusForbidden, CodePermissionDenied, ""},
+	{project.ErrUserDoesNotHaveReadProjectPermission, http.StatusForbidden, CodePermissionDenied, ""},
+	{project.ErrUserDoesNotHaveReadAllProjectsPermission, http.StatusForbidden, CodePermissionDenied, ""},
+	{project.ErrUserDoesNotHaveUpdateProjectPermission, http.StatusForbidden, CodePermissionDenied, ""},
+	{project.ErrUserDoesNotHaveDeleteProjectPermission, http.StatusForbidden, CodePermissionDenied, ""},
+	{project.ErrInvalidSortField, http.StatusBadRequest, "invalid_sort_field", "sort"},
+	{project.ErrInvalidStatus, http.StatusBadRequest, "invalid_status", "status"},
+	{project.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor", "after"},
+	{project.ErrNoSearchQuery, http.StatusBadRequest, "missing_search_query", "q"},
+	{project.ErrInvalidEventID, http.StatusBadRequest, "invalid_event_id", "Last-Event-ID"},
+	{project.ErrNoImportRows, http.StatusBadRequest, "missing_import_rows", ""},
+	{project.ErrTooManyImportRows, http.StatusRequestEntityTooLarge, "too_many_import_rows", ""},
+	{project.ErrShortCodeDuplicatedInImport, http.StatusConflict, "short_code_duplicated", "shortCode"},
+
+	{serviceaccount.ErrServiceAccountNotFound, http.StatusNotFound, "service_account_not_found", ""},
+	{serviceaccount.ErrServiceAccountHasBeenDeleted, http.StatusGone, "service_account_deleted", ""},
+	{serviceaccount.ErrAPIKeyNotFound, http.StatusNotFound, "api_key_not_found", ""},
+	{serviceaccount.ErrAPIKeyHasBeenRevoked, http.StatusConflict, "api_key_revoked", ""},
+	{serviceaccount.ErrAPIKeyHasExpired, http.StatusUnauthorized, "api_key_expired", ""},
+	{serviceaccount.ErrInvalidAPIKey, http.StatusUnauthorized, "invalid_api_key", ""},
+	{serviceaccount.ErrInvalidScope, http.StatusBadRequest, "invalid_scope", "scopes"},
+	{serviceaccount.ErrNoScopesProvided, http.StatusBadRequest, "missing_scopes", "scopes"},
+	{serviceaccount.ErrInvalidExpiry, http.StatusBadRequest, "invalid_expiry", "expiresAt"},
+	{serviceaccount.ErrUserDoesNotHaveManageServiceAccountsPermission, http.StatusForbidden, CodePermissionDenied, ""},
+
+	{webhook.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found", ""},
+	{webhook.ErrWebhookHasBeenDeleted, http.StatusGone, "webhook_deleted", ""},
+	{webhook.ErrInvalidURL, http.StatusBadRequest, "invalid_url", "url"},
+	{webhook.ErrInvalidEventType, http.StatusBadRequest, "invalid_event_type", "eventTypes"},
+	{webhook.ErrNoEventTypesProvided, http.StatusBadRequest, "missing_event_types", "eventTypes"},
+	{webhook.ErrInvalidDeliveryStatus, http.StatusBadRequest, "invalid_delivery_status", "status"},
+	{webhook.ErrDeliveryNotFound, http.StatusNotFound, "delivery_not_found", ""},
+	{webhook.ErrDeliveryIsPending, http.StatusConflict, "delivery_pending", ""},
+	{webhook.ErrUserDoesNotHaveManageWebhooksPermission, http.StatusForbidden, CodePermissionDenied, ""},
+}
+
+// FromError returns the problem corresponding to the error.
+// Unknown errors are logged and reported as internal errors without revealing their message.
+func FromError(err error) *Problem {
+	if p, ok := Lookup(err); ok {
+		return p
+	}
+
+	log.Println(err.Error())
+	return New(http.StatusInternalServerError, CodeInternal, "an unexpected error occurred")
+}
+
+// Lookup returns the problem corresponding to the error, if the error is a problem or a known domain error.
+func Lookup(err error) (*Problem, bool) {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p, true
+	}
+
+	var verr *valueobject.ValidationError
+	if errors.As(err, &verr) {
+		var fields []FieldError
+		for _, f := range verr.Fields {
+			fields = append(fields, FieldError{Field: f.Field, Constraint: string(f.Constraint), Limit: f.Limit, Detail: f.Message})
+		}
+		return Validation(fields...), true
+	}
+
+	for _, m := range mappings {
+		if errors.Is(err, m.err) {
+			p := New(m.status, m.code, err.Error())
+			if m.field != "" {
+				p.Errors = []FieldError{{Field: m.field, Detail: err.Error()}}
+			}
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// MalformedBody returns the problem of a request body that cannot be decoded.
+func MalformedBody(err error) *Problem {
+	return New(http.StatusBadRequest, CodeMalformedBody, err.Error())
+}
+
+// InvalidParameter returns the problem of an invalid query or path parameter.
+func InvalidParameter(name string, err error) *Problem {
+	p := New(http.StatusBadRequest, CodeInvalidParameter, err.Error())
+	p.Errors = []FieldError{{Field: name, Detail: err.Error()}}
+	return p
+}
+
+// NotAcceptable returns the problem of a request accepting none of the offered media types.
+func NotAcceptable(offers ...string) *Problem {
+	return New(http.StatusNotAcceptable, CodeNotAcceptable, "the resource is only available as "+strings.Join(offers, ", "))
+}
+
+// UnsupportedMediaType returns the problem of a request body in none of the accepted media types.
+func UnsupportedMediaType(accepted ...string) *Problem {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "the request body must be "+strings.Join(accepted, " or "))
+}
+
+// Validation returns the problem of a request containing invalid fields.
+func Validation(errs ...FieldError) *Problem {
+	p := New(http.StatusUnprocessableEntity, CodeValidationFailed, "the request contains invalid fields")
+	p.Errors = errs
+	return p
+}
@@ -0,0 +1,87 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package problem writes error responses as RFC 7807 problem details, see https://tools.ietf.org/html/rfc7807.
+// Every problem carries a stable, machine-readable code, so that clients can branch on it instead of the message text.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// typePrefix is prepended to the code of a problem to form its type URI.
+const typePrefix = "urn:dasch:problem:"
+
+// Problem describes an error response.
+type Problem struct {
+	// Type is a URI identifying the kind of problem, it is derived from the code.
+	Type string `json:"type"`
+	// Title is a short summary of the kind of problem, which does not change between occurrences.
+	Title string `json:"title"`
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request which caused the problem.
+	Instance string `json:"instance,omitempty"`
+	// Code is the stable, machine-readable code of the kind of problem.
+	Code string `json:"code"`
+	// Errors lists the invalid fields of the request, if any.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why the value of a field of the request is invalid.
+type FieldError struct {
+	Field string `json:"field"`
+	// Constraint is the rule violated by the value, e.g. required, maxLength or hexadecimal.
+	Constraint string `json:"constraint,omitempty"`
+	// Limit is the maximum length of a value violating the maxLength constraint.
+	Limit  int    `json:"limit,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// New creates a problem with the provided status, code and detail.
+func New(status int, code string, detail string) *Problem {
+	return &Problem{
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Error returns the detail of the problem.
+func (p *Problem) Error() string {
+	return p.Detail
+}
+
+// Write writes the problem as response to the request.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	res := *p
+	if res.Instance == "" && r != nil {
+		res.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(res.Status)
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,94 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromError(t *testing.T) {
+	p := problem.FromError(project.ErrShortCodeAlreadyExists)
+	assert.Equal(t, http.StatusConflict, p.Status)
+	assert.Equal(t, "short_code_already_exists", p.Code)
+	assert.Equal(t, "urn:dasch:problem:short_code_already_exists", p.Type)
+	assert.Equal(t, "Conflict", p.Title)
+	assert.Equal(t, []problem.FieldError{{Field: "shortCode", Detail: project.ErrShortCodeAlreadyExists.Error()}}, p.Errors)
+
+	// wrapped errors are mapped as well
+	p = problem.FromError(fmt.Errorf("loading project: %w", project.ErrProjectHasBeenDeleted))
+	assert.Equal(t, http.StatusGone, p.Status)
+	assert.Equal(t, "project_deleted", p.Code)
+
+	assert.Equal(t, http.StatusForbidden, problem.FromError(project.ErrUserDoesNotHaveUpdateProjectPermission).Status)
+
+	// problems are returned as they are
+	invalid := problem.InvalidParameter("limit", errors.New("limit must be a number"))
+	assert.Same(t, invalid, problem.FromError(invalid))
+}
+
+func TestFromError_Unknown(t *testing.T) {
+	p := problem.FromError(errors.New("connection refused by 10.0.0.1"))
+	assert.Equal(t, http.StatusInternalServerError, p.Status)
+	assert.Equal(t, problem.CodeInternal, p.Code)
+	// internal details are not revealed
+	assert.NotContains(t, p.Detail, "10.0.0.1")
+
+	_, ok := problem.Lookup(errors.New("unknown"))
+	assert.False(t, ok)
+}
+
+func TestWrite(t *testing.T) {
+	w := httptest.NewRecorder()
+	problem.Write(w, httptest.NewRequest("POST", "/v1/projects", nil), problem.Validation(
+		problem.FieldError{Field: "shortCode", Detail: "invalid short code"},
+	))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+
+	var res map[string]interface{}
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, problem.CodeValidationFailed, res["code"])
+	assert.Equal(t, float64(http.StatusUnprocessableEntity), res["status"])
+	assert.Equal(t, "/v1/projects", res["instance"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "shortCode", "detail": "invalid short code"}}, res["errors"])
+}
+
+func TestFromError_Validation(t *testing.T) {
+	v := valueobject.Validation{}
+	v.ShortCode("shortCode", "xyz")
+	v.LongName("longName", "")
+
+	p := problem.FromError(v.Err())
+	assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+	assert.Equal(t, problem.CodeValidationFailed, p.Code)
+	assert.Equal(t, []problem.FieldError{
+		{Field: "shortCode", Constraint: "hexadecimal", Detail: "invalid short code, must be two hexadecimal digits and non-empty"},
+		{Field: "longName", Constraint: "required", Detail: "invalid long name, must be within 50 characters and non-empty"},
+	}, p.Errors)
+}
@@ -0,0 +1,50 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package publisher
+
+import (
+	"context"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/message"
+)
+
+// ChannelSink publishes messages to a channel, for consumers running in the same process and for tests.
+type ChannelSink struct {
+	C chan message.Message
+}
+
+// NewChannelSink creates a new sink publishing to a channel with the provided buffer size.
+func NewChannelSink(size int) *ChannelSink {
+	return &ChannelSink{C: make(chan message.Message, size)}
+}
+
+// Publish sends the message to the channel, blocking until it is received or buffered or the context is done.
+func (s *ChannelSink) Publish(ctx context.Context, m message.Message) error {
+	select {
+	case s.C <- m:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close closes the channel.
+func (s *ChannelSink) Close() error {
+	close(s.C)
+	return nil
+}
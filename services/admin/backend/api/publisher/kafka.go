@@ -0,0 +1,72 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/message"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink publishes messages to a Kafka topic.
+// Messages are keyed by their subject, so all messages of a project end up in the same partition, in order.
+type KafkaSink struct {
+	w *kafka.Writer
+}
+
+// NewKafkaSink creates a new sink publishing to the topic on the provided brokers.
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		w: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+// Publish writes the message in the structured content mode of the CloudEvents Kafka binding
+// and waits until all in-sync replicas have acknowledged it.
+func (s *KafkaSink) Publish(ctx context.Context, m message.Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("problem serializing message %s to json: %w", m.ID, err)
+	}
+
+	err = s.w.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(m.Subject),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte("application/cloudevents+json")},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("problem publishing message %s to '%s': %w", m.ID, s.w.Topic, err)
+	}
+
+	return nil
+}
+
+// Close flushes pending messages and closes the writer.
+func (s *KafkaSink) Close() error {
+	return s.w.Close()
+}
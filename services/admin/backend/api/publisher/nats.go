@@ -0,0 +1,82 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/message"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSSink publishes messages to NATS JetStream.
+// A stream covering the subjects of the messages must exist, e.g. `<prefix>.swiss.dasch.admin.project.>`.
+type NATSSink struct {
+	nc     *nats.Conn
+	js     nats.JetStreamContext
+	prefix string
+}
+
+// NewNATSSink connects to the NATS server with the provided url.
+// The subject of a message is its type, prefixed with the provided prefix if it is not empty.
+func NewNATSSink(url string, prefix string) (*NATSSink, error) {
+	nc, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("problem connecting to NATS at '%s': %w", url, err)
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("problem creating JetStream context: %w", err)
+	}
+
+	return &NATSSink{nc: nc, js: js, prefix: prefix}, nil
+}
+
+// Publish publishes the message and waits for the acknowledgement of JetStream.
+// The id of the message is used as message id, so JetStream discards duplicates within its deduplication window.
+func (s *NATSSink) Publish(ctx context.Context, m message.Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("problem serializing message %s to json: %w", m.ID, err)
+	}
+
+	subject := m.Type
+	if s.prefix != "" {
+		subject = s.prefix + "." + m.Type
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Header.Set(nats.MsgIdHdr, m.ID)
+	msg.Header.Set("Content-Type", "application/cloudevents+json")
+	msg.Data = data
+
+	if _, err := s.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+		return fmt.Errorf("problem publishing message %s to '%s': %w", m.ID, subject, err)
+	}
+
+	return nil
+}
+
+// Close drains and closes the connection.
+func (s *NATSSink) Close() error {
+	return s.nc.Drain()
+}
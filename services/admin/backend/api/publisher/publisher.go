@@ -0,0 +1,120 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package publisher publishes the project events recorded in the event store to message brokers.
+// The event store acts as the outbox: an event is published after it has been recorded,
+// and the id of the last published event is recorded as checkpoint afterwards.
+// Delivery is at least once, consumers ignore duplicates by the id of the message.
+package publisher
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/message"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+)
+
+// Source passes the project events recorded after the event with the provided id to the handler.
+type Source interface {
+	WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error
+}
+
+// CheckpointRepository records the id of the last published event.
+type CheckpointRepository interface {
+	LoadCheckpoint(ctx context.Context) (string, error)
+	SaveCheckpoint(ctx context.Context, lastEventID string) error
+}
+
+// Sink publishes messages to a message broker.
+// Publish returns once the broker has acknowledged the message.
+type Sink interface {
+	Publish(ctx context.Context, m message.Message) error
+	Close() error
+}
+
+// Publisher publishes project events to a sink, in the order they were recorded.
+type Publisher struct {
+	source      Source
+	checkpoints CheckpointRepository
+	sink        Sink
+
+	// RetryDelay is the delay before publishing a message again after the sink failed, doubled on every failure.
+	RetryDelay time.Duration
+	// MaxRetryDelay is the upper bound of the delay between two attempts.
+	MaxRetryDelay time.Duration
+}
+
+// NewPublisher creates a new publisher of the events of the source to the sink.
+func NewPublisher(source Source, checkpoints CheckpointRepository, sink Sink) *Publisher {
+	return &Publisher{
+		source:        source,
+		checkpoints:   checkpoints,
+		sink:          sink,
+		RetryDelay:    time.Second,
+		MaxRetryDelay: time.Minute,
+	}
+}
+
+// Start publishes the events recorded after the last checkpoint, or from now on if there is none,
+// and keeps publishing new events until the context is done.
+func (p *Publisher) Start(ctx context.Context) error {
+
+	checkpoint, err := p.checkpoints.LoadCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	return p.source.WatchProjectsSince(ctx, checkpoint, func(id string, ev event.Event) {
+		if err := p.Publish(ctx, id, ev); err != nil {
+			log.Printf("failed to publish project event %s: %v", id, err)
+		}
+	})
+}
+
+// Publish publishes the project event with the provided id and records the id as the checkpoint afterwards.
+// A failing sink is retried until the message has been published or the context is done,
+// so that no later event overtakes it.
+func (p *Publisher) Publish(ctx context.Context, id string, ev event.Event) error {
+
+	m, ok := message.NewProjectMessage(id, ev)
+	if !ok {
+		return nil
+	}
+
+	delay := p.RetryDelay
+	for {
+		err := p.sink.Publish(ctx, m)
+		if err == nil {
+			break
+		}
+		log.Printf("failed to publish message %s, retrying in %s: %v", m.ID, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if delay *= 2; delay > p.MaxRetryDelay {
+			delay = p.MaxRetryDelay
+		}
+	}
+
+	return p.checkpoints.SaveCheckpoint(ctx, id)
+}
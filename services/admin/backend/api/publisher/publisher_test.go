@@ -0,0 +1,120 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package publisher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/message"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/publisher"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+// created returns a created event of a new project.
+func created() *event.ProjectCreated {
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("00F1")
+	sn, _ := valueobject.NewShortName("name")
+	ln, _ := valueobject.NewLongName("long name")
+	desc, _ := valueobject.NewDescription("description")
+	return &event.ProjectCreated{ID: id, ShortCode: sc, ShortName: sn, LongName: ln, Description: desc, CreatedAt: valueobject.NewTimestamp()}
+}
+
+func TestPublisher_Start(t *testing.T) {
+	ctx := context.Background()
+	source := &stubSource{}
+	checkpoints := &stubCheckpoints{}
+	sink := publisher.NewChannelSink(10)
+
+	// events recorded before the first start are not published
+	source.record(created())
+
+	p := publisher.NewPublisher(source, checkpoints, sink)
+	assert.Nil(t, p.Start(ctx))
+
+	ev := created()
+	source.record(ev)
+	source.record(&event.ProjectDeleted{ID: ev.ID, DeletedAt: valueobject.NewTimestamp()})
+
+	m := <-sink.C
+	assert.Equal(t, "2", m.ID)
+	assert.Equal(t, message.TypeProjectCreated, m.Type)
+	assert.Equal(t, ev.ID.String(), m.Subject)
+	m = <-sink.C
+	assert.Equal(t, "3", m.ID)
+	assert.Equal(t, message.TypeProjectDeleted, m.Type)
+	assert.Equal(t, "3", checkpoints.lastEventID)
+}
+
+func TestPublisher_StartFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	source := &stubSource{}
+	source.record(created())
+	source.record(created())
+	source.record(created())
+	checkpoints := &stubCheckpoints{lastEventID: "1"}
+	sink := publisher.NewChannelSink(10)
+
+	assert.Nil(t, publisher.NewPublisher(source, checkpoints, sink).Start(ctx))
+
+	assert.Equal(t, "2", (<-sink.C).ID)
+	assert.Equal(t, "3", (<-sink.C).ID)
+	assert.Equal(t, "3", checkpoints.lastEventID)
+}
+
+func TestPublisher_PublishRetries(t *testing.T) {
+	ctx := context.Background()
+	checkpoints := &stubCheckpoints{}
+	sink := &failingSink{failures: 2}
+
+	p := publisher.NewPublisher(&stubSource{}, checkpoints, sink)
+	p.RetryDelay = time.Millisecond
+	p.MaxRetryDelay = time.Millisecond
+
+	assert.Nil(t, p.Publish(ctx, "1", created()))
+	assert.Equal(t, 3, sink.attempts)
+	assert.Len(t, sink.published, 1)
+	assert.Equal(t, "1", checkpoints.lastEventID)
+}
+
+func TestPublisher_PublishCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checkpoints := &stubCheckpoints{lastEventID: "1"}
+	sink := &failingSink{failures: 1}
+
+	p := publisher.NewPublisher(&stubSource{}, checkpoints, sink)
+
+	// the checkpoint does not advance past an event which has not been published
+	assert.Equal(t, context.Canceled, p.Publish(ctx, "2", created()))
+	assert.Empty(t, sink.published)
+	assert.Equal(t, "1", checkpoints.lastEventID)
+}
+
+func TestPublisher_PublishSkipsUnknownEvents(t *testing.T) {
+	checkpoints := &stubCheckpoints{}
+	sink := &failingSink{}
+
+	assert.Nil(t, publisher.NewPublisher(&stubSource{}, checkpoints, sink).Publish(context.Background(), "1", nil))
+	assert.Equal(t, 0, sink.attempts)
+	assert.Equal(t, "", checkpoints.lastEventID)
+}
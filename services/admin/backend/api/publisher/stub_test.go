@@ -0,0 +1,87 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package publisher_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/message"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+)
+
+// stubSource passes recorded events to its watchers, the id of an event is its position starting at 1.
+type stubSource struct {
+	events   []event.Event
+	watchers []func(id string, ev event.Event)
+}
+
+func (s *stubSource) WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error {
+	from := len(s.events)
+	if lastEventID != "" {
+		from, _ = strconv.Atoi(lastEventID)
+	}
+	for i := from; i < len(s.events); i++ {
+		handle(strconv.Itoa(i+1), s.events[i])
+	}
+	s.watchers = append(s.watchers, handle)
+	return nil
+}
+
+// record records the event and passes it to all watchers.
+func (s *stubSource) record(ev event.Event) {
+	s.events = append(s.events, ev)
+	for _, handle := range s.watchers {
+		handle(strconv.Itoa(len(s.events)), ev)
+	}
+}
+
+// stubCheckpoints keeps the checkpoint in memory.
+type stubCheckpoints struct {
+	lastEventID string
+}
+
+func (c *stubCheckpoints) LoadCheckpoint(ctx context.Context) (string, error) {
+	return c.lastEventID, nil
+}
+
+func (c *stubCheckpoints) SaveCheckpoint(ctx context.Context, lastEventID string) error {
+	c.lastEventID = lastEventID
+	return nil
+}
+
+// failingSink fails to publish the first failures messages it gets and records the rest.
+type failingSink struct {
+	failures  int
+	attempts  int
+	published []message.Message
+}
+
+func (s *failingSink) Publish(ctx context.Context, m message.Message) error {
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("broker unavailable")
+	}
+	s.published = append(s.published, m)
+	return nil
+}
+
+func (s *failingSink) Close() error {
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rdf
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteJSONLD writes the graph as compacted JSON-LD, see https://www.w3.org/TR/json-ld11/.
+// The prefixes make up the @context. A graph with a single subject is written as a single node object,
+// otherwise the nodes are listed in @graph.
+func WriteJSONLD(w io.Writer, g *Graph, prefixes ...Prefix) error {
+	context := map[string]string{}
+	for _, p := range prefixes {
+		context[p.Name] = p.Namespace
+	}
+
+	var nodes []map[string]interface{}
+	for _, s := range g.subjects() {
+		node := map[string]interface{}{"@id": string(s)}
+		predicates, objects := g.properties(s)
+		for _, p := range predicates {
+			if p == Type {
+				var types []interface{}
+				for _, o := range objects[p] {
+					if i, ok := o.(IRI); ok {
+						types = append(types, jsonldIRI(i, prefixes))
+					}
+				}
+				node["@type"] = single(types)
+				continue
+			}
+			var values []interface{}
+			for _, o := range objects[p] {
+				values = append(values, jsonldValue(o, prefixes))
+			}
+			node[jsonldIRI(p, prefixes)] = single(values)
+		}
+		nodes = append(nodes, node)
+	}
+
+	var doc map[string]interface{}
+	if len(nodes) == 1 {
+		doc = nodes[0]
+	} else {
+		doc = map[string]interface{}{"@graph": nodes}
+	}
+	if len(context) > 0 {
+		doc["@context"] = context
+	}
+
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// jsonldValue returns the node or value object of the term.
+func jsonldValue(t Term, prefixes []Prefix) interface{} {
+	switch v := t.(type) {
+	case IRI:
+		return map[string]string{"@id": string(v)}
+	case Literal:
+		switch {
+		case v.Language != "":
+			return map[string]string{"@value": v.Value, "@language": v.Language}
+		case v.Datatype != "" && v.Datatype != XSD+"string":
+			return map[string]string{"@value": v.Value, "@type": jsonldIRI(v.Datatype, prefixes)}
+		}
+		return v.Value
+	}
+	return nil
+}
+
+// jsonldIRI returns the compact IRI, or the full IRI if no prefix matches.
+func jsonldIRI(i IRI, prefixes []Prefix) string {
+	if name, ok := prefixedName(i, prefixes); ok {
+		return name
+	}
+	return string(i)
+}
+
+// single unwraps lists with a single value.
+func single(values []interface{}) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return values
+}
@@ -0,0 +1,125 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package rdf contains a minimal RDF model, serializes it as Turtle, N-Triples and JSON-LD, and reads Turtle and TriG.
+// It only supports what is needed to represent the resources of the admin api, e.g. no blank nodes.
+package rdf
+
+// common namespaces.
+const (
+	RDF  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	XSD  = "http://www.w3.org/2001/XMLSchema#"
+	RDFS = "http://www.w3.org/2000/01/rdf-schema#"
+)
+
+// Type is the rdf:type property.
+const Type = RDF + "type"
+
+// Term is the subject, predicate or object of a triple.
+type Term interface {
+	// NTriples returns the term as written in N-Triples.
+	NTriples() string
+}
+
+// IRI identifies a resource.
+type IRI string
+
+// NTriples implements Term.
+func (i IRI) NTriples() string {
+	return "<" + escapeIRI(string(i)) + ">"
+}
+
+// Literal is a value with an optional datatype or language. Literals without either are strings.
+type Literal struct {
+	Value    string
+	Datatype IRI
+	Language string
+}
+
+// String creates a string literal.
+func String(value string) Literal {
+	return Literal{Value: value}
+}
+
+// Typed creates a literal of the datatype.
+func Typed(value string, datatype IRI) Literal {
+	return Literal{Value: value, Datatype: datatype}
+}
+
+// NTriples implements Term.
+func (l Literal) NTriples() string {
+	s := "\"" + escapeString(l.Value) + "\""
+	switch {
+	case l.Language != "":
+		s += "@" + l.Language
+	case l.Datatype != "" && l.Datatype != XSD+"string":
+		s += "^^" + l.Datatype.NTriples()
+	}
+	return s
+}
+
+// Triple is a statement about a resource.
+type Triple struct {
+	Subject   IRI
+	Predicate IRI
+	Object    Term
+}
+
+// Graph is a list of triples, which keeps the order the triples were added in.
+type Graph struct {
+	Triples []Triple
+}
+
+// Add adds a triple to the graph.
+func (g *Graph) Add(subject IRI, predicate IRI, object Term) {
+	g.Triples = append(g.Triples, Triple{Subject: subject, Predicate: predicate, Object: object})
+}
+
+// subjects returns the subjects of the graph in the order they first occur.
+func (g *Graph) subjects() []IRI {
+	var res []IRI
+	seen := map[IRI]bool{}
+	for _, t := range g.Triples {
+		if !seen[t.Subject] {
+			seen[t.Subject] = true
+			res = append(res, t.Subject)
+		}
+	}
+	return res
+}
+
+// properties returns the predicates of the subject and their objects, in the order they first occur.
+func (g *Graph) properties(subject IRI) ([]IRI, map[IRI][]Term) {
+	var predicates []IRI
+	objects := map[IRI][]Term{}
+	for _, t := range g.Triples {
+		if t.Subject != subject {
+			continue
+		}
+		if _, ok := objects[t.Predicate]; !ok {
+			predicates = append(predicates, t.Predicate)
+		}
+		objects[t.Predicate] = append(objects[t.Predicate], t.Object)
+	}
+	return predicates, objects
+}
+
+// Prefix maps a short name to a namespace, e.g. "xsd" to "http://www.w3.org/2001/XMLSchema#".
+type Prefix struct {
+	Name      string
+	Namespace string
+}
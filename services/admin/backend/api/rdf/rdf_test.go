@@ -0,0 +1,154 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rdf_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rdf"
+	"github.com/stretchr/testify/assert"
+)
+
+const ex = "http://example.org/"
+
+var prefixes = []rdf.Prefix{{Name: "ex", Namespace: ex}, {Name: "xsd", Namespace: rdf.XSD}}
+
+func newTestGraph() *rdf.Graph {
+	g := &rdf.Graph{}
+	g.Add(ex+"a", rdf.Type, rdf.IRI(ex+"Thing"))
+	g.Add(ex+"a", ex+"name", rdf.String("say \"hi\"\nback\\"))
+	g.Add(ex+"a", ex+"name", rdf.Literal{Value: "Salut", Language: "fr"})
+	g.Add(ex+"a", ex+"created", rdf.Typed("2021-03-01T10:00:00Z", rdf.XSD+"dateTime"))
+	g.Add(ex+"a", ex+"knows", rdf.IRI("urn:uuid:90c8c7ba-14c5-49b4-98ea-da479b5bf95e"))
+	return g
+}
+
+func TestWriteNTriples(t *testing.T) {
+	var b bytes.Buffer
+	assert.Nil(t, rdf.WriteNTriples(&b, newTestGraph()))
+
+	expected := `<http://example.org/a> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://example.org/Thing> .
+<http://example.org/a> <http://example.org/name> "say \"hi\"\nback\\" .
+<http://example.org/a> <http://example.org/name> "Salut"@fr .
+<http://example.org/a> <http://example.org/created> "2021-03-01T10:00:00Z"^^<http://www.w3.org/2001/XMLSchema#dateTime> .
+<http://example.org/a> <http://example.org/knows> <urn:uuid:90c8c7ba-14c5-49b4-98ea-da479b5bf95e> .
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestWriteTurtle(t *testing.T) {
+	var b bytes.Buffer
+	assert.Nil(t, rdf.WriteTurtle(&b, newTestGraph(), prefixes...))
+
+	expected := `@prefix ex: <http://example.org/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+ex:a
+    a ex:Thing ;
+    ex:name "say \"hi\"\nback\\", "Salut"@fr ;
+    ex:created "2021-03-01T10:00:00Z"^^xsd:dateTime ;
+    ex:knows <urn:uuid:90c8c7ba-14c5-49b4-98ea-da479b5bf95e> .
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestWriteJSONLD(t *testing.T) {
+	var b bytes.Buffer
+	assert.Nil(t, rdf.WriteJSONLD(&b, newTestGraph(), prefixes...))
+
+	var res map[string]interface{}
+	assert.Nil(t, json.Unmarshal(b.Bytes(), &res))
+	assert.Equal(t, map[string]interface{}{"ex": ex, "xsd": rdf.XSD}, res["@context"])
+	assert.Equal(t, ex+"a", res["@id"])
+	assert.Equal(t, "ex:Thing", res["@type"])
+	assert.Equal(t, []interface{}{"say \"hi\"\nback\\", map[string]interface{}{"@value": "Salut", "@language": "fr"}}, res["ex:name"])
+	assert.Equal(t, map[string]interface{}{"@value": "2021-03-01T10:00:00Z", "@type": "xsd:dateTime"}, res["ex:created"])
+	assert.Equal(t, map[string]interface{}{"@id": "urn:uuid:90c8c7ba-14c5-49b4-98ea-da479b5bf95e"}, res["ex:knows"])
+
+	// several subjects are listed in @graph
+	g := newTestGraph()
+	g.Add(ex+"b", rdf.Type, rdf.IRI(ex+"Thing"))
+	b.Reset()
+	assert.Nil(t, rdf.WriteJSONLD(&b, g, prefixes...))
+	res = nil
+	assert.Nil(t, json.Unmarshal(b.Bytes(), &res))
+	assert.Len(t, res["@graph"], 2)
+}
+
+func TestReadTurtle(t *testing.T) {
+	// what is written can be read again
+	var b bytes.Buffer
+	assert.Nil(t, rdf.WriteTurtle(&b, newTestGraph(), prefixes...))
+	g, err := rdf.ReadTurtle(&b)
+	assert.Nil(t, err)
+	assert.Equal(t, newTestGraph().Triples, g.Triples)
+
+	g, err = rdf.ReadTurtle(strings.NewReader(`
+PREFIX ex: <http://example.org/>
+@base <http://example.org/> .
+
+# comments are skipped
+<a> ex:count 42 ; ex:ratio 0.5 ; ex:big 1e3 ; ex:ok true ;
+    ex:text """two
+lines""", 'single é' ;
+    ex:tag "Tag"@EN-gb .
+ex:b.c ex:next ex:d.
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []rdf.Triple{
+		{Subject: ex + "a", Predicate: ex + "count", Object: rdf.Typed("42", rdf.XSD+"integer")},
+		{Subject: ex + "a", Predicate: ex + "ratio", Object: rdf.Typed("0.5", rdf.XSD+"decimal")},
+		{Subject: ex + "a", Predicate: ex + "big", Object: rdf.Typed("1e3", rdf.XSD+"double")},
+		{Subject: ex + "a", Predicate: ex + "ok", Object: rdf.Typed("true", rdf.XSD+"boolean")},
+		{Subject: ex + "a", Predicate: ex + "text", Object: rdf.String("two\nlines")},
+		{Subject: ex + "a", Predicate: ex + "text", Object: rdf.String("single é")},
+		{Subject: ex + "a", Predicate: ex + "tag", Object: rdf.Literal{Value: "Tag", Language: "en-gb"}},
+		{Subject: ex + "b.c", Predicate: ex + "next", Object: rdf.IRI(ex + "d")},
+	}, g.Triples)
+}
+
+func TestReadTurtle_TriG(t *testing.T) {
+	g, err := rdf.ReadTurtle(strings.NewReader(`
+@prefix ex: <http://example.org/> .
+ex:g1 { ex:a a ex:Thing }
+GRAPH <http://example.org/g2> { ex:b a ex:Thing . ex:c a ex:Thing . }
+{ ex:d a ex:Thing }
+`))
+	assert.Nil(t, err)
+	assert.Len(t, g.Triples, 4)
+	assert.Equal(t, rdf.Triple{Subject: ex + "c", Predicate: rdf.Type, Object: rdf.IRI(ex + "Thing")}, g.Triples[2])
+}
+
+func TestReadTurtle_Errors(t *testing.T) {
+	for doc, msg := range map[string]string{
+		"<a> <b> _:c .":                      "line 1: blank nodes are not supported",
+		"<a> <b> [ <c> <d> ] .":              "line 1: blank nodes are not supported",
+		"<a> <b> <c>":                        "line 1: expected '.'",
+		"\nex:a <b> <c> .":                   `line 2: undefined prefix "ex"`,
+		`<a> <b> "unterminated .`:            "line 1: unterminated string",
+		"@prefix ex: <http://example.org/> ": "line 1: expected '.'",
+	} {
+		_, err := rdf.ReadTurtle(strings.NewReader(doc))
+		if assert.NotNil(t, err, doc) {
+			assert.Equal(t, msg, err.Error(), doc)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// localName matches the local names which can be written as prefixed names without escaping.
+var localName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// WriteTurtle writes the graph as Turtle, see https://www.w3.org/TR/turtle/.
+// IRIs within the namespace of a prefix are abbreviated, and the triples are grouped by subject.
+func WriteTurtle(w io.Writer, g *Graph, prefixes ...Prefix) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range prefixes {
+		fmt.Fprintf(bw, "@prefix %s: %s .\n", p.Name, IRI(p.Namespace).NTriples())
+	}
+
+	for _, s := range g.subjects() {
+		fmt.Fprintf(bw, "\n%s", turtleTerm(s, prefixes))
+		predicates, objects := g.properties(s)
+		for i, p := range predicates {
+			if i > 0 {
+				bw.WriteString(" ;")
+			}
+			fmt.Fprintf(bw, "\n    %s ", turtlePredicate(p, prefixes))
+			for j, o := range objects[p] {
+				if j > 0 {
+					bw.WriteString(", ")
+				}
+				bw.WriteString(turtleTerm(o, prefixes))
+			}
+		}
+		bw.WriteString(" .\n")
+	}
+	return bw.Flush()
+}
+
+// turtlePredicate writes rdf:type as "a" and all other predicates as terms.
+func turtlePredicate(p IRI, prefixes []Prefix) string {
+	if p == Type {
+		return "a"
+	}
+	return turtleTerm(p, prefixes)
+}
+
+// turtleTerm abbreviates IRIs and datatypes using the prefixes.
+func turtleTerm(t Term, prefixes []Prefix) string {
+	switch v := t.(type) {
+	case IRI:
+		return compact(v, prefixes)
+	case Literal:
+		s := "\"" + escapeString(v.Value) + "\""
+		switch {
+		case v.Language != "":
+			s += "@" + v.Language
+		case v.Datatype != "" && v.Datatype != XSD+"string":
+			s += "^^" + compact(v.Datatype, prefixes)
+		}
+		return s
+	}
+	return t.NTriples()
+}
+
+// compact returns the prefixed name of the IRI, or the full IRI if no prefix matches.
+func compact(i IRI, prefixes []Prefix) string {
+	if name, ok := prefixedName(i, prefixes); ok {
+		return name
+	}
+	return i.NTriples()
+}
+
+// prefixedName returns the IRI as prefix:local if it lies within the namespace of one of the prefixes.
+func prefixedName(i IRI, prefixes []Prefix) (string, bool) {
+	for _, p := range prefixes {
+		if local := strings.TrimPrefix(string(i), p.Namespace); local != string(i) && localName.MatchString(local) {
+			return p.Name + ":" + local, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,471 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rdf
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// number matches the integer, decimal and double literals of Turtle.
+var number = regexp.MustCompile(`^[+-]?([0-9]+\.[0-9]+|\.[0-9]+|[0-9]+)([eE][+-]?[0-9]+)?`)
+
+// language matches the language tag of a literal.
+var language = regexp.MustCompile(`^@[A-Za-z]+(-[A-Za-z0-9]+)*`)
+
+// ReadTurtle reads a graph written as Turtle or TriG, see https://www.w3.org/TR/turtle/ and https://www.w3.org/TR/trig/.
+// The triples of all graphs of a TriG document are read into the same graph.
+// Like the rest of the package, it does not support blank nodes, nor collections.
+func ReadTurtle(r io.Reader) (*Graph, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &turtleParser{src: string(b), prefixes: map[string]string{}}
+	g := &Graph{}
+	for p.peek() != 0 {
+		if err := p.statement(g); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// turtleParser is a recursive descent parser of Turtle and TriG documents.
+type turtleParser struct {
+	src      string
+	pos      int
+	base     *url.URL
+	prefixes map[string]string
+}
+
+// errorf returns an error at the line of the current position.
+func (p *turtleParser) errorf(format string, args ...interface{}) error {
+	line := 1 + strings.Count(p.src[:p.pos], "\n")
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// statement reads a directive, a TriG graph or the triples of a subject.
+func (p *turtleParser) statement(g *Graph) error {
+	switch {
+	case p.keyword("@prefix"):
+		return p.prefix(true)
+	case p.keyword("@base"):
+		return p.baseIRI(true)
+	case p.keyword("PREFIX"):
+		return p.prefix(false)
+	case p.keyword("BASE"):
+		return p.baseIRI(false)
+	case p.keyword("GRAPH"):
+		if _, err := p.iri(); err != nil {
+			return err
+		}
+		return p.graph(g)
+	case p.peek() == '{':
+		return p.graph(g)
+	}
+
+	subject, err := p.iri()
+	if err != nil {
+		return err
+	}
+	// the subject is the name of a TriG graph
+	if p.peek() == '{' {
+		return p.graph(g)
+	}
+	if err := p.predicateObjectList(g, subject); err != nil {
+		return err
+	}
+	return p.expect('.')
+}
+
+// prefix reads the name and namespace of a prefix declaration. Turtle declarations end with a dot, SPARQL ones do not.
+func (p *turtleParser) prefix(turtle bool) error {
+	p.skip()
+	i := strings.IndexByte(p.src[p.pos:], ':')
+	if i < 0 || strings.ContainsAny(p.src[p.pos:p.pos+i], " \t\r\n") {
+		return p.errorf("expected a prefix name")
+	}
+	name := p.src[p.pos : p.pos+i]
+	p.pos += i + 1
+
+	ns, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	p.prefixes[name] = string(ns)
+
+	if turtle {
+		return p.expect('.')
+	}
+	return nil
+}
+
+// baseIRI reads a base declaration, against which relative IRIs are resolved.
+func (p *turtleParser) baseIRI(turtle bool) error {
+	base, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(string(base))
+	if err != nil {
+		return p.errorf("invalid base IRI: %v", err)
+	}
+	p.base = u
+
+	if turtle {
+		return p.expect('.')
+	}
+	return nil
+}
+
+// graph reads the triples of a TriG graph between braces. The dot after the last triple is optional.
+func (p *turtleParser) graph(g *Graph) error {
+	if err := p.expect('{'); err != nil {
+		return err
+	}
+	for p.peek() != '}' {
+		if p.peek() == 0 {
+			return p.errorf("expected '}'")
+		}
+		subject, err := p.iri()
+		if err != nil {
+			return err
+		}
+		if err := p.predicateObjectList(g, subject); err != nil {
+			return err
+		}
+		switch p.peek() {
+		case '.':
+			p.pos++
+		case '}':
+		default:
+			return p.errorf("expected '.' or '}'")
+		}
+	}
+	p.pos++
+	return nil
+}
+
+// predicateObjectList reads the predicates and objects of the subject, separated by semicolons and commas.
+func (p *turtleParser) predicateObjectList(g *Graph, subject IRI) error {
+	for {
+		predicate := IRI(Type)
+		if !p.keyword("a") {
+			var err error
+			if predicate, err = p.iri(); err != nil {
+				return err
+			}
+		}
+
+		for {
+			object, err := p.object()
+			if err != nil {
+				return err
+			}
+			g.Add(subject, predicate, object)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+
+		if p.peek() != ';' {
+			return nil
+		}
+		for p.peek() == ';' {
+			p.pos++
+		}
+		if c := p.peek(); c == '.' || c == '}' {
+			return nil
+		}
+	}
+}
+
+// object reads an IRI or a literal.
+func (p *turtleParser) object() (Term, error) {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.literal()
+	case p.keyword("true"):
+		return Typed("true", XSD+"boolean"), nil
+	case p.keyword("false"):
+		return Typed("false", XSD+"boolean"), nil
+	}
+
+	if n := number.FindString(p.src[p.pos:]); n != "" {
+		p.pos += len(n)
+		switch {
+		case strings.ContainsAny(n, "eE"):
+			return Typed(n, XSD+"double"), nil
+		case strings.Contains(n, "."):
+			return Typed(n, XSD+"decimal"), nil
+		}
+		return Typed(n, XSD+"integer"), nil
+	}
+
+	return p.iri()
+}
+
+// literal reads a quoted string, followed by an optional language tag or datatype.
+func (p *turtleParser) literal() (Literal, error) {
+	quote := p.src[p.pos : p.pos+1]
+	long := strings.HasPrefix(p.src[p.pos:], strings.Repeat(quote, 3))
+	if long {
+		quote = strings.Repeat(quote, 3)
+	}
+	p.pos += len(quote)
+
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			return Literal{}, p.errorf("unterminated string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], quote) {
+			p.pos += len(quote)
+			break
+		}
+		switch c := p.src[p.pos]; {
+		case c == '\\':
+			r, err := p.escape(true)
+			if err != nil {
+				return Literal{}, err
+			}
+			b.WriteRune(r)
+		case !long && (c == '\n' || c == '\r'):
+			return Literal{}, p.errorf("line break in string")
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	l := String(b.String())
+	if tag := language.FindString(p.src[p.pos:]); tag != "" {
+		p.pos += len(tag)
+		l.Language = strings.ToLower(tag[1:])
+	} else if strings.HasPrefix(p.src[p.pos:], "^^") {
+		p.pos += 2
+		datatype, err := p.iri()
+		if err != nil {
+			return Literal{}, err
+		}
+		l.Datatype = datatype
+	}
+	return l, nil
+}
+
+// escape reads an escape sequence. Strings allow more escapes than IRIs, which only allow \u and \U.
+func (p *turtleParser) escape(inString bool) (rune, error) {
+	if p.pos+1 >= len(p.src) {
+		return 0, p.errorf("unterminated escape sequence")
+	}
+	c := p.src[p.pos+1]
+	p.pos += 2
+
+	if c == 'u' || c == 'U' {
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return 0, p.errorf("invalid escape sequence")
+		}
+		v, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(v)) {
+			return 0, p.errorf("invalid escape sequence")
+		}
+		p.pos += n
+		return rune(v), nil
+	}
+
+	if inString {
+		switch c {
+		case 't':
+			return '\t', nil
+		case 'b':
+			return '\b', nil
+		case 'n':
+			return '\n', nil
+		case 'r':
+			return '\r', nil
+		case 'f':
+			return '\f', nil
+		case '"', '\'', '\\':
+			return rune(c), nil
+		}
+	}
+	return 0, p.errorf("invalid escape sequence \\%c", c)
+}
+
+// iri reads an IRI, either written in angle brackets or as a prefixed name.
+func (p *turtleParser) iri() (IRI, error) {
+	switch c := p.peek(); {
+	case c == '<':
+		return p.iriRef()
+	case c == '[' || strings.HasPrefix(p.src[p.pos:], "_:"):
+		return "", p.errorf("blank nodes are not supported")
+	case c == '(':
+		return "", p.errorf("collections are not supported")
+	case c == 0:
+		return "", p.errorf("unexpected end of document")
+	}
+	return p.prefixedName()
+}
+
+// iriRef reads an IRI in angle brackets and resolves it against the base IRI.
+func (p *turtleParser) iriRef() (IRI, error) {
+	if err := p.expect('<'); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			return "", p.errorf("unterminated IRI")
+		}
+		c := p.src[p.pos]
+		if c == '>' {
+			p.pos++
+			break
+		}
+		if c == '\\' {
+			r, err := p.escape(false)
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+			continue
+		}
+		if c <= 0x20 || strings.IndexByte("<\"{}|^`", c) >= 0 {
+			return "", p.errorf("invalid character %q in IRI", c)
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+
+	iri := b.String()
+	if p.base != nil {
+		ref, err := url.Parse(iri)
+		if err != nil {
+			return "", p.errorf("invalid IRI: %v", err)
+		}
+		iri = p.base.ResolveReference(ref).String()
+	}
+	return IRI(iri), nil
+}
+
+// prefixedName reads a name of the form prefix:local and expands it with the namespace of the prefix.
+func (p *turtleParser) prefixedName() (IRI, error) {
+	start := p.pos
+	var local strings.Builder
+	prefix, inLocal := "", false
+	for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+		c := p.src[p.pos]
+		switch {
+		case !inLocal && c == ':':
+			prefix, inLocal = p.src[start:p.pos], true
+		case inLocal && c == '\\' && p.pos+1 < len(p.src):
+			// local names escape reserved characters, e.g. ex:a\,b
+			p.pos++
+			local.WriteByte(p.src[p.pos])
+		default:
+			if inLocal {
+				local.WriteByte(c)
+			}
+		}
+		p.pos++
+	}
+
+	// a trailing dot ends the statement, it is not part of the name
+	name := local.String()
+	for strings.HasSuffix(name, ".") && p.src[p.pos-1] == '.' {
+		name = strings.TrimSuffix(name, ".")
+		p.pos--
+	}
+
+	if !inLocal {
+		p.pos = start
+		return "", p.errorf("expected an IRI")
+	}
+	ns, ok := p.prefixes[prefix]
+	if !ok {
+		return "", p.errorf("undefined prefix %q", prefix)
+	}
+	return IRI(ns + name), nil
+}
+
+// keyword reports whether the next token is the keyword, ignoring case, and consumes it if so.
+func (p *turtleParser) keyword(k string) bool {
+	p.skip()
+	end := p.pos + len(k)
+	if end > len(p.src) || !strings.EqualFold(p.src[p.pos:end], k) {
+		return false
+	}
+	if end < len(p.src) && isNameChar(p.src[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// expect consumes the next character, which must be c.
+func (p *turtleParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+// peek returns the next character after white space and comments, or 0 at the end of the document.
+func (p *turtleParser) peek() byte {
+	p.skip()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// skip skips white space and comments.
+func (p *turtleParser) skip() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// isNameChar reports whether c can be part of a prefixed name. All non-ASCII characters are accepted.
+func isNameChar(c byte) bool {
+	return c >= 0x80 || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_-:.%\\", c) >= 0
+}
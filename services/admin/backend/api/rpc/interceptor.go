@@ -0,0 +1,132 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rpc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcMethod is recorded as method of the metrics of gRPC calls, to tell them apart from http requests.
+const grpcMethod = "GRPC"
+
+// credentialHeaders are the metadata keys passed on to the authenticator.
+var credentialHeaders = []string{"authorization", "x-api-key"}
+
+// Authenticator authenticates the caller of a request, see middleware.Authenticator.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*principal.Principal, error)
+}
+
+// MetricService records the duration of the calls.
+type MetricService interface {
+	SaveHTTP(h *metric.HTTP)
+}
+
+// AuthInterceptors return interceptors which authenticate every call with the credentials found in the metadata
+// and store the resulting principal in the context, where it can be retrieved with principal.FromContext.
+// The same credentials as for the REST api are accepted. Calls that cannot be authenticated are rejected.
+func AuthInterceptors(auth Authenticator) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authenticate := func(ctx context.Context) (context.Context, error) {
+		// the credentials are provided to the authenticator the same way as for http requests
+		r := (&http.Request{Header: http.Header{}}).WithContext(ctx)
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, key := range credentialHeaders {
+				for _, v := range md.Get(key) {
+					r.Header.Add(key, v)
+				}
+			}
+		}
+
+		p, err := auth.Authenticate(r)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return principal.NewContext(ctx, p), nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+
+	return unary, stream
+}
+
+// MetricsInterceptors return interceptors which record the duration and status code of every call.
+func MetricsInterceptors(mService MetricService) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		appMetric := metric.NewHTTP(info.FullMethod, grpcMethod)
+		appMetric.Started()
+		res, err := handler(ctx, req)
+		appMetric.Finished()
+		appMetric.StatusCode = status.Code(err).String()
+		mService.SaveHTTP(appMetric)
+		return res, err
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		appMetric := metric.NewHTTP(info.FullMethod, grpcMethod)
+		appMetric.Started()
+		err := handler(srv, ss)
+		appMetric.Finished()
+		appMetric.StatusCode = status.Code(err).String()
+		mService.SaveHTTP(appMetric)
+		return err
+	}
+
+	return unary, stream
+}
+
+// authenticated returns the principal of the call.
+func authenticated(ctx context.Context) (*principal.Principal, error) {
+	user, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, principal.ErrNotAuthenticated.Error())
+	}
+	return user, nil
+}
+
+// contextStream is a server stream with a context replaced by an interceptor.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
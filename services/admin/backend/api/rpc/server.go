@@ -0,0 +1,378 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rpc/projectpb"
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxPageSize is the maximum number of projects returned by ListProjects.
+const maxPageSize = 100
+
+// watchBuffer is the number of events buffered for each client of WatchProjects.
+const watchBuffer = 64
+
+// ProjectServer implements the gRPC project service by calling the project use case.
+// The principal is expected in the context of every call, see AuthInterceptors.
+type ProjectServer struct {
+	projectpb.UnimplementedProjectServiceServer
+	service project.UseCase
+}
+
+// NewProjectServer creates a new gRPC project service.
+func NewProjectServer(service project.UseCase) *ProjectServer {
+	return &ProjectServer{
+		service: service,
+	}
+}
+
+// NewServer creates a gRPC server offering the project service, which authenticates every call and records its metrics.
+func NewServer(service project.UseCase, auth Authenticator, metrics MetricService) *grpc.Server {
+	metricsUnary, metricsStream := MetricsInterceptors(metrics)
+	authUnary, authStream := AuthInterceptors(auth)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(metricsUnary, authUnary),
+		grpc.ChainStreamInterceptor(metricsStream, authStream),
+	)
+	projectpb.RegisterProjectServiceServer(s, NewProjectServer(service))
+
+	return s
+}
+
+// GetProject gets a project.
+func (s *ProjectServer) GetProject(ctx context.Context, req *projectpb.GetProjectRequest) (*projectpb.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanRead(user, id) {
+		return nil, toStatus(projectEntity.ErrUserDoesNotHaveReadProjectPermission)
+	}
+
+	p, err := s.service.GetProject(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return newProject(p), nil
+}
+
+// ListProjects gets a page of projects.
+func (s *ProjectServer) ListProjects(ctx context.Context, req *projectpb.ListProjectsRequest) (*projectpb.ListProjectsResponse, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanList(user) {
+		return nil, toStatus(projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission)
+	}
+
+	if req.GetPageSize() < 0 || req.GetPageSize() > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 0 and %d", maxPageSize)
+	}
+
+	q := project.Query{
+		Limit:      int(req.GetPageSize()),
+		After:      req.GetPageToken(),
+		Descending: strings.HasPrefix(req.GetSort(), "-"),
+		Sort:       project.SortField(strings.TrimPrefix(req.GetSort(), "-")),
+		Status:     project.Status(req.GetStatus()),
+		Name:       req.GetName(),
+	}
+
+	// project admins only get the projects they have access to
+	if projectEntity.IsRestrictedToOwnProjects(user) {
+		q.ProjectIDs = append([]string{}, user.Projects...)
+	}
+
+	result, err := s.service.QueryProjects(ctx, q)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &projectpb.ListProjectsResponse{
+		NextPageToken: result.NextCursor,
+		TotalSize:     int32(result.Total),
+	}
+	for i := range result.Projects {
+		res.Projects = append(res.Projects, newProject(&result.Projects[i]))
+	}
+
+	return res, nil
+}
+
+// CreateProject creates a project.
+func (s *ProjectServer) CreateProject(ctx context.Context, req *projectpb.CreateProjectRequest) (*projectpb.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanCreate(user) {
+		return nil, toStatus(projectEntity.ErrUserDoesNotHaveCreateProjectsPermission)
+	}
+
+	sc, sn, ln, desc, err := parseValues(req.GetShortCode(), req.GetShortName(), req.GetLongName(), req.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.service.CreateProject(ctx, sc, sn, ln, desc)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	p, err := s.service.GetProject(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return newProject(p), nil
+}
+
+// UpdateProject replaces all values of a project.
+func (s *ProjectServer) UpdateProject(ctx context.Context, req *projectpb.UpdateProjectRequest) (*projectpb.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanUpdate(user, id) {
+		return nil, toStatus(projectEntity.ErrUserDoesNotHaveUpdateProjectPermission)
+	}
+
+	sc, sn, ln, desc, err := parseValues(req.GetShortCode(), req.GetShortName(), req.GetLongName(), req.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.service.UpdateProject(ctx, id, sc, sn, ln, desc)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return newProject(p), nil
+}
+
+// DeleteProject marks a project as deleted.
+func (s *ProjectServer) DeleteProject(ctx context.Context, req *projectpb.DeleteProjectRequest) (*projectpb.Project, error) {
+	user, err := authenticated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !projectEntity.CanDelete(user) {
+		return nil, toStatus(projectEntity.ErrUserDoesNotHaveDeleteProjectPermission)
+	}
+
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.service.DeleteProject(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return newProject(p), nil
+}
+
+// WatchProjects streams the changes of all projects the caller may read, until the client cancels the call.
+func (s *ProjectServer) WatchProjects(req *projectpb.WatchProjectsRequest, stream projectpb.ProjectService_WatchProjectsServer) error {
+	ctx := stream.Context()
+
+	user, err := authenticated(ctx)
+	if err != nil {
+		return err
+	}
+	if !projectEntity.CanList(user) {
+		return toStatus(projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission)
+	}
+	restricted := projectEntity.IsRestrictedToOwnProjects(user)
+
+	// the events are handed over to this goroutine, a slow client holds back the subscription
+	events := make(chan event.Event, watchBuffer)
+	err = s.service.WatchProjects(ctx, func(ev event.Event) {
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+		}
+	})
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-events:
+			res := newProjectEvent(ev)
+			if res == nil || (restricted && !user.HasProject(res.ProjectId)) {
+				continue
+			}
+
+			id, err := parseID(res.ProjectId)
+			if err != nil {
+				return err
+			}
+			p, err := s.service.GetProject(ctx, id)
+			if err != nil {
+				return toStatus(err)
+			}
+			res.Project = newProject(p)
+
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseID converts the id of a request to an identifier.
+func parseID(id string) (valueobject.Identifier, error) {
+	uuid, err := valueobject.IdentifierFromBytes([]byte(id))
+	if err != nil {
+		return valueobject.Identifier{}, toStatus(projectEntity.ErrInvalidUUID)
+	}
+	return uuid, nil
+}
+
+// parseValues converts the values of a project provided in a request to value objects.
+// The returned error lists every invalid field of the request.
+func parseValues(shortCode string, shortName string, longName string, description string) (valueobject.ShortCode, valueobject.ShortName, valueobject.LongName, valueobject.Description, error) {
+	v := valueobject.Validation{}
+	sc := v.ShortCode("short_code", shortCode)
+	sn := v.ShortName("short_name", shortName)
+	ln := v.LongName("long_name", longName)
+	desc := v.Description("description", description)
+	if err := v.Err(); err != nil {
+		return valueobject.ShortCode{}, valueobject.ShortName{}, valueobject.LongName{}, valueobject.Description{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return sc, sn, ln, desc, nil
+}
+
+// toStatus converts errors of the project use case to gRPC status errors.
+func toStatus(err error) error {
+	switch err {
+	case projectEntity.ErrProjectNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case projectEntity.ErrInvalidUUID, projectEntity.ErrInvalidSortField, projectEntity.ErrInvalidStatus, projectEntity.ErrInvalidCursor:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case projectEntity.ErrShortCodeAlreadyExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case projectEntity.ErrProjectHasBeenDeleted, projectEntity.ErrNoPropertiesChanged:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case projectEntity.ErrUserDoesNotHaveCreateProjectsPermission,
+		projectEntity.ErrUserDoesNotHaveReadProjectPermission,
+		projectEntity.ErrUserDoesNotHaveReadAllProjectsPermission,
+		projectEntity.ErrUserDoesNotHaveUpdateProjectPermission,
+		projectEntity.ErrUserDoesNotHaveDeleteProjectPermission:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Internal, projectEntity.ErrServerNotResponding.Error())
+}
+
+// timestamp converts the timestamp to protobuf, nil is returned if it has not been set.
+func timestamp(t valueobject.Timestamp) *timestamppb.Timestamp {
+	if t.Time().IsZero() {
+		return nil
+	}
+	return timestamppb.New(t.Time())
+}
+
+// identifier converts the identifier to a string, an empty string is returned if it has not been set.
+func identifier(id valueobject.Identifier) string {
+	if id == (valueobject.Identifier{}) {
+		return ""
+	}
+	return id.String()
+}
+
+// newProject converts the project aggregate to protobuf.
+func newProject(p *projectEntity.Aggregate) *projectpb.Project {
+	return &projectpb.Project{
+		Id:          p.ID().String(),
+		ShortCode:   p.ShortCode().String(),
+		ShortName:   p.ShortName().String(),
+		LongName:    p.LongName().String(),
+		Description: p.Description().String(),
+		CreatedAt:   timestamp(p.CreatedAt()),
+		CreatedBy:   identifier(p.CreatedBy()),
+		ChangedAt:   timestamp(p.ChangedAt()),
+		ChangedBy:   identifier(p.ChangedBy()),
+		DeletedAt:   timestamp(p.DeletedAt()),
+		DeletedBy:   identifier(p.DeletedBy()),
+		Keywords:    p.Keywords(),
+		Creator:     actor(p.Creator()),
+		Changer:     actor(p.Changer()),
+		Deleter:     actor(p.Deleter()),
+	}
+}
+
+// actor converts the actor to protobuf, or returns nil if it has not been set.
+func actor(a event.Actor) *projectpb.Actor {
+	if a.ID == (valueobject.Identifier{}) {
+		return nil
+	}
+	return &projectpb.Actor{Id: a.ID.String(), Type: string(a.Type)}
+}
+
+// newProjectEvent converts the project event to protobuf, without the state of the project.
+// It returns nil for events of other aggregates.
+func newProjectEvent(ev event.Event) *projectpb.ProjectEvent {
+	switch e := ev.(type) {
+	case *event.ProjectCreated:
+		return &projectpb.ProjectEvent{Type: "ProjectCreated", ProjectId: e.ID.String(), At: timestamp(e.CreatedAt), By: identifier(e.CreatedBy), Actor: actor(event.Actor{ID: e.CreatedBy, Type: e.CreatedByType})}
+	case *event.ProjectChanged:
+		return &projectpb.ProjectEvent{Type: "ProjectChanged", ProjectId: e.ID.String(), At: timestamp(e.ChangedAt), By: identifier(e.ChangedBy), Actor: actor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectShortCodeChanged:
+		return &projectpb.ProjectEvent{Type: "ProjectShortCodeChanged", ProjectId: e.ID.String(), At: timestamp(e.ChangedAt), By: identifier(e.ChangedBy), Actor: actor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectShortNameChanged:
+		return &projectpb.ProjectEvent{Type: "ProjectShortNameChanged", ProjectId: e.ID.String(), At: timestamp(e.ChangedAt), By: identifier(e.ChangedBy), Actor: actor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectLongNameChanged:
+		return &projectpb.ProjectEvent{Type: "ProjectLongNameChanged", ProjectId: e.ID.String(), At: timestamp(e.ChangedAt), By: identifier(e.ChangedBy), Actor: actor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectDescriptionChanged:
+		return &projectpb.ProjectEvent{Type: "ProjectDescriptionChanged", ProjectId: e.ID.String(), At: timestamp(e.ChangedAt), By: identifier(e.ChangedBy), Actor: actor(event.Actor{ID: e.ChangedBy, Type: e.ChangedByType})}
+	case *event.ProjectDeleted:
+		return &projectpb.ProjectEvent{Type: "ProjectDeleted", ProjectId: e.ID.String(), At: timestamp(e.DeletedAt), By: identifier(e.DeletedBy), Actor: actor(event.Actor{ID: e.DeletedBy, Type: e.DeletedByType})}
+	case *event.ProjectRestored:
+		return &projectpb.ProjectEvent{Type: "ProjectRestored", ProjectId: e.ID.String(), At: timestamp(e.RestoredAt), By: identifier(e.RestoredBy), Actor: actor(event.Actor{ID: e.RestoredBy, Type: e.RestoredByType})}
+	}
+	return nil
+}
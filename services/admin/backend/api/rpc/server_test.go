@@ -0,0 +1,195 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rpc"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rpc/projectpb"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// principals of the tokens accepted by the test server.
+var principals = stubAuthenticator{
+	"Bearer admin":   {ID: "3018c9db-7a65-44e7-b31a-0d547a10b75b", IsSystemAdmin: true},
+	"Bearer nobody":  {ID: "6dd2d5c9-86a8-4e4d-8d0b-0c3b9fd6c2bc"},
+	"ApiKey reader":  {ID: "b1a8c3c4-98f6-4a34-9d43-2f4d5a1b0c7e", IsServiceAccount: true, Scopes: []string{"projects:read"}},
+	"Bearer project": {ID: "9a3c2f3e-2d2b-4b6f-8f52-0c5e8c1a7d10", IsProjectAdmin: true},
+}
+
+// newClient starts a gRPC server on an in-memory connection and returns a client connected to it.
+func newClient(t *testing.T, service *stubProjectService, metrics *stubMetrics) projectpb.ProjectServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+	s := rpc.NewServer(service, principals, metrics)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return projectpb.NewProjectServiceClient(conn)
+}
+
+// withToken returns a context sending the credentials as metadata.
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", token)
+}
+
+func TestProjectServer_CRUD(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	client := newClient(t, service, &stubMetrics{})
+	ctx := withToken(context.Background(), "Bearer admin")
+
+	created, err := client.CreateProject(ctx, &projectpb.CreateProjectRequest{ShortCode: "0801", ShortName: "beol", LongName: "Bernoulli-Euler Online", Description: "Letters"})
+	assert.Nil(t, err)
+	assert.Equal(t, "0801", created.GetShortCode())
+	assert.Equal(t, principals["Bearer admin"].ID, created.GetCreatedBy())
+	assert.Equal(t, principals["Bearer admin"].ID, created.GetCreator().GetId())
+	assert.Equal(t, "user", created.GetCreator().GetType())
+	assert.Nil(t, created.GetChanger())
+	assert.NotNil(t, created.GetCreatedAt())
+	assert.Nil(t, created.GetChangedAt())
+
+	got, err := client.GetProject(ctx, &projectpb.GetProjectRequest{Id: created.GetId()})
+	assert.Nil(t, err)
+	assert.Equal(t, "beol", got.GetShortName())
+
+	updated, err := client.UpdateProject(ctx, &projectpb.UpdateProjectRequest{Id: created.GetId(), ShortCode: "0801", ShortName: "beol", LongName: "Bernoulli-Euler Online", Description: "Manuscripts"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Manuscripts", updated.GetDescription())
+	assert.NotNil(t, updated.GetChangedAt())
+
+	list, err := client.ListProjects(ctx, &projectpb.ListProjectsRequest{PageSize: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), list.GetTotalSize())
+	assert.Len(t, list.GetProjects(), 2)
+
+	deleted, err := client.DeleteProject(ctx, &projectpb.DeleteProjectRequest{Id: created.GetId()})
+	assert.Nil(t, err)
+	assert.NotNil(t, deleted.GetDeletedAt())
+	assert.Equal(t, principals["Bearer admin"].ID, deleted.GetDeleter().GetId())
+	assert.Equal(t, "user", deleted.GetDeleter().GetType())
+}
+
+func TestProjectServer_Keywords(t *testing.T) {
+	service := newStubProjectService(t, 0)
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Bernoullis and Euler")
+	service.projects = append(service.projects, project.NewMigratedAggregate(id, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", sc, sn, ln, desc, []string{"mathematics", "letters"}, valueobject.NewTimestamp()))
+	client := newClient(t, service, &stubMetrics{})
+
+	got, err := client.GetProject(withToken(context.Background(), "Bearer admin"), &projectpb.GetProjectRequest{Id: id.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"mathematics", "letters"}, got.GetKeywords())
+}
+
+func TestProjectServer_Errors(t *testing.T) {
+	service := newStubProjectService(t, 1)
+	client := newClient(t, service, &stubMetrics{})
+
+	_, err := client.GetProject(context.Background(), &projectpb.GetProjectRequest{Id: service.projects[0].ID().String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetProject(withToken(context.Background(), "Bearer nobody"), &projectpb.GetProjectRequest{Id: service.projects[0].ID().String()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.CreateProject(withToken(context.Background(), "ApiKey reader"), &projectpb.CreateProjectRequest{ShortCode: "0801"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetProject(withToken(context.Background(), "Bearer admin"), &projectpb.GetProjectRequest{Id: "not a uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetProject(withToken(context.Background(), "Bearer admin"), &projectpb.GetProjectRequest{Id: "b9d7a6e4-dcd6-43ff-a928-f55e9e8097f8"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.CreateProject(withToken(context.Background(), "Bearer admin"), &projectpb.CreateProjectRequest{ShortCode: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ListProjects(withToken(context.Background(), "Bearer admin"), &projectpb.ListProjectsRequest{PageSize: 1000})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestProjectServer_WatchProjects(t *testing.T) {
+	service := newStubProjectService(t, 2)
+	client := newClient(t, service, &stubMetrics{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// project admins only get the events of their own projects
+	principals["Bearer project"].Projects = []string{service.projects[1].ID().String()}
+
+	stream, err := client.WatchProjects(withToken(ctx, "Bearer project"), &projectpb.WatchProjectsRequest{})
+	assert.Nil(t, err)
+
+	// wait for the server to start watching
+	for service.watching() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	adminCtx := principal.NewContext(ctx, principals["Bearer admin"])
+	service.DeleteProject(adminCtx, service.projects[0].ID())
+	service.DeleteProject(adminCtx, service.projects[1].ID())
+
+	ev, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "ProjectDeleted", ev.GetType())
+	assert.Equal(t, service.projects[1].ID().String(), ev.GetProjectId())
+	assert.Equal(t, principals["Bearer admin"].ID, ev.GetBy())
+	assert.Equal(t, principals["Bearer admin"].ID, ev.GetActor().GetId())
+	assert.Equal(t, "user", ev.GetActor().GetType())
+	assert.NotNil(t, ev.GetProject().GetDeletedAt())
+}
+
+func TestMetricsInterceptors(t *testing.T) {
+	metrics := &stubMetrics{}
+	client := newClient(t, newStubProjectService(t, 1), metrics)
+
+	_, err := client.ListProjects(withToken(context.Background(), "Bearer admin"), &projectpb.ListProjectsRequest{})
+	assert.Nil(t, err)
+	_, err = client.ListProjects(context.Background(), &projectpb.ListProjectsRequest{})
+	assert.NotNil(t, err)
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Len(t, metrics.calls, 2)
+	assert.Equal(t, "/dasch.admin.v1.ProjectService/ListProjects", metrics.calls[0].Handler)
+	assert.Equal(t, "OK", metrics.calls[0].StatusCode)
+	assert.Equal(t, "Unauthenticated", metrics.calls[1].StatusCode)
+}
@@ -0,0 +1,191 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	projectService "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/metric"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+//stubProjectService is a project use case working on a fixed list of projects (only visible inside this package)
+type stubProjectService struct {
+	mu       sync.Mutex
+	projects []*project.Aggregate
+	watchers []func(ev event.Event)
+}
+
+//newStubProjectService creates a stub project use case containing n projects
+func newStubProjectService(t *testing.T, n int) *stubProjectService {
+	s := &stubProjectService{}
+	for i := 0; i < n; i++ {
+		id, _ := valueobject.NewIdentifier()
+		sc, err := valueobject.NewShortCode(fmt.Sprintf("%04X", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sn, _ := valueobject.NewShortName(fmt.Sprintf("project %d", i))
+		ln, _ := valueobject.NewLongName(fmt.Sprintf("long name of project %d", i))
+		desc, _ := valueobject.NewDescription(fmt.Sprintf("description of project %d", i))
+		createdBy, _ := valueobject.NewIdentifier()
+		s.projects = append(s.projects, project.NewAggregate(id, sc, sn, ln, desc, event.Actor{ID: createdBy, Type: event.ActorUser}))
+	}
+	return s
+}
+
+func (s *stubProjectService) GetProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
+	for _, p := range s.projects {
+		if p.ID() == id {
+			return p, nil
+		}
+	}
+	return nil, project.ErrProjectNotFound
+}
+
+func (s *stubProjectService) GetProjectHistory(ctx context.Context, id valueobject.Identifier) ([]event.Event, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p.Events(), nil
+}
+
+func (s *stubProjectService) ListProjects(ctx context.Context, returnDeletedProjects bool) ([]project.Aggregate, error) {
+	var projects []project.Aggregate
+	for _, p := range s.projects {
+		if returnDeletedProjects || p.DeletedAt().Time().IsZero() {
+			projects = append(projects, *p)
+		}
+	}
+	return projects, nil
+}
+
+func (s *stubProjectService) QueryProjects(ctx context.Context, q projectService.Query) (projectService.Result, error) {
+	projects, err := s.ListProjects(ctx, q.Status == projectService.StatusAll)
+	if err != nil {
+		return projectService.Result{}, err
+	}
+	return projectService.Result{Projects: projects, Total: len(projects)}, nil
+}
+
+func (s *stubProjectService) SearchProjects(ctx context.Context, query string, limit int) ([]project.Aggregate, error) {
+	return s.ListProjects(ctx, false)
+}
+
+func (s *stubProjectService) CreateProject(ctx context.Context, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (valueobject.Identifier, error) {
+	id, _ := valueobject.NewIdentifier()
+	s.projects = append(s.projects, project.NewAggregate(id, shortCode, shortName, longName, description, principal.TypedActorFromContext(ctx)))
+	return id, nil
+}
+
+func (s *stubProjectService) UpdateProject(ctx context.Context, id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (*project.Aggregate, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.UpdateProject(id, shortCode, shortName, longName, description, principal.TypedActorFromContext(ctx))
+}
+
+func (s *stubProjectService) DeleteProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.DeleteProject(id, principal.TypedActorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	s.notify(p.Events()[len(p.Events())-1])
+	return p, nil
+}
+
+func (s *stubProjectService) RestoreProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
+	p, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.RestoreProject(principal.TypedActorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	s.notify(p.Events()[len(p.Events())-1])
+	return p, nil
+}
+
+func (s *stubProjectService) ImportProjects(ctx context.Context, rows []projectService.ImportRow, dryRun bool) ([]projectService.ImportResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubProjectService) WatchProjects(ctx context.Context, handle func(ev event.Event)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers = append(s.watchers, handle)
+	return nil
+}
+
+func (s *stubProjectService) WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error {
+	return nil
+}
+
+//watching returns the number of handlers watching the projects
+func (s *stubProjectService) watching() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.watchers)
+}
+
+//notify passes the event to all handlers watching the projects
+func (s *stubProjectService) notify(ev event.Event) {
+	s.mu.Lock()
+	watchers := append([]func(ev event.Event){}, s.watchers...)
+	s.mu.Unlock()
+	for _, handle := range watchers {
+		handle(ev)
+	}
+}
+
+//stubAuthenticator authenticates bearer tokens found in its map of principals (only visible inside this package)
+type stubAuthenticator map[string]*principal.Principal
+
+func (a stubAuthenticator) Authenticate(r *http.Request) (*principal.Principal, error) {
+	p, ok := a[r.Header.Get("Authorization")]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return p, nil
+}
+
+//stubMetrics records the metrics of all calls (only visible inside this package)
+type stubMetrics struct {
+	mu    sync.Mutex
+	calls []*metric.HTTP
+}
+
+func (m *stubMetrics) SaveHTTP(h *metric.HTTP) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, h)
+}
@@ -0,0 +1,105 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package sitemap
+
+import (
+	"context"
+	"log"
+
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/server"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// ProjectPath returns the path of the page of the project in the single page application.
+func ProjectPath(id valueobject.Identifier) string {
+	return "/projects/" + id.String()
+}
+
+// Watch adds the page of every active project to the sitemap and keeps the sitemap up to date, until the context is done.
+// Pages are updated from the project events, so the sitemap is not regenerated on every request.
+func Watch(ctx context.Context, service project.UseCase, sm *server.Sitemap) error {
+	// the events are watched before the projects are listed, so that no change gets lost in between
+	err := service.WatchProjects(ctx, func(ev event.Event) {
+		id, ok := projectID(ev)
+		if !ok {
+			return
+		}
+
+		// the current state of the project is loaded, because the listed projects may be more recent than the event
+		p, err := service.GetProject(ctx, id)
+		if err != nil {
+			log.Println("Unexpected failure while updating the sitemap: ", err.Error())
+			return
+		}
+		set(sm, p)
+	})
+	if err != nil {
+		return err
+	}
+
+	// deleted projects are never part of the sitemap
+	projects, err := service.ListProjects(ctx, false)
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		set(sm, &projects[i])
+	}
+
+	return nil
+}
+
+// set adds the page of the project to the sitemap, or removes it if the project has been deleted.
+func set(sm *server.Sitemap, p *projectEntity.Aggregate) {
+	if !p.DeletedAt().Time().IsZero() {
+		sm.Remove(ProjectPath(p.ID()))
+		return
+	}
+
+	lastMod := p.ChangedAt()
+	if lastMod.Time().IsZero() {
+		lastMod = p.CreatedAt()
+	}
+	sm.Set(ProjectPath(p.ID()), lastMod.Time())
+}
+
+// projectID returns the id of the project the event belongs to.
+func projectID(ev event.Event) (valueobject.Identifier, bool) {
+	switch e := ev.(type) {
+	case *event.ProjectCreated:
+		return e.ID, true
+	case *event.ProjectChanged:
+		return e.ID, true
+	case *event.ProjectShortCodeChanged:
+		return e.ID, true
+	case *event.ProjectShortNameChanged:
+		return e.ID, true
+	case *event.ProjectLongNameChanged:
+		return e.ID, true
+	case *event.ProjectDescriptionChanged:
+		return e.ID, true
+	case *event.ProjectDeleted:
+		return e.ID, true
+	case *event.ProjectRestored:
+		return e.ID, true
+	}
+	return valueobject.Identifier{}, false
+}
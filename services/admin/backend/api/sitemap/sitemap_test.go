@@ -0,0 +1,87 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package sitemap_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/sitemap"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/server"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+// createProject creates a project with values derived from the short code.
+func createProject(t *testing.T, service *project.Service, code string) valueobject.Identifier {
+	sc, _ := valueobject.NewShortCode(code)
+	sn, _ := valueobject.NewShortName("name " + code)
+	ln, _ := valueobject.NewLongName("long name " + code)
+	desc, _ := valueobject.NewDescription("description " + code)
+	id, err := service.CreateProject(context.Background(), sc, sn, ln, desc)
+	assert.Nil(t, err)
+	return id
+}
+
+// get returns the sitemap served at `/sitemap.xml`.
+func get(sm *server.Sitemap) string {
+	w := httptest.NewRecorder()
+	sm.ServeHTTP(w, httptest.NewRequest("GET", "/sitemap.xml", nil))
+	return w.Body.String()
+}
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	service := project.NewService(newStubRepository())
+
+	active := createProject(t, service, "00F1")
+	deleted := createProject(t, service, "00F2")
+	_, err := service.DeleteProject(ctx, deleted)
+	assert.Nil(t, err)
+
+	sm := server.NewSitemap("https://admin.dasch.swiss")
+	assert.Nil(t, sitemap.Watch(ctx, service, sm))
+
+	p, err := service.GetProject(ctx, active)
+	assert.Nil(t, err)
+	body := get(sm)
+	assert.Contains(t, body, "<loc>https://admin.dasch.swiss/projects/"+active.String()+"</loc>")
+	assert.Contains(t, body, "<lastmod>"+p.CreatedAt().Time().Format(time.RFC3339Nano)+"</lastmod>")
+	assert.NotContains(t, body, deleted.String())
+
+	// projects created later are added
+	created := createProject(t, service, "00F3")
+	assert.Contains(t, get(sm), "/projects/"+created.String())
+
+	// changes update the time the page was last modified
+	sc, _ := valueobject.NewShortCode("00F4")
+	sn, _ := valueobject.NewShortName("changed name")
+	ln, _ := valueobject.NewLongName("changed long name")
+	desc, _ := valueobject.NewDescription("changed description")
+	p, err = service.UpdateProject(ctx, active, sc, sn, ln, desc)
+	assert.Nil(t, err)
+	assert.Contains(t, get(sm), "<lastmod>"+p.ChangedAt().Time().Format(time.RFC3339Nano)+"</lastmod>")
+
+	// deleted projects are removed
+	_, err = service.DeleteProject(ctx, created)
+	assert.Nil(t, err)
+	assert.NotContains(t, get(sm), created.String())
+}
@@ -0,0 +1,77 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package sitemap_test
+
+import (
+	"context"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	projectService "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// stubRepository keeps the events of the projects in memory and passes saved events to its watchers.
+// Only the methods used by the sitemap are implemented.
+type stubRepository struct {
+	projectService.Repository
+	events   map[valueobject.Identifier][]event.Event
+	ids      []valueobject.Identifier
+	watchers []func(ev event.Event)
+}
+
+func newStubRepository() *stubRepository {
+	return &stubRepository{events: map[valueobject.Identifier][]event.Event{}}
+}
+
+func (r *stubRepository) Save(ctx context.Context, p *project.Aggregate) (valueobject.Identifier, error) {
+	if _, ok := r.events[p.ID()]; !ok {
+		r.ids = append(r.ids, p.ID())
+	}
+	r.events[p.ID()] = append(r.events[p.ID()], p.Events()...)
+	for _, ev := range p.Events() {
+		for _, handle := range r.watchers {
+			handle(ev)
+		}
+	}
+	return p.ID(), nil
+}
+
+func (r *stubRepository) Load(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
+	events, ok := r.events[id]
+	if !ok {
+		return nil, project.ErrProjectNotFound
+	}
+	return project.NewAggregateFromEvents(events), nil
+}
+
+func (r *stubRepository) GetProjectIds(ctx context.Context, returnDeletedProjects bool) ([]valueobject.Identifier, error) {
+	var ids []valueobject.Identifier
+	for _, id := range r.ids {
+		p, _ := r.Load(ctx, id)
+		if returnDeletedProjects || p.DeletedAt().Time().IsZero() {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *stubRepository) Watch(ctx context.Context, handle func(ev event.Event)) error {
+	r.watchers = append(r.watchers, handle)
+	return nil
+}
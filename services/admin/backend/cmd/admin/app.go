@@ -0,0 +1,135 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	esdb "github.com/EventStore/EventStore-Client-Go/client"
+	projectRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/subjectkey"
+	"gopkg.in/yaml.v3"
+)
+
+// app holds the global flags shared by all commands.
+type app struct {
+	client *client
+	// direct is true if the commands use the event store rather than the REST api.
+	direct     bool
+	eventStore string
+	output     string
+}
+
+// printers write a result in the output format selected with the -o flag.
+// The table printer is passed as argument, since every result is printed as a different table.
+var printers = map[string]func(w io.Writer, v interface{}, table func(w io.Writer)) error{
+	"table": func(w io.Writer, _ interface{}, table func(w io.Writer)) error {
+		table(w)
+		return nil
+	},
+	"json": func(w io.Writer, v interface{}, _ func(w io.Writer)) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	},
+	"yaml": printYAML,
+}
+
+// print writes the result to standard output, as table or in the output format selected with the -o flag.
+func (a *app) print(v interface{}, table func(w io.Writer)) {
+	a.printTo(os.Stdout, v, table)
+}
+
+// printTo writes the result to w, as table or in the output format selected with the -o flag.
+func (a *app) printTo(w io.Writer, v interface{}, table func(w io.Writer)) {
+	if err := printers[a.output](w, v, table); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// printYAML writes the result as YAML using the field names of its JSON representation.
+func printYAML(w io.Writer, v interface{}, _ func(w io.Writer)) error {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is YAML, it only needs to be restyled
+	var node yaml.Node
+	if err := yaml.Unmarshal(j, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// resetStyle removes the flow and quoting styles of the JSON syntax from the node and its children.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// backend returns the backend selected with the -direct flag.
+// The returned function must be called to release the connection to the event store.
+func (a *app) backend() (backend, func(), error) {
+	if !a.direct {
+		return &restBackend{client: a.client}, func() {}, nil
+	}
+
+	es, err := a.connect()
+	if err != nil {
+		return nil, nil, err
+	}
+	return newDirectBackend(projectRepository.NewProjectRepository(es, subjectkey.NewProtector(es))), func() { es.Close() }, nil
+}
+
+// connect connects to the event store selected with the -eventstore flag.
+func (a *app) connect() (*esdb.Client, error) {
+	config, err := esdb.ParseConnectionString(a.eventStore)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event store connection string: %w", err)
+	}
+	es, err := esdb.NewClient(config)
+	if err == nil {
+		err = es.Connect()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("problem connecting to the event store: %w", err)
+	}
+	return es, nil
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/archive"
+	projectRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/subjectkey"
+)
+
+// archiveKeyUsage describes the -key flag of export and import.
+const archiveKeyUsage = "base64 encoded key of 32 bytes, which encrypts the keys of the users in the archive (default: $DSP_ARCHIVE_KEY)"
+
+// archiveKey returns the key of an archive of the flag or the DSP_ARCHIVE_KEY environment variable, or nil if neither is set.
+// The environment variable is not the default of the flag, so that the key is not printed with the usage.
+func archiveKey(flag string) ([]byte, error) {
+	if flag == "" {
+		flag = os.Getenv("DSP_ARCHIVE_KEY")
+	}
+	if flag == "" {
+		return nil, nil
+	}
+	return archive.ParseKey(flag)
+}
+
+// runExport writes all project events of the event store to an archive, which can be imported with `admin import`.
+// It always reads the event store directly, since the REST api does not expose the raw events.
+func runExport(a *app, args []string) int {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	keyFlag := flags.String("key", "", archiveKeyUsage)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin export [flags] <file>")
+		fmt.Fprintln(os.Stderr, "\nExports the events of all projects to a gzip compressed archive, with a checksum of every event.")
+		fmt.Fprintln(os.Stderr, "The keys of the users recorded in the events are added encrypted with the key of the archive, which is required")
+		fmt.Fprintln(os.Stderr, "as soon as any event records a user. The file - writes to standard output.")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+
+	key, err := archiveKey(*keyFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	es, err := a.connect()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer es.Close()
+
+	var out io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	summary, err := archive.Export(ctx, projectRepository.NewProjectRepository(es, subjectkey.NewProtector(es)), out, key)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, archive.ErrKeyRequired) {
+			fmt.Fprintln(os.Stderr, "use -key or DSP_ARCHIVE_KEY, e.g. a key generated with `openssl rand -base64 32`, and keep it apart from the archive")
+		}
+		if path != "-" {
+			_ = os.Remove(path)
+		}
+		return 1
+	}
+
+	// the archive itself may be written to standard output
+	a.printTo(os.Stderr, summary, func(w io.Writer) {
+		fmt.Fprintf(w, "exported %d events of %d streams and %d keys\n", summary.Events, summary.Streams, summary.Keys)
+	})
+	return 0
+}
+
+// importArchive verifies an archive written by `admin export` and imports its keys and events directly into the event store.
+// Streams which already exist are only replaced if force is true.
+func importArchive(a *app, in io.Reader, key []byte, force bool, dryRun bool) int {
+	es, err := a.connect()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer es.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	summary, err := archive.Import(ctx, projectRepository.NewProjectRepository(es, subjectkey.NewProtector(es)), in, key, force, dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, archive.ErrKeyRequired) {
+			fmt.Fprintln(os.Stderr, "use -key or DSP_ARCHIVE_KEY with the key the archive was exported with")
+		}
+		if len(summary.Overwritten) > 0 && !force {
+			fmt.Fprintln(os.Stderr, "use -force to replace the existing streams")
+		}
+		return 1
+	}
+
+	a.print(summary, func(w io.Writer) {
+		if len(summary.Overwritten) > 0 {
+			fmt.Fprintf(w, "replaced streams: %s\n", strings.Join(summary.Overwritten, ", "))
+		}
+		if dryRun {
+			fmt.Fprintf(w, "dry run: the archive is valid, %d events of %d streams and %d keys would be imported\n", summary.Events, summary.Streams, summary.Keys)
+			return
+		}
+		if summary.Forgotten > 0 {
+			fmt.Fprintf(w, "skipped %d keys of users forgotten in the meantime\n", summary.Forgotten)
+		}
+		fmt.Fprintf(w, "imported %d events of %d streams and %d keys, rebuild the projections of the running service to include them\n", summary.Events, summary.Streams, summary.Keys-summary.Forgotten)
+	})
+	return 0
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/url"
+	"sort"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// backend carries out the commands, either through the REST api or directly on the event store.
+// Both return the same presenters, so that the output does not depend on the backend.
+type backend interface {
+	ListProjects(ctx context.Context, status string) ([]presenter.ProjectV2, error)
+	GetProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error)
+	CreateProject(ctx context.Context, body handler.RequestBody) (presenter.ProjectV2, error)
+	UpdateProject(ctx context.Context, id valueobject.Identifier, body handler.RequestBody) (presenter.ProjectV2, error)
+	DeleteProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error)
+	RestoreProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error)
+	ProjectEvents(ctx context.Context, id valueobject.Identifier) ([]presenter.ProjectEvent, error)
+	RebuildSnapshots(ctx context.Context) (int, error)
+	// Verify verifies the hash chains of all projects, or of the project if an id is provided.
+	// The signatures of the checkpoints are verified with the public key, which is only used directly on the event store.
+	Verify(ctx context.Context, id *valueobject.Identifier, key ed25519.PublicKey) (integrity.Report, error)
+	// ForgetUser erases the personal data of the user from the events.
+	// It returns false if no personal data of the user had been recorded.
+	ForgetUser(ctx context.Context, id valueobject.Identifier) (bool, error)
+}
+
+// restBackend calls the REST api of the service.
+type restBackend struct {
+	client *client
+}
+
+func (b *restBackend) ListProjects(ctx context.Context, status string) ([]presenter.ProjectV2, error) {
+	target := "/v2/projects"
+	if status != "" {
+		target += "?" + url.Values{"status": {status}}.Encode()
+	}
+	var res []presenter.ProjectV2
+	err := b.client.do("GET", target, "", nil, &res)
+	return res, err
+}
+
+func (b *restBackend) GetProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error) {
+	var res presenter.ProjectV2
+	err := b.client.do("GET", "/v2/projects/"+id.String(), "", nil, &res)
+	return res, err
+}
+
+func (b *restBackend) CreateProject(ctx context.Context, body handler.RequestBody) (presenter.ProjectV2, error) {
+	var res presenter.ProjectV2
+	err := b.client.do("POST", "/v2/projects", "application/json", jsonBody(body), &res)
+	return res, err
+}
+
+func (b *restBackend) UpdateProject(ctx context.Context, id valueobject.Identifier, body handler.RequestBody) (presenter.ProjectV2, error) {
+	var res presenter.ProjectV2
+	err := b.client.do("PUT", "/v2/projects/"+id.String(), "application/json", jsonBody(body), &res)
+	return res, err
+}
+
+func (b *restBackend) DeleteProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error) {
+	var res presenter.ProjectV2
+	err := b.client.do("DELETE", "/v2/projects/"+id.String(), "", nil, &res)
+	return res, err
+}
+
+func (b *restBackend) RestoreProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error) {
+	var res presenter.ProjectV2
+	err := b.client.do("POST", "/v2/projects/"+id.String()+":restore", "", nil, &res)
+	return res, err
+}
+
+func (b *restBackend) ProjectEvents(ctx context.Context, id valueobject.Identifier) ([]presenter.ProjectEvent, error) {
+	var res []presenter.ProjectEvent
+	err := b.client.do("GET", "/v2/projects/"+id.String()+"/events", "", nil, &res)
+	return res, err
+}
+
+func (b *restBackend) RebuildSnapshots(ctx context.Context) (int, error) {
+	var res handler.RebuildResult
+	err := b.client.do("POST", "/v1/snapshots:rebuild", "", nil, &res)
+	return res.Processed, err
+}
+
+func (b *restBackend) Verify(ctx context.Context, id *valueobject.Identifier, key ed25519.PublicKey) (integrity.Report, error) {
+	target := "/v1/integrity"
+	if id != nil {
+		target += "?" + url.Values{"project": {id.String()}}.Encode()
+	}
+	var res integrity.Report
+	err := b.client.do("GET", target, "", nil, &res)
+	return res, err
+}
+
+func (b *restBackend) ForgetUser(ctx context.Context, id valueobject.Identifier) (bool, error) {
+	var res handler.ForgetResult
+	err := b.client.do("POST", "/v1/users/"+id.String()+":forget", "", nil, &res)
+	return res.Forgotten, err
+}
+
+// jsonBody encodes the value as request body.
+func jsonBody(v interface{}) *bytes.Reader {
+	j, _ := json.Marshal(v)
+	return bytes.NewReader(j)
+}
+
+// repository is the part of the project repository used by the direct backend.
+type repository interface {
+	project.Repository
+	RebuildSnapshots(ctx context.Context) (int, error)
+	Verify(ctx context.Context, id *valueobject.Identifier, key ed25519.PublicKey) (integrity.Report, error)
+	ForgetActor(ctx context.Context, id valueobject.Identifier) (bool, error)
+}
+
+// directBackend reads and writes the event store through the project service.
+// As there is no authenticated principal, changes are recorded without an actor.
+type directBackend struct {
+	repo    repository
+	service *project.Service
+}
+
+// newDirectBackend creates a backend using the repository.
+func newDirectBackend(repo repository) *directBackend {
+	return &directBackend{repo: repo, service: project.NewService(repo)}
+}
+
+func (b *directBackend) ListProjects(ctx context.Context, status string) ([]presenter.ProjectV2, error) {
+	res, err := b.service.QueryProjects(ctx, project.Query{Status: project.Status(status)})
+	if err != nil {
+		return nil, err
+	}
+	return presenter.NewProjectsV2(res.Projects), nil
+}
+
+func (b *directBackend) GetProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error) {
+	p, err := b.service.GetProject(ctx, id)
+	if err != nil {
+		return presenter.ProjectV2{}, err
+	}
+	return presenter.NewProjectV2(p), nil
+}
+
+func (b *directBackend) CreateProject(ctx context.Context, body handler.RequestBody) (presenter.ProjectV2, error) {
+	sc, sn, ln, desc, err := values(body)
+	if err != nil {
+		return presenter.ProjectV2{}, err
+	}
+	id, err := b.service.CreateProject(ctx, sc, sn, ln, desc)
+	if err != nil {
+		return presenter.ProjectV2{}, err
+	}
+	return b.GetProject(ctx, id)
+}
+
+func (b *directBackend) UpdateProject(ctx context.Context, id valueobject.Identifier, body handler.RequestBody) (presenter.ProjectV2, error) {
+	sc, sn, ln, desc, err := values(body)
+	if err != nil {
+		return presenter.ProjectV2{}, err
+	}
+	p, err := b.service.UpdateProject(ctx, id, sc, sn, ln, desc)
+	if err != nil {
+		return presenter.ProjectV2{}, err
+	}
+	return presenter.NewProjectV2(p), nil
+}
+
+func (b *directBackend) DeleteProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error) {
+	p, err := b.service.DeleteProject(ctx, id)
+	if err != nil {
+		return presenter.ProjectV2{}, err
+	}
+	return presenter.NewProjectV2(p), nil
+}
+
+func (b *directBackend) RestoreProject(ctx context.Context, id valueobject.Identifier) (presenter.ProjectV2, error) {
+	p, err := b.service.RestoreProject(ctx, id)
+	if err != nil {
+		return presenter.ProjectV2{}, err
+	}
+	return presenter.NewProjectV2(p), nil
+}
+
+func (b *directBackend) ProjectEvents(ctx context.Context, id valueobject.Identifier) ([]presenter.ProjectEvent, error) {
+	events, err := b.service.GetProjectHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return projectEvents(events), nil
+}
+
+func (b *directBackend) RebuildSnapshots(ctx context.Context) (int, error) {
+	return b.repo.RebuildSnapshots(ctx)
+}
+
+func (b *directBackend) Verify(ctx context.Context, id *valueobject.Identifier, key ed25519.PublicKey) (integrity.Report, error) {
+	return b.repo.Verify(ctx, id, key)
+}
+
+func (b *directBackend) ForgetUser(ctx context.Context, id valueobject.Identifier) (bool, error) {
+	return b.repo.ForgetActor(ctx, id)
+}
+
+// values validates the fields of the request body like the REST api does.
+func values(b handler.RequestBody) (valueobject.ShortCode, valueobject.ShortName, valueobject.LongName, valueobject.Description, error) {
+	v := valueobject.Validation{}
+	sc := v.ShortCode("shortCode", b.ShortCode)
+	sn := v.ShortName("shortName", b.ShortName)
+	ln := v.LongName("longName", b.LongName)
+	desc := v.Description("description", b.Description)
+	return sc, sn, ln, desc, v.Err()
+}
+
+// projectEvents converts the domain events into their presenters.
+func projectEvents(events []event.Event) []presenter.ProjectEvent {
+	res := []presenter.ProjectEvent{}
+	for _, ev := range events {
+		if e := presenter.NewProjectEvent(ev); e != nil {
+			res = append(res, *e)
+		}
+	}
+	return res
+}
+
+// sortEvents sorts the events of several projects by the time they were recorded.
+func sortEvents(events []presenter.ProjectEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].At == nil || events[j].At == nil {
+			return events[j].At != nil
+		}
+		return events[i].At.Before(*events[j].At)
+	})
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/problem"
+)
+
+// client calls the REST api of the admin service with the credentials of a user or a service account.
+type client struct {
+	baseURL string
+	token   string
+	apiKey  string
+	http    *http.Client
+}
+
+// newClient creates a new client of the admin service at the base url.
+func newClient(baseURL string, token string, apiKey string) *client {
+	return &client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// do sends a request with the body of the content type to the path and decodes the JSON response into res.
+// Error responses are returned as problem.
+func (c *client) do(method string, path string, contentType string, body io.Reader, res interface{}) error {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var p problem.Problem
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil || p.Code == "" {
+			return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+		return &p
+	}
+
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// runEvents dumps the events of one project, or of all projects including the deleted ones, oldest first.
+func runEvents(a *app, args []string) int {
+	flags := flag.NewFlagSet("events", flag.ExitOnError)
+	projectID := flags.String("project", "", "id of the project whose events are dumped (default: all projects)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin events [-project ID]")
+		fmt.Fprintln(os.Stderr, "\nDumps the events of the projects, oldest first.")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	var ids []valueobject.Identifier
+	if *projectID != "" {
+		id, err := valueobject.IdentifierFromBytes([]byte(*projectID))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid project id %q\n", *projectID)
+			return 2
+		}
+		ids = append(ids, id)
+	}
+
+	b, done, err := a.backend()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	if ids == nil {
+		projects, err := b.ListProjects(ctx, "all")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, p := range projects {
+			ids = append(ids, p.ID)
+		}
+	}
+
+	events := []presenter.ProjectEvent{}
+	for _, id := range ids {
+		projectEvents, err := b.ProjectEvents(ctx, id)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		events = append(events, projectEvents...)
+	}
+	sortEvents(events)
+
+	a.print(events, func(w io.Writer) { printEvents(w, events) })
+	return 0
+}
+
+// printEvents prints the events as a table.
+func printEvents(w io.Writer, events []presenter.ProjectEvent) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "AT\tPROJECT\tEVENT\tBY")
+	for _, e := range events {
+		at, by := "", ""
+		if e.At != nil {
+			at = e.At.Format(time.RFC3339)
+		}
+		if e.By != nil {
+			by = e.By.ID.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", at, e.ProjectID, e.Event, by)
+	}
+	_ = tw.Flush()
+}
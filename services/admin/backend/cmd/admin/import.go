@@ -0,0 +1,153 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+)
+
+// importContentTypes maps the formats of the import command to the media types of the request body.
+var importContentTypes = map[string]string{
+	"csv":   "text/csv",
+	"jsonl": "application/x-ndjson",
+}
+
+// runImport imports the projects of a file through `POST /v1/projects:import` and prints the outcome of every row.
+// The exit code is 1 if any row is invalid or could not be created.
+// Archives written by `admin export` are imported directly into the event store instead.
+func runImport(a *app, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only validate the rows or the archive, without importing anything")
+	format := flags.String("format", "", "format of the file, csv, jsonl or archive (default: derived from the file extension)")
+	force := flags.Bool("force", false, "replace the projects of an archive which exist already")
+	asJSON := flags.Bool("json", false, "print the report as JSON, same as the global flag -o json")
+	keyFlag := flags.String("key", "", archiveKeyUsage)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin import [flags] <file>")
+		fmt.Fprintln(os.Stderr, "\nImports projects from a CSV file with a header row naming the columns shortCode, shortName, longName and description,")
+		fmt.Fprintln(os.Stderr, "or from a JSON lines file with a project per line. The file - reads from standard input.")
+		fmt.Fprintln(os.Stderr, "\nAn archive written by admin export (.gz) is verified and its events are imported into the event store,")
+		fmt.Fprintln(os.Stderr, "with the event ids of the archive. Projects which exist already are only replaced with -force.")
+		fmt.Fprintln(os.Stderr, "The keys of the users in the archive are decrypted with -key, keys of users forgotten in the meantime are skipped.")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		switch *format {
+		case "ndjson":
+			*format = "jsonl"
+		case "gz":
+			*format = "archive"
+		}
+	}
+	contentType, ok := importContentTypes[*format]
+	if !ok && *format != "archive" {
+		fmt.Fprintf(os.Stderr, "unknown format %q, use -format csv, -format jsonl or -format archive\n", *format)
+		return 2
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	if *asJSON {
+		a.output = "json"
+	}
+	if *format == "archive" {
+		key, err := archiveKey(*keyFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		return importArchive(a, in, key, *force, *dryRun)
+	}
+
+	var report presenter.ImportReport
+	target := "/v1/projects:import?" + url.Values{"dryRun": {fmt.Sprint(*dryRun)}}.Encode()
+	if err := a.client.do("POST", target, contentType, in, &report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	a.print(report, func(w io.Writer) { printImportReport(w, report) })
+
+	if report.Invalid > 0 || report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// printImportReport prints the outcome of every row as a table, followed by a summary.
+func printImportReport(w io.Writer, report presenter.ImportReport) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tSHORT CODE\tSTATUS\tDETAIL")
+	for _, row := range report.Rows {
+		detail := ""
+		switch {
+		case row.ID != nil:
+			detail = row.ID.String()
+		case row.Error != nil && len(row.Error.Errors) > 0:
+			var fields []string
+			for _, f := range row.Error.Errors {
+				fields = append(fields, f.Field+": "+f.Detail)
+			}
+			detail = strings.Join(fields, "; ")
+		case row.Error != nil:
+			detail = row.Error.Detail
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", row.Line, row.ShortCode, row.Status, detail)
+	}
+	_ = tw.Flush()
+
+	if report.DryRun {
+		fmt.Fprintf(w, "\ndry run: %d of %d rows are valid, %d invalid, nothing has been created\n", report.Valid, report.Total, report.Invalid)
+		return
+	}
+	fmt.Fprintf(w, "\n%d of %d rows created, %d invalid, %d failed\n", report.Created, report.Total, report.Invalid, report.Failed)
+}
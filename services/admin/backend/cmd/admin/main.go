@@ -0,0 +1,128 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Command admin operates the admin service from the command line.
+//
+// Usage:
+//
+//	admin [-url URL] [-token TOKEN | -api-key KEY] [-direct] [-eventstore URL] [-o table|json|yaml] <command> [arguments]
+//
+// By default the commands call the REST api of the service with the token of a user or the api key of a service account.
+// With -direct, they read and write the event store instead, which works while the service is down.
+// The url, token, api key and event store can also be provided with the DSP_ADMIN_URL, DSP_ADMIN_TOKEN, DSP_API_KEY
+// and EVENTSTORE_URL environment variables.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	adminConfig "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/config"
+)
+
+// command is a subcommand of the admin CLI.
+type command struct {
+	summary string
+	// run runs the command with its arguments and returns the exit code.
+	run func(a *app, args []string) int
+}
+
+// commands are the subcommands of the admin CLI by name.
+var commands = map[string]command{
+	"project":       {summary: "list, get, create, update, delete or restore projects", run: runProject},
+	"events":        {summary: "dump the events of all projects or of one project", run: runEvents},
+	"projection":    {summary: "rebuild a projection from the events", run: runProjection},
+	"snapshot":      {summary: "rebuild the snapshots of the projects", run: runSnapshot},
+	"config":        {summary: "validate the configuration of the service", run: runConfig},
+	"health":        {summary: "check that the service or the event store is reachable", run: runHealth},
+	"export":        {summary: "export the events of all projects to an archive", run: runExport},
+	"import":        {summary: "import projects from a CSV or JSON lines file, or an archive", run: runImport},
+	"migrate-knora": {summary: "migrate the projects of DSP-API into the event store", run: runMigrateKnora},
+	"verify":        {summary: "verify that the project events have not been altered", run: runVerify},
+	"forget-user":   {summary: "erase the personal data of a user from the events", run: runForgetUser},
+	"scavenge":      {summary: "remove deleted streams, such as destroyed keys, from the disk of the event store", run: runScavenge},
+}
+
+func main() {
+	flags := flag.NewFlagSet("admin", flag.ExitOnError)
+	url := flags.String("url", envOr("DSP_ADMIN_URL", "http://localhost:8080"), "base url of the admin service")
+	token := flags.String("token", os.Getenv("DSP_ADMIN_TOKEN"), "access token of a user")
+	apiKey := flags.String("api-key", os.Getenv("DSP_API_KEY"), "api key of a service account")
+	direct := flags.Bool("direct", false, "use the event store directly instead of the REST api")
+	eventStore := flags.String("eventstore", envOr("EVENTSTORE_URL", adminConfig.EVENTSTORE_URL), "connection string of the event store, used with -direct")
+	output := flags.String("o", "table", "output format, table, json or yaml")
+	flags.Usage = func() { usage(flags) }
+	_ = flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		usage(flags)
+		os.Exit(2)
+	}
+
+	if _, ok := printers[*output]; !ok {
+		fmt.Fprintf(os.Stderr, "unknown output format %q, use -o table, json or yaml\n", *output)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flags.Arg(0))
+		usage(flags)
+		os.Exit(2)
+	}
+
+	a := &app{
+		client:     newClient(*url, *token, *apiKey),
+		direct:     *direct,
+		eventStore: *eventStore,
+		output:     *output,
+	}
+	os.Exit(cmd.run(a, flags.Args()[1:]))
+}
+
+// usage prints the global flags and the commands.
+func usage(flags *flag.FlagSet) {
+	fmt.Fprintln(os.Stderr, "Usage: admin [flags] <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "\nFlags:")
+	flags.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", name, commands[name].summary)
+	}
+}
+
+// envOr returns the value of the environment variable, or the fallback if it is not set.
+func envOr(key string, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
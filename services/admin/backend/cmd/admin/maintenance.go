@@ -0,0 +1,314 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	esdb "github.com/EventStore/EventStore-Client-Go/client"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	adminConfig "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/config"
+	projectRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/subjectkey"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// runProjection lists or rebuilds the projections of the running service.
+// Projections are held in memory by the service, so they can only be rebuilt through the REST api.
+func runProjection(a *app, args []string) int {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin projection list")
+		fmt.Fprintln(os.Stderr, "       admin projection rebuild <name>")
+	}
+	if a.direct {
+		fmt.Fprintln(os.Stderr, "projections are held by the running service and cannot be rebuilt with -direct")
+		return 2
+	}
+
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		var names []string
+		if err := a.client.do("GET", "/v1/projections", "", nil, &names); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		a.print(names, func(w io.Writer) {
+			for _, name := range names {
+				fmt.Fprintln(w, name)
+			}
+		})
+		return 0
+
+	case len(args) == 2 && args[0] == "rebuild":
+		var res handler.RebuildResult
+		if err := a.client.do("POST", "/v1/projections/"+args[1]+":rebuild", "", nil, &res); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		a.print(res, func(w io.Writer) {
+			fmt.Fprintf(w, "rebuilt %s from %d events in %s\n", res.Name, res.Processed, res.Duration)
+		})
+		return 0
+	}
+
+	usage()
+	return 2
+}
+
+// runSnapshot rebuilds the snapshots of all projects.
+func runSnapshot(a *app, args []string) int {
+	if len(args) != 1 || args[0] != "rebuild" {
+		fmt.Fprintln(os.Stderr, "Usage: admin snapshot rebuild")
+		return 2
+	}
+
+	b, done, err := a.backend()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	n, err := b.RebuildSnapshots(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	res := handler.RebuildResult{Name: "snapshots", Processed: n}
+	a.print(res, func(w io.Writer) { fmt.Fprintf(w, "stored %d snapshots\n", n) })
+	return 0
+}
+
+// runForgetUser erases the personal data of a user, e.g. to fulfil a request for erasure.
+// With -direct the running service keeps the keys it has cached until it is restarted.
+func runForgetUser(a *app, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: admin forget-user <id>")
+		return 2
+	}
+	id, err := valueobject.IdentifierFromBytes([]byte(args[0]))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid user id %q\n", args[0])
+		return 2
+	}
+
+	b, done, err := a.backend()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	forgotten, err := b.ForgetUser(ctx, id)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	res := handler.ForgetResult{ID: id, Forgotten: forgotten}
+	a.print(res, func(w io.Writer) {
+		if forgotten {
+			fmt.Fprintf(w, "forgot the personal data of %s, run admin scavenge to remove its keys from disk\n", id)
+			return
+		}
+		fmt.Fprintf(w, "no personal data of %s was recorded\n", id)
+	})
+	return 0
+}
+
+// scavengeResult is the response of the event store to a started scavenge.
+type scavengeResult struct {
+	ID string `json:"scavengeId"`
+}
+
+// runScavenge starts a scavenge of the event store, which removes the events of deleted streams from disk,
+// e.g. the keys destroyed by forget-user. The client of the event store cannot start a scavenge, so it is started
+// through the HTTP api of the node of the -eventstore connection string, whose user must be an admin or operator.
+func runScavenge(a *app, args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: admin scavenge")
+		return 2
+	}
+
+	config, err := esdb.ParseConnectionString(a.eventStore)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid event store connection string: %v\n", err)
+		return 2
+	}
+	if config.Address == "" {
+		fmt.Fprintln(os.Stderr, "a scavenge is started on a single node, the connection string must not use gossip seeds")
+		return 2
+	}
+
+	scheme := "https"
+	if config.DisableTLS {
+		scheme = "http"
+	}
+	req, err := http.NewRequest("POST", scheme+"://"+config.Address+"/admin/scavenge", nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if config.Username != "" {
+		req.SetBasicAuth(config.Username, config.Password)
+	}
+
+	client := &http.Client{
+		Timeout: commandTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: config.RootCAs, InsecureSkipVerify: config.SkipCertificateVerification},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem starting the scavenge: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		fmt.Fprintf(os.Stderr, "problem starting the scavenge: the event store responded with %s\n", resp.Status)
+		return 1
+	}
+
+	var res scavengeResult
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		fmt.Fprintf(os.Stderr, "problem reading the response of the event store: %v\n", err)
+		return 1
+	}
+
+	a.print(res, func(w io.Writer) {
+		fmt.Fprintf(w, "started scavenge %s, it runs in the background of the event store\n", res.ID)
+	})
+	return 0
+}
+
+// runHealth checks that the service, or with -direct the event store, is available.
+// The exit code is 1 if it is not.
+func runHealth(a *app, args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: admin health")
+		return 2
+	}
+
+	var res handler.Health
+	if a.direct {
+		res = handler.Health{Status: "ok", Checks: map[string]string{"eventStore": "ok"}}
+		if err := a.pingEventStore(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			res.Status, res.Checks["eventStore"] = "unavailable", "unavailable"
+		}
+	} else if err := a.client.do("GET", "/v1/health", "", nil, &res); err != nil {
+		// an unavailable dependency is reported with 503 and the health as body, which is not decoded
+		fmt.Fprintln(os.Stderr, err)
+		res = handler.Health{Status: "unavailable", Checks: map[string]string{}}
+	}
+
+	a.print(res, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "service\t%s\n", res.Status)
+		for name, status := range res.Checks {
+			fmt.Fprintf(tw, "%s\t%s\n", name, status)
+		}
+		_ = tw.Flush()
+	})
+
+	if res.Status != "ok" {
+		return 1
+	}
+	return 0
+}
+
+// pingEventStore connects to the event store and reads from it.
+func (a *app) pingEventStore() error {
+	es, err := a.connect()
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	return projectRepository.NewProjectRepository(es, subjectkey.NewProtector(es)).Ping(ctx)
+}
+
+// runConfig validates the configuration the service would read from the current environment.
+// The exit code is 1 if any setting is invalid.
+func runConfig(a *app, args []string) int {
+	if len(args) != 1 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "Usage: admin config validate")
+		return 2
+	}
+
+	type result struct {
+		Settings []adminConfig.Setting `json:"settings"`
+		Errors   []string              `json:"errors"`
+	}
+	res := result{Settings: adminConfig.Settings(os.Getenv), Errors: []string{}}
+	for _, err := range adminConfig.Validate(os.Getenv) {
+		res.Errors = append(res.Errors, err.Error())
+	}
+
+	a.print(res, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE")
+		for _, s := range res.Settings {
+			source := "default"
+			if s.FromEnv {
+				source = "environment"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, s.Value, source)
+		}
+		_ = tw.Flush()
+
+		if len(res.Errors) == 0 {
+			fmt.Fprintln(w, "\nthe configuration is valid")
+			return
+		}
+		fmt.Fprintln(w)
+		for _, e := range res.Errors {
+			fmt.Fprintln(w, "invalid", e)
+		}
+	})
+
+	if len(res.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/knora"
+	projectRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/subjectkey"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+)
+
+// migrationReaders maps the formats of the migrate-knora command to the readers of the export.
+var migrationReaders = map[string]func(r io.Reader) ([]project.LegacyProject, error){
+	"json": knora.ReadJSON,
+	"ttl":  knora.ReadRDF,
+	"trig": knora.ReadRDF,
+}
+
+// runMigrateKnora migrates the projects of an export of the knora-admin data of DSP-API into the event store
+// and prints the reconciliation report. It always writes to the event store directly, as if -direct was set.
+// The exit code is 1 if any project is invalid, conflicts with another project or could not be saved.
+func runMigrateKnora(a *app, args []string) int {
+	flags := flag.NewFlagSet("migrate-knora", flag.ExitOnError)
+	flags.StringVar(&a.eventStore, "eventstore", a.eventStore, "connection string of the event store, same as the global flag")
+	dryRun := flags.Bool("dry-run", false, "only reconcile the projects, without migrating any project")
+	format := flags.String("format", "", "format of the export, json, ttl or trig (default: derived from the file extension)")
+	asJSON := flags.Bool("json", false, "print the report as JSON, same as the global flag -o json")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin migrate-knora [flags] <file>")
+		fmt.Fprintln(os.Stderr, "\nMigrates the projects of DSP-API from a response of its admin api (json), or from its knora-admin data (ttl or trig).")
+		fmt.Fprintln(os.Stderr, "Projects which have been migrated before are not changed, but reported if they differ. The file - reads from standard input.")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	read, ok := migrationReaders[*format]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown format %q, use -format json, ttl or trig\n", *format)
+		return 2
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+	projects, err := read(in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	es, err := a.connect()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer es.Close()
+
+	migrator := project.NewMigrator(projectRepository.NewProjectRepository(es, subjectkey.NewProtector(es)))
+	report, err := migrator.Migrate(context.Background(), projects, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *asJSON {
+		a.output = "json"
+	}
+	a.print(newMigrationReport(report), func(w io.Writer) { printMigrationReport(w, report) })
+
+	if report.Count(project.MigrationInvalid) > 0 || report.Count(project.MigrationConflict) > 0 || report.Count(project.MigrationFailed) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// migrationStatuses are the statuses of the migration, in the order they are summarized.
+var migrationStatuses = []project.MigrationStatus{
+	project.MigrationValid,
+	project.MigrationCreated,
+	project.MigrationUnchanged,
+	project.MigrationDiffers,
+	project.MigrationConflict,
+	project.MigrationInvalid,
+	project.MigrationFailed,
+}
+
+// migrationReport is the JSON representation of the reconciliation report.
+type migrationReport struct {
+	DryRun   bool                            `json:"dryRun"`
+	Total    int                             `json:"total"`
+	Statuses map[project.MigrationStatus]int `json:"statuses"`
+	Projects []migrationResult               `json:"projects"`
+}
+
+// migrationResult is the JSON representation of the outcome of migrating a project.
+type migrationResult struct {
+	IRI         string   `json:"iri"`
+	ShortCode   string   `json:"shortCode"`
+	ID          string   `json:"id,omitempty"`
+	Status      string   `json:"status"`
+	Differences []string `json:"differences,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// newMigrationReport converts the report to its JSON representation.
+func newMigrationReport(report project.MigrationReport) migrationReport {
+	res := migrationReport{
+		DryRun:   report.DryRun,
+		Total:    len(report.Results),
+		Statuses: map[project.MigrationStatus]int{},
+		Projects: []migrationResult{},
+	}
+	for _, status := range migrationStatuses {
+		if n := report.Count(status); n > 0 {
+			res.Statuses[status] = n
+		}
+	}
+	for _, r := range report.Results {
+		mr := migrationResult{IRI: r.IRI, ShortCode: r.ShortCode, Status: string(r.Status), Differences: r.Differences}
+		if r.IRI != "" {
+			mr.ID = r.ID.String()
+		}
+		if r.Err != nil {
+			mr.Error = r.Err.Error()
+		}
+		res.Projects = append(res.Projects, mr)
+	}
+	return res
+}
+
+// printMigrationReport prints the outcome of every project as a table, followed by a summary.
+func printMigrationReport(w io.Writer, report project.MigrationReport) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "IRI\tSHORT CODE\tID\tSTATUS\tDETAIL")
+	for _, r := range report.Results {
+		detail := ""
+		switch {
+		case r.Err != nil:
+			detail = r.Err.Error()
+		case len(r.Differences) > 0:
+			detail = "differs in " + strings.Join(r.Differences, ", ")
+		}
+		id := ""
+		if r.IRI != "" {
+			id = r.ID.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.IRI, r.ShortCode, id, r.Status, detail)
+	}
+	_ = tw.Flush()
+
+	var counts []string
+	for _, status := range migrationStatuses {
+		if n := report.Count(status); n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	fmt.Fprintf(w, "\n%d projects: %s\n", len(report.Results), strings.Join(counts, ", "))
+	if report.DryRun {
+		fmt.Fprintln(w, "dry run: nothing has been migrated")
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/presenter"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// commandTimeout is the time a single command may take.
+const commandTimeout = 2 * time.Minute
+
+// runProject runs the project subcommands.
+func runProject(a *app, args []string) int {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin project <subcommand> [flags] [arguments]")
+		fmt.Fprintln(os.Stderr, "\nSubcommands:")
+		fmt.Fprintln(os.Stderr, "  list [-status active|deleted|all]")
+		fmt.Fprintln(os.Stderr, "  get <id>")
+		fmt.Fprintln(os.Stderr, "  create -short-code CODE -short-name NAME -long-name NAME -description TEXT")
+		fmt.Fprintln(os.Stderr, "  update <id> [-short-code CODE] [-short-name NAME] [-long-name NAME] [-description TEXT]")
+		fmt.Fprintln(os.Stderr, "  delete <id>")
+		fmt.Fprintln(os.Stderr, "  restore <id>")
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	b, done, err := a.backend()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "list":
+		flags := flag.NewFlagSet("project list", flag.ExitOnError)
+		status := flags.String("status", "", "active, deleted or all (default active)")
+		_ = flags.Parse(args)
+
+		projects, err := b.ListProjects(ctx, *status)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		a.print(projects, func(w io.Writer) { printProjects(w, projects) })
+		return 0
+
+	case "create":
+		flags := flag.NewFlagSet("project create", flag.ExitOnError)
+		body := projectFlags(flags, handler.RequestBody{})
+		_ = flags.Parse(args)
+
+		p, err := b.CreateProject(ctx, *body)
+		return a.printProject(p, err)
+
+	case "get", "update", "delete", "restore":
+		if len(args) == 0 {
+			usage()
+			return 2
+		}
+		id, err := valueobject.IdentifierFromBytes([]byte(args[0]))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid project id %q\n", args[0])
+			return 2
+		}
+
+		var p presenter.ProjectV2
+		switch sub {
+		case "get":
+			p, err = b.GetProject(ctx, id)
+		case "update":
+			// flags which are not provided keep the current values
+			if p, err = b.GetProject(ctx, id); err != nil {
+				break
+			}
+			flags := flag.NewFlagSet("project update", flag.ExitOnError)
+			body := projectFlags(flags, handler.RequestBody{ShortCode: p.ShortCode, ShortName: p.ShortName, LongName: p.LongName, Description: p.Description})
+			_ = flags.Parse(args[1:])
+			p, err = b.UpdateProject(ctx, id, *body)
+		case "delete":
+			p, err = b.DeleteProject(ctx, id)
+		case "restore":
+			p, err = b.RestoreProject(ctx, id)
+		}
+		return a.printProject(p, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown subcommand %q\n\n", sub)
+	usage()
+	return 2
+}
+
+// projectFlags defines the flags setting the fields of the request body, with the current values as defaults.
+func projectFlags(flags *flag.FlagSet, current handler.RequestBody) *handler.RequestBody {
+	body := &handler.RequestBody{}
+	flags.StringVar(&body.ShortCode, "short-code", current.ShortCode, "short code, e.g. 0801")
+	flags.StringVar(&body.ShortName, "short-name", current.ShortName, "short name")
+	flags.StringVar(&body.LongName, "long-name", current.LongName, "long name")
+	flags.StringVar(&body.Description, "description", current.Description, "description")
+	return body
+}
+
+// printProject prints the project returned by a command, or the error.
+func (a *app) printProject(p presenter.ProjectV2, err error) int {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	a.print(p, func(w io.Writer) { printProjects(w, []presenter.ProjectV2{p}) })
+	return 0
+}
+
+// printProjects prints the projects as a table.
+func printProjects(w io.Writer, projects []presenter.ProjectV2) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSHORT CODE\tSHORT NAME\tLONG NAME\tSTATUS")
+	for _, p := range projects {
+		status := "active"
+		if p.DeletedAt != nil {
+			status = "deleted"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", p.ID, p.ShortCode, p.ShortName, p.LongName, status)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// runVerify verifies that the project events have not been altered, and prints every break of their hash chains.
+// The exit code is 1 if any break was found.
+func runVerify(a *app, args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	projectID := flags.String("project", "", "id of the project whose events are verified (default: all projects and the checkpoints)")
+	publicKey := flags.String("public-key", os.Getenv("CHECKPOINT_PUBLIC_KEY"), "base64 encoded Ed25519 key verifying the signatures of the checkpoints, used with -direct")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin verify [-project ID] [-public-key KEY]")
+		fmt.Fprintln(os.Stderr, "\nVerifies the hash chain of every project, and the checkpoints over the events of all projects.")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	var id *valueobject.Identifier
+	if *projectID != "" {
+		uuid, err := valueobject.IdentifierFromBytes([]byte(*projectID))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid project id %q\n", *projectID)
+			return 2
+		}
+		id = &uuid
+	}
+
+	var key ed25519.PublicKey
+	if *publicKey != "" {
+		var err error
+		if key, err = integrity.ParsePublicKey(*publicKey); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	b, done, err := a.backend()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	report, err := b.Verify(ctx, id, key)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	a.print(report, func(w io.Writer) { printReport(w, report) })
+
+	if report.Status != "ok" {
+		return 1
+	}
+	return 0
+}
+
+// printReport prints the breaks and checkpoints as tables, followed by a summary.
+func printReport(w io.Writer, report integrity.Report) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(report.Breaks) > 0 {
+		fmt.Fprintln(tw, "STREAM\tREVISION\tEVENT\tREASON")
+		for _, b := range report.Breaks {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", b.Stream, b.Revision, b.EventID, b.Reason)
+		}
+		fmt.Fprintln(tw)
+	}
+	if len(report.Checkpoints) > 0 {
+		fmt.Fprintln(tw, "CHECKPOINT\tEVENTS\tSTATUS")
+		for _, c := range report.Checkpoints {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Checkpoint.CreatedAt.Format("2006-01-02 15:04:05"), c.Checkpoint.Events, c.Status)
+		}
+		fmt.Fprintln(tw)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "%s: %d events of %d streams verified, %d breaks", report.Status, report.Events, report.Streams, len(report.Breaks))
+	if report.Unchained > 0 {
+		fmt.Fprintf(w, ", %d events stored without hash", report.Unchained)
+	}
+	fmt.Fprintln(w)
+}
@@ -24,11 +24,35 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/EventStore/EventStore-Client-Go/client"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/export"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/handler"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/middleware"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/oai"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/publisher"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rpc"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/sitemap"
+	adminConfig "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/config"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	checkpointRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/checkpoint"
 	projectRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/project"
+	serviceAccountRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/serviceaccount"
+	subjectKeyRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/subjectkey"
+	webhookRepository "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/webhook"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/metric"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/server"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 	"log"
 )
 
@@ -37,7 +61,21 @@ func main() {
 	s := server.NewAPISPAServer("8080")
 	s.SetSPA("public/admin")
 
-	config, err := client.ParseConnectionString("esdb://localhost:2113?tls=false")
+	// every setting read from the environment is checked before anything is started, see also `admin config validate`
+	if errs := adminConfig.Validate(os.Getenv); len(errs) > 0 {
+		for _, err := range errs {
+			log.Println("Invalid configuration: ", err.Error())
+		}
+		log.Fatal("Unexpected configuration error: ", len(errs), " invalid settings")
+	}
+
+	// the event store can be changed with the EVENTSTORE_URL environment variable
+	eventStoreURL := os.Getenv("EVENTSTORE_URL")
+	if eventStoreURL == "" {
+		eventStoreURL = adminConfig.EVENTSTORE_URL
+	}
+
+	config, err := client.ParseConnectionString(eventStoreURL)
 	if err != nil {
 		log.Fatal("Unexpected configuration error: ", err.Error())
 	}
@@ -51,11 +89,206 @@ func main() {
 		log.Fatal("Unexpected failure while connecting to client: ", err.Error())
 	}
 
-	projectRepo := projectRepository.NewProjectRepository(client)
+	// the actors in the events are encrypted with a key per actor, shared by all repositories so that forgetting an actor covers every event
+	protector := subjectKeyRepository.NewProtector(client)
+
+	projectRepo := projectRepository.NewProjectRepository(client, protector)
+
+	// build the full-text index of the projects and keep it up to date
+	if err := projectRepo.StartIndexing(context.Background()); err != nil {
+		log.Fatal("Unexpected failure while indexing projects: ", err.Error())
+	}
 
 	projectService := project.NewService(projectRepo)
 
-	handler.MakeProjectHandlers(&s.Router, projectService)
+	// checkpoints over all project events are stored periodically if the CHECKPOINT_INTERVAL environment variable is set, e.g. "24h",
+	// and signed with the base64 encoded Ed25519 seed of the CHECKPOINT_SIGNING_KEY environment variable, if any
+	var signingKey ed25519.PrivateKey
+	if key := os.Getenv("CHECKPOINT_SIGNING_KEY"); key != "" {
+		if signingKey, err = integrity.ParseSigningKey(key); err != nil {
+			log.Fatal("Unexpected configuration error: ", err.Error())
+		}
+	}
+
+	checkpointInterval := os.Getenv("CHECKPOINT_INTERVAL")
+	if checkpointInterval == "" {
+		checkpointInterval = adminConfig.CHECKPOINT_INTERVAL
+	}
+	if checkpointInterval != "" {
+		interval, err := time.ParseDuration(checkpointInterval)
+		if err != nil {
+			log.Fatal("Unexpected configuration error: ", err.Error())
+		}
+		go func() {
+			for range time.Tick(interval) {
+				checkpoint, err := projectRepo.SaveCheckpoint(context.Background(), signingKey)
+				if err != nil {
+					log.Println("Unexpected failure while storing a checkpoint: ", err.Error())
+					continue
+				}
+				log.Printf("stored checkpoint over %d project events", checkpoint.Events)
+			}
+		}()
+	}
+
+	serviceAccountRepo := serviceAccountRepository.NewServiceAccountRepository(client, protector)
+
+	serviceAccountService := serviceaccount.NewService(serviceAccountRepo)
+
+	auth := middleware.NewAuthenticator(serviceAccountService)
+
+	webhookRepo := webhookRepository.NewWebhookRepository(client, protector)
+
+	webhookService := webhook.NewService(webhookRepo)
+
+	// schedule the deliveries of the project events to the webhooks and send them from the outbox
+	if err := webhookService.Start(context.Background(), projectService); err != nil {
+		log.Fatal("Unexpected failure while starting the webhook deliveries: ", err.Error())
+	}
+
+	// the project events are published to the message broker selected with the PUBLISHER_SINK environment variable,
+	// either "nats" or "kafka", publishing is disabled if no sink is selected
+	sinkName := os.Getenv("PUBLISHER_SINK")
+	if sinkName == "" {
+		sinkName = adminConfig.PUBLISHER_SINK
+	}
+
+	var sink publisher.Sink
+	switch sinkName {
+	case "":
+	case "nats":
+		natsURL := os.Getenv("NATS_URL")
+		if natsURL == "" {
+			natsURL = adminConfig.NATS_URL
+		}
+		natsSubjectPrefix := os.Getenv("NATS_SUBJECT_PREFIX")
+		if natsSubjectPrefix == "" {
+			natsSubjectPrefix = adminConfig.NATS_SUBJECT_PREFIX
+		}
+		if sink, err = publisher.NewNATSSink(natsURL, natsSubjectPrefix); err != nil {
+			log.Fatal("Unexpected failure while connecting to the message broker: ", err.Error())
+		}
+	case "kafka":
+		kafkaBrokers := os.Getenv("KAFKA_BROKERS")
+		if kafkaBrokers == "" {
+			kafkaBrokers = adminConfig.KAFKA_BROKERS
+		}
+		kafkaTopic := os.Getenv("KAFKA_TOPIC")
+		if kafkaTopic == "" {
+			kafkaTopic = adminConfig.KAFKA_TOPIC
+		}
+		sink = publisher.NewKafkaSink(strings.Split(kafkaBrokers, ","), kafkaTopic)
+	default:
+		log.Fatal("Unexpected configuration error: unknown publisher sink ", sinkName)
+	}
+
+	if sink != nil {
+		checkpointRepo := checkpointRepository.NewCheckpointRepository(client, "publisher-"+sinkName)
+		if err := publisher.NewPublisher(projectService, checkpointRepo, sink).Start(context.Background()); err != nil {
+			log.Fatal("Unexpected failure while starting the publisher: ", err.Error())
+		}
+	}
+
+	// the DOI prefix of the DataCite export can be changed with the DATACITE_DOI_PREFIX environment variable
+	doiPrefix := os.Getenv("DATACITE_DOI_PREFIX")
+	if doiPrefix == "" {
+		doiPrefix = adminConfig.DATACITE_DOI_PREFIX
+	}
+	dataCite := export.DataCiteOptions{DOIPrefix: doiPrefix, Publisher: adminConfig.DATACITE_PUBLISHER}
+
+	// harvesters need the public URL of the provider, which can be changed with the OAI_BASE_URL environment variable
+	oaiBaseURL := os.Getenv("OAI_BASE_URL")
+	if oaiBaseURL == "" {
+		oaiBaseURL = adminConfig.OAI_BASE_URL
+	}
+
+	// the sitemap lists the public project pages under the URL of the SPA, which can be changed with the SITEMAP_BASE_URL environment variable
+	sitemapBaseURL := os.Getenv("SITEMAP_BASE_URL")
+	if sitemapBaseURL == "" {
+		sitemapBaseURL = adminConfig.SITEMAP_BASE_URL
+	}
+
+	sm := server.NewSitemap(sitemapBaseURL)
+	if err := sitemap.Watch(context.Background(), projectService, sm); err != nil {
+		log.Fatal("Unexpected failure while building the sitemap: ", err.Error())
+	}
+	s.SetSitemap(sm)
+
+	// responses to mutating requests with an Idempotency-Key header are replayed for retries during the TTL,
+	// which can be changed with the IDEMPOTENCY_KEY_TTL environment variable, e.g. "1h".
+	// The responses are only kept in the memory of this instance, they are lost on a restart and not shared between replicas.
+	idempotencyKeyTTL := os.Getenv("IDEMPOTENCY_KEY_TTL")
+	if idempotencyKeyTTL == "" {
+		idempotencyKeyTTL = adminConfig.IDEMPOTENCY_KEY_TTL
+	}
+	ttl, err := time.ParseDuration(idempotencyKeyTTL)
+	if err != nil {
+		log.Fatal("Unexpected configuration error: ", err.Error())
+	}
+
+	// the hash chains of the project events can be verified by system admins, with the signatures of the checkpoints
+	var verifyKey ed25519.PublicKey
+	if signingKey != nil {
+		verifyKey = signingKey.Public().(ed25519.PublicKey)
+	}
+
+	// the public catalogue, the OAI-PMH provider and the health check can be used without credentials,
+	// every other request to the api is authenticated before it reaches a handler
+	handler.MakeRoutes(&s.Router, handler.Routes{
+		Projects:        projectService,
+		ServiceAccounts: serviceAccountService,
+		Webhooks:        webhookService,
+		DataCite:        dataCite,
+		OAI: oai.Repository{
+			Name:       adminConfig.OAI_REPOSITORY_NAME,
+			BaseURL:    oaiBaseURL,
+			AdminEmail: adminConfig.OAI_ADMIN_EMAIL,
+			Namespace:  adminConfig.OAI_NAMESPACE,
+			DataCite:   dataCite,
+		},
+		HealthChecks: map[string]handler.HealthCheck{
+			"eventStore": projectRepo.Ping,
+		},
+		// derived state can be rebuilt from the events by system admins
+		Projections: map[string]handler.RebuildFunc{
+			"search": projectRepo.RebuildIndex,
+		},
+		RebuildSnapshots: projectRepo.RebuildSnapshots,
+		Verify: func(ctx context.Context, id *valueobject.Identifier) (integrity.Report, error) {
+			return projectRepo.Verify(ctx, id, verifyKey)
+		},
+		// system admins can erase the personal data of a user, which is encrypted in the events
+		Forget: projectRepo.ForgetActor,
+	}, auth.Middleware, middleware.NewIdempotency(ttl).Middleware)
+
+	// the gRPC api is served on its own port, which can be changed with the GRPC_PORT environment variable
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = strconv.Itoa(adminConfig.GRPC_PORT)
+	}
+
+	lis, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatal("Unexpected failure while listening for gRPC calls: ", err.Error())
+	}
+
+	metricService, err := metric.NewPrometheusService()
+	if err != nil {
+		log.Fatal("Unexpected failure while creating metrics: ", err.Error())
+	}
+
+	grpcServer := rpc.NewServer(projectService, auth, metricService)
+	go func() {
+		log.Println("Serving gRPC on port:", grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatal("Unexpected failure while serving gRPC: ", err.Error())
+		}
+	}()
+
+	err = s.ListenAndServe()
+
+	// streaming calls never end on their own, so they are not waited for
+	grpcServer.Stop()
 
-	log.Fatal(s.ListenAndServe())
+	log.Fatal(err)
 }
@@ -29,4 +29,20 @@ const (
 	DB_DATABASE            = "test"
 	DB_HOST                = "127.0.0.1"
 	API_PORT               = 8080
+	GRPC_PORT              = 50051
+	DATACITE_DOI_PREFIX    = "10.5072"
+	DATACITE_PUBLISHER     = "DaSCH - Data and Service Center for the Humanities"
+	OAI_REPOSITORY_NAME    = "DaSCH Service Platform"
+	OAI_BASE_URL           = "http://localhost:8080/v1/oai"
+	OAI_ADMIN_EMAIL        = "info@dasch.swiss"
+	OAI_NAMESPACE          = "admin.dasch.swiss"
+	SITEMAP_BASE_URL       = "http://localhost:8080"
+	PUBLISHER_SINK         = ""
+	NATS_URL               = "nats://localhost:4222"
+	NATS_SUBJECT_PREFIX    = ""
+	KAFKA_BROKERS          = "localhost:9092"
+	KAFKA_TOPIC            = "dsp.admin.projects"
+	IDEMPOTENCY_KEY_TTL    = "24h"
+	EVENTSTORE_URL         = "esdb://localhost:2113?tls=false"
+	CHECKPOINT_INTERVAL    = ""
 )
\ No newline at end of file
@@ -0,0 +1,178 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/client"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+)
+
+// Setting is a configuration value of the service.
+type Setting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// FromEnv is true if the value was read from the environment variable of the same name, rather than defaulted.
+	FromEnv bool `json:"fromEnv"`
+}
+
+// Settings returns the settings read from the environment by the service, in the order they are read at startup.
+// Settings whose environment variable is empty have their default value.
+func Settings(getenv func(string) string) []Setting {
+	defaults := []Setting{
+		{Name: "EVENTSTORE_URL", Value: EVENTSTORE_URL},
+		{Name: "GRPC_PORT", Value: strconv.Itoa(GRPC_PORT)},
+		{Name: "PUBLISHER_SINK", Value: PUBLISHER_SINK},
+		{Name: "NATS_URL", Value: NATS_URL},
+		{Name: "NATS_SUBJECT_PREFIX", Value: NATS_SUBJECT_PREFIX},
+		{Name: "KAFKA_BROKERS", Value: KAFKA_BROKERS},
+		{Name: "KAFKA_TOPIC", Value: KAFKA_TOPIC},
+		{Name: "DATACITE_DOI_PREFIX", Value: DATACITE_DOI_PREFIX},
+		{Name: "OAI_BASE_URL", Value: OAI_BASE_URL},
+		{Name: "SITEMAP_BASE_URL", Value: SITEMAP_BASE_URL},
+		{Name: "IDEMPOTENCY_KEY_TTL", Value: IDEMPOTENCY_KEY_TTL},
+		{Name: "CHECKPOINT_INTERVAL", Value: CHECKPOINT_INTERVAL},
+	}
+
+	for i, s := range defaults {
+		if v := getenv(s.Name); v != "" {
+			defaults[i].Value = v
+			defaults[i].FromEnv = true
+		}
+	}
+
+	return defaults
+}
+
+// Validate checks the settings read from the environment and returns one error per invalid setting.
+// Settings of an unused publisher sink are not checked.
+// The secret CHECKPOINT_SIGNING_KEY is checked as well, but is not part of the settings, so that it is never printed.
+func Validate(getenv func(string) string) []error {
+	values := map[string]string{}
+	for _, s := range Settings(getenv) {
+		values[s.Name] = s.Value
+	}
+
+	var errs []error
+	check := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	check("EVENTSTORE_URL", validateConnectionString(values["EVENTSTORE_URL"]))
+	check("GRPC_PORT", validatePort(values["GRPC_PORT"]))
+
+	switch sink := values["PUBLISHER_SINK"]; sink {
+	case "":
+	case "nats":
+		check("NATS_URL", validateURL(values["NATS_URL"], "nats"))
+	case "kafka":
+		check("KAFKA_BROKERS", validateBrokers(values["KAFKA_BROKERS"]))
+		if values["KAFKA_TOPIC"] == "" {
+			check("KAFKA_TOPIC", fmt.Errorf("must not be empty"))
+		}
+	default:
+		check("PUBLISHER_SINK", fmt.Errorf("unknown sink %q, must be either nats or kafka", sink))
+	}
+
+	if !strings.HasPrefix(values["DATACITE_DOI_PREFIX"], "10.") {
+		check("DATACITE_DOI_PREFIX", fmt.Errorf("%q is not a DOI prefix starting with 10.", values["DATACITE_DOI_PREFIX"]))
+	}
+	check("OAI_BASE_URL", validateURL(values["OAI_BASE_URL"], "http", "https"))
+	check("SITEMAP_BASE_URL", validateURL(values["SITEMAP_BASE_URL"], "http", "https"))
+	check("IDEMPOTENCY_KEY_TTL", validateDuration(values["IDEMPOTENCY_KEY_TTL"]))
+	if values["CHECKPOINT_INTERVAL"] != "" {
+		check("CHECKPOINT_INTERVAL", validateDuration(values["CHECKPOINT_INTERVAL"]))
+	}
+	if key := getenv("CHECKPOINT_SIGNING_KEY"); key != "" {
+		_, err := integrity.ParseSigningKey(key)
+		check("CHECKPOINT_SIGNING_KEY", err)
+	}
+
+	return errs
+}
+
+// validateConnectionString checks the connection string of the event store.
+func validateConnectionString(s string) error {
+	_, err := client.ParseConnectionString(s)
+	return err
+}
+
+// validatePort checks that the value is a TCP port number.
+func validatePort(s string) error {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%q is not a port number", s)
+	}
+	return nil
+}
+
+// validateURL checks that the value is an absolute URL with one of the provided schemes.
+func validateURL(s string, schemes ...string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q is not an absolute URL", s)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q must use the scheme %s", s, strings.Join(schemes, " or "))
+}
+
+// validateBrokers checks the comma-separated list of host:port addresses of the Kafka brokers.
+func validateBrokers(s string) error {
+	for _, broker := range strings.Split(s, ",") {
+		i := strings.LastIndex(broker, ":")
+		if i < 1 {
+			return fmt.Errorf("%q is not a host:port address", broker)
+		}
+		if err := validatePort(broker[i+1:]); err != nil {
+			return fmt.Errorf("%q is not a host:port address", broker)
+		}
+	}
+	return nil
+}
+
+// validateDuration checks that the value is a positive duration, e.g. "24h".
+func validateDuration(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if d <= 0 {
+		return fmt.Errorf("%q must be positive", s)
+	}
+	return nil
+}
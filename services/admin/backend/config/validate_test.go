@@ -0,0 +1,76 @@
+/*
+ * Copyright © 2021 the contributors.
+ *
+ *  This file is part of the DaSCH Service Platform.
+ *
+ *  The DaSCH Service Platform is free software: you can
+ *  redistribute it and/or modify it under the terms of the
+ *  GNU Affero General Public License as published by the
+ *  Free Software Foundation, either version 3 of the License,
+ *  or (at your option) any later version.
+ *
+ *  The DaSCH Service Platform is distributed in the hope that
+ *  it will be useful, but WITHOUT ANY WARRANTY; without even
+ *  the implied warranty of MERCHANTABILITY or FITNESS FOR
+ *  A PARTICULAR PURPOSE.  See the GNU Affero General Public
+ *  License for more details.
+ *
+ *  You should have received a copy of the GNU Affero General Public
+ *  License along with the DaSCH Service Platform.  If not, see
+ *  <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Defaults(t *testing.T) {
+	assert.Empty(t, config.Validate(func(string) string { return "" }))
+}
+
+func TestValidate(t *testing.T) {
+	env := map[string]string{
+		"EVENTSTORE_URL":         "http://localhost:2113",
+		"GRPC_PORT":              "70000",
+		"PUBLISHER_SINK":         "kafka",
+		"KAFKA_BROKERS":          "localhost:9092,localhost",
+		"OAI_BASE_URL":           "/v1/oai",
+		"IDEMPOTENCY_KEY_TTL":    "1 day",
+		"NATS_URL":               "not checked, as nats is not the sink",
+		"CHECKPOINT_INTERVAL":    "-1h",
+		"CHECKPOINT_SIGNING_KEY": "c2hvcnQ=",
+	}
+
+	var names []string
+	for _, err := range config.Validate(func(name string) string { return env[name] }) {
+		names = append(names, strings.SplitN(err.Error(), ":", 2)[0])
+	}
+	assert.Equal(t, []string{"EVENTSTORE_URL", "GRPC_PORT", "KAFKA_BROKERS", "OAI_BASE_URL", "IDEMPOTENCY_KEY_TTL", "CHECKPOINT_INTERVAL", "CHECKPOINT_SIGNING_KEY"}, names)
+}
+
+func TestSettings(t *testing.T) {
+	settings := config.Settings(func(name string) string {
+		if name == "KAFKA_TOPIC" {
+			return "projects"
+		}
+		return ""
+	})
+
+	for _, s := range settings {
+		switch s.Name {
+		case "KAFKA_TOPIC":
+			assert.Equal(t, "projects", s.Value)
+			assert.True(t, s.FromEnv)
+		case "GRPC_PORT":
+			assert.Equal(t, "50051", s.Value)
+			assert.False(t, s.FromEnv)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package principal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+//ErrNotAuthenticated no principal found in the context
+var ErrNotAuthenticated = errors.New("request is not authenticated")
+
+//ErrNotSystemAdmin the operation is reserved to system admins
+var ErrNotSystemAdmin = errors.New("only system admins may perform this operation")
+
+// Principal is the authenticated identity on whose behalf a request is executed.
+// It is either a user holding a JWT or a service account holding an api key.
+type Principal struct {
+	ID               string
+	Groups           []string
+	Roles            []string
+	Projects         []string
+	Scopes           []string
+	IsSystemAdmin    bool
+	IsProjectAdmin   bool
+	IsServiceAccount bool
+}
+
+// HasScope returns true if the principal is a service account authenticated with an api key granting the provided scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole returns true if the provided role is in the list of roles of the principal.
+func (p *Principal) HasRole(role string) bool {
+	if len(role) == 0 {
+		return false
+	}
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasProject returns true if the principal is a project admin of the project with the provided id.
+func (p *Principal) HasProject(id string) bool {
+	for _, pr := range p.Projects {
+		if pr == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Identifier returns the id of the principal as identifier value object.
+// A zero identifier is returned if the id is not a UUID.
+func (p *Principal) Identifier() valueobject.Identifier {
+	id, err := valueobject.IdentifierFromBytes([]byte(p.ID))
+	if err != nil {
+		return valueobject.Identifier{}
+	}
+	return id
+}
+
+// contextKey is unexported to prevent collisions with context keys defined in other packages.
+type contextKey struct{}
+
+// NewContext returns a new context carrying the provided principal.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal stored in the context, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// ActorFromContext returns the identifier of the principal stored in the context.
+// It is used to record who made a change. A zero identifier is returned if there is no principal.
+func ActorFromContext(ctx context.Context) valueobject.Identifier {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return valueobject.Identifier{}
+	}
+	return p.Identifier()
+}
+
+// TypedActorFromContext returns the principal stored in the context together with its type, like ActorFromContext.
+// The zero actor is returned if there is no principal, or if its id is not a UUID.
+func TypedActorFromContext(ctx context.Context) event.Actor {
+	p, ok := FromContext(ctx)
+	if !ok || p.Identifier() == (valueobject.Identifier{}) {
+		return event.Actor{}
+	}
+	if p.IsServiceAccount {
+		return event.Actor{ID: p.Identifier(), Type: event.ActorServiceAccount}
+	}
+	return event.Actor{ID: p.Identifier(), Type: event.ActorUser}
+}
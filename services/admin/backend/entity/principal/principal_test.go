@@ -0,0 +1,78 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package principal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal_Context(t *testing.T) {
+	_, ok := principal.FromContext(context.Background())
+	assert.False(t, ok)
+	assert.Equal(t, valueobject.Identifier{}, principal.ActorFromContext(context.Background()))
+
+	p := &principal.Principal{ID: "dc62dcd0-fb83-4488-8e5e-6d361ac79b6b"}
+	ctx := principal.NewContext(context.Background(), p)
+
+	found, ok := principal.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, p, found)
+	assert.Equal(t, "dc62dcd0-fb83-4488-8e5e-6d361ac79b6b", principal.ActorFromContext(ctx).String())
+}
+
+func TestPrincipal_TypedActorFromContext(t *testing.T) {
+	assert.Equal(t, event.Actor{}, principal.TypedActorFromContext(context.Background()))
+
+	user := principal.NewContext(context.Background(), &principal.Principal{ID: "dc62dcd0-fb83-4488-8e5e-6d361ac79b6b"})
+	assert.Equal(t, event.ActorUser, principal.TypedActorFromContext(user).Type)
+	assert.Equal(t, "dc62dcd0-fb83-4488-8e5e-6d361ac79b6b", principal.TypedActorFromContext(user).ID.String())
+
+	account := principal.NewContext(context.Background(), &principal.Principal{ID: "dc62dcd0-fb83-4488-8e5e-6d361ac79b6b", IsServiceAccount: true})
+	assert.Equal(t, event.ActorServiceAccount, principal.TypedActorFromContext(account).Type)
+
+	// an id which is not a UUID cannot be recorded
+	noUUID := principal.NewContext(context.Background(), &principal.Principal{ID: "not-a-uuid"})
+	assert.Equal(t, event.Actor{}, principal.TypedActorFromContext(noUUID))
+}
+
+func TestPrincipal_Identifier_NoUUID(t *testing.T) {
+	p := &principal.Principal{ID: "not-a-uuid"}
+	assert.Equal(t, valueobject.Identifier{}, p.Identifier())
+}
+
+func TestPrincipal_Permissions(t *testing.T) {
+	p := &principal.Principal{
+		Roles:    []string{"Role:1234:Read"},
+		Projects: []string{"1234"},
+		Scopes:   []string{"projects:read"},
+	}
+
+	assert.True(t, p.HasRole("Role:1234:Read"))
+	assert.False(t, p.HasRole("Role:1234:Update"))
+	assert.False(t, p.HasRole(""))
+	assert.True(t, p.HasProject("1234"))
+	assert.False(t, p.HasProject("5678"))
+	assert.True(t, p.HasScope("projects:read"))
+	assert.False(t, p.HasScope("projects:write"))
+}
@@ -43,6 +43,9 @@ var ErrCannotBeDeleted = errors.New("cannot be deleted")
 //ErrProjectHasBeenDeleted project has been marked as deleted
 var ErrProjectHasBeenDeleted = errors.New("project has been marked as deleted")
 
+//ErrProjectNotDeleted project has not been marked as deleted, so it cannot be restored
+var ErrProjectNotDeleted = errors.New("project has not been marked as deleted")
+
 //ErrShortCodeAlreadyExists provided short code already exists
 var ErrShortCodeAlreadyExists = errors.New("provided short code already exists")
 
@@ -60,3 +63,33 @@ var ErrUserDoesNotHaveUpdateProjectPermission = errors.New("user does not have p
 
 //ErrUserDoesNotHaveDeleteProjectPermission user does not have permission to delete projects
 var ErrUserDoesNotHaveDeleteProjectPermission = errors.New("user does not have permission to delete projects")
+
+//ErrInvalidSortField the provided sort field is not supported
+var ErrInvalidSortField = errors.New("projects can only be sorted by shortCode, shortName, createdAt or changedAt")
+
+//ErrInvalidStatus the provided status is not supported
+var ErrInvalidStatus = errors.New("status must be either active, deleted or all")
+
+//ErrInvalidCursor the provided cursor is malformed, or was returned for another sort order
+var ErrInvalidCursor = errors.New("invalid cursor provided")
+
+//ErrNoSearchQuery no search query provided
+var ErrNoSearchQuery = errors.New("no search query provided")
+
+//ErrInvalidEventID the provided event id is malformed
+var ErrInvalidEventID = errors.New("invalid event id provided")
+
+//ErrShortCodeDuplicatedInImport the short code is used by an earlier row of the same import
+var ErrShortCodeDuplicatedInImport = errors.New("short code is used by an earlier row of the import")
+
+//ErrTooManyImportRows the import contains more rows than allowed
+var ErrTooManyImportRows = errors.New("too many rows provided, at most 1000 projects can be imported at once")
+
+//ErrNoImportRows the import does not contain any rows
+var ErrNoImportRows = errors.New("no rows provided")
+
+//ErrMissingIRI the project to migrate has no IRI
+var ErrMissingIRI = errors.New("the project has no IRI")
+
+//ErrIRIDuplicatedInMigration the IRI is used by an earlier project of the same migration
+var ErrIRIDuplicatedInMigration = errors.New("IRI is used by an earlier project of the migration")
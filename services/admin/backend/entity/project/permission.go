@@ -0,0 +1,55 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package project
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// CanCreate returns true if the principal may create projects.
+func CanCreate(p *principal.Principal) bool {
+	return p.IsSystemAdmin || p.HasScope(string(serviceaccount.ScopeProjectsWrite))
+}
+
+// CanUpdate returns true if the principal may update the project with the provided id.
+func CanUpdate(p *principal.Principal, id valueobject.Identifier) bool {
+	return CanCreate(p) || p.HasRole("Role:"+id.String()+":Update")
+}
+
+// CanDelete returns true if the principal may delete projects.
+func CanDelete(p *principal.Principal) bool {
+	return p.IsSystemAdmin || p.HasScope(string(serviceaccount.ScopeProjectsWrite))
+}
+
+// CanRead returns true if the principal may read the project with the provided id.
+func CanRead(p *principal.Principal, id valueobject.Identifier) bool {
+	return p.IsSystemAdmin || p.HasScope(string(serviceaccount.ScopeProjectsRead)) || p.HasRole("Role:"+id.String()+":Read")
+}
+
+// CanList returns true if the principal may list projects.
+// Project admins may only list the projects they are admin of, see IsRestrictedToOwnProjects.
+func CanList(p *principal.Principal) bool {
+	return p.IsSystemAdmin || p.IsProjectAdmin || p.HasScope(string(serviceaccount.ScopeProjectsRead))
+}
+
+// IsRestrictedToOwnProjects returns true if the principal may only list the projects it is a project admin of.
+func IsRestrictedToOwnProjects(p *principal.Principal) bool {
+	return !p.IsSystemAdmin && !p.HasScope(string(serviceaccount.ScopeProjectsRead)) && p.IsProjectAdmin
+}
@@ -0,0 +1,61 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package project_test
+
+import (
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissions(t *testing.T) {
+	id, _ := valueobject.NewIdentifier()
+	other, _ := valueobject.NewIdentifier()
+
+	sysAdmin := &principal.Principal{IsSystemAdmin: true}
+	projectAdmin := &principal.Principal{IsProjectAdmin: true, Projects: []string{id.String()}, Roles: []string{"Role:" + id.String() + ":Read", "Role:" + id.String() + ":Update"}}
+	reader := &principal.Principal{IsServiceAccount: true, Scopes: []string{"projects:read"}}
+	writer := &principal.Principal{IsServiceAccount: true, Scopes: []string{"projects:write"}}
+
+	assert.True(t, project.CanCreate(sysAdmin))
+	assert.True(t, project.CanCreate(writer))
+	assert.False(t, project.CanCreate(projectAdmin))
+	assert.False(t, project.CanCreate(reader))
+
+	assert.True(t, project.CanUpdate(projectAdmin, id))
+	assert.False(t, project.CanUpdate(projectAdmin, other))
+	assert.True(t, project.CanUpdate(writer, other))
+
+	assert.True(t, project.CanDelete(sysAdmin))
+	assert.False(t, project.CanDelete(projectAdmin))
+
+	assert.True(t, project.CanRead(sysAdmin, id))
+	assert.True(t, project.CanRead(reader, id))
+	assert.True(t, project.CanRead(projectAdmin, id))
+	assert.False(t, project.CanRead(projectAdmin, other))
+	assert.False(t, project.CanRead(writer, id))
+
+	assert.True(t, project.CanList(projectAdmin))
+	assert.False(t, project.CanList(writer))
+	assert.True(t, project.IsRestrictedToOwnProjects(projectAdmin))
+	assert.False(t, project.IsRestrictedToOwnProjects(sysAdmin))
+	assert.False(t, project.IsRestrictedToOwnProjects(reader))
+}
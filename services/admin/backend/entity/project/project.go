@@ -23,7 +23,7 @@ import (
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 )
 
-//ProjectAggregate domain entity
+// ProjectAggregate domain entity
 type Aggregate struct {
 	id            valueobject.Identifier
 	aggregateType valueobject.AggregateType
@@ -32,11 +32,13 @@ type Aggregate struct {
 	longName      valueobject.LongName
 	description   valueobject.Description
 	createdAt     valueobject.Timestamp
-	createdBy     valueobject.Identifier
+	createdBy     event.Actor
 	changedAt     valueobject.Timestamp
-	changedBy     valueobject.Identifier
+	changedBy     event.Actor
 	deletedAt     valueobject.Timestamp
-	deletedBy     valueobject.Identifier
+	deletedBy     event.Actor
+	iri           string
+	keywords      []string
 
 	changes []event.Event
 	version int
@@ -79,6 +81,11 @@ func (p Aggregate) CreatedAt() valueobject.Timestamp {
 
 // CreatedBy returns the project's creator identifier.
 func (p Aggregate) CreatedBy() valueobject.Identifier {
+	return p.createdBy.ID
+}
+
+// Creator returns the user or service account which created the project.
+func (p Aggregate) Creator() event.Actor {
 	return p.createdBy
 }
 
@@ -89,6 +96,11 @@ func (p Aggregate) ChangedAt() valueobject.Timestamp {
 
 // ChangedBy returns the project's changer identifier.
 func (p Aggregate) ChangedBy() valueobject.Identifier {
+	return p.changedBy.ID
+}
+
+// Changer returns the user or service account which last changed the project.
+func (p Aggregate) Changer() event.Actor {
 	return p.changedBy
 }
 
@@ -99,9 +111,24 @@ func (p Aggregate) DeletedAt() valueobject.Timestamp {
 
 // DeletedBy returns the identifier of the user who deleted the project.
 func (p Aggregate) DeletedBy() valueobject.Identifier {
+	return p.deletedBy.ID
+}
+
+// Deleter returns the user or service account which deleted the project.
+func (p Aggregate) Deleter() event.Actor {
 	return p.deletedBy
 }
 
+// IRI returns the project's IRI in DSP-API, empty unless the project has been migrated from there.
+func (p Aggregate) IRI() string {
+	return p.iri
+}
+
+// Keywords returns the project's keywords, empty unless the project has been migrated from DSP-API.
+func (p Aggregate) Keywords() []string {
+	return p.keywords
+}
+
 // NewAggregateFromEvents is a helper method that creates a new project
 // from a series of events.
 func NewAggregateFromEvents(events []event.Event) *Aggregate {
@@ -115,8 +142,26 @@ func NewAggregateFromEvents(events []event.Event) *Aggregate {
 }
 
 // NewAggregate create a new project entity.
-// TODO: add user who is making the change
-func NewAggregate(id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) *Aggregate {
+func NewAggregate(id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description, createdBy event.Actor) *Aggregate {
+	p := &Aggregate{}
+
+	p.raise(&event.ProjectCreated{
+		ID:            id,
+		ShortCode:     shortCode,
+		ShortName:     shortName,
+		LongName:      longName,
+		Description:   description,
+		CreatedAt:     valueobject.NewTimestamp(),
+		CreatedBy:     createdBy.ID,
+		CreatedByType: createdBy.Type,
+	})
+
+	return p
+}
+
+// NewMigratedAggregate creates a project entity for a project migrated from DSP-API.
+// The project keeps its IRI, keywords and creation time, it has no creator.
+func NewMigratedAggregate(id valueobject.Identifier, iri string, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description, keywords []string, createdAt valueobject.Timestamp) *Aggregate {
 	p := &Aggregate{}
 
 	p.raise(&event.ProjectCreated{
@@ -125,36 +170,53 @@ func NewAggregate(id valueobject.Identifier, shortCode valueobject.ShortCode, sh
 		ShortName:   shortName,
 		LongName:    longName,
 		Description: description,
-		CreatedAt:   valueobject.NewTimestamp(),
-		CreatedBy:   valueobject.Identifier{},
+		CreatedAt:   createdAt,
+		IRI:         iri,
+		Keywords:    keywords,
 	})
 
 	return p
 }
 
 // UpdateProject updates the project.
-// TODO: add user who updated the project
-func (p *Aggregate) UpdateProject(id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) error {
+func (p *Aggregate) UpdateProject(id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description, changedBy event.Actor) error {
 	p.raise(&event.ProjectChanged{
-		ID:          id,
-		ShortCode:   shortCode,
-		ShortName:   shortName,
-		LongName:    longName,
-		Description: description,
-		ChangedAt:   valueobject.NewTimestamp(),
-		ChangedBy:   valueobject.Identifier{},
+		ID:            id,
+		ShortCode:     shortCode,
+		ShortName:     shortName,
+		LongName:      longName,
+		Description:   description,
+		ChangedAt:     valueobject.NewTimestamp(),
+		ChangedBy:     changedBy.ID,
+		ChangedByType: changedBy.Type,
 	})
 
 	return nil
 }
 
 // DeleteProject deletes the project.
-// TODO: add user who deleted the project
-func (p *Aggregate) DeleteProject(id valueobject.Identifier) error {
+func (p *Aggregate) DeleteProject(id valueobject.Identifier, deletedBy event.Actor) error {
 	p.raise(&event.ProjectDeleted{
-		ID:        p.id,
-		DeletedAt: valueobject.NewTimestamp(),
-		DeletedBy: valueobject.Identifier{},
+		ID:            p.id,
+		DeletedAt:     valueobject.NewTimestamp(),
+		DeletedBy:     deletedBy.ID,
+		DeletedByType: deletedBy.Type,
+	})
+
+	return nil
+}
+
+// RestoreProject restores the deleted project.
+func (p *Aggregate) RestoreProject(restoredBy event.Actor) error {
+	if p.deletedAt.Time().IsZero() {
+		return ErrProjectNotDeleted
+	}
+
+	p.raise(&event.ProjectRestored{
+		ID:             p.id,
+		RestoredAt:     valueobject.NewTimestamp(),
+		RestoredBy:     restoredBy.ID,
+		RestoredByType: restoredBy.Type,
 	})
 
 	return nil
@@ -162,17 +224,17 @@ func (p *Aggregate) DeleteProject(id valueobject.Identifier) error {
 
 // ChangeShortCode changes the short code of the project.
 // TODO: check if short code is free (needs to be unique)
-// TODO: add user who initiated the change
-func (p *Aggregate) ChangeShortCode(shortCode valueobject.ShortCode) error {
+func (p *Aggregate) ChangeShortCode(shortCode valueobject.ShortCode, changedBy event.Actor) error {
 	if !p.deletedAt.Time().IsZero() {
 		return ErrProjectHasBeenDeleted
 	}
 
 	p.raise(&event.ProjectShortCodeChanged{
-		ID:        p.id,
-		ShortCode: shortCode,
-		ChangedAt: valueobject.NewTimestamp(),
-		ChangedBy: valueobject.Identifier{},
+		ID:            p.id,
+		ShortCode:     shortCode,
+		ChangedAt:     valueobject.NewTimestamp(),
+		ChangedBy:     changedBy.ID,
+		ChangedByType: changedBy.Type,
 	})
 
 	return nil
@@ -180,17 +242,17 @@ func (p *Aggregate) ChangeShortCode(shortCode valueobject.ShortCode) error {
 
 // ChangeShortName changes the short name of the project.
 // TODO: check if short name is free (needs to be unique)
-// TODO: add user who initiated the change
-func (p *Aggregate) ChangeShortName(shortName valueobject.ShortName) error {
+func (p *Aggregate) ChangeShortName(shortName valueobject.ShortName, changedBy event.Actor) error {
 	if !p.deletedAt.Time().IsZero() {
 		return ErrProjectHasBeenDeleted
 	}
 
 	p.raise(&event.ProjectShortNameChanged{
-		ID:        p.id,
-		ShortName: shortName,
-		ChangedAt: valueobject.NewTimestamp(),
-		ChangedBy: valueobject.Identifier{},
+		ID:            p.id,
+		ShortName:     shortName,
+		ChangedAt:     valueobject.NewTimestamp(),
+		ChangedBy:     changedBy.ID,
+		ChangedByType: changedBy.Type,
 	})
 
 	return nil
@@ -198,34 +260,34 @@ func (p *Aggregate) ChangeShortName(shortName valueobject.ShortName) error {
 
 // ChangeLongName changes the long name of the project.
 // TODO: check if long name is free (needs to be unique)
-// TODO: add user who initiated the change
-func (p *Aggregate) ChangeLongName(longName valueobject.LongName) error {
+func (p *Aggregate) ChangeLongName(longName valueobject.LongName, changedBy event.Actor) error {
 	if !p.deletedAt.Time().IsZero() {
 		return ErrProjectHasBeenDeleted
 	}
 
 	p.raise(&event.ProjectLongNameChanged{
-		ID:        p.id,
-		LongName:  longName,
-		ChangedAt: valueobject.NewTimestamp(),
-		ChangedBy: valueobject.Identifier{},
+		ID:            p.id,
+		LongName:      longName,
+		ChangedAt:     valueobject.NewTimestamp(),
+		ChangedBy:     changedBy.ID,
+		ChangedByType: changedBy.Type,
 	})
 
 	return nil
 }
 
 // ChangeDescription changes the description of the project.
-// TODO: add user who initiated the change
-func (p *Aggregate) ChangeDescription(description valueobject.Description) error {
+func (p *Aggregate) ChangeDescription(description valueobject.Description, changedBy event.Actor) error {
 	if !p.deletedAt.Time().IsZero() {
 		return ErrProjectHasBeenDeleted
 	}
 
 	p.raise(&event.ProjectDescriptionChanged{
-		ID:          p.id,
-		Description: description,
-		ChangedAt:   valueobject.NewTimestamp(),
-		ChangedBy:   valueobject.Identifier{},
+		ID:            p.id,
+		Description:   description,
+		ChangedAt:     valueobject.NewTimestamp(),
+		ChangedBy:     changedBy.ID,
+		ChangedByType: changedBy.Type,
 	})
 
 	return nil
@@ -259,7 +321,9 @@ func (p *Aggregate) On(ev event.Event, new bool) {
 		p.longName = e.LongName
 		p.description = e.Description
 		p.createdAt = e.CreatedAt
-		p.createdBy = e.CreatedBy
+		p.createdBy = event.Actor{ID: e.CreatedBy, Type: e.CreatedByType}
+		p.iri = e.IRI
+		p.keywords = e.Keywords
 
 	case *event.ProjectChanged:
 		p.id = e.ID
@@ -268,32 +332,38 @@ func (p *Aggregate) On(ev event.Event, new bool) {
 		p.longName = e.LongName
 		p.description = e.Description
 		p.changedAt = e.ChangedAt
-		p.changedBy = e.ChangedBy
+		p.changedBy = event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}
 
 	case *event.ProjectDeleted:
 		p.id = e.ID
 		p.deletedAt = e.DeletedAt
-		p.deletedBy = e.DeletedBy
+		p.deletedBy = event.Actor{ID: e.DeletedBy, Type: e.DeletedByType}
+
+	case *event.ProjectRestored:
+		p.deletedAt = valueobject.Timestamp{}
+		p.deletedBy = event.Actor{}
+		p.changedAt = e.RestoredAt
+		p.changedBy = event.Actor{ID: e.RestoredBy, Type: e.RestoredByType}
 
 	case *event.ProjectShortCodeChanged:
 		p.shortCode = e.ShortCode
 		p.changedAt = e.ChangedAt
-		p.changedBy = e.ChangedBy
+		p.changedBy = event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}
 
 	case *event.ProjectShortNameChanged:
 		p.shortName = e.ShortName
 		p.changedAt = e.ChangedAt
-		p.changedBy = e.ChangedBy
+		p.changedBy = event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}
 
 	case *event.ProjectLongNameChanged:
 		p.longName = e.LongName
 		p.changedAt = e.ChangedAt
-		p.changedBy = e.ChangedBy
+		p.changedBy = event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}
 
 	case *event.ProjectDescriptionChanged:
 		p.description = e.Description
 		p.changedAt = e.ChangedAt
-		p.changedBy = e.ChangedBy
+		p.changedBy = event.Actor{ID: e.ChangedBy, Type: e.ChangedByType}
 
 	default:
 		log.Printf("unknown event %T", e)
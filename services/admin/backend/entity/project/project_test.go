@@ -34,7 +34,7 @@ func TestProject_NewAggregate(t *testing.T) {
 	expectedLongName, _ := valueobject.NewLongName("project long name")
 	expectedDescription, _ := valueobject.NewDescription("this is a test project")
 
-	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription)
+	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription, event.Actor{})
 	assert.Equal(t, expectedId, p.ID())
 	assert.Equal(t, expectedAggregateType, p.AggregateType())
 	assert.Equal(t, expectedShortCode, p.ShortCode())
@@ -60,6 +60,30 @@ func TestProject_NewAggregate(t *testing.T) {
 	}
 }
 
+func TestProject_NewMigratedAggregate(t *testing.T) {
+
+	expectedId, _ := valueobject.NewIdentifier()
+	expectedShortCode, _ := valueobject.NewShortCode("0801")
+	expectedShortName, _ := valueobject.NewShortName("beol")
+	expectedLongName, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	expectedDescription, _ := valueobject.NewDescription("Bernoulli-Euler Online")
+	expectedCreatedAt := valueobject.NewTimestampFromUnix(1577836800)
+
+	p := project.NewMigratedAggregate(expectedId, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", expectedShortCode, expectedShortName, expectedLongName, expectedDescription, []string{"mathematics", "letters"}, expectedCreatedAt)
+	assert.Equal(t, expectedId, p.ID())
+	assert.Equal(t, expectedShortCode, p.ShortCode())
+	assert.Equal(t, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", p.IRI())
+	assert.Equal(t, []string{"mathematics", "letters"}, p.Keywords())
+	assert.Equal(t, expectedCreatedAt, p.CreatedAt())
+	assert.Equal(t, valueobject.Identifier{}, p.CreatedBy())
+
+	// the IRI and keywords are restored from the events
+	restored := project.NewAggregateFromEvents(p.Events())
+	assert.Equal(t, "http://rdfh.ch/projects/yTerZGyxjZVqFMNNKXCDPF", restored.IRI())
+	assert.Equal(t, []string{"mathematics", "letters"}, restored.Keywords())
+	assert.Equal(t, expectedCreatedAt, restored.CreatedAt())
+}
+
 func TestProject_NewAggregateFromEvents(t *testing.T) {
 
 	expectedId, _ := valueobject.NewIdentifier()
@@ -105,7 +129,7 @@ func TestProject_ChangeShortCode(t *testing.T) {
 	expectedLongName, _ := valueobject.NewLongName("project long name")
 	expectedDescription, _ := valueobject.NewDescription("this is a test project")
 
-	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription)
+	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription, event.Actor{})
 	assert.Equal(t, expectedId, p.ID())
 	assert.Equal(t, expectedAggregateType, p.AggregateType())
 	assert.Equal(t, expectedShortCode, p.ShortCode())
@@ -132,7 +156,7 @@ func TestProject_ChangeShortCode(t *testing.T) {
 
 	newShortCode, _ := valueobject.NewShortCode("nsc")
 
-	p.ChangeShortCode(newShortCode)
+	p.ChangeShortCode(newShortCode, event.Actor{})
 
 	assert.Len(t, p.Events(), 2)
 
@@ -159,7 +183,7 @@ func TestProject_ChangeShortName(t *testing.T) {
 	expectedLongName, _ := valueobject.NewLongName("project long name")
 	expectedDescription, _ := valueobject.NewDescription("this is a test project")
 
-	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription)
+	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription, event.Actor{})
 	assert.Equal(t, expectedId, p.ID())
 	assert.Equal(t, expectedAggregateType, p.AggregateType())
 	assert.Equal(t, expectedShortCode, p.ShortCode())
@@ -186,7 +210,7 @@ func TestProject_ChangeShortName(t *testing.T) {
 
 	newShortName, _ := valueobject.NewShortName("new short name")
 
-	p.ChangeShortName(newShortName)
+	p.ChangeShortName(newShortName, event.Actor{})
 
 	assert.Len(t, p.Events(), 2)
 
@@ -213,7 +237,7 @@ func TestProject_ChangeLongName(t *testing.T) {
 	expectedLongName, _ := valueobject.NewLongName("project long name")
 	expectedDescription, _ := valueobject.NewDescription("this is a test project")
 
-	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription)
+	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription, event.Actor{})
 	assert.Equal(t, expectedId, p.ID())
 	assert.Equal(t, expectedAggregateType, p.AggregateType())
 	assert.Equal(t, expectedShortCode, p.ShortCode())
@@ -240,7 +264,7 @@ func TestProject_ChangeLongName(t *testing.T) {
 
 	newLongName, _ := valueobject.NewLongName("new long name")
 
-	p.ChangeLongName(newLongName)
+	p.ChangeLongName(newLongName, event.Actor{})
 
 	assert.Len(t, p.Events(), 2)
 
@@ -267,7 +291,7 @@ func TestProject_ChangeDescription(t *testing.T) {
 	expectedLongName, _ := valueobject.NewLongName("project long name")
 	expectedDescription, _ := valueobject.NewDescription("this is a test project")
 
-	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription)
+	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription, event.Actor{})
 	assert.Equal(t, expectedId, p.ID())
 	assert.Equal(t, expectedAggregateType, p.AggregateType())
 	assert.Equal(t, expectedShortCode, p.ShortCode())
@@ -294,7 +318,7 @@ func TestProject_ChangeDescription(t *testing.T) {
 
 	newDescription, _ := valueobject.NewDescription("new description")
 
-	p.ChangeDescription(newDescription)
+	p.ChangeDescription(newDescription, event.Actor{})
 
 	assert.Len(t, p.Events(), 2)
 
@@ -321,7 +345,7 @@ func TestProject_DeleteProject(t *testing.T) {
 	expectedLongName, _ := valueobject.NewLongName("project long name")
 	expectedDescription, _ := valueobject.NewDescription("this is a test project")
 
-	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription)
+	p := project.NewAggregate(expectedId, expectedShortCode, expectedShortName, expectedLongName, expectedDescription, event.Actor{})
 	assert.Equal(t, expectedId, p.ID())
 	assert.Equal(t, expectedAggregateType, p.AggregateType())
 	assert.Equal(t, expectedShortCode, p.ShortCode())
@@ -346,7 +370,7 @@ func TestProject_DeleteProject(t *testing.T) {
 		t.Fatalf("unexpected event type: %T", e)
 	}
 
-	p.DeleteProject(p.ID())
+	p.DeleteProject(p.ID(), event.Actor{})
 
 	assert.Len(t, p.Events(), 2)
 
@@ -364,7 +388,7 @@ func TestProject_DeleteProject(t *testing.T) {
 	newShortCode, _ := valueobject.NewShortCode("nsc")
 
 	// this should fail because the project has been deleted
-	err := p.ChangeShortCode(newShortCode)
+	err := p.ChangeShortCode(newShortCode, event.Actor{})
 
 	// assert that no new event was created
 	assert.Len(t, p.Events(), 2)
@@ -372,3 +396,40 @@ func TestProject_DeleteProject(t *testing.T) {
 	// assert that an error was returned from the ChangeShortCode function
 	assert.Equal(t, err, project.ErrProjectHasBeenDeleted)
 }
+
+func TestProject_RestoreProject(t *testing.T) {
+
+	id, _ := valueobject.NewIdentifier()
+	restoredByID, _ := valueobject.NewIdentifier()
+	restoredBy := event.Actor{ID: restoredByID, Type: event.ActorServiceAccount}
+	shortCode, _ := valueobject.NewShortCode("psc")
+	shortName, _ := valueobject.NewShortName("short name")
+	longName, _ := valueobject.NewLongName("project long name")
+	description, _ := valueobject.NewDescription("this is a test project")
+
+	p := project.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{})
+
+	// only deleted projects can be restored
+	assert.Equal(t, project.ErrProjectNotDeleted, p.RestoreProject(restoredBy))
+	assert.Len(t, p.Events(), 1)
+
+	p.DeleteProject(id, event.Actor{})
+	assert.False(t, p.DeletedAt().Time().IsZero())
+
+	assert.Nil(t, p.RestoreProject(restoredBy))
+	assert.Len(t, p.Events(), 3)
+	assert.True(t, p.DeletedAt().Time().IsZero())
+	assert.Equal(t, valueobject.Identifier{}, p.DeletedBy())
+	assert.Equal(t, restoredByID, p.ChangedBy())
+	assert.Equal(t, restoredBy, p.Changer())
+
+	switch e := p.Events()[2].(type) {
+	case *event.ProjectRestored:
+		assert.Equal(t, id, e.ID)
+		assert.Equal(t, restoredByID, e.RestoredBy)
+		assert.Equal(t, event.ActorServiceAccount, e.RestoredByType)
+		assert.False(t, e.RestoredAt.Time().IsZero())
+	default:
+		t.Fatalf("unexpected event type: %T", e)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2021 Data and Service Center for the Humanities - DaSCH
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// Snapshot is the state of a project aggregate after its first Version events,
+// so that the aggregate can be loaded without replaying all of them.
+type Snapshot struct {
+	ID            valueobject.Identifier  `json:"id"`
+	ShortCode     valueobject.ShortCode   `json:"shortCode"`
+	ShortName     valueobject.ShortName   `json:"shortName"`
+	LongName      valueobject.LongName    `json:"longName"`
+	Description   valueobject.Description `json:"description"`
+	CreatedAt     valueobject.Timestamp   `json:"createdAt"`
+	CreatedBy     valueobject.Identifier  `json:"createdBy"`
+	CreatedByType event.ActorType         `json:"createdByType,omitempty"`
+	ChangedAt     valueobject.Timestamp   `json:"changedAt"`
+	ChangedBy     valueobject.Identifier  `json:"changedBy"`
+	ChangedByType event.ActorType         `json:"changedByType,omitempty"`
+	DeletedAt     valueobject.Timestamp   `json:"deletedAt"`
+	DeletedBy     valueobject.Identifier  `json:"deletedBy"`
+	DeletedByType event.ActorType         `json:"deletedByType,omitempty"`
+	IRI           string                  `json:"iri,omitempty"`
+	Keywords      []string                `json:"keywords,omitempty"`
+	Version       int                     `json:"version"`
+	// Revision is the stream revision of the last event covered by the snapshot. It is set by the repository,
+	// since the revisions of a stream which was overwritten do not start at 0. It is nil in older snapshots.
+	Revision *uint64 `json:"revision,omitempty"`
+}
+
+// Snapshot returns the state of the project, without its uncommitted events.
+func (p Aggregate) Snapshot() Snapshot {
+	return Snapshot{
+		ID:            p.id,
+		ShortCode:     p.shortCode,
+		ShortName:     p.shortName,
+		LongName:      p.longName,
+		Description:   p.description,
+		CreatedAt:     p.createdAt,
+		CreatedBy:     p.createdBy.ID,
+		CreatedByType: p.createdBy.Type,
+		ChangedAt:     p.changedAt,
+		ChangedBy:     p.changedBy.ID,
+		ChangedByType: p.changedBy.Type,
+		DeletedAt:     p.deletedAt,
+		DeletedBy:     p.deletedBy.ID,
+		DeletedByType: p.deletedBy.Type,
+		IRI:           p.iri,
+		Keywords:      p.keywords,
+		Version:       p.version,
+	}
+}
+
+// NewAggregateFromSnapshot recreates a project from a snapshot and the events recorded after it.
+func NewAggregateFromSnapshot(s Snapshot, events []event.Event) *Aggregate {
+	at, _ := valueobject.NewAggregateType("http://ns.dasch.swiss/admin#Project")
+	p := &Aggregate{
+		id:            s.ID,
+		aggregateType: at,
+		shortCode:     s.ShortCode,
+		shortName:     s.ShortName,
+		longName:      s.LongName,
+		description:   s.Description,
+		createdAt:     s.CreatedAt,
+		createdBy:     event.Actor{ID: s.CreatedBy, Type: s.CreatedByType},
+		changedAt:     s.ChangedAt,
+		changedBy:     event.Actor{ID: s.ChangedBy, Type: s.ChangedByType},
+		deletedAt:     s.DeletedAt,
+		deletedBy:     event.Actor{ID: s.DeletedBy, Type: s.DeletedByType},
+		iri:           s.IRI,
+		keywords:      s.Keywords,
+		version:       s.Version,
+	}
+
+	for _, e := range events {
+		p.On(e, false)
+	}
+
+	return p
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProject_Snapshot(t *testing.T) {
+
+	id, _ := valueobject.NewIdentifier()
+	shortCode, _ := valueobject.NewShortCode("0801")
+	shortName, _ := valueobject.NewShortName("beol")
+	longName, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	description, _ := valueobject.NewDescription("Bernoulli-Euler Online")
+	newName, _ := valueobject.NewShortName("BEOL")
+
+	p := project.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{})
+	p.DeleteProject(id, event.Actor{})
+	p = project.NewAggregateFromEvents(p.Events())
+	assert.Equal(t, 2, p.Version())
+
+	// the snapshot survives a round trip through JSON, as done by the repository
+	b, err := json.Marshal(p.Snapshot())
+	assert.Nil(t, err)
+	var snapshot project.Snapshot
+	assert.Nil(t, json.Unmarshal(b, &snapshot))
+	assert.Equal(t, 2, snapshot.Version)
+
+	// the events after the snapshot are applied on top of it
+	later := []event.Event{&event.ProjectRestored{ID: id}, &event.ProjectShortNameChanged{ID: id, ShortName: newName}}
+	restored := project.NewAggregateFromSnapshot(snapshot, later)
+	assert.Equal(t, id, restored.ID())
+	assert.Equal(t, p.AggregateType(), restored.AggregateType())
+	assert.Equal(t, shortCode, restored.ShortCode())
+	assert.Equal(t, newName, restored.ShortName())
+	assert.Equal(t, p.CreatedAt().Unix(), restored.CreatedAt().Unix())
+	assert.True(t, restored.DeletedAt().Time().IsZero())
+	assert.Equal(t, 4, restored.Version())
+	assert.Empty(t, restored.Events())
+}
@@ -0,0 +1,50 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package serviceaccount
+
+import "errors"
+
+//ErrServiceAccountNotFound service account not found
+var ErrServiceAccountNotFound = errors.New("no service account found with the provided uuid")
+
+//ErrServiceAccountHasBeenDeleted service account has been marked as deleted
+var ErrServiceAccountHasBeenDeleted = errors.New("service account has been marked as deleted")
+
+//ErrAPIKeyNotFound api key not found
+var ErrAPIKeyNotFound = errors.New("no api key found with the provided uuid")
+
+//ErrAPIKeyHasBeenRevoked api key has been revoked
+var ErrAPIKeyHasBeenRevoked = errors.New("api key has been revoked")
+
+//ErrAPIKeyHasExpired api key has expired
+var ErrAPIKeyHasExpired = errors.New("api key has expired")
+
+//ErrInvalidAPIKey the provided api key is malformed or does not match any issued key
+var ErrInvalidAPIKey = errors.New("invalid api key provided")
+
+//ErrInvalidScope unknown scope
+var ErrInvalidScope = errors.New("invalid scope provided")
+
+//ErrNoScopesProvided no scopes provided
+var ErrNoScopesProvided = errors.New("at least one scope must be provided")
+
+//ErrInvalidExpiry expiry lies in the past
+var ErrInvalidExpiry = errors.New("expiry must lie in the future")
+
+//ErrUserDoesNotHaveManageServiceAccountsPermission user does not have permission to manage service accounts
+var ErrUserDoesNotHaveManageServiceAccountsPermission = errors.New("user does not have permission to manage service accounts")
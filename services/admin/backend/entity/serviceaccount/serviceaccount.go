@@ -0,0 +1,411 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package serviceaccount
+
+import (
+	"log"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// Scope grants the holder of an api key access to a group of operations.
+type Scope string
+
+const (
+	// ScopeProjectsRead allows reading all projects.
+	ScopeProjectsRead Scope = "projects:read"
+	// ScopeProjectsWrite allows creating, updating and deleting projects.
+	ScopeProjectsWrite Scope = "projects:write"
+)
+
+// ParseScope returns the scope corresponding to the provided string.
+func ParseScope(s string) (Scope, error) {
+	switch sc := Scope(s); sc {
+	case ScopeProjectsRead, ScopeProjectsWrite:
+		return sc, nil
+	default:
+		return "", ErrInvalidScope
+	}
+}
+
+// APIKey is a credential issued to a service account.
+type APIKey struct {
+	id         valueobject.Identifier
+	hash       string
+	scopes     []Scope
+	issuedAt   valueobject.Timestamp
+	issuedBy   event.Actor
+	expiresAt  valueobject.Timestamp
+	lastUsedAt valueobject.Timestamp
+	revokedAt  valueobject.Timestamp
+	revokedBy  event.Actor
+}
+
+// ID returns the api key's id.
+func (k APIKey) ID() valueobject.Identifier {
+	return k.id
+}
+
+// Hash returns the hex encoded SHA-256 hash of the api key's secret.
+func (k APIKey) Hash() string {
+	return k.hash
+}
+
+// Scopes returns the scopes granted by the api key.
+func (k APIKey) Scopes() []Scope {
+	return k.scopes
+}
+
+// IssuedAt returns the time the api key was issued.
+func (k APIKey) IssuedAt() valueobject.Timestamp {
+	return k.issuedAt
+}
+
+// IssuedBy returns the identifier of the user who issued the api key.
+func (k APIKey) IssuedBy() valueobject.Identifier {
+	return k.issuedBy.ID
+}
+
+// Issuer returns the user or service account which issued the api key.
+func (k APIKey) Issuer() event.Actor {
+	return k.issuedBy
+}
+
+// ExpiresAt returns the time the api key expires. A zero value means the key never expires.
+func (k APIKey) ExpiresAt() valueobject.Timestamp {
+	return k.expiresAt
+}
+
+// LastUsedAt returns the last time the api key was used to authenticate.
+func (k APIKey) LastUsedAt() valueobject.Timestamp {
+	return k.lastUsedAt
+}
+
+// RevokedAt returns the time the api key was revoked.
+func (k APIKey) RevokedAt() valueobject.Timestamp {
+	return k.revokedAt
+}
+
+// RevokedBy returns the identifier of the user who revoked the api key.
+func (k APIKey) RevokedBy() valueobject.Identifier {
+	return k.revokedBy.ID
+}
+
+// Revoker returns the user or service account which revoked the api key.
+func (k APIKey) Revoker() event.Actor {
+	return k.revokedBy
+}
+
+// HasScope returns true if the api key grants the provided scope.
+func (k APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error if the api key has been revoked or has expired at the provided time.
+func (k APIKey) Validate(now time.Time) error {
+	if !k.revokedAt.Time().IsZero() {
+		return ErrAPIKeyHasBeenRevoked
+	}
+	if !k.expiresAt.Time().IsZero() && !now.Before(k.expiresAt.Time()) {
+		return ErrAPIKeyHasExpired
+	}
+	return nil
+}
+
+//Aggregate service account domain entity
+type Aggregate struct {
+	id            valueobject.Identifier
+	aggregateType valueobject.AggregateType
+	name          valueobject.LongName
+	description   valueobject.Description
+	createdAt     valueobject.Timestamp
+	createdBy     event.Actor
+	deletedAt     valueobject.Timestamp
+	deletedBy     event.Actor
+	keys          []APIKey
+
+	changes []event.Event
+	version int
+}
+
+// ID returns the service account's id.
+func (a Aggregate) ID() valueobject.Identifier {
+	return a.id
+}
+
+// AggregateType returns the aggregate's type.
+func (a Aggregate) AggregateType() valueobject.AggregateType {
+	return a.aggregateType
+}
+
+// Name returns the service account's name.
+func (a Aggregate) Name() valueobject.LongName {
+	return a.name
+}
+
+// Description returns the service account's description.
+func (a Aggregate) Description() valueobject.Description {
+	return a.description
+}
+
+// CreatedAt returns the service account's creation time.
+func (a Aggregate) CreatedAt() valueobject.Timestamp {
+	return a.createdAt
+}
+
+// CreatedBy returns the service account's creator identifier.
+func (a Aggregate) CreatedBy() valueobject.Identifier {
+	return a.createdBy.ID
+}
+
+// Creator returns the user or service account which created the service account.
+func (a Aggregate) Creator() event.Actor {
+	return a.createdBy
+}
+
+// DeletedAt returns the service account's deletion time.
+func (a Aggregate) DeletedAt() valueobject.Timestamp {
+	return a.deletedAt
+}
+
+// DeletedBy returns the identifier of the user who deleted the service account.
+func (a Aggregate) DeletedBy() valueobject.Identifier {
+	return a.deletedBy.ID
+}
+
+// Deleter returns the user or service account which deleted the service account.
+func (a Aggregate) Deleter() event.Actor {
+	return a.deletedBy
+}
+
+// Keys returns all api keys ever issued to the service account, including revoked and expired ones.
+func (a Aggregate) Keys() []APIKey {
+	return a.keys
+}
+
+// Key returns the api key with the provided id.
+func (a Aggregate) Key(keyID valueobject.Identifier) (APIKey, error) {
+	for _, k := range a.keys {
+		if k.id.Equals(keyID) {
+			return k, nil
+		}
+	}
+	return APIKey{}, ErrAPIKeyNotFound
+}
+
+// NewAggregateFromEvents is a helper method that creates a new service account
+// from a series of events.
+func NewAggregateFromEvents(events []event.Event) *Aggregate {
+	a := &Aggregate{}
+
+	for _, e := range events {
+		a.On(e, false)
+	}
+
+	return a
+}
+
+// NewAggregate creates a new service account entity.
+func NewAggregate(id valueobject.Identifier, name valueobject.LongName, description valueobject.Description, createdBy event.Actor) *Aggregate {
+	a := &Aggregate{}
+
+	a.raise(&event.ServiceAccountCreated{
+		ID:            id,
+		Name:          name,
+		Description:   description,
+		CreatedAt:     valueobject.NewTimestamp(),
+		CreatedBy:     createdBy.ID,
+		CreatedByType: createdBy.Type,
+	})
+
+	return a
+}
+
+// DeleteServiceAccount deletes the service account, which revokes access for all of its api keys.
+func (a *Aggregate) DeleteServiceAccount(deletedBy event.Actor) error {
+	if !a.deletedAt.Time().IsZero() {
+		return ErrServiceAccountHasBeenDeleted
+	}
+
+	a.raise(&event.ServiceAccountDeleted{
+		ID:            a.id,
+		DeletedAt:     valueobject.NewTimestamp(),
+		DeletedBy:     deletedBy.ID,
+		DeletedByType: deletedBy.Type,
+	})
+
+	return nil
+}
+
+// IssueKey adds a new api key to the service account.
+// The hash is the hex encoded SHA-256 hash of the key's secret.
+// A zero expiresAt issues a key that never expires.
+func (a *Aggregate) IssueKey(keyID valueobject.Identifier, hash string, scopes []Scope, expiresAt valueobject.Timestamp, issuedBy event.Actor) error {
+	if !a.deletedAt.Time().IsZero() {
+		return ErrServiceAccountHasBeenDeleted
+	}
+
+	if len(scopes) == 0 {
+		return ErrNoScopesProvided
+	}
+
+	var s []string
+	for _, sc := range scopes {
+		if _, err := ParseScope(string(sc)); err != nil {
+			return err
+		}
+		s = append(s, string(sc))
+	}
+
+	issuedAt := valueobject.NewTimestamp()
+	if !expiresAt.Time().IsZero() && !expiresAt.Time().After(issuedAt.Time()) {
+		return ErrInvalidExpiry
+	}
+
+	a.raise(&event.APIKeyIssued{
+		ID:           a.id,
+		KeyID:        keyID,
+		KeyHash:      hash,
+		Scopes:       s,
+		ExpiresAt:    expiresAt,
+		IssuedAt:     issuedAt,
+		IssuedBy:     issuedBy.ID,
+		IssuedByType: issuedBy.Type,
+	})
+
+	return nil
+}
+
+// RevokeKey revokes the api key with the provided id.
+func (a *Aggregate) RevokeKey(keyID valueobject.Identifier, revokedBy event.Actor) error {
+	if !a.deletedAt.Time().IsZero() {
+		return ErrServiceAccountHasBeenDeleted
+	}
+
+	k, err := a.Key(keyID)
+	if err != nil {
+		return err
+	}
+
+	if !k.revokedAt.Time().IsZero() {
+		return ErrAPIKeyHasBeenRevoked
+	}
+
+	a.raise(&event.APIKeyRevoked{
+		ID:            a.id,
+		KeyID:         keyID,
+		RevokedAt:     valueobject.NewTimestamp(),
+		RevokedBy:     revokedBy.ID,
+		RevokedByType: revokedBy.Type,
+	})
+
+	return nil
+}
+
+// RecordKeyUsage records that the api key with the provided id was used to authenticate.
+func (a *Aggregate) RecordKeyUsage(keyID valueobject.Identifier) error {
+	if _, err := a.Key(keyID); err != nil {
+		return err
+	}
+
+	a.raise(&event.APIKeyUsed{
+		ID:     a.id,
+		KeyID:  keyID,
+		UsedAt: valueobject.NewTimestamp(),
+	})
+
+	return nil
+}
+
+// The raise method appends the event into the changes slice and applies it to the aggregate.
+func (a *Aggregate) raise(event event.Event) {
+	a.changes = append(a.changes, event)
+	a.On(event, true)
+}
+
+// On handles events on the service account aggregate.
+// See project.Aggregate.On for the reasoning behind never returning an error here.
+func (a *Aggregate) On(ev event.Event, new bool) {
+	switch e := ev.(type) {
+	case *event.ServiceAccountCreated:
+		at, _ := valueobject.NewAggregateType("http://ns.dasch.swiss/admin#ServiceAccount")
+		a.aggregateType = at
+		a.id = e.ID
+		a.name = e.Name
+		a.description = e.Description
+		a.createdAt = e.CreatedAt
+		a.createdBy = event.Actor{ID: e.CreatedBy, Type: e.CreatedByType}
+
+	case *event.ServiceAccountDeleted:
+		a.deletedAt = e.DeletedAt
+		a.deletedBy = event.Actor{ID: e.DeletedBy, Type: e.DeletedByType}
+
+	case *event.APIKeyIssued:
+		var scopes []Scope
+		for _, s := range e.Scopes {
+			scopes = append(scopes, Scope(s))
+		}
+		a.keys = append(a.keys, APIKey{
+			id:        e.KeyID,
+			hash:      e.KeyHash,
+			scopes:    scopes,
+			issuedAt:  e.IssuedAt,
+			issuedBy:  event.Actor{ID: e.IssuedBy, Type: e.IssuedByType},
+			expiresAt: e.ExpiresAt,
+		})
+
+	case *event.APIKeyRevoked:
+		for i := range a.keys {
+			if a.keys[i].id.Equals(e.KeyID) {
+				a.keys[i].revokedAt = e.RevokedAt
+				a.keys[i].revokedBy = event.Actor{ID: e.RevokedBy, Type: e.RevokedByType}
+			}
+		}
+
+	case *event.APIKeyUsed:
+		for i := range a.keys {
+			if a.keys[i].id.Equals(e.KeyID) {
+				a.keys[i].lastUsedAt = e.UsedAt
+			}
+		}
+
+	default:
+		log.Printf("unknown event %T", e)
+	}
+
+	if !new {
+		a.version++
+	}
+}
+
+// Events returns the uncommitted events from the service account aggregate.
+func (a Aggregate) Events() []event.Event {
+	return a.changes
+}
+
+// Version returns the last version of the service account aggregate before changes.
+func (a Aggregate) Version() int {
+	return a.version
+}
@@ -0,0 +1,151 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package serviceaccount_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceAccount_NewAggregate(t *testing.T) {
+	expectedId, _ := valueobject.NewIdentifier()
+	expectedAggregateType, _ := valueobject.NewAggregateType("http://ns.dasch.swiss/admin#ServiceAccount")
+	expectedName, _ := valueobject.NewLongName("ingest pipeline")
+	expectedDescription, _ := valueobject.NewDescription("reads project data during ingest")
+	expectedCreatedBy, _ := valueobject.NewIdentifier()
+
+	a := serviceaccount.NewAggregate(expectedId, expectedName, expectedDescription, event.Actor{ID: expectedCreatedBy, Type: event.ActorUser})
+	assert.Equal(t, expectedId, a.ID())
+	assert.Equal(t, expectedAggregateType, a.AggregateType())
+	assert.Equal(t, expectedName, a.Name())
+	assert.Equal(t, expectedDescription, a.Description())
+	assert.Equal(t, expectedCreatedBy, a.CreatedBy())
+	assert.Equal(t, event.ActorUser, a.Creator().Type)
+	assert.False(t, a.CreatedAt().Time().IsZero())
+	assert.Empty(t, a.Keys())
+
+	switch e := a.Events()[0].(type) {
+	case *event.ServiceAccountCreated:
+		assert.Equal(t, expectedId, e.ID)
+		assert.Equal(t, expectedName, e.Name)
+		assert.Equal(t, event.ActorUser, e.CreatedByType)
+	default:
+		t.Fatalf("unexpected event type: %T", e)
+	}
+}
+
+func TestServiceAccount_IssueKey(t *testing.T) {
+	a := newServiceAccount()
+	keyID, _ := valueobject.NewIdentifier()
+	expiresAt := valueobject.NewTimestampFromUnix(time.Now().Add(time.Hour).Unix())
+
+	err := a.IssueKey(keyID, "hash", []serviceaccount.Scope{serviceaccount.ScopeProjectsRead}, expiresAt, event.Actor{})
+	assert.Nil(t, err)
+
+	k, err := a.Key(keyID)
+	assert.Nil(t, err)
+	assert.Equal(t, "hash", k.Hash())
+	assert.True(t, k.HasScope(serviceaccount.ScopeProjectsRead))
+	assert.False(t, k.HasScope(serviceaccount.ScopeProjectsWrite))
+	assert.Nil(t, k.Validate(time.Now()))
+	assert.Equal(t, serviceaccount.ErrAPIKeyHasExpired, k.Validate(time.Now().Add(2*time.Hour)))
+}
+
+func TestServiceAccount_IssueKey_InvalidInput(t *testing.T) {
+	a := newServiceAccount()
+	keyID, _ := valueobject.NewIdentifier()
+
+	err := a.IssueKey(keyID, "hash", nil, valueobject.Timestamp{}, event.Actor{})
+	assert.Equal(t, serviceaccount.ErrNoScopesProvided, err)
+
+	err = a.IssueKey(keyID, "hash", []serviceaccount.Scope{"projects:everything"}, valueobject.Timestamp{}, event.Actor{})
+	assert.Equal(t, serviceaccount.ErrInvalidScope, err)
+
+	past := valueobject.NewTimestampFromUnix(time.Now().Add(-time.Hour).Unix())
+	err = a.IssueKey(keyID, "hash", []serviceaccount.Scope{serviceaccount.ScopeProjectsRead}, past, event.Actor{})
+	assert.Equal(t, serviceaccount.ErrInvalidExpiry, err)
+}
+
+func TestServiceAccount_RevokeKey(t *testing.T) {
+	a := newServiceAccount()
+	keyID, _ := valueobject.NewIdentifier()
+	a.IssueKey(keyID, "hash", []serviceaccount.Scope{serviceaccount.ScopeProjectsWrite}, valueobject.Timestamp{}, event.Actor{})
+
+	err := a.RevokeKey(keyID, event.Actor{})
+	assert.Nil(t, err)
+
+	k, _ := a.Key(keyID)
+	assert.False(t, k.RevokedAt().Time().IsZero())
+	assert.Equal(t, serviceaccount.ErrAPIKeyHasBeenRevoked, k.Validate(time.Now()))
+
+	// revoking twice is an error
+	assert.Equal(t, serviceaccount.ErrAPIKeyHasBeenRevoked, a.RevokeKey(keyID, event.Actor{}))
+
+	// unknown keys cannot be revoked
+	unknownID, _ := valueobject.NewIdentifier()
+	assert.Equal(t, serviceaccount.ErrAPIKeyNotFound, a.RevokeKey(unknownID, event.Actor{}))
+}
+
+func TestServiceAccount_RecordKeyUsage(t *testing.T) {
+	a := newServiceAccount()
+	keyID, _ := valueobject.NewIdentifier()
+	a.IssueKey(keyID, "hash", []serviceaccount.Scope{serviceaccount.ScopeProjectsRead}, valueobject.Timestamp{}, event.Actor{})
+
+	err := a.RecordKeyUsage(keyID)
+	assert.Nil(t, err)
+
+	k, _ := a.Key(keyID)
+	assert.False(t, k.LastUsedAt().Time().IsZero())
+}
+
+func TestServiceAccount_DeleteServiceAccount(t *testing.T) {
+	a := newServiceAccount()
+
+	err := a.DeleteServiceAccount(event.Actor{})
+	assert.Nil(t, err)
+	assert.False(t, a.DeletedAt().Time().IsZero())
+
+	// no keys can be issued for deleted service accounts
+	keyID, _ := valueobject.NewIdentifier()
+	err = a.IssueKey(keyID, "hash", []serviceaccount.Scope{serviceaccount.ScopeProjectsRead}, valueobject.Timestamp{}, event.Actor{})
+	assert.Equal(t, serviceaccount.ErrServiceAccountHasBeenDeleted, err)
+}
+
+func TestServiceAccount_NewAggregateFromEvents(t *testing.T) {
+	a := newServiceAccount()
+	keyID, _ := valueobject.NewIdentifier()
+	a.IssueKey(keyID, "hash", []serviceaccount.Scope{serviceaccount.ScopeProjectsRead}, valueobject.Timestamp{}, event.Actor{})
+
+	r := serviceaccount.NewAggregateFromEvents(a.Events())
+	assert.Equal(t, a.ID(), r.ID())
+	assert.Equal(t, a.Name(), r.Name())
+	assert.Len(t, r.Keys(), 1)
+	assert.Equal(t, 2, r.Version())
+}
+
+func newServiceAccount() *serviceaccount.Aggregate {
+	id, _ := valueobject.NewIdentifier()
+	name, _ := valueobject.NewLongName("ingest pipeline")
+	desc, _ := valueobject.NewDescription("reads project data during ingest")
+	return serviceaccount.NewAggregate(id, name, desc, event.Actor{})
+}
@@ -0,0 +1,285 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package webhook
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// DeliveryStatus is the state of a delivery.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries are attempted until they succeed or are dead-lettered.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered deliveries were acknowledged by the receiver with a 2xx status code.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDeadLettered deliveries are no longer attempted unless a redelivery is requested.
+	DeliveryDeadLettered DeliveryStatus = "dead_lettered"
+)
+
+// ParseDeliveryStatus returns the delivery status corresponding to the provided string.
+func ParseDeliveryStatus(s string) (DeliveryStatus, error) {
+	switch st := DeliveryStatus(s); st {
+	case DeliveryPending, DeliveryDelivered, DeliveryDeadLettered:
+		return st, nil
+	default:
+		return "", ErrInvalidDeliveryStatus
+	}
+}
+
+//Delivery aggregate, an entry of the webhook outbox
+type Delivery struct {
+	id             valueobject.Identifier
+	aggregateType  valueobject.AggregateType
+	webhookID      valueobject.Identifier
+	eventType      EventType
+	sourceEventID  string
+	payload        json.RawMessage
+	scheduledAt    valueobject.Timestamp
+	status         DeliveryStatus
+	attempts       int
+	lastStatusCode int
+	lastError      string
+	lastAttemptAt  valueobject.Timestamp
+	nextAttemptAt  valueobject.Timestamp
+	deadLetteredAt valueobject.Timestamp
+
+	changes []event.Event
+	version int
+}
+
+// ID returns the delivery's id.
+func (d Delivery) ID() valueobject.Identifier {
+	return d.id
+}
+
+// AggregateType returns the aggregate's type.
+func (d Delivery) AggregateType() valueobject.AggregateType {
+	return d.aggregateType
+}
+
+// WebhookID returns the id of the webhook the delivery is sent to.
+func (d Delivery) WebhookID() valueobject.Identifier {
+	return d.webhookID
+}
+
+// EventType returns the type of the event the delivery notifies about.
+func (d Delivery) EventType() EventType {
+	return d.eventType
+}
+
+// SourceEventID returns the id of the project event the delivery notifies about.
+func (d Delivery) SourceEventID() string {
+	return d.sourceEventID
+}
+
+// Payload returns the body which is posted to the webhook.
+func (d Delivery) Payload() json.RawMessage {
+	return d.payload
+}
+
+// ScheduledAt returns the time the delivery was scheduled.
+func (d Delivery) ScheduledAt() valueobject.Timestamp {
+	return d.scheduledAt
+}
+
+// Status returns the delivery's status.
+func (d Delivery) Status() DeliveryStatus {
+	return d.status
+}
+
+// Attempts returns the number of attempts since the delivery was scheduled or its redelivery requested.
+func (d Delivery) Attempts() int {
+	return d.attempts
+}
+
+// LastStatusCode returns the status code of the last attempt, 0 if no response was received.
+func (d Delivery) LastStatusCode() int {
+	return d.lastStatusCode
+}
+
+// LastError returns the reason the last attempt failed.
+func (d Delivery) LastError() string {
+	return d.lastError
+}
+
+// LastAttemptAt returns the time of the last attempt.
+func (d Delivery) LastAttemptAt() valueobject.Timestamp {
+	return d.lastAttemptAt
+}
+
+// NextAttemptAt returns the time after which a pending delivery is attempted next.
+func (d Delivery) NextAttemptAt() valueobject.Timestamp {
+	return d.nextAttemptAt
+}
+
+// DeadLetteredAt returns the time the delivery was dead-lettered.
+func (d Delivery) DeadLetteredAt() valueobject.Timestamp {
+	return d.deadLetteredAt
+}
+
+// IsDue returns true if the delivery is pending and its next attempt is not after the provided time.
+func (d Delivery) IsDue(now time.Time) bool {
+	return d.status == DeliveryPending && !d.nextAttemptAt.Time().After(now)
+}
+
+// NewDeliveryFromEvents is a helper method that creates a new delivery
+// from a series of events.
+func NewDeliveryFromEvents(events []event.Event) *Delivery {
+	d := &Delivery{}
+
+	for _, e := range events {
+		d.On(e, false)
+	}
+
+	return d
+}
+
+// NewDelivery schedules the delivery of the payload to the webhook.
+func NewDelivery(id valueobject.Identifier, webhookID valueobject.Identifier, eventType EventType, sourceEventID string, payload json.RawMessage) *Delivery {
+	d := &Delivery{}
+
+	d.raise(&event.WebhookDeliveryScheduled{
+		ID:            id,
+		WebhookID:     webhookID,
+		EventType:     string(eventType),
+		SourceEventID: sourceEventID,
+		Payload:       payload,
+		ScheduledAt:   valueobject.NewTimestamp(),
+	})
+
+	return d
+}
+
+// RecordSuccess records an attempt which was acknowledged by the receiver.
+func (d *Delivery) RecordSuccess(statusCode int) {
+	d.raise(&event.WebhookDeliveryAttempted{
+		ID:          d.id,
+		Succeeded:   true,
+		StatusCode:  statusCode,
+		AttemptedAt: valueobject.NewTimestamp(),
+	})
+}
+
+// RecordFailure records a failed attempt which is retried after the provided time.
+// A zero retry time is used for the last attempt before the delivery is dead-lettered.
+func (d *Delivery) RecordFailure(statusCode int, reason string, retryAt time.Time) {
+	e := &event.WebhookDeliveryAttempted{
+		ID:          d.id,
+		StatusCode:  statusCode,
+		Error:       reason,
+		AttemptedAt: valueobject.NewTimestamp(),
+	}
+	if !retryAt.IsZero() {
+		e.NextAttemptAt = valueobject.NewTimestampFromUnix(retryAt.Unix())
+	}
+	d.raise(e)
+}
+
+// DeadLetter stops attempting the delivery.
+func (d *Delivery) DeadLetter(reason string) {
+	d.raise(&event.WebhookDeliveryDeadLettered{
+		ID:             d.id,
+		Reason:         reason,
+		DeadLetteredAt: valueobject.NewTimestamp(),
+	})
+}
+
+// Redeliver requests the delivery to be attempted again, starting with a fresh attempt count.
+func (d *Delivery) Redeliver(requestedBy event.Actor) error {
+	if d.status == DeliveryPending {
+		return ErrDeliveryIsPending
+	}
+
+	d.raise(&event.WebhookDeliveryRedeliveryRequested{
+		ID:              d.id,
+		RequestedAt:     valueobject.NewTimestamp(),
+		RequestedBy:     requestedBy.ID,
+		RequestedByType: requestedBy.Type,
+	})
+
+	return nil
+}
+
+// The raise method appends the event into the changes slice and applies it to the aggregate.
+func (d *Delivery) raise(event event.Event) {
+	d.changes = append(d.changes, event)
+	d.On(event, true)
+}
+
+// On handles events on the delivery aggregate.
+func (d *Delivery) On(ev event.Event, new bool) {
+	switch e := ev.(type) {
+	case *event.WebhookDeliveryScheduled:
+		at, _ := valueobject.NewAggregateType("http://ns.dasch.swiss/admin#WebhookDelivery")
+		d.aggregateType = at
+		d.id = e.ID
+		d.webhookID = e.WebhookID
+		d.eventType = EventType(e.EventType)
+		d.sourceEventID = e.SourceEventID
+		d.payload = e.Payload
+		d.scheduledAt = e.ScheduledAt
+		d.status = DeliveryPending
+		d.nextAttemptAt = e.ScheduledAt
+
+	case *event.WebhookDeliveryAttempted:
+		d.attempts++
+		d.lastStatusCode = e.StatusCode
+		d.lastError = e.Error
+		d.lastAttemptAt = e.AttemptedAt
+		d.nextAttemptAt = e.NextAttemptAt
+		if e.Succeeded {
+			d.status = DeliveryDelivered
+		}
+
+	case *event.WebhookDeliveryDeadLettered:
+		d.status = DeliveryDeadLettered
+		d.lastError = e.Reason
+		d.deadLetteredAt = e.DeadLetteredAt
+		d.nextAttemptAt = valueobject.Timestamp{}
+
+	case *event.WebhookDeliveryRedeliveryRequested:
+		d.status = DeliveryPending
+		d.attempts = 0
+		d.nextAttemptAt = e.RequestedAt
+		d.deadLetteredAt = valueobject.Timestamp{}
+
+	default:
+		log.Printf("unknown event %T", e)
+	}
+
+	if !new {
+		d.version++
+	}
+}
+
+// Events returns the uncommitted events from the delivery aggregate.
+func (d Delivery) Events() []event.Event {
+	return d.changes
+}
+
+// Version returns the last version of the delivery aggregate before changes.
+func (d Delivery) Version() int {
+	return d.version
+}
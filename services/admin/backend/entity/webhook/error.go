@@ -0,0 +1,47 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package webhook
+
+import "errors"
+
+//ErrWebhookNotFound webhook not found
+var ErrWebhookNotFound = errors.New("no webhook found with the provided uuid")
+
+//ErrWebhookHasBeenDeleted webhook has been marked as deleted
+var ErrWebhookHasBeenDeleted = errors.New("webhook has been marked as deleted")
+
+//ErrInvalidURL the url is not an absolute http or https url
+var ErrInvalidURL = errors.New("url must be an absolute http or https url")
+
+//ErrInvalidEventType unknown event type
+var ErrInvalidEventType = errors.New("event type must be either project.created, project.changed or project.deleted")
+
+//ErrNoEventTypesProvided no event types provided
+var ErrNoEventTypesProvided = errors.New("at least one event type must be provided")
+
+//ErrDeliveryNotFound delivery not found
+var ErrDeliveryNotFound = errors.New("no delivery found with the provided uuid")
+
+//ErrDeliveryIsPending the delivery is still being attempted
+var ErrDeliveryIsPending = errors.New("delivery is still pending")
+
+//ErrInvalidDeliveryStatus unknown delivery status
+var ErrInvalidDeliveryStatus = errors.New("status must be either pending, delivered or dead_lettered")
+
+//ErrUserDoesNotHaveManageWebhooksPermission user does not have permission to manage webhooks
+var ErrUserDoesNotHaveManageWebhooksPermission = errors.New("user does not have permission to manage webhooks")
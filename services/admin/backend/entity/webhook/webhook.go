@@ -0,0 +1,263 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package webhook
+
+import (
+	"log"
+	"net/url"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// EventType is the type of the project events a webhook is notified about.
+type EventType string
+
+const (
+	// EventProjectCreated is sent when a project is created.
+	EventProjectCreated EventType = "project.created"
+	// EventProjectChanged is sent when any property of a project changes, e.g. when it is renamed.
+	EventProjectChanged EventType = "project.changed"
+	// EventProjectDeleted is sent when a project is deleted.
+	EventProjectDeleted EventType = "project.deleted"
+	// EventProjectRestored is sent when a deleted project is restored.
+	EventProjectRestored EventType = "project.restored"
+)
+
+// ParseEventType returns the event type corresponding to the provided string.
+func ParseEventType(s string) (EventType, error) {
+	switch t := EventType(s); t {
+	case EventProjectCreated, EventProjectChanged, EventProjectDeleted, EventProjectRestored:
+		return t, nil
+	default:
+		return "", ErrInvalidEventType
+	}
+}
+
+// EventTypeOf returns the type of the project event, false is returned for events webhooks are not notified about.
+func EventTypeOf(ev event.Event) (EventType, bool) {
+	switch ev.(type) {
+	case *event.ProjectCreated:
+		return EventProjectCreated, true
+	case *event.ProjectChanged, *event.ProjectShortCodeChanged, *event.ProjectShortNameChanged, *event.ProjectLongNameChanged, *event.ProjectDescriptionChanged:
+		return EventProjectChanged, true
+	case *event.ProjectDeleted:
+		return EventProjectDeleted, true
+	case *event.ProjectRestored:
+		return EventProjectRestored, true
+	}
+	return "", false
+}
+
+// ValidateURL returns an error if the url is not an absolute http or https url.
+func ValidateURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+//Aggregate webhook domain entity
+type Aggregate struct {
+	id            valueobject.Identifier
+	aggregateType valueobject.AggregateType
+	url           string
+	secret        string
+	eventTypes    []EventType
+	description   valueobject.Description
+	createdAt     valueobject.Timestamp
+	createdBy     event.Actor
+	deletedAt     valueobject.Timestamp
+	deletedBy     event.Actor
+
+	changes []event.Event
+	version int
+}
+
+// ID returns the webhook's id.
+func (a Aggregate) ID() valueobject.Identifier {
+	return a.id
+}
+
+// AggregateType returns the aggregate's type.
+func (a Aggregate) AggregateType() valueobject.AggregateType {
+	return a.aggregateType
+}
+
+// URL returns the url the payloads are posted to.
+func (a Aggregate) URL() string {
+	return a.url
+}
+
+// Secret returns the secret the payloads are signed with.
+func (a Aggregate) Secret() string {
+	return a.secret
+}
+
+// EventTypes returns the types of the events the webhook is notified about.
+func (a Aggregate) EventTypes() []EventType {
+	return a.eventTypes
+}
+
+// Description returns the webhook's description.
+func (a Aggregate) Description() valueobject.Description {
+	return a.description
+}
+
+// CreatedAt returns the webhook's creation time.
+func (a Aggregate) CreatedAt() valueobject.Timestamp {
+	return a.createdAt
+}
+
+// CreatedBy returns the webhook's creator identifier.
+func (a Aggregate) CreatedBy() valueobject.Identifier {
+	return a.createdBy.ID
+}
+
+// Creator returns the user or service account which created the webhook.
+func (a Aggregate) Creator() event.Actor {
+	return a.createdBy
+}
+
+// DeletedAt returns the webhook's deletion time.
+func (a Aggregate) DeletedAt() valueobject.Timestamp {
+	return a.deletedAt
+}
+
+// DeletedBy returns the identifier of the user who deleted the webhook.
+func (a Aggregate) DeletedBy() valueobject.Identifier {
+	return a.deletedBy.ID
+}
+
+// Deleter returns the user or service account which deleted the webhook.
+func (a Aggregate) Deleter() event.Actor {
+	return a.deletedBy
+}
+
+// Subscribes returns true if the webhook is active and notified about events of the provided type.
+func (a Aggregate) Subscribes(t EventType) bool {
+	if !a.deletedAt.Time().IsZero() {
+		return false
+	}
+	for _, et := range a.eventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAggregateFromEvents is a helper method that creates a new webhook
+// from a series of events.
+func NewAggregateFromEvents(events []event.Event) *Aggregate {
+	a := &Aggregate{}
+
+	for _, e := range events {
+		a.On(e, false)
+	}
+
+	return a
+}
+
+// NewAggregate creates a new webhook entity.
+// The url and the event types are expected to be valid, see ValidateURL and ParseEventType.
+func NewAggregate(id valueobject.Identifier, u string, secret string, eventTypes []EventType, description valueobject.Description, createdBy event.Actor) *Aggregate {
+	a := &Aggregate{}
+
+	var types []string
+	for _, t := range eventTypes {
+		types = append(types, string(t))
+	}
+
+	a.raise(&event.WebhookCreated{
+		ID:            id,
+		URL:           u,
+		Secret:        secret,
+		EventTypes:    types,
+		Description:   description,
+		CreatedAt:     valueobject.NewTimestamp(),
+		CreatedBy:     createdBy.ID,
+		CreatedByType: createdBy.Type,
+	})
+
+	return a
+}
+
+// DeleteWebhook deletes the webhook, which stops all deliveries to it.
+func (a *Aggregate) DeleteWebhook(deletedBy event.Actor) error {
+	if !a.deletedAt.Time().IsZero() {
+		return ErrWebhookHasBeenDeleted
+	}
+
+	a.raise(&event.WebhookDeleted{
+		ID:            a.id,
+		DeletedAt:     valueobject.NewTimestamp(),
+		DeletedBy:     deletedBy.ID,
+		DeletedByType: deletedBy.Type,
+	})
+
+	return nil
+}
+
+// The raise method appends the event into the changes slice and applies it to the aggregate.
+func (a *Aggregate) raise(event event.Event) {
+	a.changes = append(a.changes, event)
+	a.On(event, true)
+}
+
+// On handles events on the webhook aggregate.
+// See project.Aggregate.On for the reasoning behind never returning an error here.
+func (a *Aggregate) On(ev event.Event, new bool) {
+	switch e := ev.(type) {
+	case *event.WebhookCreated:
+		at, _ := valueobject.NewAggregateType("http://ns.dasch.swiss/admin#Webhook")
+		a.aggregateType = at
+		a.id = e.ID
+		a.url = e.URL
+		a.secret = e.Secret
+		a.eventTypes = nil
+		for _, t := range e.EventTypes {
+			a.eventTypes = append(a.eventTypes, EventType(t))
+		}
+		a.description = e.Description
+		a.createdAt = e.CreatedAt
+		a.createdBy = event.Actor{ID: e.CreatedBy, Type: e.CreatedByType}
+
+	case *event.WebhookDeleted:
+		a.deletedAt = e.DeletedAt
+		a.deletedBy = event.Actor{ID: e.DeletedBy, Type: e.DeletedByType}
+
+	default:
+		log.Printf("unknown event %T", e)
+	}
+
+	if !new {
+		a.version++
+	}
+}
+
+// Events returns the uncommitted events from the webhook aggregate.
+func (a Aggregate) Events() []event.Event {
+	return a.changes
+}
+
+// Version returns the last version of the webhook aggregate before changes.
+func (a Aggregate) Version() int {
+	return a.version
+}
@@ -0,0 +1,168 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package webhook_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_NewAggregate(t *testing.T) {
+	expectedId, _ := valueobject.NewIdentifier()
+	expectedAggregateType, _ := valueobject.NewAggregateType("http://ns.dasch.swiss/admin#Webhook")
+	expectedDescription, _ := valueobject.NewDescription("notifies the archive")
+	expectedCreatedBy, _ := valueobject.NewIdentifier()
+	expectedEventTypes := []webhook.EventType{webhook.EventProjectCreated, webhook.EventProjectDeleted}
+
+	a := webhook.NewAggregate(expectedId, "https://example.com/hook", "secret", expectedEventTypes, expectedDescription, event.Actor{ID: expectedCreatedBy, Type: event.ActorUser})
+	assert.Equal(t, expectedId, a.ID())
+	assert.Equal(t, expectedAggregateType, a.AggregateType())
+	assert.Equal(t, "https://example.com/hook", a.URL())
+	assert.Equal(t, "secret", a.Secret())
+	assert.Equal(t, expectedEventTypes, a.EventTypes())
+	assert.Equal(t, expectedDescription, a.Description())
+	assert.Equal(t, expectedCreatedBy, a.CreatedBy())
+	assert.Equal(t, event.ActorUser, a.Creator().Type)
+	assert.False(t, a.CreatedAt().Time().IsZero())
+
+	assert.True(t, a.Subscribes(webhook.EventProjectCreated))
+	assert.False(t, a.Subscribes(webhook.EventProjectChanged))
+
+	switch e := a.Events()[0].(type) {
+	case *event.WebhookCreated:
+		assert.Equal(t, expectedId, e.ID)
+		assert.Equal(t, []string{"project.created", "project.deleted"}, e.EventTypes)
+	default:
+		t.Fatalf("unexpected event type: %T", e)
+	}
+}
+
+func TestWebhook_DeleteWebhook(t *testing.T) {
+	a := newWebhook()
+	id, _ := valueobject.NewIdentifier()
+	deletedBy := event.Actor{ID: id, Type: event.ActorServiceAccount}
+
+	assert.Nil(t, a.DeleteWebhook(deletedBy))
+	assert.False(t, a.DeletedAt().Time().IsZero())
+	assert.Equal(t, id, a.DeletedBy())
+	assert.Equal(t, deletedBy, a.Deleter())
+	assert.False(t, a.Subscribes(webhook.EventProjectCreated))
+
+	assert.Equal(t, webhook.ErrWebhookHasBeenDeleted, a.DeleteWebhook(deletedBy))
+}
+
+func TestWebhook_NewAggregateFromEvents(t *testing.T) {
+	a := newWebhook()
+	_ = a.DeleteWebhook(event.Actor{})
+
+	b := webhook.NewAggregateFromEvents(a.Events())
+	assert.Equal(t, a.ID(), b.ID())
+	assert.Equal(t, a.URL(), b.URL())
+	assert.Equal(t, a.EventTypes(), b.EventTypes())
+	assert.Equal(t, a.DeletedAt(), b.DeletedAt())
+	assert.Equal(t, 2, b.Version())
+	assert.Empty(t, b.Events())
+}
+
+func TestParseEventType(t *testing.T) {
+	et, err := webhook.ParseEventType("project.changed")
+	assert.Nil(t, err)
+	assert.Equal(t, webhook.EventProjectChanged, et)
+
+	_, err = webhook.ParseEventType("user.created")
+	assert.Equal(t, webhook.ErrInvalidEventType, err)
+}
+
+func TestEventTypeOf(t *testing.T) {
+	et, ok := webhook.EventTypeOf(&event.ProjectLongNameChanged{})
+	assert.True(t, ok)
+	assert.Equal(t, webhook.EventProjectChanged, et)
+
+	_, ok = webhook.EventTypeOf(&event.WebhookCreated{})
+	assert.False(t, ok)
+}
+
+func TestValidateURL(t *testing.T) {
+	assert.Nil(t, webhook.ValidateURL("https://example.com/hook"))
+	assert.Nil(t, webhook.ValidateURL("http://localhost:8080"))
+	assert.Equal(t, webhook.ErrInvalidURL, webhook.ValidateURL("ftp://example.com"))
+	assert.Equal(t, webhook.ErrInvalidURL, webhook.ValidateURL("/relative"))
+	assert.Equal(t, webhook.ErrInvalidURL, webhook.ValidateURL("::"))
+}
+
+func TestDelivery_Lifecycle(t *testing.T) {
+	d := newDelivery()
+	assert.Equal(t, webhook.DeliveryPending, d.Status())
+	assert.True(t, d.IsDue(time.Now()))
+
+	retryAt := time.Now().Add(time.Minute)
+	d.RecordFailure(500, "unexpected status code 500", retryAt)
+	assert.Equal(t, webhook.DeliveryPending, d.Status())
+	assert.Equal(t, 1, d.Attempts())
+	assert.Equal(t, 500, d.LastStatusCode())
+	assert.False(t, d.IsDue(time.Now()))
+	assert.True(t, d.IsDue(retryAt.Add(time.Second)))
+
+	assert.Equal(t, webhook.ErrDeliveryIsPending, d.Redeliver(event.Actor{}))
+
+	d.DeadLetter("gave up after 1 attempts")
+	assert.Equal(t, webhook.DeliveryDeadLettered, d.Status())
+	assert.False(t, d.IsDue(retryAt.Add(time.Second)))
+	assert.False(t, d.DeadLetteredAt().Time().IsZero())
+
+	assert.Nil(t, d.Redeliver(event.Actor{}))
+	assert.Equal(t, webhook.DeliveryPending, d.Status())
+	assert.Equal(t, 0, d.Attempts())
+	assert.True(t, d.IsDue(time.Now()))
+
+	d.RecordSuccess(204)
+	assert.Equal(t, webhook.DeliveryDelivered, d.Status())
+	assert.False(t, d.IsDue(time.Now()))
+
+	r := webhook.NewDeliveryFromEvents(d.Events())
+	assert.Equal(t, d.Status(), r.Status())
+	assert.Equal(t, d.Attempts(), r.Attempts())
+	assert.Equal(t, d.Payload(), r.Payload())
+	assert.Equal(t, 5, r.Version())
+}
+
+func TestParseDeliveryStatus(t *testing.T) {
+	st, err := webhook.ParseDeliveryStatus("dead_lettered")
+	assert.Nil(t, err)
+	assert.Equal(t, webhook.DeliveryDeadLettered, st)
+
+	_, err = webhook.ParseDeliveryStatus("failed")
+	assert.Equal(t, webhook.ErrInvalidDeliveryStatus, err)
+}
+
+func newWebhook() *webhook.Aggregate {
+	id, _ := valueobject.NewIdentifier()
+	description, _ := valueobject.NewDescription("notifies the archive")
+	return webhook.NewAggregate(id, "https://example.com/hook", "secret", []webhook.EventType{webhook.EventProjectCreated}, description, event.Actor{})
+}
+
+func newDelivery() *webhook.Delivery {
+	id, _ := valueobject.NewIdentifier()
+	webhookID, _ := valueobject.NewIdentifier()
+	return webhook.NewDelivery(id, webhookID, webhook.EventProjectCreated, "C:1/P:1", []byte(`{"type":"project.created"}`))
+}
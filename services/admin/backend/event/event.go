@@ -16,6 +16,29 @@
 
 package event
 
+import "github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+
+// RedactedActor replaces the actor of an event once the personal data of the actor has been erased.
+var RedactedActor, _ = valueobject.IdentifierFromBytes([]byte("ffffffff-ffff-ffff-ffff-ffffffffffff"))
+
+// ActorType is the kind of principal which made a change. It is recorded next to the id of the actor,
+// and is empty for changes recorded without a principal or before the type was recorded.
+// Unlike the id, it does not identify a person, so it is not encrypted.
+type ActorType string
+
+const (
+	// ActorUser is a person authenticated with an access token.
+	ActorUser ActorType = "user"
+	// ActorServiceAccount is a service account authenticated with an api key.
+	ActorServiceAccount ActorType = "serviceAccount"
+)
+
+// Actor is the user or service account which made a change. The zero value is no actor.
+type Actor struct {
+	ID   valueobject.Identifier
+	Type ActorType
+}
+
 // Event is a domain event marker.
 type Event interface {
 	isEvent()
@@ -0,0 +1,284 @@
+/*
+ * Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package integrity makes the event log tamper-evident.
+//
+// Every event is stored with a Link holding the hash of the event and the hash of the event before it in the same stream,
+// so that changing, removing or reordering events of a stream breaks its chain. A Checkpoint records a hash over all events
+// of the log up to a point in time, which also covers the latest events of every stream. Signed checkpoints show that the
+// log has not been altered since they were made, even by someone able to recompute the hashes.
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidSigningKey is returned if a signing key is not the base64 encoded seed of an Ed25519 key.
+var ErrInvalidSigningKey = errors.New("the signing key must be a base64 encoded Ed25519 seed of 32 bytes")
+
+// Link is the part of the metadata of an event which chains it to the event before it in its stream.
+type Link struct {
+	Hash string `json:"hash"`
+	// PreviousHash is the hash of the previous event of the stream, or empty if the event starts the chain.
+	PreviousHash string `json:"previousHash"`
+}
+
+// NewLink returns the link of an event which follows the event with the previous hash.
+func NewLink(previousHash string, stream string, id string, eventType string, data []byte) Link {
+	return Link{Hash: Hash(previousHash, stream, id, eventType, data), PreviousHash: previousHash}
+}
+
+// Hash returns the hex encoded SHA-256 hash of an event, chained to the hash of the previous event of its stream.
+// The fields are length-prefixed, so that no two different events have the same input.
+func Hash(previousHash string, stream string, id string, eventType string, data []byte) string {
+	h := sha256.New()
+	for _, f := range [][]byte{[]byte(previousHash), []byte(stream), []byte(id), []byte(eventType), data} {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(f)))
+		h.Write(n[:])
+		h.Write(f)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Record is a stored event to be verified.
+type Record struct {
+	Stream   string
+	Revision uint64
+	ID       string
+	Type     string
+	Data     []byte
+	// Link is nil if the event was stored without one, e.g. before the chain was introduced.
+	Link *Link
+}
+
+// Break is an event which does not match the chain of its stream.
+type Break struct {
+	Stream   string `json:"stream"`
+	Revision uint64 `json:"revision"`
+	EventID  string `json:"eventId"`
+	Reason   string `json:"reason"`
+}
+
+// Checkpoint records the hash over all events of the log, in the order they were stored, up to a point in time.
+type Checkpoint struct {
+	// Events is the number of events covered by the checkpoint.
+	Events    int       `json:"events"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Signature is the base64 encoded Ed25519 signature of the checkpoint, or empty if it was not signed.
+	Signature string `json:"signature,omitempty"`
+}
+
+// message returns the signed content of the checkpoint.
+func (c Checkpoint) message() []byte {
+	return []byte(strconv.Itoa(c.Events) + "\n" + c.Hash + "\n" + c.CreatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// Sign signs the checkpoint with the private key.
+func (c *Checkpoint) Sign(key ed25519.PrivateKey) {
+	c.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, c.message()))
+}
+
+// VerifySignature returns true if the checkpoint was signed with the private key of the public key.
+func (c Checkpoint) VerifySignature(key ed25519.PublicKey) bool {
+	sig, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, c.message(), sig)
+}
+
+// ParseSigningKey returns the Ed25519 private key of a base64 encoded seed.
+func ParseSigningKey(s string) (ed25519.PrivateKey, error) {
+	seed, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidSigningKey
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ParsePublicKey returns the Ed25519 public key of its base64 encoding.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("the public key must be a base64 encoded Ed25519 key of %d bytes", ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// The statuses of a verified checkpoint.
+const (
+	// CheckpointOK means that the events match the checkpoint and that its signature, if any, is valid.
+	CheckpointOK = "ok"
+	// CheckpointUnverified means that the events match the checkpoint, but it is signed and no public key was provided.
+	CheckpointUnverified = "unverified"
+	// CheckpointUnsigned means that the events match the checkpoint, but it is not signed although a public key was provided.
+	CheckpointUnsigned = "unsigned"
+	// CheckpointMismatch means that the events covered by the checkpoint have been altered.
+	CheckpointMismatch = "mismatch"
+	// CheckpointMissingEvents means that the log has fewer events than covered by the checkpoint.
+	CheckpointMissingEvents = "missing_events"
+	// CheckpointInvalidSignature means that the checkpoint itself has been altered, or was signed with another key.
+	CheckpointInvalidSignature = "invalid_signature"
+)
+
+// CheckpointResult is the outcome of verifying a checkpoint.
+type CheckpointResult struct {
+	Checkpoint Checkpoint `json:"checkpoint"`
+	Status     string     `json:"status"`
+}
+
+// Report is the outcome of verifying the event log, or the stream of a single aggregate.
+type Report struct {
+	// Status is ok if neither breaks nor failed checkpoints have been found, and broken otherwise.
+	Status  string `json:"status"`
+	Streams int    `json:"streams"`
+	Events  int    `json:"events"`
+	// Unchained is the number of events stored without a link, which can only be verified by a checkpoint.
+	Unchained   int                `json:"unchained"`
+	Breaks      []Break            `json:"breaks"`
+	Checkpoints []CheckpointResult `json:"checkpoints,omitempty"`
+}
+
+// Verifier walks the events of the log in the order they were stored, and reports every break of a chain.
+type Verifier struct {
+	key         ed25519.PublicKey
+	checkpoints []Checkpoint
+	results     []CheckpointResult
+	// last is the hash of the last event of each stream, empty if that event has no link.
+	last   map[string]string
+	global []byte
+	report Report
+}
+
+// NewVerifier creates a verifier which also verifies the checkpoints, with the public key if one is provided.
+func NewVerifier(checkpoints []Checkpoint, key ed25519.PublicKey) *Verifier {
+	sorted := append([]Checkpoint{}, checkpoints...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Events < sorted[j].Events })
+
+	v := &Verifier{
+		key:         key,
+		checkpoints: sorted,
+		last:        map[string]string{},
+		report:      Report{Breaks: []Break{}},
+	}
+	v.verifyCheckpoints()
+	return v
+}
+
+// Add verifies the next event of the log.
+func (v *Verifier) Add(r Record) {
+	previous, seen := v.last[r.Stream]
+	hash := ""
+
+	switch {
+	case r.Link == nil && previous != "":
+		v.broken(r, "the event has no hash, but the previous event of the stream has one")
+	case r.Link == nil:
+		v.report.Unchained++
+	case r.Link.PreviousHash != previous:
+		v.broken(r, "the previous hash does not match the previous event of the stream")
+		hash = r.Link.Hash
+	case r.Link.Hash != Hash(r.Link.PreviousHash, r.Stream, r.ID, r.Type, r.Data):
+		v.broken(r, "the hash does not match the event")
+		hash = r.Link.Hash
+	default:
+		hash = r.Link.Hash
+	}
+
+	if !seen {
+		v.report.Streams++
+	}
+	v.last[r.Stream] = hash
+
+	// unchained events are covered by the checkpoints with a hash of their own
+	if hash == "" {
+		hash = Hash("", r.Stream, r.ID, r.Type, r.Data)
+	}
+	sum := sha256.Sum256(append(v.global, hash...))
+	v.global = sum[:]
+	v.report.Events++
+
+	v.verifyCheckpoints()
+}
+
+func (v *Verifier) broken(r Record, reason string) {
+	v.report.Breaks = append(v.report.Breaks, Break{Stream: r.Stream, Revision: r.Revision, EventID: r.ID, Reason: reason})
+}
+
+// verifyCheckpoints verifies the checkpoints covering the events added so far.
+func (v *Verifier) verifyCheckpoints() {
+	for len(v.checkpoints) > 0 && v.checkpoints[0].Events <= v.report.Events {
+		v.verifyCheckpoint(v.checkpoints[0])
+		v.checkpoints = v.checkpoints[1:]
+	}
+}
+
+func (v *Verifier) verifyCheckpoint(c Checkpoint) {
+	res := CheckpointResult{Checkpoint: c, Status: CheckpointOK}
+	switch {
+	case c.Signature != "" && v.key != nil && !c.VerifySignature(v.key):
+		res.Status = CheckpointInvalidSignature
+	case c.Events != v.report.Events || c.Hash != hex.EncodeToString(v.global):
+		res.Status = CheckpointMismatch
+	case c.Signature != "" && v.key == nil:
+		res.Status = CheckpointUnverified
+	case c.Signature == "" && v.key != nil:
+		res.Status = CheckpointUnsigned
+	}
+	v.results = append(v.results, res)
+}
+
+// Checkpoint returns an unsigned checkpoint over the events added so far.
+func (v *Verifier) Checkpoint() Checkpoint {
+	return Checkpoint{Events: v.report.Events, Hash: hex.EncodeToString(v.global), CreatedAt: time.Now().UTC()}
+}
+
+// Report returns the outcome of the verification of the events added so far.
+// Checkpoints covering more events than were added are reported as missing events.
+func (v *Verifier) Report() Report {
+	res := v.report
+	res.Breaks = append([]Break{}, v.report.Breaks...)
+	res.Checkpoints = append([]CheckpointResult{}, v.results...)
+	for _, c := range v.checkpoints {
+		res.Checkpoints = append(res.Checkpoints, CheckpointResult{Checkpoint: c, Status: CheckpointMissingEvents})
+	}
+
+	res.Status = "ok"
+	if len(res.Breaks) > 0 {
+		res.Status = "broken"
+	}
+	for _, c := range res.Checkpoints {
+		if c.Status != CheckpointOK && c.Status != CheckpointUnverified && c.Status != CheckpointUnsigned {
+			res.Status = "broken"
+		}
+	}
+	if len(res.Checkpoints) == 0 {
+		res.Checkpoints = nil
+	}
+
+	return res
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package integrity_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/stretchr/testify/assert"
+)
+
+// chained returns the records of the events of a stream, chained the same way they are stored.
+func chained(stream string, types ...string) []integrity.Record {
+	var records []integrity.Record
+	previous := ""
+	for i, t := range types {
+		id := stream + "-" + t
+		data := []byte(`{"n":` + string(rune('0'+i)) + `}`)
+		link := integrity.NewLink(previous, stream, id, t, data)
+		records = append(records, integrity.Record{Stream: stream, Revision: uint64(i), ID: id, Type: t, Data: data, Link: &link})
+		previous = link.Hash
+	}
+	return records
+}
+
+func verify(checkpoints []integrity.Checkpoint, key ed25519.PublicKey, records ...integrity.Record) integrity.Report {
+	v := integrity.NewVerifier(checkpoints, key)
+	for _, r := range records {
+		v.Add(r)
+	}
+	return v.Report()
+}
+
+func TestVerifier_Chain(t *testing.T) {
+	a := chained("Project-a", "ProjectCreated", "ProjectChanged", "ProjectDeleted")
+	b := chained("Project-b", "ProjectCreated")
+
+	report := verify(nil, nil, a[0], b[0], a[1], a[2])
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, 2, report.Streams)
+	assert.Equal(t, 4, report.Events)
+	assert.Empty(t, report.Breaks)
+
+	// changed data
+	changed := a[1]
+	changed.Data = []byte(`{"n":9}`)
+	report = verify(nil, nil, a[0], changed, a[2])
+	assert.Equal(t, "broken", report.Status)
+	assert.Equal(t, []integrity.Break{{Stream: "Project-a", Revision: 1, EventID: a[1].ID, Reason: "the hash does not match the event"}}, report.Breaks)
+
+	// removed event
+	report = verify(nil, nil, a[0], a[2])
+	assert.Len(t, report.Breaks, 1)
+	assert.Equal(t, a[2].ID, report.Breaks[0].EventID)
+
+	// removed link
+	unlinked := a[1]
+	unlinked.Link = nil
+	report = verify(nil, nil, a[0], unlinked, a[2])
+	assert.Len(t, report.Breaks, 2)
+}
+
+func TestVerifier_Unchained(t *testing.T) {
+	// events stored before the chain was introduced are followed by a new chain
+	legacy := integrity.Record{Stream: "Project-a", ID: "1", Type: "ProjectCreated", Data: []byte(`{}`)}
+	link := integrity.NewLink("", "Project-a", "2", "ProjectChanged", []byte(`{}`))
+	next := integrity.Record{Stream: "Project-a", Revision: 1, ID: "2", Type: "ProjectChanged", Data: []byte(`{}`), Link: &link}
+
+	report := verify(nil, nil, legacy, next)
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, 1, report.Unchained)
+}
+
+func TestVerifier_Checkpoints(t *testing.T) {
+	seed, _ := base64.StdEncoding.DecodeString("nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A=")
+	key, err := integrity.ParseSigningKey(base64.StdEncoding.EncodeToString(seed))
+	assert.Nil(t, err)
+	public := key.Public().(ed25519.PublicKey)
+
+	records := chained("Project-a", "ProjectCreated", "ProjectChanged", "ProjectDeleted")
+
+	v := integrity.NewVerifier(nil, nil)
+	v.Add(records[0])
+	v.Add(records[1])
+	checkpoint := v.Checkpoint()
+	checkpoint.Sign(key)
+	assert.Equal(t, 2, checkpoint.Events)
+	assert.True(t, checkpoint.VerifySignature(public))
+
+	report := verify([]integrity.Checkpoint{checkpoint}, public, records...)
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, integrity.CheckpointOK, report.Checkpoints[0].Status)
+
+	// without a public key, the signature cannot be verified
+	report = verify([]integrity.Checkpoint{checkpoint}, nil, records...)
+	assert.Equal(t, integrity.CheckpointUnverified, report.Checkpoints[0].Status)
+
+	// the last event of a stream is not covered by a chain, but by the checkpoints
+	report = verify([]integrity.Checkpoint{checkpoint}, public, records[0])
+	assert.Equal(t, "broken", report.Status)
+	assert.Empty(t, report.Breaks)
+	assert.Equal(t, integrity.CheckpointMissingEvents, report.Checkpoints[0].Status)
+
+	// recomputed hashes do not match the signed checkpoint
+	changed := chained("Project-a", "ProjectCreated", "ProjectDeleted")
+	report = verify([]integrity.Checkpoint{checkpoint}, public, changed...)
+	assert.Empty(t, report.Breaks)
+	assert.Equal(t, integrity.CheckpointMismatch, report.Checkpoints[0].Status)
+
+	forged := checkpoint
+	forged.CreatedAt = forged.CreatedAt.Add(time.Hour)
+	report = verify([]integrity.Checkpoint{forged}, public, records...)
+	assert.Equal(t, integrity.CheckpointInvalidSignature, report.Checkpoints[0].Status)
+}
+
+func TestParseSigningKey(t *testing.T) {
+	_, err := integrity.ParseSigningKey("c2hvcnQ=")
+	assert.Equal(t, integrity.ErrInvalidSigningKey, err)
+
+	_, err = integrity.ParsePublicKey("not base64")
+	assert.NotNil(t, err)
+}
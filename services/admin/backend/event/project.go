@@ -29,64 +29,83 @@ func (e ProjectShortCodeChanged) isEvent()   {}
 func (e ProjectShortNameChanged) isEvent()   {}
 func (e ProjectLongNameChanged) isEvent()    {}
 func (e ProjectDescriptionChanged) isEvent() {}
+func (e ProjectRestored) isEvent()           {}
 
 // ProjectCreated event
 type ProjectCreated struct {
-	ID          valueobject.Identifier  `json:"id"`
-	ShortCode   valueobject.ShortCode   `json:"shortCode"`
-	ShortName   valueobject.ShortName   `json:"shortName"`
-	LongName    valueobject.LongName    `json:"longName"`
-	Description valueobject.Description `json:"description"`
-	CreatedAt   valueobject.Timestamp   `json:"createdAt"`
-	CreatedBy   valueobject.Identifier  `json:"createdBy"`
+	ID            valueobject.Identifier  `json:"id"`
+	ShortCode     valueobject.ShortCode   `json:"shortCode"`
+	ShortName     valueobject.ShortName   `json:"shortName"`
+	LongName      valueobject.LongName    `json:"longName"`
+	Description   valueobject.Description `json:"description"`
+	CreatedAt     valueobject.Timestamp   `json:"createdAt"`
+	CreatedBy     valueobject.Identifier  `json:"createdBy"`
+	CreatedByType ActorType               `json:"createdByType,omitempty"`
+	// IRI and Keywords are only set for projects migrated from DSP-API.
+	IRI      string   `json:"iri,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
 }
 
 // ProjectChanged event
 type ProjectChanged struct {
-	ID          valueobject.Identifier  `json:"id"`
-	ShortCode   valueobject.ShortCode   `json:"shortCode"`
-	ShortName   valueobject.ShortName   `json:"shortName"`
-	LongName    valueobject.LongName    `json:"longName"`
-	Description valueobject.Description `json:"description"`
-	ChangedAt   valueobject.Timestamp   `json:"changedAt"`
-	ChangedBy   valueobject.Identifier  `json:"changedBy"`
+	ID            valueobject.Identifier  `json:"id"`
+	ShortCode     valueobject.ShortCode   `json:"shortCode"`
+	ShortName     valueobject.ShortName   `json:"shortName"`
+	LongName      valueobject.LongName    `json:"longName"`
+	Description   valueobject.Description `json:"description"`
+	ChangedAt     valueobject.Timestamp   `json:"changedAt"`
+	ChangedBy     valueobject.Identifier  `json:"changedBy"`
+	ChangedByType ActorType               `json:"changedByType,omitempty"`
 }
 
 // ProjectDeleted event
 type ProjectDeleted struct {
-	ID        valueobject.Identifier `json:"id"`
-	DeletedAt valueobject.Timestamp  `json:"deletedAt"`
-	DeletedBy valueobject.Identifier `json:"deletedBy"`
+	ID            valueobject.Identifier `json:"id"`
+	DeletedAt     valueobject.Timestamp  `json:"deletedAt"`
+	DeletedBy     valueobject.Identifier `json:"deletedBy"`
+	DeletedByType ActorType              `json:"deletedByType,omitempty"`
+}
+
+// ProjectRestored event
+type ProjectRestored struct {
+	ID             valueobject.Identifier `json:"id"`
+	RestoredAt     valueobject.Timestamp  `json:"restoredAt"`
+	RestoredBy     valueobject.Identifier `json:"restoredBy"`
+	RestoredByType ActorType              `json:"restoredByType,omitempty"`
 }
 
 // ProjectShortCodeChanged event
 type ProjectShortCodeChanged struct {
-	ID        valueobject.Identifier `json:"id"`
-	ShortCode valueobject.ShortCode  `json:"shortCode"`
-	ChangedAt valueobject.Timestamp  `json:"changedAt"`
-	ChangedBy valueobject.Identifier `json:"changedBy"`
+	ID            valueobject.Identifier `json:"id"`
+	ShortCode     valueobject.ShortCode  `json:"shortCode"`
+	ChangedAt     valueobject.Timestamp  `json:"changedAt"`
+	ChangedBy     valueobject.Identifier `json:"changedBy"`
+	ChangedByType ActorType              `json:"changedByType,omitempty"`
 }
 
 // ProjectShortNameChanged event
 type ProjectShortNameChanged struct {
-	ID        valueobject.Identifier `json:"id"`
-	ShortName valueobject.ShortName  `json:"shortName"`
-	ChangedAt valueobject.Timestamp  `json:"changedAt"`
-	ChangedBy valueobject.Identifier `json:"changedBy"`
+	ID            valueobject.Identifier `json:"id"`
+	ShortName     valueobject.ShortName  `json:"shortName"`
+	ChangedAt     valueobject.Timestamp  `json:"changedAt"`
+	ChangedBy     valueobject.Identifier `json:"changedBy"`
+	ChangedByType ActorType              `json:"changedByType,omitempty"`
 }
 
 // ProjectLongNameChanged event
 type ProjectLongNameChanged struct {
-	ID        valueobject.Identifier `json:"id"`
-	LongName  valueobject.LongName   `json:"longName"`
-	ChangedAt valueobject.Timestamp  `json:"changedAt"`
-	ChangedBy valueobject.Identifier `json:"changedBy"`
+	ID            valueobject.Identifier `json:"id"`
+	LongName      valueobject.LongName   `json:"longName"`
+	ChangedAt     valueobject.Timestamp  `json:"changedAt"`
+	ChangedBy     valueobject.Identifier `json:"changedBy"`
+	ChangedByType ActorType              `json:"changedByType,omitempty"`
 }
 
 // ProjectDescriptionChanged event
 type ProjectDescriptionChanged struct {
-	ID          valueobject.Identifier  `json:"id"`
-	Description valueobject.Description `json:"description"`
-	ChangedAt   valueobject.Timestamp   `json:"changedAt"`
-	ChangedBy   valueobject.Identifier  `json:"changedBy"`
+	ID            valueobject.Identifier  `json:"id"`
+	Description   valueobject.Description `json:"description"`
+	ChangedAt     valueobject.Timestamp   `json:"changedAt"`
+	ChangedBy     valueobject.Identifier  `json:"changedBy"`
+	ChangedByType ActorType               `json:"changedByType,omitempty"`
 }
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// implementation of marker interface to make sure that event structs can only
+// come from this package.
+func (e ServiceAccountCreated) isEvent() {}
+func (e ServiceAccountDeleted) isEvent() {}
+func (e APIKeyIssued) isEvent()          {}
+func (e APIKeyRevoked) isEvent()         {}
+func (e APIKeyUsed) isEvent()            {}
+
+// ServiceAccountCreated event
+type ServiceAccountCreated struct {
+	ID            valueobject.Identifier  `json:"id"`
+	Name          valueobject.LongName    `json:"name"`
+	Description   valueobject.Description `json:"description"`
+	CreatedAt     valueobject.Timestamp   `json:"createdAt"`
+	CreatedBy     valueobject.Identifier  `json:"createdBy"`
+	CreatedByType ActorType               `json:"createdByType,omitempty"`
+}
+
+// ServiceAccountDeleted event
+type ServiceAccountDeleted struct {
+	ID            valueobject.Identifier `json:"id"`
+	DeletedAt     valueobject.Timestamp  `json:"deletedAt"`
+	DeletedBy     valueobject.Identifier `json:"deletedBy"`
+	DeletedByType ActorType              `json:"deletedByType,omitempty"`
+}
+
+// APIKeyIssued event
+// Only the SHA-256 hash of the key secret is recorded, the secret itself is never stored.
+type APIKeyIssued struct {
+	ID           valueobject.Identifier `json:"id"`
+	KeyID        valueobject.Identifier `json:"keyId"`
+	KeyHash      string                 `json:"keyHash"`
+	Scopes       []string               `json:"scopes"`
+	ExpiresAt    valueobject.Timestamp  `json:"expiresAt"`
+	IssuedAt     valueobject.Timestamp  `json:"issuedAt"`
+	IssuedBy     valueobject.Identifier `json:"issuedBy"`
+	IssuedByType ActorType              `json:"issuedByType,omitempty"`
+}
+
+// APIKeyRevoked event
+type APIKeyRevoked struct {
+	ID            valueobject.Identifier `json:"id"`
+	KeyID         valueobject.Identifier `json:"keyId"`
+	RevokedAt     valueobject.Timestamp  `json:"revokedAt"`
+	RevokedBy     valueobject.Identifier `json:"revokedBy"`
+	RevokedByType ActorType              `json:"revokedByType,omitempty"`
+}
+
+// APIKeyUsed event
+type APIKeyUsed struct {
+	ID     valueobject.Identifier `json:"id"`
+	KeyID  valueobject.Identifier `json:"keyId"`
+	UsedAt valueobject.Timestamp  `json:"usedAt"`
+}
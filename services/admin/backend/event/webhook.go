@@ -0,0 +1,90 @@
+/*
+ * Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"encoding/json"
+
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// implementation of marker interface to make sure that event structs can only
+// come from this package.
+func (e WebhookCreated) isEvent()                     {}
+func (e WebhookDeleted) isEvent()                     {}
+func (e WebhookDeliveryScheduled) isEvent()           {}
+func (e WebhookDeliveryAttempted) isEvent()           {}
+func (e WebhookDeliveryDeadLettered) isEvent()        {}
+func (e WebhookDeliveryRedeliveryRequested) isEvent() {}
+
+// WebhookCreated event
+// The secret is needed to sign the payloads and is therefore recorded as is.
+type WebhookCreated struct {
+	ID            valueobject.Identifier  `json:"id"`
+	URL           string                  `json:"url"`
+	Secret        string                  `json:"secret"`
+	EventTypes    []string                `json:"eventTypes"`
+	Description   valueobject.Description `json:"description"`
+	CreatedAt     valueobject.Timestamp   `json:"createdAt"`
+	CreatedBy     valueobject.Identifier  `json:"createdBy"`
+	CreatedByType ActorType               `json:"createdByType,omitempty"`
+}
+
+// WebhookDeleted event
+type WebhookDeleted struct {
+	ID            valueobject.Identifier `json:"id"`
+	DeletedAt     valueobject.Timestamp  `json:"deletedAt"`
+	DeletedBy     valueobject.Identifier `json:"deletedBy"`
+	DeletedByType ActorType              `json:"deletedByType,omitempty"`
+}
+
+// WebhookDeliveryScheduled event
+// SourceEventID is the id of the project event the delivery notifies about.
+type WebhookDeliveryScheduled struct {
+	ID            valueobject.Identifier `json:"id"`
+	WebhookID     valueobject.Identifier `json:"webhookId"`
+	EventType     string                 `json:"eventType"`
+	SourceEventID string                 `json:"sourceEventId"`
+	Payload       json.RawMessage        `json:"payload"`
+	ScheduledAt   valueobject.Timestamp  `json:"scheduledAt"`
+}
+
+// WebhookDeliveryAttempted event
+// NextAttemptAt is only set if a failed attempt is retried.
+type WebhookDeliveryAttempted struct {
+	ID            valueobject.Identifier `json:"id"`
+	Succeeded     bool                   `json:"succeeded"`
+	StatusCode    int                    `json:"statusCode"`
+	Error         string                 `json:"error"`
+	AttemptedAt   valueobject.Timestamp  `json:"attemptedAt"`
+	NextAttemptAt valueobject.Timestamp  `json:"nextAttemptAt"`
+}
+
+// WebhookDeliveryDeadLettered event
+type WebhookDeliveryDeadLettered struct {
+	ID             valueobject.Identifier `json:"id"`
+	Reason         string                 `json:"reason"`
+	DeadLetteredAt valueobject.Timestamp  `json:"deadLetteredAt"`
+}
+
+// WebhookDeliveryRedeliveryRequested event
+type WebhookDeliveryRedeliveryRequested struct {
+	ID              valueobject.Identifier `json:"id"`
+	RequestedAt     valueobject.Timestamp  `json:"requestedAt"`
+	RequestedBy     valueobject.Identifier `json:"requestedBy"`
+	RequestedByType ActorType              `json:"requestedByType,omitempty"`
+}
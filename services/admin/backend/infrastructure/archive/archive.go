@@ -0,0 +1,455 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package archive reads and writes portable archives of event streams, independent of the event store they come from.
+//
+// An archive is a gzip compressed file of newline delimited JSON (NDJSON). The first line is a header naming the format
+// and its version, followed by one line per event in the order the events were recorded, one line per key of the data subjects
+// whose personal data is encrypted in the events, and a trailer closing the archive:
+//
+//	{"kind":"header","format":"dsp-admin-event-archive","version":2,"createdAt":"2021-06-01T12:00:00Z"}
+//	{"kind":"event","stream":"Project-…","revision":0,"id":"…","type":"ProjectCreated","contentType":"application/json","createdAt":"…","data":{…},"sha256":"…"}
+//	{"kind":"key","id":"…","ciphertext":"…","sha256":"…"}
+//	{"kind":"trailer","streams":1,"events":1,"keys":1,"sha256":"…"}
+//
+// The checksum of an event covers its stream, revision, id, type, content type, data and metadata.
+// A key is stored along with its subject as AES-256-GCM ciphertext, encrypted with a key of the archive, since anyone holding
+// both the archive and the keys could read the personal data, even of subjects forgotten later on.
+// The checksum of the trailer covers the checksums of all events and keys in order, so that missing, reordered or truncated lines are detected.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format is the name of the archive format written in the header.
+const Format = "dsp-admin-event-archive"
+
+// Version is the version of the archive format written by this package, version 2 added the keys.
+// Archives of later versions are refused, as they may contain information which would be lost.
+const Version = 2
+
+// KeySize is the size of the keys of archives in bytes, for AES-256.
+const KeySize = 32
+
+// kinds of the lines of an archive.
+const (
+	kindHeader  = "header"
+	kindEvent   = "event"
+	kindKey     = "key"
+	kindTrailer = "trailer"
+)
+
+// ErrInvalidArchive is returned if a file is not an archive, or if it is malformed.
+var ErrInvalidArchive = errors.New("not a valid event archive")
+
+// ErrUnsupportedVersion is returned if an archive was written in a later version of the format.
+var ErrUnsupportedVersion = errors.New("unsupported version of the event archive format")
+
+// ErrChecksumMismatch is returned if the content of an archive does not match its checksums.
+var ErrChecksumMismatch = errors.New("checksum mismatch, the archive is corrupted")
+
+// ErrTruncated is returned if an archive ends before its trailer.
+var ErrTruncated = errors.New("the archive is truncated")
+
+// ErrKeyRequired is returned if the keys of data subjects are exported or imported without the key of the archive.
+var ErrKeyRequired = errors.New("the events contain encrypted personal data, whose keys can only be archived with a key of the archive")
+
+// ErrWrongKey is returned if the keys of an archive cannot be decrypted with the key provided.
+var ErrWrongKey = errors.New("the keys of the data subjects cannot be decrypted, the key of the archive is wrong")
+
+// Event is an event of a stream, as stored in an archive.
+type Event struct {
+	Stream string
+	// Revision is the position of the event in its stream, starting at 0.
+	Revision    uint64
+	ID          string
+	Type        string
+	ContentType string
+	// CreatedAt is the time the event was recorded in the event store it was exported from.
+	CreatedAt time.Time
+	Data      []byte
+	Metadata  []byte
+}
+
+// Checksum returns the hex encoded SHA-256 checksum of the event.
+func (e Event) Checksum() string {
+	h := sha256.New()
+	for _, field := range []string{e.Stream, strconv.FormatUint(e.Revision, 10), e.ID, e.Type, e.ContentType} {
+		writeField(h, []byte(field))
+	}
+	writeField(h, e.Data)
+	writeField(h, e.Metadata)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Key is a key of a data subject, which the personal data in the events of an archive is encrypted with.
+type Key struct {
+	ID      string
+	Subject string
+	Secret  []byte
+}
+
+// sealedKey is the plaintext of the ciphertext of a key line.
+type sealedKey struct {
+	Subject string `json:"subject"`
+	Secret  []byte `json:"secret"`
+}
+
+// ParseKey returns the key of an archive from its base64 encoding.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("the key of an archive must be a base64 encoded key of %d bytes", KeySize)
+	}
+	return key, nil
+}
+
+// keyChecksum returns the hex encoded SHA-256 checksum of a key line.
+func keyChecksum(id string, ciphertext string) string {
+	h := sha256.New()
+	writeField(h, []byte(id))
+	writeField(h, []byte(ciphertext))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sealKey encrypts the subject and secret of the key with the key of the archive, and returns the nonce followed by the ciphertext,
+// base64 encoded. The id of the key is authenticated, so that the ciphertext cannot be moved to another key.
+func sealKey(archiveKey []byte, k Key) (string, error) {
+	plaintext, err := json.Marshal(sealedKey{Subject: k.Subject, Secret: k.Secret})
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(archiveKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, []byte(k.ID))), nil
+}
+
+// openKey decrypts a key encrypted by sealKey.
+func openKey(archiveKey []byte, id string, ciphertext string) (Key, error) {
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	gcm, err := newGCM(archiveKey)
+	if err != nil {
+		return Key{}, err
+	}
+	if len(b) < gcm.NonceSize() {
+		return Key{}, fmt.Errorf("%w: the ciphertext of key %s is too short", ErrInvalidArchive, id)
+	}
+	plaintext, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], []byte(id))
+	if err != nil {
+		return Key{}, ErrWrongKey
+	}
+
+	var k sealedKey
+	if err := json.Unmarshal(plaintext, &k); err != nil {
+		return Key{}, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	return Key{ID: id, Subject: k.Subject, Secret: k.Secret}, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeField writes the field prefixed with its length, so that the boundaries between fields are unambiguous.
+func writeField(h hash.Hash, field []byte) {
+	fmt.Fprintf(h, "%d:", len(field))
+	h.Write(field)
+}
+
+// line is a line of an archive, the fields used depend on its kind.
+type line struct {
+	Kind string `json:"kind"`
+
+	// header
+	Format string `json:"format,omitempty"`
+	// Version is a pointer, so that it is written for the header only.
+	Version *int `json:"version,omitempty"`
+
+	// event
+	Stream         string          `json:"stream,omitempty"`
+	Revision       *uint64         `json:"revision,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	ContentType    string          `json:"contentType,omitempty"`
+	Data           json.RawMessage `json:"data,omitempty"`
+	DataBase64     string          `json:"dataBase64,omitempty"`
+	Metadata       json.RawMessage `json:"metadata,omitempty"`
+	MetadataBase64 string          `json:"metadataBase64,omitempty"`
+
+	// key
+	Ciphertext string `json:"ciphertext,omitempty"`
+
+	// header and event
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+
+	// trailer
+	Streams *int `json:"streams,omitempty"`
+	Events  *int `json:"events,omitempty"`
+	Keys    *int `json:"keys,omitempty"`
+
+	// event, key and trailer
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// encodeBytes stores the bytes as JSON if they are compact JSON, so that the archive stays readable, or as base64 otherwise.
+// Other JSON is stored as base64 as well, since it would be compacted when written and no longer match its checksum.
+func encodeBytes(b []byte) (json.RawMessage, string) {
+	if len(b) == 0 {
+		return nil, ""
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, b); err == nil && bytes.Equal(compact.Bytes(), b) {
+		return json.RawMessage(b), ""
+	}
+	return nil, base64.StdEncoding.EncodeToString(b)
+}
+
+// decodeBytes reverses encodeBytes.
+func decodeBytes(raw json.RawMessage, b64 string) ([]byte, error) {
+	if b64 != "" {
+		return base64.StdEncoding.DecodeString(b64)
+	}
+	return []byte(raw), nil
+}
+
+// Writer writes an archive. Close must be called to write the trailer.
+type Writer struct {
+	gz      *gzip.Writer
+	enc     *json.Encoder
+	chain   hash.Hash
+	streams map[string]bool
+	events  int
+	keys    int
+}
+
+// NewWriter creates a writer and writes the header of the archive.
+func NewWriter(w io.Writer) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	aw := &Writer{gz: gz, enc: json.NewEncoder(gz), chain: sha256.New(), streams: map[string]bool{}}
+	// the data of the events must be written as is
+	aw.enc.SetEscapeHTML(false)
+
+	version := Version
+	now := time.Now().UTC()
+	if err := aw.enc.Encode(line{Kind: kindHeader, Format: Format, Version: &version, CreatedAt: &now}); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Write appends the event to the archive.
+func (w *Writer) Write(e Event) error {
+	sum := e.Checksum()
+	l := line{Kind: kindEvent, Stream: e.Stream, Revision: &e.Revision, ID: e.ID, Type: e.Type, ContentType: e.ContentType, SHA256: sum}
+	if !e.CreatedAt.IsZero() {
+		t := e.CreatedAt.UTC()
+		l.CreatedAt = &t
+	}
+	l.Data, l.DataBase64 = encodeBytes(e.Data)
+	l.Metadata, l.MetadataBase64 = encodeBytes(e.Metadata)
+
+	if err := w.enc.Encode(l); err != nil {
+		return err
+	}
+
+	w.chain.Write([]byte(sum))
+	w.streams[e.Stream] = true
+	w.events++
+	return nil
+}
+
+// WriteKey appends the key of a data subject to the archive, encrypted with the key of the archive.
+func (w *Writer) WriteKey(k Key, archiveKey []byte) error {
+	if archiveKey == nil {
+		return ErrKeyRequired
+	}
+	ciphertext, err := sealKey(archiveKey, k)
+	if err != nil {
+		return err
+	}
+
+	sum := keyChecksum(k.ID, ciphertext)
+	if err := w.enc.Encode(line{Kind: kindKey, ID: k.ID, Ciphertext: ciphertext, SHA256: sum}); err != nil {
+		return err
+	}
+
+	w.chain.Write([]byte(sum))
+	w.keys++
+	return nil
+}
+
+// Close writes the trailer and flushes the archive. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	streams := len(w.streams)
+	if err := w.enc.Encode(line{Kind: kindTrailer, Streams: &streams, Events: &w.events, Keys: &w.keys, SHA256: hex.EncodeToString(w.chain.Sum(nil))}); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// ReadAll reads and verifies a whole archive and returns its events and the decrypted keys of the data subjects, in order.
+// Nothing is returned unless the archive is complete and matches all its checksums. The key of the archive is only needed
+// if the archive contains keys.
+func ReadAll(r io.Reader, archiveKey []byte) ([]Event, []Key, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	// events are small, but their size is not limited
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var events []Event
+	var keys []Key
+	chain := sha256.New()
+	streams := map[string]uint64{}
+	header, trailer := false, false
+
+	for n := 1; scanner.Scan(); n++ {
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w: %v", n, ErrInvalidArchive, err)
+		}
+		if trailer {
+			return nil, nil, fmt.Errorf("line %d: %w: content after the trailer", n, ErrInvalidArchive)
+		}
+		if !header && l.Kind != kindHeader {
+			return nil, nil, fmt.Errorf("line %d: %w: missing header", n, ErrInvalidArchive)
+		}
+
+		switch l.Kind {
+		case kindHeader:
+			if header || l.Format != Format || l.Version == nil {
+				return nil, nil, fmt.Errorf("line %d: %w: unexpected header", n, ErrInvalidArchive)
+			}
+			if *l.Version > Version {
+				return nil, nil, fmt.Errorf("%w: version %d, expected at most %d", ErrUnsupportedVersion, *l.Version, Version)
+			}
+			header = true
+
+		case kindEvent:
+			e, err := l.event()
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w: %v", n, ErrInvalidArchive, err)
+			}
+			if e.Checksum() != l.SHA256 {
+				return nil, nil, fmt.Errorf("line %d: %w", n, ErrChecksumMismatch)
+			}
+			// the events of a stream must follow each other without gaps
+			if next := streams[e.Stream]; e.Revision != next {
+				return nil, nil, fmt.Errorf("line %d: %w: revision %d of stream %s, expected %d", n, ErrInvalidArchive, e.Revision, e.Stream, next)
+			}
+			streams[e.Stream] = e.Revision + 1
+			chain.Write([]byte(l.SHA256))
+			events = append(events, e)
+
+		case kindKey:
+			if l.ID == "" || l.Ciphertext == "" {
+				return nil, nil, fmt.Errorf("line %d: %w: id and ciphertext are required", n, ErrInvalidArchive)
+			}
+			if keyChecksum(l.ID, l.Ciphertext) != l.SHA256 {
+				return nil, nil, fmt.Errorf("line %d: %w", n, ErrChecksumMismatch)
+			}
+			if archiveKey == nil {
+				return nil, nil, ErrKeyRequired
+			}
+			k, err := openKey(archiveKey, l.ID, l.Ciphertext)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			chain.Write([]byte(l.SHA256))
+			keys = append(keys, k)
+
+		case kindTrailer:
+			// archives of version 1 have no keys
+			trailerKeys := 0
+			if l.Keys != nil {
+				trailerKeys = *l.Keys
+			}
+			if l.Streams == nil || l.Events == nil || *l.Streams != len(streams) || *l.Events != len(events) || trailerKeys != len(keys) {
+				return nil, nil, fmt.Errorf("line %d: %w: the number of streams, events or keys differs from the trailer", n, ErrChecksumMismatch)
+			}
+			if hex.EncodeToString(chain.Sum(nil)) != l.SHA256 {
+				return nil, nil, fmt.Errorf("line %d: %w", n, ErrChecksumMismatch)
+			}
+			trailer = true
+
+		default:
+			return nil, nil, fmt.Errorf("line %d: %w: unknown kind %q", n, ErrInvalidArchive, l.Kind)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if !trailer {
+		return nil, nil, ErrTruncated
+	}
+
+	return events, keys, nil
+}
+
+// event converts an event line to the event.
+func (l line) event() (Event, error) {
+	if l.Stream == "" || l.Revision == nil || l.Type == "" {
+		return Event{}, errors.New("stream, revision and type are required")
+	}
+	data, err := decodeBytes(l.Data, l.DataBase64)
+	if err != nil {
+		return Event{}, err
+	}
+	metadata, err := decodeBytes(l.Metadata, l.MetadataBase64)
+	if err != nil {
+		return Event{}, err
+	}
+
+	e := Event{Stream: l.Stream, Revision: *l.Revision, ID: l.ID, Type: l.Type, ContentType: l.ContentType, Data: data, Metadata: metadata}
+	if l.CreatedAt != nil {
+		e.CreatedAt = *l.CreatedAt
+	}
+	return e, nil
+}
@@ -0,0 +1,255 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package archive_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/archive"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore is an event store keeping its streams and the keys of the data subjects in memory, standing in for any other backend.
+type memoryStore struct {
+	order   []archive.Event
+	streams map[string][]archive.Event
+	// keys are the keys of the events by event id
+	keys      map[string][]archive.Key
+	imported  []archive.Key
+	destroyed map[string]bool
+}
+
+func newMemoryStore(events ...archive.Event) *memoryStore {
+	s := &memoryStore{streams: map[string][]archive.Event{}, keys: map[string][]archive.Key{}, destroyed: map[string]bool{}}
+	for _, e := range events {
+		s.order = append(s.order, e)
+		s.streams[e.Stream] = append(s.streams[e.Stream], e)
+	}
+	return s
+}
+
+func (s *memoryStore) ExportEvents(ctx context.Context, handle func(e archive.Event) error) error {
+	for _, e := range s.order {
+		if err := handle(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) SubjectKeys(ctx context.Context, e archive.Event) ([]archive.Key, error) {
+	return s.keys[e.ID], nil
+}
+
+func (s *memoryStore) ImportKey(ctx context.Context, k archive.Key) (bool, error) {
+	if s.destroyed[k.ID] {
+		return false, nil
+	}
+	s.imported = append(s.imported, k)
+	return true, nil
+}
+
+func (s *memoryStore) StreamExists(ctx context.Context, stream string) (bool, error) {
+	return len(s.streams[stream]) > 0, nil
+}
+
+func (s *memoryStore) ImportEvents(ctx context.Context, stream string, events []archive.Event, overwrite bool) error {
+	if overwrite {
+		s.streams[stream] = nil
+	}
+	for _, e := range events {
+		e.Revision = uint64(len(s.streams[stream]))
+		s.streams[stream] = append(s.streams[stream], e)
+		s.order = append(s.order, e)
+	}
+	return nil
+}
+
+// testEvents are the events of two interleaved streams, with data in every supported encoding.
+func testEvents() []archive.Event {
+	at := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	return []archive.Event{
+		{Stream: "Project-a", Revision: 0, ID: "1", Type: "ProjectCreated", ContentType: "application/json", CreatedAt: at, Data: []byte(`{"id":"a","longName":"<Bernoulli & Euler>"}`)},
+		{Stream: "Project-b", Revision: 0, ID: "2", Type: "ProjectCreated", ContentType: "application/json", CreatedAt: at, Data: []byte(`{ "id": "b" }`), Metadata: []byte(`{"source":"import"}`)},
+		{Stream: "Project-a", Revision: 1, ID: "3", Type: "ProjectDeleted", ContentType: "application/octet-stream", CreatedAt: at, Data: []byte{0xff, 0x00}},
+	}
+}
+
+func export(t *testing.T, events ...archive.Event) []byte {
+	var buf bytes.Buffer
+	summary, err := archive.Export(context.Background(), newMemoryStore(events...), &buf, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, archive.Summary{Streams: 2, Events: len(events)}, summary)
+	return buf.Bytes()
+}
+
+func TestExportImport(t *testing.T) {
+	b := export(t, testEvents()...)
+
+	dst := newMemoryStore()
+	summary, err := archive.Import(context.Background(), dst, bytes.NewReader(b), nil, false, false)
+	assert.Nil(t, err)
+	assert.Equal(t, archive.Summary{Streams: 2, Events: 3}, summary)
+
+	// ids, types, data, metadata and order are kept
+	assert.Equal(t, testEvents(), dst.order)
+}
+
+func TestImport_RefusesToOverwrite(t *testing.T) {
+	b := export(t, testEvents()...)
+	existing := archive.Event{Stream: "Project-b", ID: "9", Type: "ProjectCreated", Data: []byte(`{"id":"b"}`)}
+	dst := newMemoryStore(existing)
+
+	summary, err := archive.Import(context.Background(), dst, bytes.NewReader(b), nil, false, false)
+	assert.True(t, errors.Is(err, archive.ErrStreamExists))
+	assert.Equal(t, []string{"Project-b"}, summary.Overwritten)
+	assert.Equal(t, []archive.Event{existing}, dst.order)
+
+	// a dry run reports the streams which would be overwritten
+	summary, err = archive.Import(context.Background(), dst, bytes.NewReader(b), nil, true, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Project-b"}, summary.Overwritten)
+	assert.Len(t, dst.order, 1)
+
+	_, err = archive.Import(context.Background(), dst, bytes.NewReader(b), nil, true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, testEvents()[1], dst.streams["Project-b"][0])
+	assert.Len(t, dst.streams["Project-b"], 1)
+}
+
+func TestReadAll_Corrupted(t *testing.T) {
+	lines := strings.Split(string(gunzip(t, export(t, testEvents()...))), "\n")
+
+	tests := []struct {
+		name  string
+		lines []string
+		err   error
+	}{
+		{"changed data", replace(lines, 1, `"id":"a"`, `"id":"x"`), archive.ErrChecksumMismatch},
+		{"missing event", append(lines[:1:1], lines[2:]...), archive.ErrInvalidArchive},
+		{"missing stream", append(lines[:2:2], lines[3:]...), archive.ErrChecksumMismatch},
+		{"reordered streams", []string{lines[0], lines[2], lines[1], lines[3], lines[4]}, archive.ErrChecksumMismatch},
+		{"truncated", lines[:3], archive.ErrTruncated},
+		{"missing header", lines[1:], archive.ErrInvalidArchive},
+		{"later version", replace(lines, 0, `"version":2`, `"version":3`), archive.ErrUnsupportedVersion},
+	}
+
+	for _, tt := range tests {
+		_, _, err := archive.ReadAll(bytes.NewReader(gzipped(t, strings.Join(tt.lines, "\n"))), nil)
+		assert.True(t, errors.Is(err, tt.err), "%s: %v", tt.name, err)
+	}
+
+	_, _, err := archive.ReadAll(strings.NewReader("not compressed"), nil)
+	assert.True(t, errors.Is(err, archive.ErrInvalidArchive))
+}
+
+func TestReadAll_Version1(t *testing.T) {
+	// archives written before the keys were added have no keys in their trailer
+	lines := strings.Split(string(gunzip(t, export(t, testEvents()...))), "\n")
+	lines = replace(lines, 0, `"version":2`, `"version":1`)
+	lines = replace(lines, 4, `"keys":0,`, ``)
+
+	events, keys, err := archive.ReadAll(bytes.NewReader(gzipped(t, strings.Join(lines, "\n"))), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, testEvents(), events)
+	assert.Empty(t, keys)
+}
+
+func TestExportImport_Keys(t *testing.T) {
+	ctx := context.Background()
+	archiveKey := bytes.Repeat([]byte{7}, archive.KeySize)
+	alice := archive.Key{ID: "k1", Subject: "alice", Secret: bytes.Repeat([]byte{1}, 32)}
+	bob := archive.Key{ID: "k2", Subject: "bob", Secret: bytes.Repeat([]byte{2}, 32)}
+
+	src := newMemoryStore(testEvents()...)
+	src.keys["1"] = []archive.Key{alice}
+	src.keys["3"] = []archive.Key{alice, bob}
+
+	// the keys are never exported in plain text
+	_, err := archive.Export(ctx, src, &bytes.Buffer{}, nil)
+	assert.True(t, errors.Is(err, archive.ErrKeyRequired))
+
+	var buf bytes.Buffer
+	summary, err := archive.Export(ctx, src, &buf, archiveKey)
+	assert.Nil(t, err)
+	assert.Equal(t, archive.Summary{Streams: 2, Events: 3, Keys: 2}, summary)
+	assert.NotContains(t, string(gunzip(t, buf.Bytes())), "alice")
+
+	_, err = archive.Import(ctx, newMemoryStore(), bytes.NewReader(buf.Bytes()), nil, false, true)
+	assert.True(t, errors.Is(err, archive.ErrKeyRequired))
+	_, err = archive.Import(ctx, newMemoryStore(), bytes.NewReader(buf.Bytes()), bytes.Repeat([]byte{8}, archive.KeySize), false, true)
+	assert.True(t, errors.Is(err, archive.ErrWrongKey))
+
+	// destroyed keys are not imported again
+	dst := newMemoryStore()
+	dst.destroyed[bob.ID] = true
+	summary, err = archive.Import(ctx, dst, bytes.NewReader(buf.Bytes()), archiveKey, false, false)
+	assert.Nil(t, err)
+	assert.Equal(t, archive.Summary{Streams: 2, Events: 3, Keys: 2, Forgotten: 1}, summary)
+	assert.Equal(t, []archive.Key{alice}, dst.imported)
+	assert.Equal(t, testEvents(), dst.order)
+
+	// a key cannot be moved to another id
+	lines := strings.Split(string(gunzip(t, buf.Bytes())), "\n")
+	moved := replace(lines, 4, `"id":"k1"`, `"id":"k2"`)
+	_, _, err = archive.ReadAll(bytes.NewReader(gzipped(t, strings.Join(moved, "\n"))), archiveKey)
+	assert.True(t, errors.Is(err, archive.ErrChecksumMismatch))
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := archive.ParseKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, archive.KeySize)))
+	assert.Nil(t, err)
+	assert.Len(t, key, archive.KeySize)
+
+	for _, s := range []string{"", "not base64", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		_, err := archive.ParseKey(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+// replace returns a copy of the lines, with old replaced by new in the line with the index.
+func replace(lines []string, i int, old string, new string) []string {
+	res := append([]string{}, lines...)
+	res[i] = strings.Replace(res[i], old, new, 1)
+	return res
+}
+
+func gunzip(t *testing.T, b []byte) []byte {
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	assert.Nil(t, err)
+	res, err := ioutil.ReadAll(gz)
+	assert.Nil(t, err)
+	return res
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(s))
+	assert.Nil(t, err)
+	assert.Nil(t, gz.Close())
+	return buf.Bytes()
+}
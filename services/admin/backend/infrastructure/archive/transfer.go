@@ -0,0 +1,175 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrStreamExists is returned if an archive contains streams which already exist, and overwriting them was not forced.
+var ErrStreamExists = errors.New("the event store already contains streams of the archive")
+
+// Source is an event store whose events can be exported.
+type Source interface {
+	// ExportEvents passes every event to the handler, in the order the events were recorded.
+	ExportEvents(ctx context.Context, handle func(e Event) error) error
+	// SubjectKeys returns the keys which the personal data in the event is encrypted with, destroyed keys are missing.
+	SubjectKeys(ctx context.Context, e Event) ([]Key, error)
+}
+
+// Destination is an event store into which archives can be imported.
+type Destination interface {
+	// StreamExists returns true if the stream contains any event.
+	StreamExists(ctx context.Context, stream string) (bool, error)
+	// ImportEvents appends the events to the stream. If overwrite is true, the events already in the stream are removed first.
+	ImportEvents(ctx context.Context, stream string, events []Event, overwrite bool) error
+	// ImportKey adds the key of a data subject, and returns false if the key has been destroyed in the event store.
+	ImportKey(ctx context.Context, k Key) (bool, error)
+}
+
+// Summary counts the streams and events of an export or import.
+type Summary struct {
+	Streams int `json:"streams"`
+	Events  int `json:"events"`
+	Keys    int `json:"keys"`
+	// Forgotten counts the keys of the archive which have been destroyed in the event store, and were not imported again.
+	Forgotten int `json:"forgotten,omitempty"`
+	// Overwritten lists the streams which existed before the import, in the order they were imported.
+	Overwritten []string `json:"overwritten,omitempty"`
+}
+
+// Export writes every event of the source to an archive, followed by the keys of the personal data in the events,
+// encrypted with the key of the archive. Without a key, ErrKeyRequired is returned as soon as an event with personal data is exported.
+func Export(ctx context.Context, src Source, w io.Writer, archiveKey []byte) (Summary, error) {
+	aw, err := NewWriter(w)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	streams := map[string]bool{}
+	var keys []Key
+	seen := map[string]bool{}
+	var summary Summary
+	err = src.ExportEvents(ctx, func(e Event) error {
+		eventKeys, err := src.SubjectKeys(ctx, e)
+		if err != nil {
+			return err
+		}
+		for _, k := range eventKeys {
+			if archiveKey == nil {
+				return ErrKeyRequired
+			}
+			if !seen[k.ID] {
+				seen[k.ID] = true
+				keys = append(keys, k)
+			}
+		}
+
+		streams[e.Stream] = true
+		summary.Events++
+		return aw.Write(e)
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	summary.Streams = len(streams)
+
+	for _, k := range keys {
+		if err := aw.WriteKey(k, archiveKey); err != nil {
+			return Summary{}, err
+		}
+	}
+	summary.Keys = len(keys)
+
+	return summary, aw.Close()
+}
+
+// Import verifies the archive and appends its events to the destination, in the order of the archive, after adding its keys.
+// Nothing is written if the archive is invalid, or if any of its streams exists and force is false.
+// With force, existing streams are replaced by the streams of the archive. If dryRun is true, nothing is written at all.
+// The key of the archive is only needed if the archive contains keys.
+func Import(ctx context.Context, dst Destination, r io.Reader, archiveKey []byte, force bool, dryRun bool) (Summary, error) {
+	events, keys, err := ReadAll(r, archiveKey)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// the streams in the order of their first event
+	var streams []string
+	seen := map[string]bool{}
+	for _, e := range events {
+		if !seen[e.Stream] {
+			seen[e.Stream] = true
+			streams = append(streams, e.Stream)
+		}
+	}
+
+	summary := Summary{Streams: len(streams), Events: len(events), Keys: len(keys)}
+	existing := map[string]bool{}
+	for _, stream := range streams {
+		exists, err := dst.StreamExists(ctx, stream)
+		if err != nil {
+			return Summary{}, err
+		}
+		if exists {
+			existing[stream] = true
+			summary.Overwritten = append(summary.Overwritten, stream)
+		}
+	}
+	if len(existing) > 0 && !force {
+		return summary, fmt.Errorf("%w: %s", ErrStreamExists, strings.Join(summary.Overwritten, ", "))
+	}
+	if dryRun {
+		return summary, nil
+	}
+
+	// the keys are imported first, so that the events can be read as soon as they are imported
+	for _, k := range keys {
+		imported, err := dst.ImportKey(ctx, k)
+		if err != nil {
+			return summary, fmt.Errorf("problem importing key %s: %w", k.ID, err)
+		}
+		if !imported {
+			summary.Forgotten++
+		}
+	}
+
+	// consecutive events of the same stream are appended at once, so that the order of the archive is kept
+	for start := 0; start < len(events); {
+		stream := events[start].Stream
+		end := start + 1
+		for end < len(events) && events[end].Stream == stream {
+			end++
+		}
+
+		// an existing stream is only removed before its first events are appended
+		overwrite := existing[stream]
+		delete(existing, stream)
+
+		if err := dst.ImportEvents(ctx, stream, events[start:end], overwrite); err != nil {
+			return summary, fmt.Errorf("problem importing stream %s: %w", stream, err)
+		}
+		start = end
+	}
+
+	return summary, nil
+}
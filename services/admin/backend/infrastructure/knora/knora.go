@@ -0,0 +1,218 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package knora reads the projects of DSP-API (Knora) from an export of its knora-admin data,
+// to migrate them into the admin service.
+package knora
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/api/rdf"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+)
+
+// Admin is the namespace of the knora-admin ontology.
+const Admin = "http://www.knora.org/ontology/knora-admin#"
+
+// Created is the property of the creation time of a project. DSP-API does not record it, but exports may add it.
+const Created = "http://purl.org/dc/terms/created"
+
+// jsonProject is a project as returned by the admin api of DSP-API, e.g. `GET /admin/projects`.
+type jsonProject struct {
+	ID          string          `json:"id"`
+	ShortCode   string          `json:"shortcode"`
+	ShortName   string          `json:"shortname"`
+	LongName    string          `json:"longname"`
+	Description json.RawMessage `json:"description"`
+	Keywords    []string        `json:"keywords"`
+	Status      bool            `json:"status"`
+	Created     string          `json:"created"`
+}
+
+// langString is a string in a language, e.g. a description of a project.
+type langString struct {
+	Value    string `json:"value"`
+	Language string `json:"language"`
+}
+
+// ReadJSON reads the projects of a response of the admin api of DSP-API. The response may either list the
+// projects, as `GET /admin/projects` does, contain a single project, as `GET /admin/projects/iri/<IRI>` does,
+// or be an array of projects.
+func ReadJSON(r io.Reader) ([]project.LegacyProject, error) {
+	var doc json.RawMessage
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("problem reading the projects: %w", err)
+	}
+
+	var projects []jsonProject
+	if err := json.Unmarshal(doc, &projects); err != nil {
+		var res struct {
+			Projects []jsonProject `json:"projects"`
+			Project  *jsonProject  `json:"project"`
+		}
+		if err := json.Unmarshal(doc, &res); err != nil {
+			return nil, fmt.Errorf("problem reading the projects: %w", err)
+		}
+		projects = res.Projects
+		if res.Project != nil {
+			projects = append(projects, *res.Project)
+		}
+	}
+
+	res := make([]project.LegacyProject, len(projects))
+	for i, p := range projects {
+		descriptions, err := jsonDescriptions(p.Description)
+		if err != nil {
+			return nil, fmt.Errorf("problem reading the description of project %s: %w", p.ID, err)
+		}
+		createdAt, err := parseTime(p.Created)
+		if err != nil {
+			return nil, fmt.Errorf("problem reading the creation time of project %s: %w", p.ID, err)
+		}
+
+		res[i] = project.LegacyProject{
+			IRI:         p.ID,
+			ShortCode:   strings.TrimSpace(p.ShortCode),
+			ShortName:   strings.TrimSpace(p.ShortName),
+			LongName:    strings.TrimSpace(p.LongName),
+			Description: description(descriptions),
+			Keywords:    keywords(p.Keywords),
+			Active:      p.Status,
+			CreatedAt:   createdAt,
+		}
+	}
+	return res, nil
+}
+
+// jsonDescriptions reads the descriptions of a project, which are either a list of strings in a language or a single string.
+func jsonDescriptions(raw json.RawMessage) ([]langString, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []langString{{Value: s}}, nil
+	}
+	var res []langString
+	err := json.Unmarshal(raw, &res)
+	return res, err
+}
+
+// ReadRDF reads the projects of the knora-admin data of DSP-API, written as Turtle or TriG.
+// Every resource of type knora-admin:knoraProject is read as a project, in the order they first occur.
+func ReadRDF(r io.Reader) ([]project.LegacyProject, error) {
+	g, err := rdf.ReadTurtle(r)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading the projects: %w", err)
+	}
+
+	var iris []rdf.IRI
+	projects := map[rdf.IRI]*project.LegacyProject{}
+	descriptions := map[rdf.IRI][]langString{}
+	for _, t := range g.Triples {
+		if t.Predicate == rdf.Type && t.Object == rdf.IRI(Admin+"knoraProject") && projects[t.Subject] == nil {
+			iris = append(iris, t.Subject)
+			projects[t.Subject] = &project.LegacyProject{IRI: string(t.Subject)}
+		}
+	}
+
+	for _, t := range g.Triples {
+		p := projects[t.Subject]
+		l, ok := t.Object.(rdf.Literal)
+		if p == nil || !ok {
+			continue
+		}
+		value := strings.TrimSpace(l.Value)
+
+		switch t.Predicate {
+		case Admin + "projectShortcode":
+			p.ShortCode = value
+		case Admin + "projectShortname":
+			p.ShortName = value
+		case Admin + "projectLongname":
+			p.LongName = value
+		case Admin + "projectDescription":
+			descriptions[t.Subject] = append(descriptions[t.Subject], langString{Value: l.Value, Language: l.Language})
+		case Admin + "projectKeyword":
+			p.Keywords = append(p.Keywords, value)
+		case Admin + "status":
+			p.Active = value == "true" || value == "1"
+		case Created:
+			if p.CreatedAt, err = parseTime(value); err != nil {
+				return nil, fmt.Errorf("problem reading the creation time of project %s: %w", t.Subject, err)
+			}
+		}
+	}
+
+	res := make([]project.LegacyProject, len(iris))
+	for i, iri := range iris {
+		p := projects[iri]
+		p.Description = description(descriptions[iri])
+		p.Keywords = keywords(p.Keywords)
+		res[i] = *p
+	}
+	return res, nil
+}
+
+// description returns the English description, or else the one without a language, or else the first one.
+// The admin service does not support descriptions in several languages.
+func description(descriptions []langString) string {
+	if len(descriptions) == 0 {
+		return ""
+	}
+	best := descriptions[0]
+	for _, d := range descriptions {
+		if strings.EqualFold(d.Language, "en") {
+			best = d
+			break
+		}
+		if d.Language == "" && best.Language != "" {
+			best = d
+		}
+	}
+	return strings.TrimSpace(best.Value)
+}
+
+// keywords trims and sorts the keywords and removes empty ones, so that they can be compared
+// regardless of the order DSP-API returns them in.
+func keywords(values []string) []string {
+	var res []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// parseTime parses a xsd:dateTime, which may lack a time zone. Empty values are the zero time.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05", value)
+}
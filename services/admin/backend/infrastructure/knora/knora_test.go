@@ -0,0 +1,115 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package knora_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/knora"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/stretchr/testify/assert"
+)
+
+// anything is the anything project of the DSP-API test data.
+var anything = project.LegacyProject{
+	IRI:         "http://rdfh.ch/projects/0001",
+	ShortCode:   "0001",
+	ShortName:   "anything",
+	LongName:    "Anything Project",
+	Description: "Anything Project",
+	Keywords:    []string{"arbitrary test data", "things"},
+	Active:      true,
+}
+
+func TestReadJSON(t *testing.T) {
+	projects, err := knora.ReadJSON(strings.NewReader(`{"projects": [
+		{
+			"id": "http://rdfh.ch/projects/0001",
+			"shortname": "anything",
+			"shortcode": "0001",
+			"longname": "Anything Project",
+			"description": [{"value": "Irgendein Projekt", "language": "de"}, {"value": "Anything Project", "language": "en"}],
+			"keywords": ["things", "arbitrary test data"],
+			"logo": null,
+			"ontologies": ["http://www.knora.org/ontology/0001/anything"],
+			"status": true,
+			"selfjoin": false
+		},
+		{
+			"id": "http://rdfh.ch/projects/0002",
+			"shortname": "old",
+			"shortcode": "0002",
+			"longname": "Old Project",
+			"description": "An old project",
+			"status": false,
+			"created": "2016-03-01T12:00:00Z"
+		}
+	]}`))
+	assert.Nil(t, err)
+	assert.Len(t, projects, 2)
+	assert.Equal(t, anything, projects[0])
+	assert.Equal(t, "An old project", projects[1].Description)
+	assert.False(t, projects[1].Active)
+	assert.Equal(t, time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC), projects[1].CreatedAt)
+
+	// a single project
+	projects, err = knora.ReadJSON(strings.NewReader(`{"project": {"id": "http://rdfh.ch/projects/0001", "shortcode": "0001"}}`))
+	assert.Nil(t, err)
+	assert.Len(t, projects, 1)
+
+	_, err = knora.ReadJSON(strings.NewReader(`{"projects": [{"id": "http://rdfh.ch/projects/0001", "created": "yesterday"}]}`))
+	assert.NotNil(t, err)
+}
+
+func TestReadRDF(t *testing.T) {
+	projects, err := knora.ReadRDF(strings.NewReader(`
+@prefix knora-admin: <http://www.knora.org/ontology/knora-admin#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix dcterms: <http://purl.org/dc/terms/> .
+
+<http://www.knora.org/data/admin> {
+    <http://rdfh.ch/projects/0001> a knora-admin:knoraProject ;
+        knora-admin:projectShortname "anything"^^xsd:string ;
+        knora-admin:projectShortcode "0001"^^xsd:string ;
+        knora-admin:projectLongname "Anything Project"^^xsd:string ;
+        knora-admin:projectDescription "Irgendein Projekt"@de, "Anything Project"@en ;
+        knora-admin:projectKeyword "things"^^xsd:string, "arbitrary test data"^^xsd:string ;
+        knora-admin:status "true"^^xsd:boolean ;
+        knora-admin:hasSelfJoinEnabled "false"^^xsd:boolean .
+
+    <http://rdfh.ch/users/root> a knora-admin:User ;
+        knora-admin:status true .
+
+    <http://rdfh.ch/projects/0002> a knora-admin:knoraProject ;
+        knora-admin:projectShortcode "0002" ;
+        knora-admin:status false ;
+        dcterms:created "2016-03-01T12:00:00"^^xsd:dateTime .
+}
+`))
+	assert.Nil(t, err)
+	assert.Len(t, projects, 2)
+	assert.Equal(t, anything, projects[0])
+	assert.Equal(t, "0002", projects[1].ShortCode)
+	assert.False(t, projects[1].Active)
+	assert.Equal(t, time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC), projects[1].CreatedAt)
+
+	_, err = knora.ReadRDF(strings.NewReader(`<http://rdfh.ch/projects/0001> a _:b .`))
+	assert.NotNil(t, err)
+}
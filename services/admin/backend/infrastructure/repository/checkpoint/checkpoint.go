@@ -0,0 +1,98 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EventStore/EventStore-Client-Go/client"
+	"github.com/EventStore/EventStore-Client-Go/direction"
+	esErrors "github.com/EventStore/EventStore-Client-Go/errors"
+	"github.com/EventStore/EventStore-Client-Go/messages"
+	"github.com/EventStore/EventStore-Client-Go/streamrevision"
+	"github.com/gofrs/uuid"
+)
+
+// streamPrefix is the prefix of the names of all checkpoint streams.
+const streamPrefix = "Checkpoint-"
+
+// checkpoint is the event recorded for every checkpoint, only the last one of a stream is ever read.
+type checkpoint struct {
+	LastEventID string `json:"lastEventId"`
+}
+
+// checkpointRepository contains a pointer to the client and the name of the stream the checkpoints are recorded in.
+type checkpointRepository struct {
+	c        *client.Client
+	streamID string
+}
+
+// NewCheckpointRepository creates a new repository to record the checkpoints of the consumer with the provided name in.
+func NewCheckpointRepository(client *client.Client, name string) *checkpointRepository {
+	return &checkpointRepository{
+		c:        client,
+		streamID: streamPrefix + name,
+	}
+}
+
+// LoadCheckpoint returns the id of the last event processed by the consumer,
+// or an empty string if no checkpoint has been recorded yet.
+func (r *checkpointRepository) LoadCheckpoint(ctx context.Context) (string, error) {
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Backwards, r.streamID, streamrevision.StreamRevisionEnd, 1, false)
+	if err == esErrors.ErrStreamNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("problem reading stream '%s': %w", r.streamID, err)
+	}
+
+	if len(recordedEvents) == 0 {
+		return "", nil
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(recordedEvents[0].Data, &cp); err != nil {
+		return "", fmt.Errorf("problem deserializing '%s' event from json", recordedEvents[0].EventType)
+	}
+
+	return cp.LastEventID, nil
+}
+
+// SaveCheckpoint records the id of the last event processed by the consumer.
+func (r *checkpointRepository) SaveCheckpoint(ctx context.Context, lastEventID string) error {
+	j, err := json.Marshal(checkpoint{LastEventID: lastEventID})
+	if err != nil {
+		return fmt.Errorf("problem serializing checkpoint to json")
+	}
+
+	eventID, _ := uuid.NewV4()
+	pe := messages.ProposedEvent{
+		EventID:     eventID,
+		EventType:   "CheckpointRecorded",
+		ContentType: "application/json",
+		Data:        j,
+	}
+
+	if _, err := r.c.AppendToStream(ctx, r.streamID, streamrevision.StreamRevisionAny, []messages.ProposedEvent{pe}); err != nil {
+		return fmt.Errorf("problem appending events to stream '%s': %w", r.streamID, err)
+	}
+
+	return nil
+}
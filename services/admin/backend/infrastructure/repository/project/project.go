@@ -19,36 +19,80 @@ package project
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/EventStore/EventStore-Client-Go/position"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/EventStore/EventStore-Client-Go/client"
 	"github.com/EventStore/EventStore-Client-Go/direction"
+	esErrors "github.com/EventStore/EventStore-Client-Go/errors"
 	"github.com/EventStore/EventStore-Client-Go/messages"
+	"github.com/EventStore/EventStore-Client-Go/position"
 	"github.com/EventStore/EventStore-Client-Go/streamrevision"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/archive"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/search"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/shredding"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 	"github.com/gofrs/uuid"
 )
 
-// projectRepository contains a pointer to the client.
+// streamPrefix is the prefix of the names of all project streams.
+const streamPrefix = "Project-"
+
+// snapshotStreamPrefix is the prefix of the names of the streams holding the snapshots of the projects.
+// It must not start with streamPrefix, so that snapshots are not mistaken for project events.
+const snapshotStreamPrefix = "Snapshot-Project-"
+
+// pageSize is the number of events read from the event store at once.
+const pageSize = 1000
+
+// resubscribeDelay is the delay before a dropped subscription is renewed, which doubles with every failed attempt up to maxResubscribeDelay.
+const resubscribeDelay = time.Second
+
+// maxResubscribeDelay is the longest delay between two attempts to renew a dropped subscription.
+const maxResubscribeDelay = 30 * time.Second
+
+// checkpointStreamID is the stream holding the checkpoints over all project events.
+// It must not start with streamPrefix, so that checkpoints are not mistaken for project events.
+const checkpointStreamID = "Integrity-Projects"
+
+// errUnexpectedEventType is returned by decodeEvent for records which are not project events.
+var errUnexpectedEventType = errors.New("unexpected event type")
+
+// eventMetadata is the metadata stored with every project event.
+type eventMetadata struct {
+	// Chain links the event to the previous event of its stream, it is missing for events stored before chaining was introduced.
+	Chain *integrity.Link `json:"chain,omitempty"`
+}
+
+// projectRepository contains a pointer to the client, the full-text index of the projects
+// and the protector of the personal data in the events.
 type projectRepository struct {
-	c *client.Client
+	c         *client.Client
+	index     *search.ProjectIndex
+	protector *shredding.Protector
 }
 
 // NewProjectRepository creates a new repository to store project events in.
-func NewProjectRepository(client *client.Client) *projectRepository {
+// The actors of the events are encrypted by the protector, see subjectkey.NewProtector.
+// The search index of the repository stays empty until StartIndexing is called.
+func NewProjectRepository(client *client.Client, protector *shredding.Protector) *projectRepository {
 	return &projectRepository{
-		c: client,
+		c:         client,
+		index:     search.NewProjectIndex(),
+		protector: protector,
 	}
 }
 
 // Save stores the project events in the projectRepository.
-func (r *projectRepository) Save(ctv context.Context, p *project.Aggregate) (valueobject.Identifier, error) {
+func (r *projectRepository) Save(ctx context.Context, p *project.Aggregate) (valueobject.Identifier, error) {
 	var proposedEvents []messages.ProposedEvent
 	streamRevision := streamrevision.StreamRevisionStreamExists
 
@@ -101,107 +145,809 @@ func (r *projectRepository) Save(ctv context.Context, p *project.Aggregate) (val
 				Data:        j,
 			}
 
+			proposedEvents = append(proposedEvents, pe)
+
+		case *event.ProjectRestored:
+			j, err := json.Marshal(e)
+			if err != nil {
+				return e.ID, fmt.Errorf("problem serializing '%T' event to json", e)
+			}
+
+			eventID, _ := uuid.NewV4()
+			pe := messages.ProposedEvent{
+				EventID:     eventID,
+				EventType:   "ProjectRestored",
+				ContentType: "application/json",
+				Data:        j,
+			}
+
 			proposedEvents = append(proposedEvents, pe)
 		}
 
 	}
 
-	streamID := "Project-" + p.ID().String()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
-	defer cancel()
+	streamID := streamPrefix + p.ID().String()
+
+	// every event is chained to the one before it, which must still be the last event of the stream when appending
+	previousHash := ""
+	if streamRevision != streamrevision.StreamRevisionNoStream {
+		recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Backwards, streamID, streamrevision.StreamRevisionEnd, 1, false)
+		if err != nil {
+			return p.ID(), fmt.Errorf("problem reading stream '%s': %w", streamID, err)
+		}
+		if len(recordedEvents) > 0 {
+			last := recordedEvents[0]
+			streamRevision = streamrevision.NewStreamRevision(last.EventNumber)
+			if link := decodeLink(last); link != nil {
+				previousHash = link.Hash
+			}
+		}
+	}
+
+	for i, pe := range proposedEvents {
+		data, err := r.protector.Protect(ctx, pe.Data)
+		if err != nil {
+			return p.ID(), fmt.Errorf("problem encrypting the personal data of '%s' event: %w", pe.EventType, err)
+		}
+		proposedEvents[i].Data = data
+
+		link := integrity.NewLink(previousHash, streamID, pe.EventID.String(), pe.EventType, data)
+		j, err := json.Marshal(eventMetadata{Chain: &link})
+		if err != nil {
+			return p.ID(), fmt.Errorf("problem serializing metadata of '%s' event to json", pe.EventType)
+		}
+		proposedEvents[i].UserMetadata = j
+		previousHash = link.Hash
+	}
 
 	_, err := r.c.AppendToStream(ctx, streamID, streamRevision, proposedEvents)
 	if err != nil {
-		log.Fatalf("Unexpected failure %+v", err)
+		return p.ID(), fmt.Errorf("problem appending events to stream '%s': %w", streamID, err)
 	}
 
 	return p.ID(), nil
 }
 
-// Load reads the events from the event store and recreates a project aggregate.
+// Load recreates a project aggregate from its latest snapshot, if any, and the events recorded after it.
 func (r *projectRepository) Load(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error) {
-	streamID := "Project-" + id.String()
+	p, _, err := r.load(ctx, id)
+	if err != nil {
+		return &project.Aggregate{}, err
+	}
+	return p, nil
+}
 
-	// TODO: figure out the correct way to replay all the events, currently hardcoded to replay the last 1000 events
-	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, streamrevision.StreamRevisionStart, 1000, false)
+// load recreates a project aggregate like Load, and also returns the revision of the last event of the stream it covers.
+func (r *projectRepository) load(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, uint64, error) {
+	snapshot, err := r.loadSnapshot(ctx, id)
 	if err != nil {
-		log.Printf("Unexpected failure %+v", err)
-		return &project.Aggregate{}, project.ErrProjectNotFound
+		log.Printf("ignoring snapshot of project %s: %v", id, err)
+		snapshot = nil
+	}
+	if snapshot != nil && snapshot.Revision == nil {
+		log.Printf("ignoring snapshot of project %s: it does not record the revision of its last event", id)
+		snapshot = nil
+	}
+
+	if snapshot == nil {
+		events, last, err := r.readEvents(ctx, id, streamrevision.StreamRevisionStart)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(events) == 0 {
+			return nil, 0, project.ErrProjectNotFound
+		}
+		return project.NewAggregateFromEvents(events), *last, nil
 	}
 
+	events, last, err := r.readEvents(ctx, id, *snapshot.Revision+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	if last == nil {
+		last = snapshot.Revision
+	}
+
+	return project.NewAggregateFromSnapshot(*snapshot, events), *last, nil
+}
+
+// LoadEvents reads all events of the project from the event store, oldest first.
+func (r *projectRepository) LoadEvents(ctx context.Context, id valueobject.Identifier) ([]event.Event, error) {
+	events, _, err := r.readEvents(ctx, id, streamrevision.StreamRevisionStart)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, project.ErrProjectNotFound
+	}
+
+	return events, nil
+}
+
+// readEvents reads the events of the project starting at the provided stream revision, oldest first.
+// It also returns the revision of the last event read, or nil if there is none.
+// The revisions of a stream start after the deleted events if the stream was overwritten, so the pages
+// are read from the revision following the last event read, not from multiples of the page size.
+func (r *projectRepository) readEvents(ctx context.Context, id valueobject.Identifier, from uint64) ([]event.Event, *uint64, error) {
+	streamID := streamPrefix + id.String()
+
 	var events []event.Event
+	var last *uint64
 
-	for _, record := range recordedEvents {
-		switch eventType := record.EventType; eventType {
-		case "ProjectCreated":
-			var e event.ProjectCreated
-			err := json.Unmarshal(record.Data, &e)
-			if err != nil {
-				return &project.Aggregate{}, fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
-			}
-			events = append(events, &e)
-		case "ProjectChanged":
-			var e event.ProjectChanged
-			err := json.Unmarshal(record.Data, &e)
-			if err != nil {
-				return &project.Aggregate{}, fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+	for {
+		recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, from, pageSize, false)
+		if err != nil {
+			log.Printf("Unexpected failure %+v", err)
+			return nil, nil, project.ErrProjectNotFound
+		}
+
+		for _, record := range recordedEvents {
+			revision := record.EventNumber
+			last = &revision
+
+			e, err := r.decodeEvent(ctx, record)
+			if err == errUnexpectedEventType {
+				log.Printf("unexpected event type: %s", record.EventType)
+				continue
 			}
-			events = append(events, &e)
-		case "ProjectDeleted":
-			var e event.ProjectDeleted
-			err := json.Unmarshal(record.Data, &e)
 			if err != nil {
-				return &project.Aggregate{}, fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+				return nil, nil, err
 			}
-			events = append(events, &e)
-		default:
-			log.Printf("unexpected event type: %T", eventType)
+			events = append(events, e)
+		}
+
+		if len(recordedEvents) < pageSize {
+			return events, last, nil
+		}
+		from = *last + 1
+	}
+}
+
+// SaveSnapshot stores a snapshot of the current state of the project,
+// so that it can be loaded without replaying the events recorded up to now.
+func (r *projectRepository) SaveSnapshot(ctx context.Context, id valueobject.Identifier) error {
+	p, revision, err := r.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	snapshot := p.Snapshot()
+	snapshot.Revision = &revision
+	j, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("problem serializing snapshot of project '%s' to json", id)
+	}
+	if j, err = r.protector.Protect(ctx, j); err != nil {
+		return fmt.Errorf("problem encrypting the personal data of the snapshot of project '%s': %w", id, err)
+	}
+
+	eventID, _ := uuid.NewV4()
+	se := messages.ProposedEvent{
+		EventID:     eventID,
+		EventType:   "ProjectSnapshot",
+		ContentType: "application/json",
+		Data:        j,
+	}
+
+	// only the latest snapshot is read, the older ones are deleted so that they do not keep personal data which has been erased since
+	streamID := snapshotStreamPrefix + id.String()
+	if _, err := r.c.DeleteStream(ctx, streamID, streamrevision.StreamRevisionAny); err != nil && !errors.Is(err, esErrors.ErrStreamNotFound) {
+		return fmt.Errorf("problem deleting stream '%s': %w", streamID, err)
+	}
+	if _, err := r.c.AppendToStream(ctx, streamID, streamrevision.StreamRevisionAny, []messages.ProposedEvent{se}); err != nil {
+		return fmt.Errorf("problem appending snapshot to stream '%s': %w", streamID, err)
+	}
+
+	return nil
+}
+
+// RebuildSnapshots stores a fresh snapshot of every project, including the deleted ones,
+// and returns the number of snapshots stored.
+func (r *projectRepository) RebuildSnapshots(ctx context.Context) (int, error) {
+	ids, err := r.GetProjectIds(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+
+	for n, id := range ids {
+		if err := r.SaveSnapshot(ctx, id); err != nil {
+			return n, err
 		}
+	}
 
+	return len(ids), nil
+}
+
+// ForgetActor erases the personal data of the user or service account from the events, by destroying its keys.
+// Since the protector is shared, this covers the service account and webhook events and the outbox of the webhooks as well.
+// The snapshots are rebuilt, so that none of them keeps the actor. It returns false if no event recorded the actor.
+func (r *projectRepository) ForgetActor(ctx context.Context, id valueobject.Identifier) (bool, error) {
+	forgotten, err := r.protector.Forget(ctx, id.String())
+	if err != nil {
+		return false, fmt.Errorf("problem destroying the keys of actor '%s': %w", id, err)
+	}
+
+	if _, err := r.RebuildSnapshots(ctx); err != nil {
+		return forgotten, err
 	}
 
-	return project.NewAggregateFromEvents(events), nil
+	return forgotten, nil
 }
 
-// GetProjectIds returns a list of all active project ids.
-// returnDeletedProjects can be used to also return projects marked as deleted in the list.
-func (r *projectRepository) GetProjectIds(ctx context.Context, returnDeletedProjects bool) ([]valueobject.Identifier, error) {
-	numberOfEventsToRead := 1000
-	numberOfEvents := uint64(numberOfEventsToRead)
+// loadSnapshot reads the latest snapshot of the project, or returns nil if none was stored.
+func (r *projectRepository) loadSnapshot(ctx context.Context, id valueobject.Identifier) (*project.Snapshot, error) {
+	streamID := snapshotStreamPrefix + id.String()
+
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Backwards, streamID, streamrevision.StreamRevisionEnd, 1, false)
+	if errors.Is(err, esErrors.ErrStreamNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(recordedEvents) == 0 {
+		return nil, nil
+	}
 
-	recordedEvents, err := r.c.ReadAllEvents(ctx, direction.Forwards, position.StartPosition, numberOfEvents, true)
+	data, err := r.protector.Reveal(ctx, recordedEvents[0].Data)
 	if err != nil {
-		log.Printf("Unexpected failure %+v", err)
 		return nil, err
 	}
 
+	var s project.Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("problem deserializing snapshot from json: %w", err)
+	}
+
+	return &s, nil
+}
+
+// Ping checks that the event store can be read.
+func (r *projectRepository) Ping(ctx context.Context) error {
+	if _, err := r.c.ReadAllEvents(ctx, direction.Backwards, position.EndPosition, 1, false); err != nil {
+		return fmt.Errorf("problem reading from the event store: %w", err)
+	}
+	return nil
+}
+
+// GetProjectIds returns a list of all active project ids.
+// returnDeletedProjects can be used to also return projects marked as deleted in the list.
+func (r *projectRepository) GetProjectIds(ctx context.Context, returnDeletedProjects bool) ([]valueobject.Identifier, error) {
 	var projectIds []valueobject.Identifier
 
-	// filter to select only ProjectCreated events
-	for _, record := range recordedEvents {
+	// filter to select only the events creating, deleting or restoring a project
+	err := r.readProjectRecords(ctx, func(record messages.RecordedEvent) error {
 		switch eventType := record.EventType; eventType {
 		case "ProjectCreated":
 			var e event.ProjectCreated
-			err := json.Unmarshal(record.Data, &e)
+			err := r.unmarshalEvent(ctx, record, &e)
 			if err != nil {
-				return []valueobject.Identifier{}, fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+				return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
 			}
 			projectIds = append(projectIds, e.ID)
 		case "ProjectDeleted":
 			var e event.ProjectDeleted
-			err := json.Unmarshal(record.Data, &e)
+			err := r.unmarshalEvent(ctx, record, &e)
 			if err != nil {
-				return []valueobject.Identifier{}, fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+				return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
 			}
 			if !returnDeletedProjects { // if deleted project should not be returned
 				for i := range projectIds { // loop through the project ids
 					if projectIds[i] == e.ID { // if a deleted project is found among the project ids
 						projectIds = append(projectIds[:i], projectIds[i+1:]...) // remove it
+						break
 					}
 				}
 			}
+		case "ProjectRestored":
+			var e event.ProjectRestored
+			err := r.unmarshalEvent(ctx, record, &e)
+			if err != nil {
+				return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+			}
+			if !returnDeletedProjects { // restored projects are listed again, after the ones which were never deleted
+				projectIds = append(projectIds, e.ID)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Unexpected failure %+v", err)
+		return []valueobject.Identifier{}, err
 	}
 
 	return projectIds, nil
 }
+
+// Search returns the ids of the active projects matching the query, best matches first.
+// If limit is greater than zero, at most limit ids are returned.
+func (r *projectRepository) Search(ctx context.Context, query string, limit int) ([]valueobject.Identifier, error) {
+	return r.index.Search(query, limit), nil
+}
+
+// RebuildIndex replaces the search index with one built by replaying all project events recorded up to now,
+// and returns the number of events replayed.
+func (r *projectRepository) RebuildIndex(ctx context.Context) (int, error) {
+	var events []event.Event
+
+	err := r.readProjectRecords(ctx, func(record messages.RecordedEvent) error {
+		e, err := r.decodeEvent(ctx, record)
+		if err != nil {
+			log.Printf("skipping event %s of stream %s: %v", record.EventID, record.StreamID, err)
+			return nil
+		}
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.index.Rebuild(events)
+
+	return len(events), nil
+}
+
+// readProjectRecords calls handle with every record of the project streams, in the order they were appended.
+// The records deleted from a stream which was overwritten stay in $all until the event store is scavenged,
+// they are skipped.
+func (r *projectRepository) readProjectRecords(ctx context.Context, handle func(record messages.RecordedEvent) error) error {
+	truncated := map[string]uint64{}
+
+	from := position.StartPosition
+	for {
+		recordedEvents, err := r.c.ReadAllEvents(ctx, direction.Forwards, from, pageSize, false)
+		if err != nil {
+			return fmt.Errorf("problem reading project events: %w", err)
+		}
+
+		for _, record := range recordedEvents {
+			if record.Position == from || !strings.HasPrefix(record.StreamID, streamPrefix) {
+				continue
+			}
+
+			before, ok := truncated[record.StreamID]
+			if !ok {
+				if before, err = r.truncateBefore(ctx, record.StreamID); err != nil {
+					return err
+				}
+				truncated[record.StreamID] = before
+			}
+			if record.EventNumber < before {
+				continue
+			}
+
+			if err := handle(record); err != nil {
+				return err
+			}
+		}
+
+		if len(recordedEvents) < pageSize {
+			return nil
+		}
+		from = recordedEvents[len(recordedEvents)-1].Position
+	}
+}
+
+// streamMetadata is the part of the metadata of a stream set by the event store when the stream is deleted.
+type streamMetadata struct {
+	// TruncateBefore is the first revision of the stream which has not been deleted.
+	TruncateBefore *uint64 `json:"$tb"`
+}
+
+// truncateBefore returns the first revision of the stream which has not been deleted, which is 0 unless it was.
+func (r *projectRepository) truncateBefore(ctx context.Context, stream string) (uint64, error) {
+	metadataStream := "$$" + stream
+
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Backwards, metadataStream, streamrevision.StreamRevisionEnd, 1, false)
+	if errors.Is(err, esErrors.ErrStreamNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("problem reading stream '%s': %w", metadataStream, err)
+	}
+	if len(recordedEvents) == 0 {
+		return 0, nil
+	}
+
+	var m streamMetadata
+	if err := json.Unmarshal(recordedEvents[0].Data, &m); err != nil {
+		return 0, fmt.Errorf("problem deserializing metadata of stream '%s' from json: %w", stream, err)
+	}
+	if m.TruncateBefore == nil {
+		return 0, nil
+	}
+	return *m.TruncateBefore, nil
+}
+
+// ExportEvents calls handle with every project event in the order they were appended.
+// Snapshots are not exported, since they can be rebuilt from the events.
+// The events are numbered from 0 within their stream, even if the revisions of an overwritten stream start later.
+func (r *projectRepository) ExportEvents(ctx context.Context, handle func(e archive.Event) error) error {
+	revisions := map[string]uint64{}
+
+	return r.readProjectRecords(ctx, func(record messages.RecordedEvent) error {
+		revision := revisions[record.StreamID]
+		revisions[record.StreamID] = revision + 1
+
+		return handle(archive.Event{
+			Stream:      record.StreamID,
+			Revision:    revision,
+			ID:          record.EventID.String(),
+			Type:        record.EventType,
+			ContentType: record.ContentType,
+			CreatedAt:   record.CreatedDate,
+			Data:        record.Data,
+			Metadata:    record.UserMetadata,
+		})
+	})
+}
+
+// SubjectKeys returns the keys of the actors of an exported event, which are encrypted in its data.
+func (r *projectRepository) SubjectKeys(ctx context.Context, e archive.Event) ([]archive.Key, error) {
+	if e.ContentType != "application/json" {
+		return nil, nil
+	}
+
+	subjectKeys, err := r.protector.SubjectKeys(ctx, e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading the keys of event '%s': %w", e.ID, err)
+	}
+
+	keys := make([]archive.Key, 0, len(subjectKeys))
+	for _, k := range subjectKeys {
+		keys = append(keys, archive.Key{ID: k.Key.ID, Subject: k.Subject, Secret: k.Key.Secret})
+	}
+	return keys, nil
+}
+
+// ImportKey stores the key of an actor from an archive. It returns false if the key has been destroyed in this event store.
+func (r *projectRepository) ImportKey(ctx context.Context, k archive.Key) (bool, error) {
+	return r.protector.ImportKey(ctx, shredding.SubjectKey{Subject: k.Subject, Key: shredding.Key{ID: k.ID, Secret: k.Secret}})
+}
+
+// StreamExists checks if the event store contains events of the stream.
+func (r *projectRepository) StreamExists(ctx context.Context, stream string) (bool, error) {
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, stream, streamrevision.StreamRevisionStart, 1, false)
+	if errors.Is(err, esErrors.ErrStreamNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("problem reading stream '%s': %w", stream, err)
+	}
+
+	return len(recordedEvents) > 0, nil
+}
+
+// ImportEvents appends events of an archive to a project stream, keeping their ids.
+// If overwrite is set, the events and the snapshots recorded so far are deleted first. The revisions of the imported events
+// then follow the deleted ones, which readProjectRecords skips.
+func (r *projectRepository) ImportEvents(ctx context.Context, stream string, events []archive.Event, overwrite bool) error {
+	if !strings.HasPrefix(stream, streamPrefix) {
+		return fmt.Errorf("stream '%s' is not a project stream", stream)
+	}
+
+	if overwrite {
+		if _, err := r.c.DeleteStream(ctx, stream, streamrevision.StreamRevisionAny); err != nil && !errors.Is(err, esErrors.ErrStreamNotFound) {
+			return fmt.Errorf("problem deleting stream '%s': %w", stream, err)
+		}
+		// the snapshots would refer to revisions of the deleted events
+		snapshots := snapshotStreamPrefix + strings.TrimPrefix(stream, streamPrefix)
+		if _, err := r.c.DeleteStream(ctx, snapshots, streamrevision.StreamRevisionAny); err != nil && !errors.Is(err, esErrors.ErrStreamNotFound) {
+			return fmt.Errorf("problem deleting stream '%s': %w", snapshots, err)
+		}
+	}
+
+	proposedEvents := make([]messages.ProposedEvent, 0, len(events))
+	for _, e := range events {
+		eventID, err := uuid.FromString(e.ID)
+		if err != nil {
+			return fmt.Errorf("invalid id '%s' of event %d: %w", e.ID, e.Revision, err)
+		}
+		proposedEvents = append(proposedEvents, messages.ProposedEvent{
+			EventID:      eventID,
+			EventType:    e.Type,
+			ContentType:  e.ContentType,
+			Data:         e.Data,
+			UserMetadata: e.Metadata,
+		})
+	}
+
+	if _, err := r.c.AppendToStream(ctx, stream, streamrevision.StreamRevisionAny, proposedEvents); err != nil {
+		return fmt.Errorf("problem appending events to stream '%s': %w", stream, err)
+	}
+
+	return nil
+}
+
+// Verify walks the chain of every project stream, or of the stream of the project if an id is provided, and reports every break.
+// Without an id, the checkpoints are verified as well, and their signatures with the public key if one is provided.
+func (r *projectRepository) Verify(ctx context.Context, id *valueobject.Identifier, key ed25519.PublicKey) (integrity.Report, error) {
+	if id != nil {
+		v := integrity.NewVerifier(nil, nil)
+		streamID := streamPrefix + id.String()
+		from := streamrevision.StreamRevisionStart
+		for {
+			recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, from, pageSize, false)
+			if errors.Is(err, esErrors.ErrStreamNotFound) {
+				return integrity.Report{}, project.ErrProjectNotFound
+			}
+			if err != nil {
+				return integrity.Report{}, fmt.Errorf("problem reading stream '%s': %w", streamID, err)
+			}
+
+			for _, record := range recordedEvents {
+				v.Add(integrityRecord(record))
+			}
+
+			if len(recordedEvents) < pageSize {
+				break
+			}
+			from = recordedEvents[len(recordedEvents)-1].EventNumber + 1
+		}
+
+		report := v.Report()
+		if report.Events == 0 {
+			return integrity.Report{}, project.ErrProjectNotFound
+		}
+		return report, nil
+	}
+
+	checkpoints, err := r.loadCheckpoints(ctx)
+	if err != nil {
+		return integrity.Report{}, err
+	}
+
+	v := integrity.NewVerifier(checkpoints, key)
+	err = r.readProjectRecords(ctx, func(record messages.RecordedEvent) error {
+		v.Add(integrityRecord(record))
+		return nil
+	})
+	if err != nil {
+		return integrity.Report{}, err
+	}
+
+	return v.Report(), nil
+}
+
+// SaveCheckpoint stores a checkpoint over all project events recorded up to now, signed with the private key if one is provided.
+func (r *projectRepository) SaveCheckpoint(ctx context.Context, key ed25519.PrivateKey) (integrity.Checkpoint, error) {
+	v := integrity.NewVerifier(nil, nil)
+	err := r.readProjectRecords(ctx, func(record messages.RecordedEvent) error {
+		v.Add(integrityRecord(record))
+		return nil
+	})
+	if err != nil {
+		return integrity.Checkpoint{}, err
+	}
+
+	checkpoint := v.Checkpoint()
+	if key != nil {
+		checkpoint.Sign(key)
+	}
+
+	j, err := json.Marshal(checkpoint)
+	if err != nil {
+		return integrity.Checkpoint{}, fmt.Errorf("problem serializing checkpoint to json")
+	}
+
+	eventID, _ := uuid.NewV4()
+	ce := messages.ProposedEvent{
+		EventID:     eventID,
+		EventType:   "ProjectLogCheckpoint",
+		ContentType: "application/json",
+		Data:        j,
+	}
+
+	if _, err := r.c.AppendToStream(ctx, checkpointStreamID, streamrevision.StreamRevisionAny, []messages.ProposedEvent{ce}); err != nil {
+		return integrity.Checkpoint{}, fmt.Errorf("problem appending checkpoint to stream '%s': %w", checkpointStreamID, err)
+	}
+
+	return checkpoint, nil
+}
+
+// loadCheckpoints reads all checkpoints stored so far, oldest first.
+func (r *projectRepository) loadCheckpoints(ctx context.Context) ([]integrity.Checkpoint, error) {
+	var checkpoints []integrity.Checkpoint
+
+	from := streamrevision.StreamRevisionStart
+	for {
+		recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, checkpointStreamID, from, pageSize, false)
+		if errors.Is(err, esErrors.ErrStreamNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("problem reading stream '%s': %w", checkpointStreamID, err)
+		}
+
+		for _, record := range recordedEvents {
+			var c integrity.Checkpoint
+			if err := json.Unmarshal(record.Data, &c); err != nil {
+				return nil, fmt.Errorf("problem deserializing checkpoint from json: %w", err)
+			}
+			checkpoints = append(checkpoints, c)
+		}
+
+		if len(recordedEvents) < pageSize {
+			return checkpoints, nil
+		}
+		from += pageSize
+	}
+}
+
+// integrityRecord returns the record of a stored event to be verified.
+func integrityRecord(record messages.RecordedEvent) integrity.Record {
+	return integrity.Record{
+		Stream:   record.StreamID,
+		Revision: record.EventNumber,
+		ID:       record.EventID.String(),
+		Type:     record.EventType,
+		Data:     record.Data,
+		Link:     decodeLink(record),
+	}
+}
+
+// decodeLink returns the link of a stored event to the previous event of its stream, or nil if it has none.
+func decodeLink(record messages.RecordedEvent) *integrity.Link {
+	var m eventMetadata
+	if len(record.UserMetadata) == 0 || json.Unmarshal(record.UserMetadata, &m) != nil {
+		return nil
+	}
+	return m.Chain
+}
+
+// StartIndexing builds the search index from all project events in the event store and keeps it up to date
+// with the events appended later on, until the context is done.
+func (r *projectRepository) StartIndexing(ctx context.Context) error {
+	return r.Subscribe(ctx, r.index.Apply)
+}
+
+// Subscribe passes every project event to the handler, starting with the first event ever recorded.
+// New events are passed to the handler as they are appended to the event store, until the context is done.
+// The handler is called from a single goroutine, in the order the events were recorded.
+func (r *projectRepository) Subscribe(ctx context.Context, handle func(ev event.Event)) error {
+	return r.subscribe(ctx, position.StartPosition, func(_ position.Position, ev event.Event) { handle(ev) })
+}
+
+// Watch passes the project events appended to the event store from now on to the handler, until the context is done.
+// The handler is called from a single goroutine, in the order the events were recorded.
+func (r *projectRepository) Watch(ctx context.Context, handle func(ev event.Event)) error {
+	return r.subscribe(ctx, position.EndPosition, func(_ position.Position, ev event.Event) { handle(ev) })
+}
+
+// WatchSince passes the project events appended to the event store after the event with the provided id to the handler,
+// or from now on if no id is provided, until the context is done.
+// The id of an event is its position in the event store, formatted as `C:<commit>/P:<prepare>`.
+// The handler is called from a single goroutine, in the order the events were recorded.
+func (r *projectRepository) WatchSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error {
+	from := position.EndPosition
+	if lastEventID != "" {
+		var err error
+		if from, err = parsePosition(lastEventID); err != nil {
+			return err
+		}
+	}
+
+	return r.subscribe(ctx, from, func(pos position.Position, ev event.Event) { handle(formatPosition(pos), ev) })
+}
+
+// subscribe passes the project events recorded after the provided position to the handler, together with their position.
+// If the subscription is dropped, e.g. because the connection to the event store was lost, it is renewed with a growing delay,
+// from the position of the last event seen, until the context is done.
+func (r *projectRepository) subscribe(ctx context.Context, from position.Position, handle func(pos position.Position, ev event.Event)) error {
+	// the end is resolved to the position of the last event, so that no event appended while resubscribing is missed
+	if from == position.EndPosition {
+		recordedEvents, err := r.c.ReadAllEvents(ctx, direction.Backwards, position.EndPosition, 1, false)
+		if err != nil {
+			return fmt.Errorf("problem subscribing to project events: %w", err)
+		}
+		from = position.StartPosition
+		if len(recordedEvents) > 0 {
+			from = recordedEvents[0].Position
+		}
+	}
+
+	// last is only accessed by the goroutine of the current subscription, which starts the next one when it is dropped
+	last := from
+
+	eventAppeared := func(record messages.RecordedEvent) {
+		last = record.Position
+		if !strings.HasPrefix(record.StreamID, streamPrefix) {
+			return
+		}
+		e, err := r.decodeEvent(ctx, record)
+		if err != nil {
+			log.Printf("skipping event %s of stream %s: %v", record.EventID, record.StreamID, err)
+			return
+		}
+		handle(record.Position, e)
+	}
+
+	var start func() error
+	dropped := func(reason string) {
+		delay := resubscribeDelay
+		for ctx.Err() == nil {
+			log.Printf("project subscription dropped, resubscribing in %s: %s", delay, reason)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			err := start()
+			if err == nil {
+				return
+			}
+			reason = err.Error()
+
+			if delay *= 2; delay > maxResubscribeDelay {
+				delay = maxResubscribeDelay
+			}
+		}
+	}
+
+	start = func() error {
+		sub, err := r.c.SubscribeToAll(ctx, last, false, eventAppeared, nil, dropped)
+		if err != nil {
+			return fmt.Errorf("problem subscribing to project events: %w", err)
+		}
+		return sub.Start()
+	}
+
+	return start()
+}
+
+// formatPosition formats the position of an event in the event store as its id.
+func formatPosition(pos position.Position) string {
+	return fmt.Sprintf("C:%d/P:%d", pos.Commit, pos.Prepare)
+}
+
+// parsePosition parses the id of an event into its position in the event store.
+func parsePosition(id string) (position.Position, error) {
+	var pos position.Position
+	if n, err := fmt.Sscanf(id, "C:%d/P:%d", &pos.Commit, &pos.Prepare); err != nil || n != 2 || formatPosition(pos) != id {
+		return position.Position{}, project.ErrInvalidEventID
+	}
+	return pos, nil
+}
+
+// unmarshalEvent decrypts the personal data of the recorded event and deserializes it into the provided event.
+func (r *projectRepository) unmarshalEvent(ctx context.Context, record messages.RecordedEvent, e event.Event) error {
+	data, err := r.protector.Reveal(ctx, record.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, e)
+}
+
+// decodeEvent decrypts the personal data of the recorded event and deserializes it into the corresponding project event.
+func (r *projectRepository) decodeEvent(ctx context.Context, record messages.RecordedEvent) (event.Event, error) {
+	var e event.Event
+	switch record.EventType {
+	case "ProjectCreated":
+		e = &event.ProjectCreated{}
+	case "ProjectChanged":
+		e = &event.ProjectChanged{}
+	case "ProjectDeleted":
+		e = &event.ProjectDeleted{}
+	case "ProjectRestored":
+		e = &event.ProjectRestored{}
+	case "ProjectShortCodeChanged":
+		e = &event.ProjectShortCodeChanged{}
+	case "ProjectShortNameChanged":
+		e = &event.ProjectShortNameChanged{}
+	case "ProjectLongNameChanged":
+		e = &event.ProjectLongNameChanged{}
+	case "ProjectDescriptionChanged":
+		e = &event.ProjectDescriptionChanged{}
+	default:
+		return nil, errUnexpectedEventType
+	}
+
+	if err := r.unmarshalEvent(ctx, record, e); err != nil {
+		return nil, fmt.Errorf("problem deserializing '%s' event from json: %w", record.EventType, err)
+	}
+
+	return e, nil
+}
@@ -17,14 +17,21 @@
 package project_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/EventStore/EventStore-Client-Go/direction"
 	"github.com/EventStore/EventStore-Client-Go/streamrevision"
 	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event/integrity"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/archive"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/repository/subjectkey"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 	"github.com/stretchr/testify/assert"
 )
@@ -36,7 +43,7 @@ func TestProjectRepository_Save(t *testing.T) {
 	c := CreateTestClient(container, t)
 	defer c.Close()
 
-	r := project.NewProjectRepository(c)
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
 	defer cancel()
@@ -48,7 +55,7 @@ func TestProjectRepository_Save(t *testing.T) {
 	description, _ := valueobject.NewDescription("project description")
 
 	// create new project
-	expectedProject := projectEntity.NewAggregate(id, shortCode, shortName, longName, description)
+	expectedProject := projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{})
 
 	// save event to event store
 	_, err = r.Save(ctx, expectedProject)
@@ -72,7 +79,7 @@ func TestProjectRepository_Load(t *testing.T) {
 	c := CreateTestClient(container, t)
 	defer c.Close()
 
-	r := project.NewProjectRepository(c)
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
 	defer cancel()
@@ -84,7 +91,7 @@ func TestProjectRepository_Load(t *testing.T) {
 	description, _ := valueobject.NewDescription("project description")
 
 	// create new project
-	project := projectEntity.NewAggregate(id, shortCode, shortName, longName, description)
+	project := projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{})
 
 	// save event to event store
 	r.Save(ctx, project)
@@ -106,6 +113,288 @@ func TestProjectRepository_Load(t *testing.T) {
 	assert.Equal(t, project.CreatedBy(), projectFromEvents.CreatedBy())
 }
 
+func TestProjectRepository_SaveSnapshot(t *testing.T) {
+	container := GetEmptyDatabase()
+	defer container.Close()
+
+	c := CreateTestClient(container, t)
+	defer c.Close()
+
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id, _ := valueobject.NewIdentifier()
+	shortCode, _ := valueobject.NewShortCode("00FF")
+	shortName, _ := valueobject.NewShortName("short name")
+	longName, _ := valueobject.NewLongName("project long name")
+	description, _ := valueobject.NewDescription("project description")
+
+	// create a project and store a snapshot of it
+	p := projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{})
+	_, err := r.Save(ctx, p)
+	assert.Nil(t, err)
+	assert.Nil(t, r.SaveSnapshot(ctx, id))
+
+	// delete the project after the snapshot was taken
+	p, _ = r.Load(ctx, id)
+	p.DeleteProject(id, event.Actor{})
+	_, err = r.Save(ctx, p)
+	assert.Nil(t, err)
+
+	// the event recorded after the snapshot is applied on top of it
+	loaded, err := r.Load(ctx, id)
+	if err != nil {
+		t.Fatalf("Unexpected failure %+v", err)
+	}
+	assert.Equal(t, shortCode, loaded.ShortCode())
+	assert.False(t, loaded.DeletedAt().Time().IsZero())
+	assert.Equal(t, 2, loaded.Version())
+
+	n, err := r.RebuildSnapshots(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestProjectRepository_RebuildIndex(t *testing.T) {
+	container := GetEmptyDatabase()
+	defer container.Close()
+
+	c := CreateTestClient(container, t)
+	defer c.Close()
+
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id, _ := valueobject.NewIdentifier()
+	shortCode, _ := valueobject.NewShortCode("00FF")
+	shortName, _ := valueobject.NewShortName("short name")
+	longName, _ := valueobject.NewLongName("project long name")
+	description, _ := valueobject.NewDescription("project description")
+
+	_, err := r.Save(ctx, projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{}))
+	assert.Nil(t, err)
+
+	n, err := r.RebuildIndex(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	ids, _ := r.Search(ctx, "long name", 0)
+	assert.Equal(t, []valueobject.Identifier{id}, ids)
+}
+
+func TestProjectRepository_ExportImport(t *testing.T) {
+	container := GetEmptyDatabase()
+	defer container.Close()
+
+	c := CreateTestClient(container, t)
+	defer c.Close()
+
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id, _ := valueobject.NewIdentifier()
+	shortCode, _ := valueobject.NewShortCode("00FF")
+	shortName, _ := valueobject.NewShortName("short name")
+	longName, _ := valueobject.NewLongName("project long name")
+	description, _ := valueobject.NewDescription("project description")
+
+	user, _ := valueobject.NewIdentifier()
+	_, err := r.Save(ctx, projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{ID: user, Type: event.ActorUser}))
+	assert.Nil(t, err)
+
+	// the key of the actor is only exported with a key of the archive
+	_, err = archive.Export(ctx, r, &bytes.Buffer{}, nil)
+	assert.True(t, errors.Is(err, archive.ErrKeyRequired))
+
+	archiveKey := bytes.Repeat([]byte{7}, archive.KeySize)
+	var buf bytes.Buffer
+	summary, err := archive.Export(ctx, r, &buf, archiveKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.Events)
+	assert.Equal(t, 1, summary.Keys)
+
+	// the stream exists already
+	b := buf.Bytes()
+	_, err = archive.Import(ctx, r, bytes.NewReader(b), archiveKey, false, false)
+	assert.True(t, errors.Is(err, archive.ErrStreamExists))
+
+	summary, err = archive.Import(ctx, r, bytes.NewReader(b), archiveKey, true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, summary.Forgotten)
+
+	events, err := r.LoadEvents(ctx, id)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, user, events[0].(*event.ProjectCreated).CreatedBy)
+
+	// a forgotten actor stays forgotten when the archive is imported again
+	_, err = r.ForgetActor(ctx, user)
+	assert.Nil(t, err)
+	summary, err = archive.Import(ctx, r, bytes.NewReader(b), archiveKey, true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.Forgotten)
+
+	events, err = r.LoadEvents(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, event.RedactedActor, events[0].(*event.ProjectCreated).CreatedBy)
+}
+
+func TestProjectRepository_ImportOverwrite(t *testing.T) {
+	container := GetEmptyDatabase()
+	defer container.Close()
+
+	c := CreateTestClient(container, t)
+	defer c.Close()
+
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id, _ := valueobject.NewIdentifier()
+	shortCode, _ := valueobject.NewShortCode("00FF")
+	shortName, _ := valueobject.NewShortName("short name")
+	longName, _ := valueobject.NewLongName("project long name")
+	description, _ := valueobject.NewDescription("project description")
+
+	_, err := r.Save(ctx, projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{}))
+	assert.Nil(t, err)
+	assert.Nil(t, r.SaveSnapshot(ctx, id))
+
+	var buf bytes.Buffer
+	_, err = archive.Export(ctx, r, &buf, nil)
+	assert.Nil(t, err)
+
+	// the deleted events stay in $all until the event store is scavenged
+	for i := 0; i < 2; i++ {
+		_, err = archive.Import(ctx, r, bytes.NewReader(buf.Bytes()), nil, true, false)
+		assert.Nil(t, err)
+	}
+
+	report, err := r.Verify(ctx, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, 1, report.Events)
+
+	ids, err := r.GetProjectIds(ctx, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []valueobject.Identifier{id}, ids)
+
+	n, err := r.RebuildIndex(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	// the revisions of the overwritten stream do not start at 0
+	assert.Nil(t, r.SaveSnapshot(ctx, id))
+	p, err := r.Load(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, shortCode, p.ShortCode())
+	p.DeleteProject(id, event.Actor{})
+	_, err = r.Save(ctx, p)
+	assert.Nil(t, err)
+
+	p, err = r.Load(ctx, id)
+	assert.Nil(t, err)
+	assert.False(t, p.DeletedAt().Time().IsZero())
+
+	// the archive numbers the events of every stream from 0, so it can be imported again
+	buf.Reset()
+	summary, err := archive.Export(ctx, r, &buf, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, summary.Events)
+	_, err = archive.Import(ctx, r, bytes.NewReader(buf.Bytes()), nil, true, true)
+	assert.Nil(t, err)
+}
+
+func TestProjectRepository_Verify(t *testing.T) {
+	container := GetEmptyDatabase()
+	defer container.Close()
+
+	c := CreateTestClient(container, t)
+	defer c.Close()
+
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id, _ := valueobject.NewIdentifier()
+	shortCode, _ := valueobject.NewShortCode("00FF")
+	shortName, _ := valueobject.NewShortName("short name")
+	longName, _ := valueobject.NewLongName("project long name")
+	description, _ := valueobject.NewDescription("project description")
+
+	_, err := r.Save(ctx, projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{}))
+	assert.Nil(t, err)
+
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	checkpoint, err := r.SaveCheckpoint(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, checkpoint.Events)
+
+	report, err := r.Verify(ctx, nil, key.Public().(ed25519.PublicKey))
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, 0, report.Unchained)
+	assert.Equal(t, integrity.CheckpointOK, report.Checkpoints[0].Status)
+
+	report, err = r.Verify(ctx, &id, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Events)
+}
+
+func TestProjectRepository_ForgetActor(t *testing.T) {
+	container := GetEmptyDatabase()
+	defer container.Close()
+
+	c := CreateTestClient(container, t)
+	defer c.Close()
+
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id, _ := valueobject.NewIdentifier()
+	actor, _ := valueobject.NewIdentifier()
+	shortCode, _ := valueobject.NewShortCode("00FF")
+	shortName, _ := valueobject.NewShortName("short name")
+	longName, _ := valueobject.NewLongName("project long name")
+	description, _ := valueobject.NewDescription("project description")
+
+	_, err := r.Save(ctx, projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{ID: actor, Type: event.ActorUser}))
+	assert.Nil(t, err)
+
+	// the actor is not stored in plain text
+	recordedEvents, err := c.ReadStreamEvents(ctx, direction.Forwards, "Project-"+id.String(), streamrevision.StreamRevisionStart, 1, false)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(recordedEvents[0].Data), actor.String())
+
+	p, err := r.Load(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, actor, p.CreatedBy())
+
+	forgotten, err := r.ForgetActor(ctx, actor)
+	assert.Nil(t, err)
+	assert.True(t, forgotten)
+
+	p, err = r.Load(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, event.RedactedActor, p.CreatedBy())
+	assert.Equal(t, longName, p.LongName())
+
+	// the hash chain is not affected
+	report, err := r.Verify(ctx, &id, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", report.Status)
+}
+
 func TestProjectRepository_GetProjectIds(t *testing.T) {
 	container := GetEmptyDatabase()
 	defer container.Close()
@@ -113,7 +402,7 @@ func TestProjectRepository_GetProjectIds(t *testing.T) {
 	c := CreateTestClient(container, t)
 	defer c.Close()
 
-	r := project.NewProjectRepository(c)
+	r := project.NewProjectRepository(c, subjectkey.NewProtector(c))
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
 	defer cancel()
@@ -125,7 +414,7 @@ func TestProjectRepository_GetProjectIds(t *testing.T) {
 	description, _ := valueobject.NewDescription("project description")
 
 	// create new project
-	project := projectEntity.NewAggregate(id, shortCode, shortName, longName, description)
+	project := projectEntity.NewAggregate(id, shortCode, shortName, longName, description, event.Actor{})
 
 	// save event to event store
 	r.Save(ctx, project)
@@ -138,3 +427,13 @@ func TestProjectRepository_GetProjectIds(t *testing.T) {
 
 	assert.Len(t, projectIds, 1)
 }
+
+func TestProjectRepository_WatchSince_InvalidEventID(t *testing.T) {
+	// malformed ids are rejected before the event store is contacted
+	r := project.NewProjectRepository(nil, nil)
+
+	for _, id := range []string{"42", "C:1/P:", "C:1/P:2/3", "C:-1/P:2"} {
+		err := r.WatchSince(context.Background(), id, func(id string, ev event.Event) {})
+		assert.Equal(t, projectEntity.ErrInvalidEventID, err, id)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package serviceaccount
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/EventStore/EventStore-Client-Go/client"
+	"github.com/EventStore/EventStore-Client-Go/direction"
+	"github.com/EventStore/EventStore-Client-Go/messages"
+	"github.com/EventStore/EventStore-Client-Go/position"
+	"github.com/EventStore/EventStore-Client-Go/streamrevision"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/shredding"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gofrs/uuid"
+)
+
+// pageSize is the number of events read from the event store at once.
+const pageSize = 1000
+
+// streamPrefix is the prefix of the streams of the service accounts, followed by their id.
+const streamPrefix = "ServiceAccount-"
+
+// serviceAccountRepository contains a pointer to the client and the protector of the personal data in the events.
+type serviceAccountRepository struct {
+	c         *client.Client
+	protector *shredding.Protector
+}
+
+// NewServiceAccountRepository creates a new repository to store service account events in.
+// The actors of the events are encrypted by the protector, see subjectkey.NewProtector.
+func NewServiceAccountRepository(client *client.Client, protector *shredding.Protector) *serviceAccountRepository {
+	return &serviceAccountRepository{
+		c:         client,
+		protector: protector,
+	}
+}
+
+// Save stores the service account events in the serviceAccountRepository.
+func (r *serviceAccountRepository) Save(ctx context.Context, a *serviceaccount.Aggregate) (valueobject.Identifier, error) {
+	var proposedEvents []messages.ProposedEvent
+	streamRevision := streamrevision.StreamRevisionStreamExists
+
+	for _, ev := range a.Events() {
+		eventType := ""
+		switch ev.(type) {
+		case *event.ServiceAccountCreated:
+			eventType = "ServiceAccountCreated"
+			streamRevision = streamrevision.StreamRevisionNoStream
+		case *event.ServiceAccountDeleted:
+			eventType = "ServiceAccountDeleted"
+		case *event.APIKeyIssued:
+			eventType = "APIKeyIssued"
+		case *event.APIKeyRevoked:
+			eventType = "APIKeyRevoked"
+		case *event.APIKeyUsed:
+			eventType = "APIKeyUsed"
+		default:
+			return a.ID(), fmt.Errorf("unexpected event type: %T", ev)
+		}
+
+		j, err := json.Marshal(ev)
+		if err != nil {
+			return a.ID(), fmt.Errorf("problem serializing '%T' event to json", ev)
+		}
+		if j, err = r.protector.Protect(ctx, j); err != nil {
+			return a.ID(), fmt.Errorf("problem protecting the personal data of '%T' event: %w", ev, err)
+		}
+
+		eventID, _ := uuid.NewV4()
+		proposedEvents = append(proposedEvents, messages.ProposedEvent{
+			EventID:     eventID,
+			EventType:   eventType,
+			ContentType: "application/json",
+			Data:        j,
+		})
+	}
+
+	streamID := streamPrefix + a.ID().String()
+
+	if _, err := r.c.AppendToStream(ctx, streamID, streamRevision, proposedEvents); err != nil {
+		return a.ID(), fmt.Errorf("problem appending events to stream '%s': %w", streamID, err)
+	}
+
+	return a.ID(), nil
+}
+
+// Load reads the events from the event store and recreates a service account aggregate.
+func (r *serviceAccountRepository) Load(ctx context.Context, id valueobject.Identifier) (*serviceaccount.Aggregate, error) {
+	streamID := streamPrefix + id.String()
+
+	var events []event.Event
+
+	// every use of an API key is recorded, so the stream is read in pages
+	from := streamrevision.StreamRevisionStart
+	for {
+		recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, from, pageSize, false)
+		if err != nil {
+			log.Printf("Unexpected failure %+v", err)
+			return &serviceaccount.Aggregate{}, serviceaccount.ErrServiceAccountNotFound
+		}
+
+		decoded, err := r.decodeEvents(ctx, recordedEvents)
+		if err != nil {
+			return &serviceaccount.Aggregate{}, err
+		}
+		events = append(events, decoded...)
+
+		if len(recordedEvents) < pageSize {
+			return serviceaccount.NewAggregateFromEvents(events), nil
+		}
+		from += pageSize
+	}
+}
+
+// decodeEvents decrypts and deserializes the recorded service account events, records of other types are skipped.
+func (r *serviceAccountRepository) decodeEvents(ctx context.Context, recordedEvents []messages.RecordedEvent) ([]event.Event, error) {
+	var events []event.Event
+
+	for _, record := range recordedEvents {
+		var e event.Event
+		switch record.EventType {
+		case "ServiceAccountCreated":
+			e = &event.ServiceAccountCreated{}
+		case "ServiceAccountDeleted":
+			e = &event.ServiceAccountDeleted{}
+		case "APIKeyIssued":
+			e = &event.APIKeyIssued{}
+		case "APIKeyRevoked":
+			e = &event.APIKeyRevoked{}
+		case "APIKeyUsed":
+			e = &event.APIKeyUsed{}
+		default:
+			log.Printf("unexpected event type: %s", record.EventType)
+			continue
+		}
+
+		data, err := r.protector.Reveal(ctx, record.Data)
+		if err != nil {
+			return nil, fmt.Errorf("problem revealing the personal data of '%s' event: %w", record.EventType, err)
+		}
+		if err := json.Unmarshal(data, e); err != nil {
+			return nil, fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// GetServiceAccountIds returns a list of all active service account ids.
+// returnDeletedServiceAccounts can be used to also return service accounts marked as deleted in the list.
+func (r *serviceAccountRepository) GetServiceAccountIds(ctx context.Context, returnDeletedServiceAccounts bool) ([]valueobject.Identifier, error) {
+	var ids []valueobject.Identifier
+
+	err := r.readRecords(ctx, func(record messages.RecordedEvent) error {
+		switch record.EventType {
+		case "ServiceAccountCreated":
+			var e event.ServiceAccountCreated
+			if err := json.Unmarshal(record.Data, &e); err != nil {
+				return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+			}
+			ids = append(ids, e.ID)
+		case "ServiceAccountDeleted":
+			var e event.ServiceAccountDeleted
+			if err := json.Unmarshal(record.Data, &e); err != nil {
+				return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+			}
+			if !returnDeletedServiceAccounts {
+				for i := range ids {
+					if ids[i] == e.ID {
+						ids = append(ids[:i], ids[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Unexpected failure %+v", err)
+		return []valueobject.Identifier{}, err
+	}
+
+	return ids, nil
+}
+
+// readRecords calls handle with every record of the service account streams, in the order they were appended.
+func (r *serviceAccountRepository) readRecords(ctx context.Context, handle func(record messages.RecordedEvent) error) error {
+	from := position.StartPosition
+	for {
+		recordedEvents, err := r.c.ReadAllEvents(ctx, direction.Forwards, from, pageSize, false)
+		if err != nil {
+			return fmt.Errorf("problem reading service account events: %w", err)
+		}
+
+		for _, record := range recordedEvents {
+			if record.Position == from || !strings.HasPrefix(record.StreamID, streamPrefix) {
+				continue
+			}
+			if err := handle(record); err != nil {
+				return err
+			}
+		}
+
+		if len(recordedEvents) < pageSize {
+			return nil
+		}
+		from = recordedEvents[len(recordedEvents)-1].Position
+	}
+}
@@ -0,0 +1,241 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package subjectkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EventStore/EventStore-Client-Go/client"
+	"github.com/EventStore/EventStore-Client-Go/direction"
+	esErrors "github.com/EventStore/EventStore-Client-Go/errors"
+	"github.com/EventStore/EventStore-Client-Go/messages"
+	"github.com/EventStore/EventStore-Client-Go/streamrevision"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/shredding"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gofrs/uuid"
+)
+
+// keyStreamPrefix is the prefix of the streams holding a key each, named by the id of the key.
+const keyStreamPrefix = "SubjectKey-"
+
+// subjectStreamPrefix is the prefix of the streams listing the ids of the keys of a data subject.
+const subjectStreamPrefix = "SubjectKeys-"
+
+// PersonalFields are the fields of the events and snapshots which identify a person, the actors of the project,
+// service account and webhook events, including the project events embedded in the payloads of webhook deliveries.
+var PersonalFields = []string{"createdBy", "changedBy", "deletedBy", "restoredBy", "issuedBy", "revokedBy", "requestedBy"}
+
+// streamDeletedMessage is part of the error returned by the event store if a tombstoned stream is read or written.
+// The client only has an error of its own for missing streams, so a tombstone can only be recognized by its message.
+const streamDeletedMessage = "is deleted"
+
+// keyCreated is the event recorded in the stream of a key.
+type keyCreated struct {
+	Secret []byte `json:"secret"`
+}
+
+// subjectKeyAdded is the event recorded in the stream of a data subject for each of its keys.
+type subjectKeyAdded struct {
+	KeyID string `json:"keyId"`
+}
+
+// subjectKeyRepository contains a pointer to the client.
+type subjectKeyRepository struct {
+	c *client.Client
+}
+
+// NewSubjectKeyRepository creates a new repository to store the keys of the data subjects in.
+// Each key is stored in a stream of its own, which is hard deleted when the key is destroyed: the stream can neither be read
+// nor written again, but the key is only removed from disk by the next scavenge of the event store, and stays in its backups.
+func NewSubjectKeyRepository(client *client.Client) *subjectKeyRepository {
+	return &subjectKeyRepository{c: client}
+}
+
+// NewProtector creates the protector of the personal fields of all events, with the keys stored in the event store.
+// The repositories must share it, since it caches the keys: forgetting a subject only clears the cache of the protector used.
+func NewProtector(client *client.Client) *shredding.Protector {
+	// events recorded without an actor have the nil identifier, which is not personal data
+	return shredding.NewProtector(NewSubjectKeyRepository(client), event.RedactedActor.String(),
+		[]string{valueobject.Identifier{}.String()}, PersonalFields...)
+}
+
+// SubjectKey returns the latest key of the subject, and creates one if the subject has none yet.
+func (r *subjectKeyRepository) SubjectKey(ctx context.Context, subject string) (shredding.Key, error) {
+	streamID := subjectStreamPrefix + subject
+
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Backwards, streamID, streamrevision.StreamRevisionEnd, 1, false)
+	if err != nil && !errors.Is(err, esErrors.ErrStreamNotFound) {
+		return shredding.Key{}, fmt.Errorf("problem reading stream '%s': %w", streamID, err)
+	}
+	if err == nil && len(recordedEvents) > 0 {
+		var e subjectKeyAdded
+		if err := json.Unmarshal(recordedEvents[0].Data, &e); err != nil {
+			return shredding.Key{}, fmt.Errorf("problem deserializing key of subject from json: %w", err)
+		}
+		key, err := r.Key(ctx, e.KeyID)
+		if !errors.Is(err, shredding.ErrKeyNotFound) {
+			return key, err
+		}
+	}
+
+	id, _ := uuid.NewV4()
+	key, err := shredding.NewKey(id.String())
+	if err != nil {
+		return shredding.Key{}, err
+	}
+
+	if err := r.append(ctx, keyStreamPrefix+key.ID, streamrevision.StreamRevisionNoStream, "SubjectKeyCreated", keyCreated{Secret: key.Secret}); err != nil {
+		return shredding.Key{}, err
+	}
+	if err := r.append(ctx, streamID, streamrevision.StreamRevisionAny, "SubjectKeyAdded", subjectKeyAdded{KeyID: key.ID}); err != nil {
+		return shredding.Key{}, err
+	}
+
+	return key, nil
+}
+
+// Key returns the key with the id, or shredding.ErrKeyNotFound if it has been destroyed.
+func (r *subjectKeyRepository) Key(ctx context.Context, id string) (shredding.Key, error) {
+	streamID := keyStreamPrefix + id
+
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, streamrevision.StreamRevisionStart, 1, false)
+	if errors.Is(err, esErrors.ErrStreamNotFound) || isStreamDeleted(err) {
+		return shredding.Key{}, shredding.ErrKeyNotFound
+	}
+	if err != nil {
+		return shredding.Key{}, fmt.Errorf("problem reading stream '%s': %w", streamID, err)
+	}
+	if len(recordedEvents) == 0 {
+		return shredding.Key{}, shredding.ErrKeyNotFound
+	}
+
+	var e keyCreated
+	if err := json.Unmarshal(recordedEvents[0].Data, &e); err != nil {
+		return shredding.Key{}, fmt.Errorf("problem deserializing key from json: %w", err)
+	}
+
+	return shredding.Key{ID: id, Secret: e.Secret}, nil
+}
+
+// DestroyKeys hard deletes the streams of all keys of the subject, and deletes the list of its keys.
+// It returns false if the subject had no keys.
+func (r *subjectKeyRepository) DestroyKeys(ctx context.Context, subject string) (bool, error) {
+	streamID := subjectStreamPrefix + subject
+
+	var ids []string
+	from := streamrevision.StreamRevisionStart
+	for {
+		recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, from, 100, false)
+		if errors.Is(err, esErrors.ErrStreamNotFound) {
+			break
+		}
+		if err != nil {
+			return false, fmt.Errorf("problem reading stream '%s': %w", streamID, err)
+		}
+
+		for _, record := range recordedEvents {
+			var e subjectKeyAdded
+			if err := json.Unmarshal(record.Data, &e); err != nil {
+				return false, fmt.Errorf("problem deserializing key of subject from json: %w", err)
+			}
+			ids = append(ids, e.KeyID)
+		}
+
+		if len(recordedEvents) < 100 {
+			break
+		}
+		from += 100
+	}
+
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	// the events of a soft deleted stream can be restored through its metadata until the next scavenge, a tombstone cannot be undone.
+	// The ids of the keys are random, so a key stream never has to be written again,
+	// and the keys destroyed by an earlier attempt which failed halfway are already tombstoned.
+	for _, id := range ids {
+		if _, err := r.c.TombstoneStream(ctx, keyStreamPrefix+id, streamrevision.StreamRevisionAny); err != nil && !isStreamDeleted(err) {
+			return false, fmt.Errorf("problem destroying key '%s': %w", id, err)
+		}
+	}
+
+	// the subject may be recorded again later on, with a new key, so the list of its keys is only soft deleted
+	if _, err := r.c.DeleteStream(ctx, streamID, streamrevision.StreamRevisionAny); err != nil {
+		return false, fmt.Errorf("problem deleting stream '%s': %w", streamID, err)
+	}
+
+	return true, nil
+}
+
+// ImportKey stores a key of the subject exported from another event store, e.g. with an archive of the events.
+// A key stream which has been hard deleted cannot be written again, so a destroyed key is never restored and false is returned.
+func (r *subjectKeyRepository) ImportKey(ctx context.Context, subject string, key shredding.Key) (bool, error) {
+	streamID := keyStreamPrefix + key.ID
+
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, streamrevision.StreamRevisionStart, 1, false)
+	switch {
+	case isStreamDeleted(err):
+		return false, nil
+	case err == nil && len(recordedEvents) > 0:
+		// the key was imported before, or exported from this event store
+		return true, nil
+	case err != nil && !errors.Is(err, esErrors.ErrStreamNotFound):
+		return false, fmt.Errorf("problem reading stream '%s': %w", streamID, err)
+	}
+
+	if err := r.append(ctx, streamID, streamrevision.StreamRevisionNoStream, "SubjectKeyCreated", keyCreated{Secret: key.Secret}); err != nil {
+		return false, err
+	}
+	if err := r.append(ctx, subjectStreamPrefix+subject, streamrevision.StreamRevisionAny, "SubjectKeyAdded", subjectKeyAdded{KeyID: key.ID}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// isStreamDeleted returns true if the error is caused by a tombstoned stream.
+func isStreamDeleted(err error) bool {
+	return err != nil && strings.Contains(err.Error(), streamDeletedMessage)
+}
+
+// append appends an event with the data to the stream.
+func (r *subjectKeyRepository) append(ctx context.Context, streamID string, revision streamrevision.StreamRevision, eventType string, data interface{}) error {
+	j, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("problem serializing '%s' event to json", eventType)
+	}
+
+	eventID, _ := uuid.NewV4()
+	e := messages.ProposedEvent{
+		EventID:     eventID,
+		EventType:   eventType,
+		ContentType: "application/json",
+		Data:        j,
+	}
+
+	if _, err := r.c.AppendToStream(ctx, streamID, revision, []messages.ProposedEvent{e}); err != nil {
+		return fmt.Errorf("problem appending event to stream '%s': %w", streamID, err)
+	}
+	return nil
+}
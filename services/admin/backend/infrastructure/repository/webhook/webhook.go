@@ -0,0 +1,403 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/EventStore/EventStore-Client-Go/client"
+	"github.com/EventStore/EventStore-Client-Go/direction"
+	esErrors "github.com/EventStore/EventStore-Client-Go/errors"
+	"github.com/EventStore/EventStore-Client-Go/messages"
+	"github.com/EventStore/EventStore-Client-Go/position"
+	"github.com/EventStore/EventStore-Client-Go/streamrevision"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/shredding"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gofrs/uuid"
+)
+
+// pageSize is the number of events read from the event store at once.
+const pageSize = 1000
+
+// webhookStreamPrefix is the prefix of the streams of the webhooks, followed by their id.
+const webhookStreamPrefix = "Webhook-"
+
+// deliveryStreamPrefix is the prefix of the streams of the deliveries, followed by their id.
+const deliveryStreamPrefix = "WebhookDelivery-"
+
+// checkpointStreamID is the name of the stream the checkpoints of the outbox are recorded in, only the last one is ever read.
+const checkpointStreamID = "WebhookOutbox"
+
+// checkpoint is the id of the last project event for which deliveries have been scheduled.
+type checkpoint struct {
+	LastEventID string `json:"lastEventId"`
+}
+
+// webhookRepository contains a pointer to the client and the protector of the personal data in the events.
+type webhookRepository struct {
+	c         *client.Client
+	protector *shredding.Protector
+}
+
+// NewWebhookRepository creates a new repository to store webhook and delivery events in.
+// The actors of the events are encrypted by the protector, see subjectkey.NewProtector, and so are the actors
+// of the project events in the payloads of the deliveries.
+func NewWebhookRepository(client *client.Client, protector *shredding.Protector) *webhookRepository {
+	return &webhookRepository{
+		c:         client,
+		protector: protector,
+	}
+}
+
+// Save stores the webhook events in the webhookRepository.
+func (r *webhookRepository) Save(ctx context.Context, a *webhook.Aggregate) (valueobject.Identifier, error) {
+	streamRevision := streamrevision.StreamRevisionStreamExists
+
+	var proposedEvents []messages.ProposedEvent
+
+	for _, ev := range a.Events() {
+		eventType := ""
+		switch ev.(type) {
+		case *event.WebhookCreated:
+			eventType = "WebhookCreated"
+			streamRevision = streamrevision.StreamRevisionNoStream
+		case *event.WebhookDeleted:
+			eventType = "WebhookDeleted"
+		default:
+			return a.ID(), fmt.Errorf("unexpected event type: %T", ev)
+		}
+
+		pe, err := r.proposeProtected(ctx, eventType, ev)
+		if err != nil {
+			return a.ID(), err
+		}
+		proposedEvents = append(proposedEvents, pe)
+	}
+
+	return a.ID(), r.append(ctx, webhookStreamPrefix+a.ID().String(), streamRevision, proposedEvents)
+}
+
+// Load reads the events from the event store and recreates a webhook aggregate.
+func (r *webhookRepository) Load(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error) {
+	events, err := r.read(ctx, webhookStreamPrefix+id.String())
+	if err != nil {
+		return &webhook.Aggregate{}, webhook.ErrWebhookNotFound
+	}
+
+	return webhook.NewAggregateFromEvents(events), nil
+}
+
+// GetWebhookIds returns a list of all active webhook ids.
+// returnDeletedWebhooks can be used to also return webhooks marked as deleted in the list.
+func (r *webhookRepository) GetWebhookIds(ctx context.Context, returnDeletedWebhooks bool) ([]valueobject.Identifier, error) {
+	var ids []valueobject.Identifier
+
+	err := r.readRecords(ctx, webhookStreamPrefix, func(record messages.RecordedEvent) error {
+		switch record.EventType {
+		case "WebhookCreated":
+			var e event.WebhookCreated
+			if err := json.Unmarshal(record.Data, &e); err != nil {
+				return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+			}
+			ids = append(ids, e.ID)
+		case "WebhookDeleted":
+			var e event.WebhookDeleted
+			if err := json.Unmarshal(record.Data, &e); err != nil {
+				return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+			}
+			if !returnDeletedWebhooks {
+				for i := range ids {
+					if ids[i] == e.ID {
+						ids = append(ids[:i], ids[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Unexpected failure %+v", err)
+		return []valueobject.Identifier{}, err
+	}
+
+	return ids, nil
+}
+
+// SaveDelivery stores the delivery events in the webhookRepository.
+// Scheduling a delivery with an id which is already taken fails, which keeps the outbox free of duplicates.
+func (r *webhookRepository) SaveDelivery(ctx context.Context, d *webhook.Delivery) (valueobject.Identifier, error) {
+	streamRevision := streamrevision.StreamRevisionStreamExists
+
+	var proposedEvents []messages.ProposedEvent
+
+	for _, ev := range d.Events() {
+		eventType := ""
+		switch ev.(type) {
+		case *event.WebhookDeliveryScheduled:
+			eventType = "WebhookDeliveryScheduled"
+			streamRevision = streamrevision.StreamRevisionNoStream
+		case *event.WebhookDeliveryAttempted:
+			eventType = "WebhookDeliveryAttempted"
+		case *event.WebhookDeliveryDeadLettered:
+			eventType = "WebhookDeliveryDeadLettered"
+		case *event.WebhookDeliveryRedeliveryRequested:
+			eventType = "WebhookDeliveryRedeliveryRequested"
+		default:
+			return d.ID(), fmt.Errorf("unexpected event type: %T", ev)
+		}
+
+		pe, err := r.proposeProtected(ctx, eventType, ev)
+		if err != nil {
+			return d.ID(), err
+		}
+		proposedEvents = append(proposedEvents, pe)
+	}
+
+	return d.ID(), r.append(ctx, deliveryStreamPrefix+d.ID().String(), streamRevision, proposedEvents)
+}
+
+// LoadDelivery reads the events from the event store and recreates a delivery aggregate.
+func (r *webhookRepository) LoadDelivery(ctx context.Context, id valueobject.Identifier) (*webhook.Delivery, error) {
+	events, err := r.read(ctx, deliveryStreamPrefix+id.String())
+	if err != nil {
+		return &webhook.Delivery{}, webhook.ErrDeliveryNotFound
+	}
+
+	return webhook.NewDeliveryFromEvents(events), nil
+}
+
+// GetDeliveryIds returns a list of all delivery ids, in the order the deliveries were scheduled.
+func (r *webhookRepository) GetDeliveryIds(ctx context.Context) ([]valueobject.Identifier, error) {
+	var ids []valueobject.Identifier
+
+	err := r.readRecords(ctx, deliveryStreamPrefix, func(record messages.RecordedEvent) error {
+		if record.EventType != "WebhookDeliveryScheduled" {
+			return nil
+		}
+		var e event.WebhookDeliveryScheduled
+		if err := json.Unmarshal(record.Data, &e); err != nil {
+			return fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+		}
+		ids = append(ids, e.ID)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Unexpected failure %+v", err)
+		return []valueobject.Identifier{}, err
+	}
+
+	return ids, nil
+}
+
+// readRecords calls handle with every record of the streams starting with the prefix, in the order they were appended.
+func (r *webhookRepository) readRecords(ctx context.Context, prefix string, handle func(record messages.RecordedEvent) error) error {
+	from := position.StartPosition
+	for {
+		recordedEvents, err := r.c.ReadAllEvents(ctx, direction.Forwards, from, pageSize, false)
+		if err != nil {
+			return fmt.Errorf("problem reading events: %w", err)
+		}
+
+		for _, record := range recordedEvents {
+			if record.Position == from || !strings.HasPrefix(record.StreamID, prefix) {
+				continue
+			}
+			if err := handle(record); err != nil {
+				return err
+			}
+		}
+
+		if len(recordedEvents) < pageSize {
+			return nil
+		}
+		from = recordedEvents[len(recordedEvents)-1].Position
+	}
+}
+
+// LoadCheckpoint returns the id of the last project event for which deliveries have been scheduled,
+// or an empty string if no checkpoint has been recorded yet.
+func (r *webhookRepository) LoadCheckpoint(ctx context.Context) (string, error) {
+	recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Backwards, checkpointStreamID, streamrevision.StreamRevisionEnd, 1, false)
+	if err == esErrors.ErrStreamNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("problem reading stream '%s': %w", checkpointStreamID, err)
+	}
+
+	if len(recordedEvents) == 0 {
+		return "", nil
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(recordedEvents[0].Data, &cp); err != nil {
+		return "", fmt.Errorf("problem deserializing '%s' event from json", recordedEvents[0].EventType)
+	}
+
+	return cp.LastEventID, nil
+}
+
+// SaveCheckpoint records the id of the last project event for which deliveries have been scheduled.
+func (r *webhookRepository) SaveCheckpoint(ctx context.Context, lastEventID string) error {
+	pe, err := propose("WebhookOutboxCheckpointRecorded", checkpoint{LastEventID: lastEventID})
+	if err != nil {
+		return err
+	}
+
+	return r.append(ctx, checkpointStreamID, streamrevision.StreamRevisionAny, []messages.ProposedEvent{pe})
+}
+
+// append appends the proposed events to the stream.
+func (r *webhookRepository) append(ctx context.Context, streamID string, streamRevision streamrevision.StreamRevision, proposedEvents []messages.ProposedEvent) error {
+	if _, err := r.c.AppendToStream(ctx, streamID, streamRevision, proposedEvents); err != nil {
+		return fmt.Errorf("problem appending events to stream '%s': %w", streamID, err)
+	}
+
+	return nil
+}
+
+// propose serializes the event into a proposed event of the provided type.
+func propose(eventType string, ev interface{}) (messages.ProposedEvent, error) {
+	j, err := json.Marshal(ev)
+	if err != nil {
+		return messages.ProposedEvent{}, fmt.Errorf("problem serializing '%T' event to json", ev)
+	}
+
+	eventID, _ := uuid.NewV4()
+	return messages.ProposedEvent{
+		EventID:     eventID,
+		EventType:   eventType,
+		ContentType: "application/json",
+		Data:        j,
+	}, nil
+}
+
+// proposeProtected serializes the event like propose, with its personal fields encrypted.
+func (r *webhookRepository) proposeProtected(ctx context.Context, eventType string, ev interface{}) (messages.ProposedEvent, error) {
+	pe, err := propose(eventType, ev)
+	if err != nil {
+		return pe, err
+	}
+
+	if pe.Data, err = r.transform(ctx, pe.Data, r.protector.Protect); err != nil {
+		return pe, fmt.Errorf("problem protecting the personal data of '%T' event: %w", ev, err)
+	}
+	return pe, nil
+}
+
+// transform applies the protector function to the event, and to the project event in the payload of a scheduled delivery.
+// The payload is stored as it is sent, so its project event would otherwise keep the actors in plain text.
+func (r *webhookRepository) transform(ctx context.Context, data []byte, f func(ctx context.Context, data []byte) ([]byte, error)) ([]byte, error) {
+	data, err := f(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var e map[string]json.RawMessage
+	if err := json.Unmarshal(data, &e); err != nil || e["payload"] == nil {
+		return data, nil
+	}
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(e["payload"], &p); err != nil || p["data"] == nil {
+		return data, nil
+	}
+
+	d, err := f(ctx, p["data"])
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(d, p["data"]) {
+		return data, nil
+	}
+
+	p["data"] = d
+	if e["payload"], err = json.Marshal(p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(e)
+}
+
+// read reads the events of the stream from the event store, oldest first.
+func (r *webhookRepository) read(ctx context.Context, streamID string) ([]event.Event, error) {
+	var events []event.Event
+
+	from := streamrevision.StreamRevisionStart
+	for {
+		recordedEvents, err := r.c.ReadStreamEvents(ctx, direction.Forwards, streamID, from, pageSize, false)
+		if err != nil {
+			log.Printf("Unexpected failure %+v", err)
+			return nil, err
+		}
+
+		decoded, err := r.decodeEvents(ctx, recordedEvents)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, decoded...)
+
+		if len(recordedEvents) < pageSize {
+			return events, nil
+		}
+		from += pageSize
+	}
+}
+
+// decodeEvents decrypts and deserializes the recorded webhook and delivery events, records of other types are skipped.
+func (r *webhookRepository) decodeEvents(ctx context.Context, recordedEvents []messages.RecordedEvent) ([]event.Event, error) {
+	var events []event.Event
+
+	for _, record := range recordedEvents {
+		var e event.Event
+		switch record.EventType {
+		case "WebhookCreated":
+			e = &event.WebhookCreated{}
+		case "WebhookDeleted":
+			e = &event.WebhookDeleted{}
+		case "WebhookDeliveryScheduled":
+			e = &event.WebhookDeliveryScheduled{}
+		case "WebhookDeliveryAttempted":
+			e = &event.WebhookDeliveryAttempted{}
+		case "WebhookDeliveryDeadLettered":
+			e = &event.WebhookDeliveryDeadLettered{}
+		case "WebhookDeliveryRedeliveryRequested":
+			e = &event.WebhookDeliveryRedeliveryRequested{}
+		default:
+			log.Printf("unexpected event type: %s", record.EventType)
+			continue
+		}
+
+		data, err := r.transform(ctx, record.Data, r.protector.Reveal)
+		if err != nil {
+			return nil, fmt.Errorf("problem revealing the personal data of '%s' event: %w", record.EventType, err)
+		}
+		if err := json.Unmarshal(data, e); err != nil {
+			return nil, fmt.Errorf("problem deserializing '%s' event from json", record.EventType)
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,199 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Stemmer reduces a folded, lower case token to its stem.
+type Stemmer func(token string) string
+
+// Analyzer splits text into the tokens stored in and looked up from the index.
+type Analyzer struct {
+	stopwords map[string]bool
+	stemmers  []Stemmer
+}
+
+// NewAnalyzer creates an analyzer removing the provided stopwords and stemming with the provided stemmers.
+func NewAnalyzer(stopwords []string, stemmers ...Stemmer) *Analyzer {
+	a := &Analyzer{
+		stopwords: map[string]bool{},
+		stemmers:  stemmers,
+	}
+	for _, s := range stopwords {
+		a.stopwords[fold(s)] = true
+	}
+	return a
+}
+
+// NewMultilingualAnalyzer creates an analyzer for text in English, German, French or Italian.
+// Since the language of a text is not known, the stopwords of all languages are removed
+// and every token is stemmed for all languages.
+func NewMultilingualAnalyzer() *Analyzer {
+	var stopwords []string
+	stopwords = append(stopwords, englishStopwords...)
+	stopwords = append(stopwords, germanStopwords...)
+	stopwords = append(stopwords, frenchStopwords...)
+	stopwords = append(stopwords, italianStopwords...)
+	return NewAnalyzer(stopwords, StemEnglish, StemGerman, StemFrench, StemItalian)
+}
+
+// Tokens splits the text into lower case tokens without diacritics and removes stopwords.
+// Single letters are dropped, single digits are kept.
+func (a *Analyzer) Tokens(text string) []string {
+	var tokens []string
+	for _, t := range strings.FieldsFunc(fold(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if a.stopwords[t] {
+			continue
+		}
+		if len([]rune(t)) == 1 && !unicode.IsDigit([]rune(t)[0]) {
+			continue
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// Stems returns the distinct stems of the token, including the token itself.
+func (a *Analyzer) Stems(token string) []string {
+	stems := []string{token}
+	for _, stem := range a.stemmers {
+		s := stem(token)
+		if s != "" && !containsString(stems, s) {
+			stems = append(stems, s)
+		}
+	}
+	return stems
+}
+
+// foldings maps characters with diacritics to their base characters.
+var foldings = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'ß': "ss",
+}
+
+// fold converts the text to lower case and removes diacritics, so that e.g. "Zürich" matches "zurich".
+func fold(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if f, ok := foldings[r]; ok {
+			b.WriteString(f)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// StemEnglish is a light stemmer removing plural and common verb suffixes.
+func StemEnglish(t string) string {
+	switch {
+	case hasSuffix(t, "ies", 2):
+		return t[:len(t)-3] + "y"
+	case hasSuffix(t, "sses", 2), hasSuffix(t, "ches", 2), hasSuffix(t, "shes", 2), hasSuffix(t, "xes", 2):
+		return t[:len(t)-2]
+	case hasSuffix(t, "ing", 3):
+		return t[:len(t)-3]
+	case hasSuffix(t, "ed", 3):
+		return t[:len(t)-2]
+	case hasSuffix(t, "s", 3) && !strings.HasSuffix(t, "ss") && !strings.HasSuffix(t, "us"):
+		return t[:len(t)-1]
+	}
+	return t
+}
+
+// StemGerman is a light stemmer removing inflectional suffixes.
+func StemGerman(t string) string {
+	for _, suffix := range []string{"ern", "em", "en", "er", "es", "e", "s", "n"} {
+		if hasSuffix(t, suffix, 3) {
+			return t[:len(t)-len(suffix)]
+		}
+	}
+	return t
+}
+
+// StemFrench is a light stemmer removing plural and feminine suffixes.
+func StemFrench(t string) string {
+	switch {
+	case hasSuffix(t, "aux", 2):
+		return t[:len(t)-3] + "al"
+	case hasSuffix(t, "es", 3):
+		return t[:len(t)-2]
+	case hasSuffix(t, "s", 3), hasSuffix(t, "x", 3), hasSuffix(t, "e", 3):
+		return t[:len(t)-1]
+	}
+	return t
+}
+
+// StemItalian is a light stemmer removing the final vowel, which carries gender and number.
+func StemItalian(t string) string {
+	switch {
+	case hasSuffix(t, "che", 2), hasSuffix(t, "ghe", 2), hasSuffix(t, "chi", 2), hasSuffix(t, "ghi", 2):
+		return t[:len(t)-2]
+	case hasSuffix(t, "a", 3), hasSuffix(t, "e", 3), hasSuffix(t, "i", 3), hasSuffix(t, "o", 3):
+		return t[:len(t)-1]
+	}
+	return t
+}
+
+// hasSuffix returns true if the token ends with the suffix and at least minStem characters remain.
+func hasSuffix(t string, suffix string, minStem int) bool {
+	return strings.HasSuffix(t, suffix) && len(t)-len(suffix) >= minStem
+}
+
+// containsString returns true if the list contains the value.
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var englishStopwords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "by", "for", "from", "in", "into", "is", "it",
+	"of", "on", "or", "that", "the", "their", "this", "to", "was", "were", "with",
+}
+
+var germanStopwords = []string{
+	"aber", "als", "am", "auf", "aus", "bei", "das", "dem", "den", "der", "des", "die", "ein", "eine",
+	"einem", "einen", "einer", "eines", "im", "ist", "mit", "nach", "oder", "sich", "sind", "so", "über",
+	"um", "und", "von", "vom", "zu", "zum", "zur",
+}
+
+var frenchStopwords = []string{
+	"au", "aux", "avec", "ce", "ces", "dans", "de", "des", "du", "elle", "en", "est", "et", "il", "la",
+	"le", "les", "leur", "ou", "par", "pour", "qui", "sur", "un", "une",
+}
+
+var italianStopwords = []string{
+	"al", "alla", "alle", "che", "con", "da", "dal", "dei", "del", "della", "delle", "di", "e", "gli",
+	"il", "in", "la", "le", "nel", "nella", "per", "su", "tra", "un", "una", "uno",
+}
@@ -0,0 +1,340 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// BM25 parameters, see https://en.wikipedia.org/wiki/Okapi_BM25
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// weights of the different ways a query token can match a term of the index.
+const (
+	exactMatch  = 1.0
+	stemMatch   = 0.7
+	prefixMatch = 0.5
+	fuzzyMatch  = 0.4
+)
+
+// stemPrefix marks stems in the term dictionary, so that they are never matched by prefix or fuzzy queries.
+const stemPrefix = "~"
+
+// Field is a field of the indexed documents. Matches in fields with a higher boost rank higher.
+type Field struct {
+	Name  string
+	Boost float64
+}
+
+// Hit is a document matching a query.
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Index is an in-memory inverted index supporting exact, stemmed, prefix and fuzzy matches ranked with BM25.
+// It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	analyzer *Analyzer
+	fields   []Field
+	// postings maps a term to the documents and fields containing it, and how often.
+	postings map[string]map[string]map[string]int
+	// docs maps a document to the length of each of its fields, in tokens.
+	docs map[string]map[string]int
+	// fieldLengths is the sum of the lengths of each field over all documents.
+	fieldLengths map[string]int
+	// dictionary is the sorted list of all terms, it is rebuilt on the next search after a change.
+	dictionary []string
+	dirty      bool
+}
+
+// NewIndex creates an empty index for documents with the provided fields.
+func NewIndex(analyzer *Analyzer, fields ...Field) *Index {
+	return &Index{
+		analyzer:     analyzer,
+		fields:       fields,
+		postings:     map[string]map[string]map[string]int{},
+		docs:         map[string]map[string]int{},
+		fieldLengths: map[string]int{},
+	}
+}
+
+// Put adds the document to the index, replacing any previous version of it.
+// The document maps field names to their text, unknown fields are ignored.
+func (i *Index) Put(id string, doc map[string]string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.delete(id)
+
+	lengths := map[string]int{}
+	for _, f := range i.fields {
+		tokens := i.analyzer.Tokens(doc[f.Name])
+		lengths[f.Name] = len(tokens)
+		i.fieldLengths[f.Name] += len(tokens)
+
+		for _, t := range tokens {
+			i.add(t, id, f.Name)
+			for _, s := range i.analyzer.Stems(t) {
+				i.add(stemPrefix+s, id, f.Name)
+			}
+		}
+	}
+
+	i.docs[id] = lengths
+	i.dirty = true
+}
+
+// Delete removes the document from the index.
+func (i *Index) Delete(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.delete(id)
+}
+
+// Len returns the number of documents in the index.
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.docs)
+}
+
+// Search returns the documents matching any token of the query, best matches first.
+// Documents matching more tokens of the query rank higher. If limit is greater than zero, at most limit hits are returned.
+func (i *Index) Search(query string, limit int) []Hit {
+	i.mu.Lock()
+	if i.dirty {
+		i.rebuildDictionary()
+	}
+	i.mu.Unlock()
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	tokens := i.analyzer.Tokens(query)
+	if len(tokens) == 0 {
+		return []Hit{}
+	}
+
+	scores := map[string]float64{}
+	matched := map[string]int{}
+
+	for _, t := range tokens {
+		// the best score of any expansion of the token, per document
+		best := map[string]float64{}
+		for term, weight := range i.expand(t) {
+			for id, score := range i.score(term) {
+				if s := weight * score; s > best[id] {
+					best[id] = s
+				}
+			}
+		}
+		for id, s := range best {
+			scores[id] += s
+			matched[id]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		// coordination factor, rewarding documents that match more of the query
+		hits = append(hits, Hit{ID: id, Score: s * float64(matched[id]) / float64(len(tokens))})
+	}
+
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ID < hits[b].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+// expand returns the terms of the dictionary matching the query token and the weight of each match.
+func (i *Index) expand(token string) map[string]float64 {
+	terms := map[string]float64{}
+	match := func(term string, weight float64) {
+		if _, ok := i.postings[term]; ok && weight > terms[term] {
+			terms[term] = weight
+		}
+	}
+
+	match(token, exactMatch)
+
+	for _, s := range i.analyzer.Stems(token) {
+		match(stemPrefix+s, stemMatch)
+	}
+
+	// prefix matches, e.g. "arch" matches "archive"
+	if len(token) >= 2 {
+		for j := sort.SearchStrings(i.dictionary, token); j < len(i.dictionary) && strings.HasPrefix(i.dictionary[j], token); j++ {
+			match(i.dictionary[j], prefixMatch*float64(len(token))/float64(len(i.dictionary[j])))
+		}
+	}
+
+	// fuzzy matches, allowing more typos in longer tokens
+	// tokens containing digits, such as short codes or years, must not match similar numbers
+	maxDistance := 0
+	switch n := len([]rune(token)); {
+	case strings.IndexFunc(token, unicode.IsDigit) >= 0:
+		maxDistance = 0
+	case n >= 8:
+		maxDistance = 2
+	case n >= 4:
+		maxDistance = 1
+	}
+	if maxDistance > 0 {
+		for _, term := range i.dictionary {
+			if strings.HasPrefix(term, stemPrefix) {
+				continue
+			}
+			if d := levenshtein(token, term, maxDistance); d > 0 && d <= maxDistance {
+				match(term, fuzzyMatch/float64(d))
+			}
+		}
+	}
+
+	return terms
+}
+
+// score returns the BM25 score of the term for each document containing it, summed over the boosted fields.
+func (i *Index) score(term string) map[string]float64 {
+	docs := i.postings[term]
+	n := float64(len(i.docs))
+	df := float64(len(docs))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	scores := map[string]float64{}
+	for id, fields := range docs {
+		for _, f := range i.fields {
+			tf := float64(fields[f.Name])
+			if tf == 0 {
+				continue
+			}
+			avg := float64(i.fieldLengths[f.Name]) / n
+			norm := 1 - b + b*float64(i.docs[id][f.Name])/avg
+			scores[id] += f.Boost * idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+	return scores
+}
+
+// add records an occurrence of the term in the field of the document.
+func (i *Index) add(term string, id string, field string) {
+	if i.postings[term] == nil {
+		i.postings[term] = map[string]map[string]int{}
+	}
+	if i.postings[term][id] == nil {
+		i.postings[term][id] = map[string]int{}
+	}
+	i.postings[term][id][field]++
+}
+
+// delete removes the document from the index, the lock must be held by the caller.
+func (i *Index) delete(id string) {
+	lengths, ok := i.docs[id]
+	if !ok {
+		return
+	}
+
+	for f, l := range lengths {
+		i.fieldLengths[f] -= l
+	}
+
+	for term, docs := range i.postings {
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(i.postings, term)
+		}
+	}
+
+	delete(i.docs, id)
+	i.dirty = true
+}
+
+// rebuildDictionary sorts the terms of the index, the lock must be held by the caller.
+func (i *Index) rebuildDictionary() {
+	i.dictionary = make([]string, 0, len(i.postings))
+	for term := range i.postings {
+		i.dictionary = append(i.dictionary, term)
+	}
+	sort.Strings(i.dictionary)
+	i.dirty = false
+}
+
+// levenshtein returns the edit distance between a and b.
+// The computation stops early once the distance exceeds max, in which case max+1 is returned.
+func levenshtein(a string, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for x := 1; x <= len(ra); x++ {
+		curr[0] = x
+		rowMin := curr[0]
+		for y := 1; y <= len(rb); y++ {
+			cost := 1
+			if ra[x-1] == rb[y-1] {
+				cost = 0
+			}
+			curr[y] = minInt(prev[y]+1, curr[y-1]+1, prev[y-1]+cost)
+			if curr[y] < rowMin {
+				rowMin = curr[y]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// minInt returns the smallest of the provided values.
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
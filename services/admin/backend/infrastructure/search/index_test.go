@@ -0,0 +1,118 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package search_test
+
+import (
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIndex() *search.Index {
+	i := search.NewIndex(search.NewMultilingualAnalyzer(),
+		search.Field{Name: "title", Boost: 3},
+		search.Field{Name: "body", Boost: 1},
+	)
+	i.Put("letters", map[string]string{"title": "Letters of Bernoulli", "body": "Correspondence of the Bernoulli family from Basel"})
+	i.Put("zurich", map[string]string{"title": "Zürcher Bibliotheken", "body": "Die Geschichte der Bibliotheken in Zürich"})
+	i.Put("roma", map[string]string{"title": "Archivio storico", "body": "Documenti della città di Roma e lettere"})
+	i.Put("basel", map[string]string{"title": "Basel archive", "body": "Photographs of the city of Basel"})
+	return i
+}
+
+func ids(hits []search.Hit) []string {
+	res := []string{}
+	for _, h := range hits {
+		res = append(res, h.ID)
+	}
+	return res
+}
+
+func TestIndex_Search_Exact(t *testing.T) {
+	i := newTestIndex()
+	assert.Equal(t, 4, i.Len())
+
+	// matches in the title rank higher than matches in the body
+	assert.Equal(t, []string{"basel", "letters"}, ids(i.Search("basel", 0)))
+}
+
+func TestIndex_Search_MatchesMoreTokensFirst(t *testing.T) {
+	i := newTestIndex()
+	assert.Equal(t, "letters", i.Search("bernoulli basel", 0)[0].ID)
+}
+
+func TestIndex_Search_Prefix(t *testing.T) {
+	i := newTestIndex()
+	assert.ElementsMatch(t, []string{"basel", "roma"}, ids(i.Search("archiv", 0)))
+}
+
+func TestIndex_Search_Fuzzy(t *testing.T) {
+	i := newTestIndex()
+	assert.Equal(t, []string{"letters"}, ids(i.Search("bernouli", 0)))
+	assert.Equal(t, []string{"letters"}, ids(i.Search("corespondence", 0)))
+}
+
+func TestIndex_Search_Diacritics(t *testing.T) {
+	i := newTestIndex()
+	assert.Equal(t, []string{"zurich"}, ids(i.Search("zurich", 0)))
+	assert.Equal(t, []string{"roma"}, ids(i.Search("citta", 0)))
+}
+
+func TestIndex_Search_Stemming(t *testing.T) {
+	i := newTestIndex()
+	// german plural
+	assert.Equal(t, []string{"zurich"}, ids(i.Search("bibliothek", 0)))
+	// english plural
+	assert.Equal(t, []string{"basel"}, ids(i.Search("photograph", 0)))
+	// italian plural
+	assert.Contains(t, ids(i.Search("lettera", 0)), "roma")
+}
+
+func TestIndex_Search_Stopwords(t *testing.T) {
+	i := newTestIndex()
+	assert.Empty(t, i.Search("the of der", 0))
+}
+
+func TestIndex_Search_Limit(t *testing.T) {
+	i := newTestIndex()
+	assert.Len(t, i.Search("basel", 1), 1)
+}
+
+func TestIndex_PutAndDelete(t *testing.T) {
+	i := newTestIndex()
+
+	i.Put("basel", map[string]string{"title": "Geneva archive"})
+	assert.Equal(t, []string{"letters"}, ids(i.Search("basel", 0)))
+	assert.Equal(t, []string{"basel"}, ids(i.Search("geneva", 0)))
+
+	i.Delete("basel")
+	assert.Empty(t, i.Search("geneva", 0))
+	assert.Equal(t, 3, i.Len())
+}
+
+func TestAnalyzer_Tokens(t *testing.T) {
+	a := search.NewMultilingualAnalyzer()
+	assert.Equal(t, []string{"geschichte", "stadt", "zurich", "1848"}, a.Tokens("Die Geschichte der Stadt Zürich, 1848 - a"))
+}
+
+func TestIndex_Search_NoFuzzyNumbers(t *testing.T) {
+	i := search.NewIndex(search.NewMultilingualAnalyzer(), search.Field{Name: "title", Boost: 1})
+	i.Put("1848", map[string]string{"title": "Revolution 1848"})
+	assert.Empty(t, i.Search("1849", 0))
+}
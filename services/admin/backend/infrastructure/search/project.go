@@ -0,0 +1,157 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package search
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// fields of the project documents.
+const (
+	fieldShortCode   = "shortCode"
+	fieldShortName   = "shortName"
+	fieldLongName    = "longName"
+	fieldDescription = "description"
+	fieldKeywords    = "keywords"
+)
+
+// ProjectIndex is a full-text index of all active projects, which is kept up to date by applying project events.
+// It is safe for concurrent use.
+type ProjectIndex struct {
+	mu    sync.RWMutex
+	index *Index
+	// docs contains the current document of each active project, so that events changing single fields can be applied.
+	docs map[string]map[string]string
+	// deleted contains the last document of each deleted project, so that it can be indexed again if the project is restored.
+	deleted map[string]map[string]string
+}
+
+// NewProjectIndex creates an empty project index.
+func NewProjectIndex() *ProjectIndex {
+	return &ProjectIndex{
+		index: NewIndex(NewMultilingualAnalyzer(),
+			Field{Name: fieldShortCode, Boost: 3},
+			Field{Name: fieldShortName, Boost: 3},
+			Field{Name: fieldLongName, Boost: 2},
+			Field{Name: fieldDescription, Boost: 1},
+			Field{Name: fieldKeywords, Boost: 2},
+		),
+		docs:    map[string]map[string]string{},
+		deleted: map[string]map[string]string{},
+	}
+}
+
+// Apply updates the index with the provided event. Events of other aggregates are ignored.
+func (i *ProjectIndex) Apply(ev event.Event) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.apply(ev)
+}
+
+// Rebuild replaces the content of the index with the result of applying the provided events, oldest first.
+func (i *ProjectIndex) Rebuild(events []event.Event) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	fresh := NewProjectIndex()
+	i.index, i.docs, i.deleted = fresh.index, fresh.docs, fresh.deleted
+	for _, ev := range events {
+		i.apply(ev)
+	}
+}
+
+// apply updates the index with the provided event. The caller must hold the lock.
+func (i *ProjectIndex) apply(ev event.Event) {
+	switch e := ev.(type) {
+	case *event.ProjectCreated:
+		i.put(e.ID, map[string]string{
+			fieldShortCode:   e.ShortCode.String(),
+			fieldShortName:   e.ShortName.String(),
+			fieldLongName:    e.LongName.String(),
+			fieldDescription: e.Description.String(),
+			fieldKeywords:    strings.Join(e.Keywords, "\n"),
+		})
+	case *event.ProjectChanged:
+		// the keywords cannot be changed, so they are kept
+		i.put(e.ID, map[string]string{
+			fieldShortCode:   e.ShortCode.String(),
+			fieldShortName:   e.ShortName.String(),
+			fieldLongName:    e.LongName.String(),
+			fieldDescription: e.Description.String(),
+			fieldKeywords:    i.docs[e.ID.String()][fieldKeywords],
+		})
+	case *event.ProjectShortCodeChanged:
+		i.change(e.ID, fieldShortCode, e.ShortCode.String())
+	case *event.ProjectShortNameChanged:
+		i.change(e.ID, fieldShortName, e.ShortName.String())
+	case *event.ProjectLongNameChanged:
+		i.change(e.ID, fieldLongName, e.LongName.String())
+	case *event.ProjectDescriptionChanged:
+		i.change(e.ID, fieldDescription, e.Description.String())
+	case *event.ProjectDeleted:
+		if doc, ok := i.docs[e.ID.String()]; ok {
+			i.deleted[e.ID.String()] = doc
+		}
+		delete(i.docs, e.ID.String())
+		i.index.Delete(e.ID.String())
+	case *event.ProjectRestored:
+		if doc, ok := i.deleted[e.ID.String()]; ok {
+			delete(i.deleted, e.ID.String())
+			i.put(e.ID, doc)
+		}
+	}
+}
+
+// Search returns the ids of the projects matching the query, best matches first.
+// If limit is greater than zero, at most limit ids are returned.
+func (i *ProjectIndex) Search(query string, limit int) []valueobject.Identifier {
+	i.mu.RLock()
+	index := i.index
+	i.mu.RUnlock()
+
+	var ids []valueobject.Identifier
+	for _, hit := range index.Search(query, limit) {
+		id, err := valueobject.IdentifierFromBytes([]byte(hit.ID))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// put stores the document of the project in the index.
+func (i *ProjectIndex) put(id valueobject.Identifier, doc map[string]string) {
+	i.docs[id.String()] = doc
+	i.index.Put(id.String(), doc)
+}
+
+// change updates a single field of a project already in the index.
+func (i *ProjectIndex) change(id valueobject.Identifier, field string, value string) {
+	doc, ok := i.docs[id.String()]
+	if !ok {
+		return
+	}
+	doc[field] = value
+	i.index.Put(id.String(), doc)
+}
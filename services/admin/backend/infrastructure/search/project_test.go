@@ -0,0 +1,103 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package search_test
+
+import (
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/search"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectIndex_Apply(t *testing.T) {
+	i := search.NewProjectIndex()
+
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Letters and manuscripts of the Bernoulli dynasty and Leonhard Euler")
+
+	i.Apply(&event.ProjectCreated{ID: id, ShortCode: sc, ShortName: sn, LongName: ln, Description: desc})
+	assert.Equal(t, []valueobject.Identifier{id}, i.Search("euler", 0))
+	assert.Equal(t, []valueobject.Identifier{id}, i.Search("0801", 0))
+
+	newLongName, _ := valueobject.NewLongName("Basler Edition der Bernoulli-Briefwechsel")
+	i.Apply(&event.ProjectLongNameChanged{ID: id, LongName: newLongName})
+	assert.Equal(t, []valueobject.Identifier{id}, i.Search("briefwechsel", 0))
+
+	newDesc, _ := valueobject.NewDescription("Correspondence")
+	i.Apply(&event.ProjectChanged{ID: id, ShortCode: sc, ShortName: sn, LongName: ln, Description: newDesc})
+	assert.Empty(t, i.Search("briefwechsel", 0))
+	assert.Equal(t, []valueobject.Identifier{id}, i.Search("correspondence", 0))
+
+	i.Apply(&event.ProjectDeleted{ID: id})
+	assert.Empty(t, i.Search("euler", 0))
+
+	i.Apply(&event.ProjectRestored{ID: id})
+	assert.Equal(t, []valueobject.Identifier{id}, i.Search("euler", 0))
+}
+
+func TestProjectIndex_Keywords(t *testing.T) {
+	i := search.NewProjectIndex()
+
+	id, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0803")
+	sn, _ := valueobject.NewShortName("incunabula")
+	ln, _ := valueobject.NewLongName("Bilderfolgen Basler Frühdrucke")
+	desc, _ := valueobject.NewDescription("Basel incunabula")
+
+	i.Apply(&event.ProjectCreated{ID: id, ShortCode: sc, ShortName: sn, LongName: ln, Description: desc, Keywords: []string{"Holzschnitt", "Narrenschiff"}})
+	assert.Equal(t, []valueobject.Identifier{id}, i.Search("narrenschiff", 0))
+
+	// the keywords are kept when the other fields change
+	i.Apply(&event.ProjectChanged{ID: id, ShortCode: sc, ShortName: sn, LongName: ln, Description: desc})
+	assert.Equal(t, []valueobject.Identifier{id}, i.Search("holzschnitt", 0))
+}
+
+func TestProjectIndex_Rebuild(t *testing.T) {
+	i := search.NewProjectIndex()
+
+	beol, _ := valueobject.NewIdentifier()
+	incunabula, _ := valueobject.NewIdentifier()
+	sc, _ := valueobject.NewShortCode("0801")
+	sn, _ := valueobject.NewShortName("beol")
+	ln, _ := valueobject.NewLongName("Bernoulli-Euler Online")
+	desc, _ := valueobject.NewDescription("Letters and manuscripts of the Bernoulli dynasty and Leonhard Euler")
+	sc2, _ := valueobject.NewShortCode("0803")
+	sn2, _ := valueobject.NewShortName("incunabula")
+	ln2, _ := valueobject.NewLongName("Bilderfolgen Basler Frühdrucke")
+	desc2, _ := valueobject.NewDescription("Basel incunabula")
+
+	i.Apply(&event.ProjectCreated{ID: incunabula, ShortCode: sc2, ShortName: sn2, LongName: ln2, Description: desc2})
+
+	events := []event.Event{
+		&event.ProjectCreated{ID: beol, ShortCode: sc, ShortName: sn, LongName: ln, Description: desc},
+	}
+
+	// projects missing from the replayed events are dropped
+	i.Rebuild(events)
+	assert.Equal(t, []valueobject.Identifier{beol}, i.Search("euler", 0))
+	assert.Empty(t, i.Search("incunabula", 0))
+
+	// rebuilding twice yields the same index
+	i.Rebuild(events)
+	assert.Equal(t, []valueobject.Identifier{beol}, i.Search("euler", 0))
+}
@@ -0,0 +1,345 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package shredding encrypts the personal data in events with a key per data subject, so that it can be erased
+// from an immutable event log by destroying the key (crypto-shredding).
+//
+// A personal field of an event holds the identifier of its subject, such as the user who made a change. It is stored as
+//
+//	{"keyId":"…","ciphertext":"…"}
+//
+// with the AES-256-GCM encrypted value, and decrypted when the event is read. Once the key of the subject has been
+// destroyed, readers get a placeholder instead.
+package shredding
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrKeyNotFound is returned by a KeyStore if the key does not exist, or has been destroyed.
+var ErrKeyNotFound = errors.New("the key does not exist or has been destroyed")
+
+// KeySize is the size of the keys in bytes, for AES-256.
+const KeySize = 32
+
+// Key is the key of a data subject.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeyStore holds the keys of the data subjects.
+type KeyStore interface {
+	// SubjectKey returns the key of the subject, and creates one if the subject has none yet.
+	SubjectKey(ctx context.Context, subject string) (Key, error)
+	// Key returns the key with the id, or ErrKeyNotFound.
+	Key(ctx context.Context, id string) (Key, error)
+	// DestroyKeys destroys all keys of the subject, and returns false if the subject had none.
+	DestroyKeys(ctx context.Context, subject string) (bool, error)
+	// ImportKey adds a key of the subject exported from a key store, and returns false if the key has been destroyed in this one.
+	ImportKey(ctx context.Context, subject string, key Key) (bool, error)
+}
+
+// SubjectKey is a key along with the data subject it belongs to.
+type SubjectKey struct {
+	Subject string
+	Key     Key
+}
+
+// NewKey creates a random key with the id.
+func NewKey(id string) (Key, error) {
+	secret := make([]byte, KeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, Secret: secret}, nil
+}
+
+// encrypted is a personal field as stored in an event.
+type encrypted struct {
+	KeyID      string `json:"keyId"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// Protector encrypts and decrypts the personal fields of events.
+// It caches the keys it has used, and remembers destroyed keys, so that most events are read without a lookup.
+type Protector struct {
+	keys        KeyStore
+	fields      []string
+	placeholder string
+	exempt      map[string]bool
+
+	mu        sync.Mutex
+	bySubject map[string]Key
+	byID      map[string]Key
+	destroyed map[string]bool
+}
+
+// NewProtector creates a protector of the JSON fields with the names, which are replaced with the placeholder once their key is destroyed.
+// Field values which do not identify a subject, such as the placeholder, are never encrypted.
+func NewProtector(keys KeyStore, placeholder string, exempt []string, fields ...string) *Protector {
+	p := &Protector{
+		keys:        keys,
+		fields:      fields,
+		placeholder: placeholder,
+		exempt:      map[string]bool{"": true, placeholder: true},
+		bySubject:   map[string]Key{},
+		byID:        map[string]Key{},
+		destroyed:   map[string]bool{},
+	}
+	for _, v := range exempt {
+		p.exempt[v] = true
+	}
+	return p
+}
+
+// Protect encrypts the personal fields of the JSON object, each with the key of the subject it identifies.
+// Data without personal fields is returned unchanged.
+func (p *Protector) Protect(ctx context.Context, data []byte) ([]byte, error) {
+	return p.transform(data, func(name string, value json.RawMessage) (interface{}, error) {
+		var subject string
+		if err := json.Unmarshal(value, &subject); err != nil || p.exempt[subject] {
+			return nil, nil
+		}
+
+		key, err := p.subjectKey(ctx, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		ciphertext, err := seal(key, name, []byte(subject))
+		if err != nil {
+			return nil, err
+		}
+		return encrypted{KeyID: key.ID, Ciphertext: ciphertext}, nil
+	})
+}
+
+// Reveal decrypts the personal fields of the JSON object. Fields whose key has been destroyed are replaced with the placeholder.
+// Fields stored in plain text, before they were protected, are returned as they are.
+func (p *Protector) Reveal(ctx context.Context, data []byte) ([]byte, error) {
+	return p.transform(data, func(name string, value json.RawMessage) (interface{}, error) {
+		var e encrypted
+		if err := json.Unmarshal(value, &e); err != nil || e.KeyID == "" {
+			return nil, nil
+		}
+
+		key, err := p.key(ctx, e.KeyID)
+		if errors.Is(err, ErrKeyNotFound) {
+			return p.placeholder, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		plaintext, err := open(key, name, e.Ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("problem decrypting field '%s': %w", name, err)
+		}
+		return string(plaintext), nil
+	})
+}
+
+// Forget destroys the keys of the subject, so that its personal data can no longer be revealed.
+// It returns false if no personal data of the subject has been protected.
+func (p *Protector) Forget(ctx context.Context, subject string) (bool, error) {
+	forgotten, err := p.keys.DestroyKeys(ctx, subject)
+	if err != nil {
+		return false, err
+	}
+
+	// the keys cached by id are not known to belong to a subject, so they are all read again when needed
+	p.mu.Lock()
+	p.bySubject = map[string]Key{}
+	p.byID = map[string]Key{}
+	p.mu.Unlock()
+
+	return forgotten, nil
+}
+
+// SubjectKeys returns the keys of the personal fields of the JSON object with the subjects they identify, e.g. to export them
+// along with the data. Fields stored in plain text and fields whose key has been destroyed have none.
+func (p *Protector) SubjectKeys(ctx context.Context, data []byte) ([]SubjectKey, error) {
+	var keys []SubjectKey
+	_, err := p.transform(data, func(name string, value json.RawMessage) (interface{}, error) {
+		var e encrypted
+		if err := json.Unmarshal(value, &e); err != nil || e.KeyID == "" {
+			return nil, nil
+		}
+
+		key, err := p.key(ctx, e.KeyID)
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the subject is the plaintext of the field
+		subject, err := open(key, name, e.Ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("problem decrypting field '%s': %w", name, err)
+		}
+		keys = append(keys, SubjectKey{Subject: string(subject), Key: key})
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ImportKey adds a key exported by SubjectKeys, e.g. from an archive. It returns false if the key has been destroyed
+// in the key store, since importing data must not undo the erasure of a subject.
+func (p *Protector) ImportKey(ctx context.Context, key SubjectKey) (bool, error) {
+	imported, err := p.keys.ImportKey(ctx, key.Subject, key.Key)
+	if err != nil {
+		return false, err
+	}
+
+	// the key may have been looked up before it was imported
+	if imported {
+		p.mu.Lock()
+		delete(p.destroyed, key.Key.ID)
+		p.mu.Unlock()
+	}
+	return imported, nil
+}
+
+// transform replaces the personal fields of the JSON object with the values returned by replace, unless they are nil.
+func (p *Protector) transform(data []byte, replace func(name string, value json.RawMessage) (interface{}, error)) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("problem deserializing personal data from json: %w", err)
+	}
+
+	changed := false
+	for _, name := range p.fields {
+		value, ok := object[name]
+		if !ok {
+			continue
+		}
+		v, err := replace(name, value)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		if object[name], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(object)
+}
+
+func (p *Protector) subjectKey(ctx context.Context, subject string) (Key, error) {
+	p.mu.Lock()
+	key, ok := p.bySubject[subject]
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	key, err := p.keys.SubjectKey(ctx, subject)
+	if err != nil {
+		return Key{}, fmt.Errorf("problem getting the key of a data subject: %w", err)
+	}
+
+	p.mu.Lock()
+	p.bySubject[subject] = key
+	p.byID[key.ID] = key
+	p.mu.Unlock()
+	return key, nil
+}
+
+func (p *Protector) key(ctx context.Context, id string) (Key, error) {
+	p.mu.Lock()
+	key, ok := p.byID[id]
+	destroyed := p.destroyed[id]
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if destroyed {
+		return Key{}, ErrKeyNotFound
+	}
+
+	key, err := p.keys.Key(ctx, id)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+		p.destroyed[id] = true
+		return Key{}, err
+	case err != nil:
+		return Key{}, fmt.Errorf("problem getting key '%s': %w", id, err)
+	}
+	p.byID[id] = key
+	return key, nil
+}
+
+// seal encrypts the plaintext of the field, and returns the nonce followed by the ciphertext, base64 encoded.
+// The name of the field is authenticated, so that encrypted values cannot be moved to another field.
+func seal(key Key, field string, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, []byte(field))), nil
+}
+
+// open decrypts a value encrypted by seal.
+func open(key Key, field string, ciphertext string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < gcm.NonceSize() {
+		return nil, errors.New("the ciphertext is too short")
+	}
+	return gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], []byte(field))
+}
+
+func newGCM(key Key) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
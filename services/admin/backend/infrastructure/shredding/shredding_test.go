@@ -0,0 +1,236 @@
+/*
+ *  Copyright 2021 Data and Service Center for the Humanities - DaSCH.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package shredding_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/shredding"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	alice       = "5b2e0d6e-7a4f-4d1c-9a0b-3c5d7e9f1a2b"
+	bob         = "0f1e2d3c-4b5a-4968-8776-655443322110"
+	nobody      = "00000000-0000-0000-0000-000000000000"
+	placeholder = "ffffffff-ffff-ffff-ffff-ffffffffffff"
+)
+
+// stubKeyStore keeps the keys in memory and counts the lookups by id.
+type stubKeyStore struct {
+	keys      map[string]shredding.Key
+	subjects  map[string][]string
+	destroyed map[string]bool
+	lookups   int
+}
+
+func newStubKeyStore() *stubKeyStore {
+	return &stubKeyStore{keys: map[string]shredding.Key{}, subjects: map[string][]string{}, destroyed: map[string]bool{}}
+}
+
+func (s *stubKeyStore) SubjectKey(ctx context.Context, subject string) (shredding.Key, error) {
+	if ids := s.subjects[subject]; len(ids) > 0 {
+		return s.keys[ids[len(ids)-1]], nil
+	}
+	key, err := shredding.NewKey(strconv.Itoa(len(s.keys) + len(s.destroyed) + 1))
+	if err != nil {
+		return shredding.Key{}, err
+	}
+	s.keys[key.ID] = key
+	s.subjects[subject] = append(s.subjects[subject], key.ID)
+	return key, nil
+}
+
+func (s *stubKeyStore) Key(ctx context.Context, id string) (shredding.Key, error) {
+	s.lookups++
+	key, ok := s.keys[id]
+	if !ok {
+		return shredding.Key{}, shredding.ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (s *stubKeyStore) DestroyKeys(ctx context.Context, subject string) (bool, error) {
+	ids := s.subjects[subject]
+	for _, id := range ids {
+		delete(s.keys, id)
+		s.destroyed[id] = true
+	}
+	delete(s.subjects, subject)
+	return len(ids) > 0, nil
+}
+
+func (s *stubKeyStore) ImportKey(ctx context.Context, subject string, key shredding.Key) (bool, error) {
+	if s.destroyed[key.ID] {
+		return false, nil
+	}
+	if _, ok := s.keys[key.ID]; !ok {
+		s.keys[key.ID] = key
+		s.subjects[subject] = append(s.subjects[subject], key.ID)
+	}
+	return true, nil
+}
+
+func newProtector(keys shredding.KeyStore) *shredding.Protector {
+	return shredding.NewProtector(keys, placeholder, []string{nobody}, "createdBy", "changedBy")
+}
+
+func TestProtector_ProtectReveal(t *testing.T) {
+	ctx := context.Background()
+	p := newProtector(newStubKeyStore())
+
+	data := []byte(`{"id":"1","longName":"Bernoulli","createdBy":"` + alice + `","changedBy":"` + bob + `"}`)
+	protected, err := p.Protect(ctx, data)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(protected), alice)
+	assert.NotContains(t, string(protected), bob)
+	assert.Contains(t, string(protected), `"longName":"Bernoulli"`)
+
+	revealed, err := p.Reveal(ctx, protected)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(data), string(revealed))
+
+	// fields without a subject, and data without personal fields, are left as they are
+	for _, d := range []string{`{"id":"1","createdBy":"` + nobody + `"}`, `{"id":"1"}`} {
+		protected, err = p.Protect(ctx, []byte(d))
+		assert.Nil(t, err)
+		assert.Equal(t, d, string(protected))
+	}
+
+	// plain text stored before the fields were protected is revealed as it is
+	revealed, err = p.Reveal(ctx, data)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), string(revealed))
+}
+
+func TestProtector_Forget(t *testing.T) {
+	ctx := context.Background()
+	keys := newStubKeyStore()
+	p := newProtector(keys)
+
+	protected, err := p.Protect(ctx, []byte(`{"createdBy":"`+alice+`","changedBy":"`+bob+`"}`))
+	assert.Nil(t, err)
+
+	forgotten, err := p.Forget(ctx, alice)
+	assert.Nil(t, err)
+	assert.True(t, forgotten)
+
+	// a protector which has never seen the key reads the same
+	for _, p := range []*shredding.Protector{p, newProtector(keys)} {
+		revealed, err := p.Reveal(ctx, protected)
+		assert.Nil(t, err)
+
+		var fields map[string]string
+		assert.Nil(t, json.Unmarshal(revealed, &fields))
+		assert.Equal(t, map[string]string{"createdBy": placeholder, "changedBy": bob}, fields)
+	}
+
+	// destroyed keys are not looked up again
+	lookups := keys.lookups
+	_, err = p.Reveal(ctx, protected)
+	assert.Nil(t, err)
+	assert.Equal(t, lookups, keys.lookups)
+
+	forgotten, err = p.Forget(ctx, alice)
+	assert.Nil(t, err)
+	assert.False(t, forgotten)
+
+	// new personal data of a forgotten subject gets a new key
+	protected, err = p.Protect(ctx, []byte(`{"createdBy":"`+alice+`"}`))
+	assert.Nil(t, err)
+	revealed, err := p.Reveal(ctx, protected)
+	assert.Nil(t, err)
+	assert.Contains(t, string(revealed), alice)
+}
+
+func TestProtector_SubjectKeys(t *testing.T) {
+	ctx := context.Background()
+	p := newProtector(newStubKeyStore())
+
+	data := []byte(`{"createdBy":"` + alice + `","changedBy":"` + bob + `"}`)
+	protected, err := p.Protect(ctx, data)
+	assert.Nil(t, err)
+
+	keys, err := p.SubjectKeys(ctx, protected)
+	assert.Nil(t, err)
+	assert.Len(t, keys, 2)
+
+	// another key store can reveal the data once the keys are imported
+	dst := newStubKeyStore()
+	other := newProtector(dst)
+	revealed, err := other.Reveal(ctx, protected)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(revealed), alice)
+
+	for _, key := range keys {
+		imported, err := other.ImportKey(ctx, key)
+		assert.Nil(t, err)
+		assert.True(t, imported)
+	}
+	revealed, err = other.Reveal(ctx, protected)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(data), string(revealed))
+
+	// importing the keys again does not undo forgetting a subject
+	_, err = other.Forget(ctx, alice)
+	assert.Nil(t, err)
+	for _, key := range keys {
+		imported, err := other.ImportKey(ctx, key)
+		assert.Nil(t, err)
+		assert.Equal(t, key.Subject != alice, imported)
+	}
+	revealed, err = other.Reveal(ctx, protected)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"createdBy":"`+placeholder+`","changedBy":"`+bob+`"}`, string(revealed))
+
+	// destroyed keys and plain text are not exported
+	keys, err = other.SubjectKeys(ctx, protected)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{bob}, subjects(keys))
+	keys, err = p.SubjectKeys(ctx, data)
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+}
+
+func subjects(keys []shredding.SubjectKey) []string {
+	var res []string
+	for _, key := range keys {
+		res = append(res, key.Subject)
+	}
+	return res
+}
+
+func TestProtector_Tampered(t *testing.T) {
+	ctx := context.Background()
+	p := newProtector(newStubKeyStore())
+
+	protected, err := p.Protect(ctx, []byte(`{"createdBy":"`+alice+`"}`))
+	assert.Nil(t, err)
+
+	// encrypted values are bound to their field
+	moved := strings.Replace(string(protected), "createdBy", "changedBy", 1)
+	_, err = p.Reveal(ctx, []byte(moved))
+	assert.NotNil(t, err)
+
+	_, err = p.Reveal(ctx, []byte(`not json`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"context"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// MaxImportRows is the maximum number of projects which can be imported at once.
+const MaxImportRows = 1000
+
+// ImportStatus is the outcome of importing a row.
+type ImportStatus string
+
+const (
+	// ImportValid rows would be created, they are only reported in dry-run mode.
+	ImportValid ImportStatus = "valid"
+	// ImportCreated rows have been created.
+	ImportCreated ImportStatus = "created"
+	// ImportInvalid rows contain invalid values or a short code which is already used, they are never created.
+	ImportInvalid ImportStatus = "invalid"
+	// ImportFailed rows are valid, but could not be saved.
+	ImportFailed ImportStatus = "failed"
+)
+
+// ImportRow contains the values of a project to import, as read from a file.
+type ImportRow struct {
+	// Line is the line of the row in the file, used to report the result.
+	Line        int
+	ShortCode   string
+	ShortName   string
+	LongName    string
+	Description string
+}
+
+// ImportResult is the outcome of importing a row.
+type ImportResult struct {
+	Line      int
+	ShortCode string
+	Status    ImportStatus
+	// ID is the id of the created project, empty unless Status is ImportCreated.
+	ID valueobject.Identifier
+	// Err is the reason why the row is invalid or could not be saved.
+	// A valueobject.ValidationError lists every invalid field of the row.
+	Err error
+}
+
+// ImportProjects validates the rows and, unless dryRun is set, creates a project for every valid row.
+// A row is valid if all its values are valid and its short code is used neither by an existing project,
+// including deleted ones, nor by an earlier row. Invalid rows do not prevent the valid ones from being created.
+// The principal found in the context is recorded as the creator of the projects.
+func (s *Service) ImportProjects(ctx context.Context, rows []ImportRow, dryRun bool) ([]ImportResult, error) {
+
+	if len(rows) == 0 {
+		return nil, project.ErrNoImportRows
+	}
+	if len(rows) > MaxImportRows {
+		return nil, project.ErrTooManyImportRows
+	}
+
+	existingProjects, err := s.ListProjects(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// short codes of the existing projects and of the rows seen so far
+	used := map[string]bool{}
+	for _, p := range existingProjects {
+		used[p.ShortCode().String()] = true
+	}
+	seen := map[string]bool{}
+
+	results := make([]ImportResult, len(rows))
+	for i, row := range rows {
+		res := ImportResult{Line: row.Line, ShortCode: row.ShortCode, Status: ImportValid}
+
+		v := valueobject.Validation{}
+		sc := v.ShortCode("shortCode", row.ShortCode)
+		sn := v.ShortName("shortName", row.ShortName)
+		ln := v.LongName("longName", row.LongName)
+		desc := v.Description("description", row.Description)
+
+		switch {
+		case v.Err() != nil:
+			res.Status, res.Err = ImportInvalid, v.Err()
+		case used[sc.String()]:
+			res.Status, res.Err = ImportInvalid, project.ErrShortCodeAlreadyExists
+		case seen[sc.String()]:
+			res.Status, res.Err = ImportInvalid, project.ErrShortCodeDuplicatedInImport
+		}
+		if sc.String() != "" {
+			seen[sc.String()] = true
+		}
+
+		if res.Status == ImportValid && !dryRun {
+			id, _ := valueobject.NewIdentifier()
+			agg := project.NewAggregate(id, sc, sn, ln, desc, principal.TypedActorFromContext(ctx))
+			if _, err := s.repo.Save(ctx, agg); err != nil {
+				res.Status, res.Err = ImportFailed, err
+			} else {
+				res.Status, res.ID = ImportCreated, id
+			}
+		}
+
+		results[i] = res
+	}
+
+	return results, nil
+}
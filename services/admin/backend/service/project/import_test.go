@@ -0,0 +1,114 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+// importRows returns rows with a valid row, an invalid row, a row using the short code of an existing project
+// and a row repeating the short code of the first row.
+func importRows() []project.ImportRow {
+	return []project.ImportRow{
+		{Line: 2, ShortCode: "0A01", ShortName: "first", LongName: "first project", Description: "the first project"},
+		{Line: 3, ShortCode: "XYZ", ShortName: "", LongName: "second project", Description: "the second project"},
+		{Line: 4, ShortCode: "00FF", ShortName: "existing", LongName: "existing project", Description: "an existing project"},
+		{Line: 5, ShortCode: "0A01", ShortName: "again", LongName: "first project again", Description: "the first project again"},
+	}
+}
+
+// newServiceWithProject creates a service with a project using the short code 00FF.
+func newServiceWithProject(t *testing.T) *project.Service {
+	repo := NewInMemRepo()
+	service := project.NewService(repo)
+	sc, _ := valueobject.NewShortCode("00FF")
+	sn, _ := valueobject.NewShortName("short name")
+	ln, _ := valueobject.NewLongName("long name")
+	desc, _ := valueobject.NewDescription("description")
+	_, err := service.CreateProject(context.Background(), sc, sn, ln, desc)
+	assert.Nil(t, err)
+	return service
+}
+
+func TestService_ImportProjects_DryRun(t *testing.T) {
+	service := newServiceWithProject(t)
+	ctx := context.Background()
+
+	results, err := service.ImportProjects(ctx, importRows(), true)
+	assert.Nil(t, err)
+	assert.Len(t, results, 4)
+
+	assert.Equal(t, 2, results[0].Line)
+	assert.Equal(t, project.ImportValid, results[0].Status)
+	assert.Nil(t, results[0].Err)
+
+	assert.Equal(t, project.ImportInvalid, results[1].Status)
+	var verr *valueobject.ValidationError
+	assert.True(t, errors.As(results[1].Err, &verr))
+	assert.Len(t, verr.Fields, 2)
+
+	assert.Equal(t, project.ImportInvalid, results[2].Status)
+	assert.Equal(t, projectEntity.ErrShortCodeAlreadyExists, results[2].Err)
+
+	assert.Equal(t, project.ImportInvalid, results[3].Status)
+	assert.Equal(t, projectEntity.ErrShortCodeDuplicatedInImport, results[3].Err)
+
+	// nothing has been created
+	projects, err := service.ListProjects(ctx, true)
+	assert.Nil(t, err)
+	assert.Len(t, projects, 1)
+}
+
+func TestService_ImportProjects_Commit(t *testing.T) {
+	service := newServiceWithProject(t)
+	ctx := context.Background()
+
+	results, err := service.ImportProjects(ctx, importRows(), false)
+	assert.Nil(t, err)
+
+	assert.Equal(t, project.ImportCreated, results[0].Status)
+	p, err := service.GetProject(ctx, results[0].ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "0A01", p.ShortCode().String())
+	assert.Equal(t, "first project", p.LongName().String())
+
+	for _, res := range results[1:] {
+		assert.Equal(t, project.ImportInvalid, res.Status)
+		assert.Equal(t, valueobject.Identifier{}, res.ID)
+	}
+
+	projects, err := service.ListProjects(ctx, true)
+	assert.Nil(t, err)
+	assert.Len(t, projects, 2)
+}
+
+func TestService_ImportProjects_RowCount(t *testing.T) {
+	service := project.NewService(NewInMemRepo())
+
+	_, err := service.ImportProjects(context.Background(), nil, true)
+	assert.Equal(t, projectEntity.ErrNoImportRows, err)
+
+	_, err = service.ImportProjects(context.Background(), make([]project.ImportRow, project.MaxImportRows+1), true)
+	assert.Equal(t, projectEntity.ErrTooManyImportRows, err)
+}
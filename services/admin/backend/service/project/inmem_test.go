@@ -18,16 +18,21 @@ package project_test
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/infrastructure/search"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 	"github.com/gofrs/uuid"
 )
 
 //inMemRepo is the in memory repository (only visible inside this package)
 type inMemRepo struct {
-	m map[uuid.UUID][]event.Event
+	m        map[uuid.UUID][]event.Event
+	log      []event.Event
+	watchers []func(ev event.Event)
+	since    []func(id string, ev event.Event)
 }
 
 //NewInMemRepo create a new in memory repository
@@ -53,6 +58,18 @@ func (r *inMemRepo) Save(ctx context.Context, e *project.Aggregate) (valueobject
 
 	// store
 	r.m[e.ID().UUID()] = events
+
+	// notify watchers, the id of an event is its position in the log
+	for _, ev := range e.Events() {
+		r.log = append(r.log, ev)
+		for _, handle := range r.watchers {
+			handle(ev)
+		}
+		for _, handle := range r.since {
+			handle(strconv.Itoa(len(r.log)), ev)
+		}
+	}
+
 	return e.ID(), nil
 }
 
@@ -64,6 +81,14 @@ func (r *inMemRepo) Load(ctx context.Context, id valueobject.Identifier) (*proje
 	return project.NewAggregateFromEvents(r.m[id.UUID()]), nil
 }
 
+//LoadEvents of a project
+func (r *inMemRepo) LoadEvents(ctx context.Context, id valueobject.Identifier) ([]event.Event, error) {
+	if r.m[id.UUID()] == nil {
+		return nil, project.ErrProjectNotFound
+	}
+	return r.m[id.UUID()], nil
+}
+
 func (r *inMemRepo) GetProjectIds(ctx context.Context, returnDeletedProjects bool) ([]valueobject.Identifier, error) {
 
 	i := 0
@@ -84,3 +109,36 @@ func (r *inMemRepo) GetProjectIds(ctx context.Context, returnDeletedProjects boo
 
 	return projectIds, nil
 }
+
+//Search the projects by building a search index from all stored events
+func (r *inMemRepo) Search(ctx context.Context, query string, limit int) ([]valueobject.Identifier, error) {
+	index := search.NewProjectIndex()
+	for _, events := range r.m {
+		for _, e := range events {
+			index.Apply(e)
+		}
+	}
+	return index.Search(query, limit), nil
+}
+
+//Watch passes the events saved from now on to the handler
+func (r *inMemRepo) Watch(ctx context.Context, handle func(ev event.Event)) error {
+	r.watchers = append(r.watchers, handle)
+	return nil
+}
+
+//WatchSince passes the events saved after the event with the id to the handler, or from now on if no id is provided
+func (r *inMemRepo) WatchSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error {
+	from := len(r.log)
+	if lastEventID != "" {
+		var err error
+		if from, err = strconv.Atoi(lastEventID); err != nil || from < 0 || from > len(r.log) {
+			return project.ErrInvalidEventID
+		}
+	}
+	for i := from; i < len(r.log); i++ {
+		handle(strconv.Itoa(i+1), r.log[i])
+	}
+	r.since = append(r.since, handle)
+	return nil
+}
@@ -21,14 +21,18 @@ import (
 	"context"
 
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 )
 
 //Reader interface
 type Reader interface {
 	Load(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error)
+	LoadEvents(ctx context.Context, id valueobject.Identifier) ([]event.Event, error)
 	GetProjectIds(ctx context.Context, returnDeletedProjects bool) ([]valueobject.Identifier, error)
-	// Search(query string) ([]*project.Aggregate, error)
+	Search(ctx context.Context, query string, limit int) ([]valueobject.Identifier, error)
+	Watch(ctx context.Context, handle func(ev event.Event)) error
+	WatchSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error
 	// List() ([]*project.Aggregate, error)
 }
 
@@ -48,8 +52,15 @@ type Repository interface {
 //UseCase interface which should be implemented by services.
 type UseCase interface {
 	GetProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error)
+	GetProjectHistory(ctx context.Context, id valueobject.Identifier) ([]event.Event, error)
 	ListProjects(ctx context.Context, returnDeletedProjects bool) ([]project.Aggregate, error)
+	QueryProjects(ctx context.Context, q Query) (Result, error)
+	SearchProjects(ctx context.Context, query string, limit int) ([]project.Aggregate, error)
 	CreateProject(ctx context.Context, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (valueobject.Identifier, error)
 	UpdateProject(ctx context.Context, id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (*project.Aggregate, error)
 	DeleteProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error)
+	RestoreProject(ctx context.Context, id valueobject.Identifier) (*project.Aggregate, error)
+	ImportProjects(ctx context.Context, rows []ImportRow, dryRun bool) ([]ImportResult, error)
+	WatchProjects(ctx context.Context, handle func(ev event.Event)) error
+	WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error
 }
@@ -0,0 +1,239 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gofrs/uuid"
+)
+
+// MigrationStatus is the outcome of migrating a project from DSP-API.
+type MigrationStatus string
+
+const (
+	// MigrationValid projects would be created, they are only reported in dry-run mode.
+	MigrationValid MigrationStatus = "valid"
+	// MigrationCreated projects have been created.
+	MigrationCreated MigrationStatus = "created"
+	// MigrationUnchanged projects have been migrated before and still have the same values.
+	MigrationUnchanged MigrationStatus = "unchanged"
+	// MigrationDiffers projects have been migrated before, but some of their values differ by now.
+	// They are left as they are, the differing fields are reported.
+	MigrationDiffers MigrationStatus = "differs"
+	// MigrationConflict projects use a short code which is already used by another project.
+	MigrationConflict MigrationStatus = "conflict"
+	// MigrationInvalid projects contain invalid values.
+	MigrationInvalid MigrationStatus = "invalid"
+	// MigrationFailed projects are valid, but could not be loaded or saved.
+	MigrationFailed MigrationStatus = "failed"
+)
+
+// LegacyProject is a project of DSP-API (Knora), as read from an export of its knora-admin data.
+type LegacyProject struct {
+	IRI         string
+	ShortCode   string
+	ShortName   string
+	LongName    string
+	Description string
+	Keywords    []string
+	// Active is false for projects which have been deactivated, they are migrated as deleted projects.
+	Active bool
+	// CreatedAt is the time the project was created in DSP-API. The time of the migration is used if it is zero.
+	CreatedAt time.Time
+}
+
+// MigrationResult is the outcome of migrating a project.
+type MigrationResult struct {
+	IRI       string
+	ShortCode string
+	// ID is the id of the migrated project, derived from the IRI.
+	ID     valueobject.Identifier
+	Status MigrationStatus
+	// Differences lists the fields whose values differ from the project migrated before, if Status is MigrationDiffers.
+	Differences []string
+	// Err is the reason why the project is invalid, conflicts with another project or could not be saved.
+	Err error
+}
+
+// MigrationReport reconciles the projects of DSP-API with the projects of the admin service.
+type MigrationReport struct {
+	DryRun  bool
+	Results []MigrationResult
+}
+
+// Count returns the number of projects with the status.
+func (r MigrationReport) Count(status MigrationStatus) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Migrator migrates the projects of DSP-API into a repository.
+type Migrator struct {
+	repo Repository
+}
+
+// NewMigrator creates a new migrator writing to the repository.
+func NewMigrator(r Repository) *Migrator {
+	return &Migrator{
+		repo: r,
+	}
+}
+
+// MigrationID returns the id of the project migrated from the IRI.
+// It is a name-based UUID of the IRI, so that migrating a project again finds the project migrated before.
+func MigrationID(iri string) valueobject.Identifier {
+	id, _ := valueobject.IdentifierFromBytes([]byte(uuid.NewV5(uuid.NamespaceURL, iri).String()))
+	return id
+}
+
+// Migrate creates a ProjectCreated event for every valid project, keeping its IRI, keywords and creation time,
+// followed by a ProjectDeleted event for inactive projects. Unless dryRun is set, the events are saved to the repository.
+//
+// The migration is idempotent: projects which have been migrated before are not changed, but compared with the
+// legacy project, and reported as unchanged or differing. Projects are never created with a short code which is
+// already used by another project.
+func (m *Migrator) Migrate(ctx context.Context, projects []LegacyProject, dryRun bool) (MigrationReport, error) {
+	report := MigrationReport{DryRun: dryRun}
+
+	ids, err := m.repo.GetProjectIds(ctx, true)
+	if err != nil {
+		return report, err
+	}
+
+	// ids of the projects by short code, including deleted ones
+	shortCodes := map[string]valueobject.Identifier{}
+	for _, id := range ids {
+		p, err := m.repo.Load(ctx, id)
+		if err != nil {
+			return report, err
+		}
+		shortCodes[p.ShortCode().String()] = id
+	}
+
+	seen := map[string]bool{}
+	for _, lp := range projects {
+		res := m.migrate(ctx, lp, shortCodes, seen, dryRun)
+		if res.Status == MigrationValid || res.Status == MigrationCreated {
+			shortCodes[lp.ShortCode] = res.ID
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	return report, nil
+}
+
+// migrate migrates a single project.
+func (m *Migrator) migrate(ctx context.Context, lp LegacyProject, shortCodes map[string]valueobject.Identifier, seen map[string]bool, dryRun bool) MigrationResult {
+	res := MigrationResult{IRI: lp.IRI, ShortCode: lp.ShortCode}
+
+	if strings.TrimSpace(lp.IRI) == "" {
+		res.Status, res.Err = MigrationInvalid, project.ErrMissingIRI
+		return res
+	}
+	if seen[lp.IRI] {
+		res.Status, res.Err = MigrationInvalid, project.ErrIRIDuplicatedInMigration
+		return res
+	}
+	seen[lp.IRI] = true
+	res.ID = MigrationID(lp.IRI)
+
+	v := valueobject.Validation{}
+	sc := v.ShortCode("shortCode", lp.ShortCode)
+	sn := v.ShortName("shortName", lp.ShortName)
+	ln := v.LongName("longName", lp.LongName)
+	desc := v.Description("description", lp.Description)
+	if v.Err() != nil {
+		res.Status, res.Err = MigrationInvalid, v.Err()
+		return res
+	}
+
+	existing, err := m.repo.Load(ctx, res.ID)
+	switch {
+	case err == nil:
+		res.Differences = differences(existing, lp)
+		res.Status = MigrationUnchanged
+		if len(res.Differences) > 0 {
+			res.Status = MigrationDiffers
+		}
+		return res
+	case !errors.Is(err, project.ErrProjectNotFound):
+		res.Status, res.Err = MigrationFailed, err
+		return res
+	}
+
+	if other, ok := shortCodes[sc.String()]; ok && !other.Equals(res.ID) {
+		res.Status, res.Err = MigrationConflict, project.ErrShortCodeAlreadyExists
+		return res
+	}
+
+	res.Status = MigrationValid
+	if dryRun {
+		return res
+	}
+
+	createdAt := valueobject.NewTimestamp()
+	if !lp.CreatedAt.IsZero() {
+		createdAt = valueobject.NewTimestampFromTime(lp.CreatedAt)
+	}
+	agg := project.NewMigratedAggregate(res.ID, lp.IRI, sc, sn, ln, desc, lp.Keywords, createdAt)
+	if !lp.Active {
+		_ = agg.DeleteProject(res.ID, event.Actor{})
+	}
+
+	if _, err := m.repo.Save(ctx, agg); err != nil {
+		res.Status, res.Err = MigrationFailed, err
+		return res
+	}
+	res.Status = MigrationCreated
+	return res
+}
+
+// differences returns the fields of the migrated project whose values differ from the legacy project.
+func differences(p *project.Aggregate, lp LegacyProject) []string {
+	var fields []string
+	if p.ShortCode().String() != lp.ShortCode {
+		fields = append(fields, "shortCode")
+	}
+	if p.ShortName().String() != lp.ShortName {
+		fields = append(fields, "shortName")
+	}
+	if p.LongName().String() != lp.LongName {
+		fields = append(fields, "longName")
+	}
+	if p.Description().String() != lp.Description {
+		fields = append(fields, "description")
+	}
+	if strings.Join(p.Keywords(), "\n") != strings.Join(lp.Keywords, "\n") {
+		fields = append(fields, "keywords")
+	}
+	if p.DeletedAt().Time().IsZero() != lp.Active {
+		fields = append(fields, "status")
+	}
+	return fields
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+// legacyProjects returns an active project, an inactive project, an invalid project,
+// a project using the short code of an existing project and a project repeating the IRI of the first one.
+func legacyProjects() []project.LegacyProject {
+	return []project.LegacyProject{
+		{IRI: "http://rdfh.ch/projects/0001", ShortCode: "0001", ShortName: "anything", LongName: "Anything Project", Description: "Anything Project", Keywords: []string{"things"}, Active: true, CreatedAt: time.Date(2016, 3, 1, 9, 15, 30, 250000000, time.UTC)},
+		{IRI: "http://rdfh.ch/projects/0002", ShortCode: "0002", ShortName: "old", LongName: "Old Project", Description: "An old project", Active: false},
+		{IRI: "http://rdfh.ch/projects/0003", ShortCode: "XYZ", ShortName: "invalid", LongName: "Invalid Project", Description: "An invalid project", Active: true},
+		{IRI: "http://rdfh.ch/projects/00FF", ShortCode: "00FF", ShortName: "taken", LongName: "Taken Project", Description: "A project with a taken short code", Active: true},
+		{IRI: "http://rdfh.ch/projects/0001", ShortCode: "0001", ShortName: "anything", LongName: "Anything Project", Description: "Anything Project", Active: true},
+	}
+}
+
+// newRepoWithProject creates a repository with a project using the short code 00FF.
+func newRepoWithProject(t *testing.T) project.Repository {
+	repo := NewInMemRepo()
+	sc, _ := valueobject.NewShortCode("00FF")
+	sn, _ := valueobject.NewShortName("short name")
+	ln, _ := valueobject.NewLongName("long name")
+	desc, _ := valueobject.NewDescription("description")
+	_, err := project.NewService(repo).CreateProject(context.Background(), sc, sn, ln, desc)
+	assert.Nil(t, err)
+	return repo
+}
+
+func TestMigrator_Migrate(t *testing.T) {
+	repo := newRepoWithProject(t)
+	ctx := context.Background()
+
+	report, err := project.NewMigrator(repo).Migrate(ctx, legacyProjects(), false)
+	assert.Nil(t, err)
+	assert.Len(t, report.Results, 5)
+	assert.Equal(t, 2, report.Count(project.MigrationCreated))
+
+	assert.Equal(t, project.MigrationCreated, report.Results[0].Status)
+	assert.Equal(t, project.MigrationID("http://rdfh.ch/projects/0001"), report.Results[0].ID)
+	p, err := repo.Load(ctx, report.Results[0].ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://rdfh.ch/projects/0001", p.IRI())
+	assert.Equal(t, "anything", p.ShortName().String())
+	assert.Equal(t, []string{"things"}, p.Keywords())
+	assert.True(t, time.Date(2016, 3, 1, 9, 15, 30, 250000000, time.UTC).Equal(p.CreatedAt().Time()))
+	assert.True(t, p.DeletedAt().Time().IsZero())
+
+	// inactive projects are migrated as deleted projects
+	assert.Equal(t, project.MigrationCreated, report.Results[1].Status)
+	p, err = repo.Load(ctx, report.Results[1].ID)
+	assert.Nil(t, err)
+	assert.False(t, p.DeletedAt().Time().IsZero())
+
+	assert.Equal(t, project.MigrationInvalid, report.Results[2].Status)
+	var verr *valueobject.ValidationError
+	assert.True(t, errors.As(report.Results[2].Err, &verr))
+
+	assert.Equal(t, project.MigrationConflict, report.Results[3].Status)
+	assert.Equal(t, projectEntity.ErrShortCodeAlreadyExists, report.Results[3].Err)
+
+	assert.Equal(t, project.MigrationInvalid, report.Results[4].Status)
+	assert.Equal(t, projectEntity.ErrIRIDuplicatedInMigration, report.Results[4].Err)
+}
+
+func TestMigrator_Migrate_DryRun(t *testing.T) {
+	repo := newRepoWithProject(t)
+	ctx := context.Background()
+
+	report, err := project.NewMigrator(repo).Migrate(ctx, legacyProjects(), true)
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Count(project.MigrationValid))
+	assert.Equal(t, 0, report.Count(project.MigrationCreated))
+
+	_, err = repo.Load(ctx, project.MigrationID("http://rdfh.ch/projects/0001"))
+	assert.Equal(t, projectEntity.ErrProjectNotFound, err)
+}
+
+func TestMigrator_Migrate_Idempotent(t *testing.T) {
+	repo := newRepoWithProject(t)
+	ctx := context.Background()
+	migrator := project.NewMigrator(repo)
+
+	_, err := migrator.Migrate(ctx, legacyProjects()[:2], false)
+	assert.Nil(t, err)
+
+	// migrating again creates nothing and reports the projects which changed in DSP-API since
+	projects := legacyProjects()[:2]
+	projects[0].LongName = "Anything Project, renamed"
+	projects[1].Active = true
+	report, err := migrator.Migrate(ctx, projects, false)
+	assert.Nil(t, err)
+	assert.Equal(t, project.MigrationDiffers, report.Results[0].Status)
+	assert.Equal(t, []string{"longName"}, report.Results[0].Differences)
+	assert.Equal(t, project.MigrationDiffers, report.Results[1].Status)
+	assert.Equal(t, []string{"status"}, report.Results[1].Differences)
+
+	report, err = migrator.Migrate(ctx, legacyProjects()[:2], false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, report.Count(project.MigrationUnchanged))
+
+	ids, err := repo.GetProjectIds(ctx, true)
+	assert.Nil(t, err)
+	assert.Len(t, ids, 3)
+}
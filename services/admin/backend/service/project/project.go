@@ -18,7 +18,10 @@ package project
 
 import (
 	"context"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 )
 
@@ -35,6 +38,7 @@ func NewService(r Repository) *Service {
 }
 
 // CreateProject creates new project with the provided values.
+// The principal found in the context is recorded as the creator of the project.
 func (s *Service) CreateProject(ctx context.Context, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (valueobject.Identifier, error) {
 
 	// generate new uuid
@@ -61,7 +65,7 @@ func (s *Service) CreateProject(ctx context.Context, shortCode valueobject.Short
 	}
 
 	// create project aggregate
-	agg := project.NewAggregate(id, shortCode, shortName, longName, description)
+	agg := project.NewAggregate(id, shortCode, shortName, longName, description, principal.TypedActorFromContext(ctx))
 
 	// save event to event store
 	if _, err := s.repo.Save(ctx, agg); err != nil {
@@ -72,6 +76,7 @@ func (s *Service) CreateProject(ctx context.Context, shortCode valueobject.Short
 }
 
 // UpdateProject updates the current project info with the provided values.
+// The principal found in the context is recorded as the one who changed the project.
 func (s *Service) UpdateProject(ctx context.Context, id valueobject.Identifier, shortCode valueobject.ShortCode, shortName valueobject.ShortName, longName valueobject.LongName, description valueobject.Description) (*project.Aggregate, error) {
 
 	// get the project to update
@@ -91,7 +96,7 @@ func (s *Service) UpdateProject(ctx context.Context, id valueobject.Identifier,
 	}
 
 	// update the project
-	if err := p.UpdateProject(id, shortCode, shortName, longName, description); err != nil {
+	if err := p.UpdateProject(id, shortCode, shortName, longName, description, principal.TypedActorFromContext(ctx)); err != nil {
 		return &project.Aggregate{}, err
 	}
 
@@ -104,6 +109,7 @@ func (s *Service) UpdateProject(ctx context.Context, id valueobject.Identifier,
 }
 
 // DeleteProject deletes a project corresponding to the provided uuid.
+// The principal found in the context is recorded as the one who deleted the project.
 func (s *Service) DeleteProject(ctx context.Context, uuid valueobject.Identifier) (*project.Aggregate, error) {
 
 	// get the project to delete
@@ -113,7 +119,31 @@ func (s *Service) DeleteProject(ctx context.Context, uuid valueobject.Identifier
 	}
 
 	// delete the project
-	p.DeleteProject(uuid)
+	p.DeleteProject(uuid, principal.TypedActorFromContext(ctx))
+
+	// save the event
+	if _, err := s.repo.Save(ctx, p); err != nil {
+		return &project.Aggregate{}, err
+	}
+
+	return p, nil
+}
+
+// RestoreProject restores a deleted project corresponding to the provided uuid.
+// The project keeps its short code, which cannot be used by other projects while it is deleted.
+// The principal found in the context is recorded as the one who restored the project.
+func (s *Service) RestoreProject(ctx context.Context, uuid valueobject.Identifier) (*project.Aggregate, error) {
+
+	// get the project to restore
+	p, err := s.repo.Load(ctx, uuid)
+	if err != nil {
+		return &project.Aggregate{}, err
+	}
+
+	// restore the project
+	if err := p.RestoreProject(principal.TypedActorFromContext(ctx)); err != nil {
+		return &project.Aggregate{}, err
+	}
 
 	// save the event
 	if _, err := s.repo.Save(ctx, p); err != nil {
@@ -134,6 +164,17 @@ func (s *Service) GetProject(ctx context.Context, uuid valueobject.Identifier) (
 	return p, nil
 }
 
+// GetProjectHistory gets all events of the project with the corresponding uuid, oldest first.
+func (s *Service) GetProjectHistory(ctx context.Context, uuid valueobject.Identifier) ([]event.Event, error) {
+
+	events, err := s.repo.LoadEvents(ctx, uuid)
+	if err != nil {
+		return []event.Event{}, err
+	}
+
+	return events, nil
+}
+
 // ListProjects lists all the active projects found in the event store.
 // returnDeletedProjects can be used to also return projects that have been marked as deleted.
 func (s *Service) ListProjects(ctx context.Context, returnDeletedProjects bool) ([]project.Aggregate, error) {
@@ -168,3 +209,44 @@ func isIdentical(p project.Aggregate, shortCode valueobject.ShortCode, shortName
 
 	return false
 }
+
+// SearchProjects returns the active projects matching the full-text query, best matches first.
+// If limit is greater than zero, at most limit projects are returned.
+func (s *Service) SearchProjects(ctx context.Context, query string, limit int) ([]project.Aggregate, error) {
+
+	ids, err := s.repo.Search(ctx, query, limit)
+	if err != nil {
+		return []project.Aggregate{}, err
+	}
+
+	projects := []project.Aggregate{}
+
+	for _, id := range ids {
+		p, err := s.GetProject(ctx, id)
+		if err != nil {
+			return []project.Aggregate{}, err
+		}
+
+		// the index might not have caught up with a deletion yet
+		if !p.DeletedAt().Time().IsZero() {
+			continue
+		}
+
+		projects = append(projects, *p)
+	}
+
+	return projects, nil
+}
+
+// WatchProjects passes every project event recorded from now on to the handler, until the context is done.
+// The handler is called from a single goroutine, in the order the events were recorded.
+func (s *Service) WatchProjects(ctx context.Context, handle func(ev event.Event)) error {
+	return s.repo.Watch(ctx, handle)
+}
+
+// WatchProjectsSince passes every project event recorded after the event with the provided id to the handler, until the context is done.
+// The id of each event is passed along, so that a client can resume watching where it left off.
+// If no id is provided, only the events recorded from now on are passed to the handler.
+func (s *Service) WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error {
+	return s.repo.WatchSince(ctx, lastEventID, handle)
+}
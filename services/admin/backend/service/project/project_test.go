@@ -23,6 +23,8 @@ import (
 	"testing"
 	"time"
 
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
 	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
 	"github.com/stretchr/testify/assert"
 )
@@ -238,3 +240,118 @@ func TestService_DeleteProject(t *testing.T) {
 	assert.NotZero(t, deletedProject.DeletedAt())
 	// TODO: assert DeletedBy is not an empty UUID
 }
+
+func TestService_RestoreProject(t *testing.T) {
+	repo := NewInMemRepo()
+	service := project.NewService(repo)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	// create value objects
+	sc, _ := valueobject.NewShortCode("00FF")
+	sn, _ := valueobject.NewShortName("short name")
+	ln, _ := valueobject.NewLongName("project long name")
+	desc, _ := valueobject.NewDescription("project description")
+
+	// create project
+	projectId, _ := service.CreateProject(ctx, sc, sn, ln, desc)
+
+	// active projects cannot be restored
+	_, err := service.RestoreProject(ctx, projectId)
+	assert.Equal(t, projectEntity.ErrProjectNotDeleted, err)
+
+	// delete and restore project
+	_, err = service.DeleteProject(ctx, projectId)
+	assert.Nil(t, err)
+	restoredProject, err := service.RestoreProject(ctx, projectId)
+	assert.Nil(t, err)
+	assert.True(t, restoredProject.DeletedAt().Time().IsZero())
+
+	p, err := service.GetProject(ctx, projectId)
+	assert.Nil(t, err)
+	assert.True(t, p.DeletedAt().Time().IsZero())
+}
+
+func TestService_SearchProjects(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000A", "000B"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	// all projects match, the one matching more tokens ranks first
+	projects, err := service.SearchProjects(ctx, "long name 000b", 0)
+	assert.Nil(t, err)
+	assert.Len(t, projects, 2)
+	assert.Equal(t, ids["000B"], projects[0].ID())
+
+	projects, err = service.SearchProjects(ctx, "long name 000b", 1)
+	assert.Nil(t, err)
+	assert.Len(t, projects, 1)
+
+	// deleted projects are not returned
+	service.DeleteProject(ctx, ids["000B"])
+	projects, err = service.SearchProjects(ctx, "000b", 0)
+	assert.Nil(t, err)
+	assert.Empty(t, projects)
+}
+
+func TestService_GetProjectHistory(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000A"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	p, _ := service.GetProject(ctx, ids["000A"])
+	ln, _ := valueobject.NewLongName("changed long name")
+	_, err := service.UpdateProject(ctx, ids["000A"], p.ShortCode(), p.ShortName(), ln, p.Description())
+	assert.Nil(t, err)
+	_, err = service.DeleteProject(ctx, ids["000A"])
+	assert.Nil(t, err)
+
+	// all events are returned, oldest first
+	history, err := service.GetProjectHistory(ctx, ids["000A"])
+	assert.Nil(t, err)
+	assert.Len(t, history, 3)
+	assert.IsType(t, &event.ProjectCreated{}, history[0])
+	assert.IsType(t, &event.ProjectChanged{}, history[1])
+	assert.IsType(t, &event.ProjectDeleted{}, history[2])
+
+	unknown, _ := valueobject.NewIdentifier()
+	_, err = service.GetProjectHistory(ctx, unknown)
+	assert.Equal(t, projectEntity.ErrProjectNotFound, err)
+}
+
+func TestService_WatchProjects(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000A"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	var watched []event.Event
+	assert.Nil(t, service.WatchProjects(ctx, func(ev event.Event) { watched = append(watched, ev) }))
+
+	// only events recorded after the call are passed to the handler
+	_, err := service.DeleteProject(ctx, ids["000A"])
+	assert.Nil(t, err)
+	assert.Len(t, watched, 1)
+	assert.IsType(t, &event.ProjectDeleted{}, watched[0])
+}
+
+func TestService_WatchProjectsSince(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000A"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	// without an id only the events recorded after the call are passed to the handler
+	var watchedIDs []string
+	assert.Nil(t, service.WatchProjectsSince(ctx, "", func(id string, ev event.Event) { watchedIDs = append(watchedIDs, id) }))
+	_, err := service.DeleteProject(ctx, ids["000A"])
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2"}, watchedIDs)
+
+	// watching can be resumed after an event, which is not passed to the handler again
+	var resumed []event.Event
+	assert.Nil(t, service.WatchProjectsSince(ctx, "1", func(id string, ev event.Event) { resumed = append(resumed, ev) }))
+	assert.Len(t, resumed, 1)
+	assert.IsType(t, &event.ProjectDeleted{}, resumed[0])
+
+	err = service.WatchProjectsSince(ctx, "not an id", func(id string, ev event.Event) {})
+	assert.Equal(t, projectEntity.ErrInvalidEventID, err)
+}
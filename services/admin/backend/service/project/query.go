@@ -0,0 +1,286 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// SortField is a field projects can be sorted by.
+type SortField string
+
+const (
+	SortByShortCode SortField = "shortCode"
+	SortByShortName SortField = "shortName"
+	SortByCreatedAt SortField = "createdAt"
+	SortByChangedAt SortField = "changedAt"
+)
+
+// Status is the lifecycle status of a project.
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusDeleted Status = "deleted"
+	StatusAll     Status = "all"
+)
+
+// Query describes which projects should be returned by QueryProjects and in which order.
+// The zero value returns all active projects sorted by short code.
+type Query struct {
+	// Limit is the maximum number of projects returned, 0 means no limit.
+	Limit int
+	// After is the cursor returned as NextCursor by the previous page.
+	After string
+	// Sort is the field the projects are sorted by, defaults to SortByShortCode.
+	Sort SortField
+	// Descending reverses the sort order.
+	Descending bool
+	// Status restricts the result to projects with the given status, defaults to StatusActive.
+	Status Status
+	// ShortName only returns projects whose short name contains the value, ignoring case.
+	ShortName string
+	// LongName only returns projects whose long name contains the value, ignoring case.
+	LongName string
+	// Name only returns projects whose short or long name contains the value, ignoring case.
+	Name string
+	// ProjectIDs restricts the result to the projects with the given ids, unless it is nil.
+	ProjectIDs []string
+	// ModifiedFrom only returns projects modified at or after the time, unless it is zero. See ModifiedAt.
+	ModifiedFrom time.Time
+	// ModifiedUntil only returns projects modified at or before the time, unless it is zero. See ModifiedAt.
+	ModifiedUntil time.Time
+}
+
+// Result is a page of projects returned by QueryProjects.
+type Result struct {
+	// Projects of the current page.
+	Projects []project.Aggregate
+	// Total is the number of projects matching the query, across all pages.
+	Total int
+	// NextCursor can be passed as Query.After to get the next page. It is empty on the last page.
+	NextCursor string
+}
+
+// QueryProjects returns a page of the projects matching the provided query.
+// Pages are addressed with an opaque cursor, which stays valid if projects are added or removed in the meantime.
+// A cursor is only valid with the sort field and order of the query it was returned for.
+// Every page loads, filters and sorts all projects, so the cost of a page grows with the number of projects, not with the limit.
+func (s *Service) QueryProjects(ctx context.Context, q Query) (Result, error) {
+
+	if q.Sort == "" {
+		q.Sort = SortByShortCode
+	}
+	if q.Status == "" {
+		q.Status = StatusActive
+	}
+
+	key, err := sortKeyFunc(q.Sort)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if q.Status != StatusActive && q.Status != StatusDeleted && q.Status != StatusAll {
+		return Result{}, project.ErrInvalidStatus
+	}
+
+	var after *cursor
+	if q.After != "" {
+		c, err := decodeCursor(q.After)
+		if err != nil {
+			return Result{}, err
+		}
+		if c.sort != q.Sort || c.descending != q.Descending {
+			return Result{}, project.ErrInvalidCursor
+		}
+		after = &c
+	}
+
+	projects, err := s.ListProjects(ctx, q.Status != StatusActive)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// apply the filters
+	var matches []project.Aggregate
+	for _, p := range projects {
+		if q.matches(p) {
+			matches = append(matches, p)
+		}
+	}
+
+	// sort by the requested field, ties are broken by the id to get a stable order
+	less := func(a, b cursor) bool {
+		if a.key != b.key {
+			return (a.key < b.key) != q.Descending
+		}
+		return (a.id < b.id) != q.Descending
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return less(q.cursorOf(matches[i], key), q.cursorOf(matches[j], key))
+	})
+
+	res := Result{
+		Projects: []project.Aggregate{},
+		Total:    len(matches),
+	}
+
+	for _, p := range matches {
+		if after != nil && !less(*after, q.cursorOf(p, key)) {
+			continue
+		}
+		if q.Limit > 0 && len(res.Projects) == q.Limit {
+			res.NextCursor = q.cursorOf(res.Projects[len(res.Projects)-1], key).encode()
+			break
+		}
+		res.Projects = append(res.Projects, p)
+	}
+
+	return res, nil
+}
+
+// matches returns true if the project passes all filters of the query.
+func (q Query) matches(p project.Aggregate) bool {
+	deleted := !p.DeletedAt().Time().IsZero()
+	if (q.Status == StatusActive && deleted) || (q.Status == StatusDeleted && !deleted) {
+		return false
+	}
+
+	if q.ProjectIDs != nil && !contains(q.ProjectIDs, p.ID().String()) {
+		return false
+	}
+
+	modified := ModifiedAt(p).Time()
+	if !q.ModifiedFrom.IsZero() && modified.Before(q.ModifiedFrom) {
+		return false
+	}
+	if !q.ModifiedUntil.IsZero() && modified.After(q.ModifiedUntil) {
+		return false
+	}
+
+	shortName := strings.ToLower(p.ShortName().String())
+	longName := strings.ToLower(p.LongName().String())
+
+	if q.ShortName != "" && !strings.Contains(shortName, strings.ToLower(q.ShortName)) {
+		return false
+	}
+	if q.LongName != "" && !strings.Contains(longName, strings.ToLower(q.LongName)) {
+		return false
+	}
+	if q.Name != "" && !strings.Contains(shortName, strings.ToLower(q.Name)) && !strings.Contains(longName, strings.ToLower(q.Name)) {
+		return false
+	}
+
+	return true
+}
+
+// ModifiedAt returns the time the project was last modified: when it was deleted, last changed or created.
+func ModifiedAt(p project.Aggregate) valueobject.Timestamp {
+	if !p.DeletedAt().Time().IsZero() {
+		return p.DeletedAt()
+	}
+	if !p.ChangedAt().Time().IsZero() {
+		return p.ChangedAt()
+	}
+	return p.CreatedAt()
+}
+
+// sortKeyFunc returns a function which maps a project to a string, whose lexical order is the order of the sort field.
+func sortKeyFunc(field SortField) (func(p project.Aggregate) string, error) {
+	switch field {
+	case SortByShortCode:
+		return func(p project.Aggregate) string { return p.ShortCode().String() }, nil
+	case SortByShortName:
+		return func(p project.Aggregate) string { return strings.ToLower(p.ShortName().String()) }, nil
+	case SortByCreatedAt:
+		return func(p project.Aggregate) string { return timeKey(p.CreatedAt()) }, nil
+	case SortByChangedAt:
+		return func(p project.Aggregate) string { return timeKey(p.ChangedAt()) }, nil
+	default:
+		return nil, project.ErrInvalidSortField
+	}
+}
+
+// timeKey formats the timestamp with a fixed width, so that the lexical order is the chronological order.
+func timeKey(t valueobject.Timestamp) string {
+	return t.Time().UTC().Format("20060102150405.000000000")
+}
+
+// cursor identifies the position of a project within the sort order.
+type cursor struct {
+	// sort and descending are the sort order the position refers to.
+	sort       SortField
+	descending bool
+	key        string
+	id         string
+}
+
+// cursorOf returns the cursor pointing at the provided project in the sort order of the query.
+func (q Query) cursorOf(p project.Aggregate, key func(p project.Aggregate) string) cursor {
+	return cursor{sort: q.Sort, descending: q.Descending, key: key(p), id: p.ID().String()}
+}
+
+// encode returns the opaque string representation of the cursor.
+func (c cursor) encode() string {
+	order := "asc"
+	if c.descending {
+		order = "desc"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(string(c.sort) + "\x00" + order + "\x00" + c.key + "\x00" + c.id))
+}
+
+// decodeCursor parses a cursor previously returned by encode.
+func decodeCursor(s string) (cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, project.ErrInvalidCursor
+	}
+
+	// the key is the only part which may contain the separator
+	parts := strings.SplitN(string(b), "\x00", 3)
+	if len(parts) != 3 || (parts[1] != "asc" && parts[1] != "desc") {
+		return cursor{}, project.ErrInvalidCursor
+	}
+	i := strings.LastIndex(parts[2], "\x00")
+	if i < 0 {
+		return cursor{}, project.ErrInvalidCursor
+	}
+	key, id := parts[2][:i], parts[2][i+1:]
+
+	if _, err := valueobject.IdentifierFromBytes([]byte(id)); err != nil {
+		return cursor{}, project.ErrInvalidCursor
+	}
+
+	return cursor{sort: SortField(parts[0]), descending: parts[1] == "desc", key: key, id: id}, nil
+}
+
+// contains returns true if the list contains the value.
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
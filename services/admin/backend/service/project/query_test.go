@@ -0,0 +1,181 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	projectEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/project"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/project"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_QueryProjects_Pagination(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000C", "000A", "000D", "000B"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	page, err := service.QueryProjects(ctx, project.Query{Limit: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, page.Total)
+	assert.Equal(t, []string{"000A", "000B", "000C"}, shortCodes(page.Projects))
+	assert.NotEmpty(t, page.NextCursor)
+
+	page, err = service.QueryProjects(ctx, project.Query{Limit: 3, After: page.NextCursor})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, page.Total)
+	assert.Equal(t, []string{"000D"}, shortCodes(page.Projects))
+	assert.Empty(t, page.NextCursor)
+
+	// the cursor stays valid if the project it points at is deleted in the meantime
+	first, _ := service.QueryProjects(ctx, project.Query{Limit: 1})
+	service.DeleteProject(ctx, ids["000A"])
+	page, err = service.QueryProjects(ctx, project.Query{Limit: 1, After: first.NextCursor})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000B"}, shortCodes(page.Projects))
+}
+
+func TestService_QueryProjects_SortAndFilter(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000A", "000B", "000C"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	page, err := service.QueryProjects(ctx, project.Query{Sort: project.SortByShortCode, Descending: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000C", "000B", "000A"}, shortCodes(page.Projects))
+
+	page, err = service.QueryProjects(ctx, project.Query{ShortName: "NAME 000B"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000B"}, shortCodes(page.Projects))
+
+	page, err = service.QueryProjects(ctx, project.Query{Name: "long name 000c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000C"}, shortCodes(page.Projects))
+
+	page, err = service.QueryProjects(ctx, project.Query{ProjectIDs: []string{ids["000A"].String()}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000A"}, shortCodes(page.Projects))
+
+	page, err = service.QueryProjects(ctx, project.Query{ProjectIDs: []string{}})
+	assert.Nil(t, err)
+	assert.Empty(t, page.Projects)
+}
+
+func TestService_QueryProjects_Status(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000A", "000B"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	service.DeleteProject(ctx, ids["000A"])
+
+	page, err := service.QueryProjects(ctx, project.Query{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000B"}, shortCodes(page.Projects))
+
+	page, err = service.QueryProjects(ctx, project.Query{Status: project.StatusDeleted})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000A"}, shortCodes(page.Projects))
+
+	page, err = service.QueryProjects(ctx, project.Query{Status: project.StatusAll})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, page.Total)
+}
+
+func TestService_QueryProjects_Modified(t *testing.T) {
+	service, ids := newServiceWithProjects(t, []string{"000A", "000B"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	mid := time.Now()
+	time.Sleep(time.Millisecond)
+	service.DeleteProject(ctx, ids["000A"])
+
+	// deleting a project modifies it
+	page, err := service.QueryProjects(ctx, project.Query{Status: project.StatusAll, ModifiedFrom: mid})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000A"}, shortCodes(page.Projects))
+
+	page, err = service.QueryProjects(ctx, project.Query{Status: project.StatusAll, ModifiedUntil: mid})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000B"}, shortCodes(page.Projects))
+}
+
+func TestService_QueryProjects_InvalidQuery(t *testing.T) {
+	service, _ := newServiceWithProjects(t, []string{"000A"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	_, err := service.QueryProjects(ctx, project.Query{Sort: "description"})
+	assert.Equal(t, projectEntity.ErrInvalidSortField, err)
+
+	_, err = service.QueryProjects(ctx, project.Query{Status: "archived"})
+	assert.Equal(t, projectEntity.ErrInvalidStatus, err)
+
+	_, err = service.QueryProjects(ctx, project.Query{After: "not a cursor"})
+	assert.Equal(t, projectEntity.ErrInvalidCursor, err)
+}
+
+func TestService_QueryProjects_CursorOfAnotherSortOrder(t *testing.T) {
+	service, _ := newServiceWithProjects(t, []string{"000A", "000B", "000C"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	page, err := service.QueryProjects(ctx, project.Query{Limit: 1, Sort: project.SortByShortName})
+	assert.Nil(t, err)
+
+	// a cursor is only valid with the sort field and order it was returned for
+	_, err = service.QueryProjects(ctx, project.Query{Limit: 1, After: page.NextCursor})
+	assert.Equal(t, projectEntity.ErrInvalidCursor, err)
+	_, err = service.QueryProjects(ctx, project.Query{Limit: 1, Sort: project.SortByShortName, Descending: true, After: page.NextCursor})
+	assert.Equal(t, projectEntity.ErrInvalidCursor, err)
+
+	page, err = service.QueryProjects(ctx, project.Query{Limit: 1, Sort: project.SortByShortName, After: page.NextCursor})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"000B"}, shortCodes(page.Projects))
+}
+
+// newServiceWithProjects creates a service with one project per short code and returns the ids by short code.
+func newServiceWithProjects(t *testing.T, shortCodes []string) (*project.Service, map[string]valueobject.Identifier) {
+	service := project.NewService(NewInMemRepo())
+	ids := map[string]valueobject.Identifier{}
+
+	for _, code := range shortCodes {
+		sc, err := valueobject.NewShortCode(code)
+		assert.Nil(t, err)
+		sn, _ := valueobject.NewShortName("name " + code)
+		ln, _ := valueobject.NewLongName("long name " + code)
+		desc, _ := valueobject.NewDescription("description")
+
+		id, err := service.CreateProject(context.Background(), sc, sn, ln, desc)
+		assert.Nil(t, err)
+		ids[code] = id
+	}
+
+	return service, ids
+}
+
+// shortCodes returns the short codes of the projects in order.
+func shortCodes(projects []projectEntity.Aggregate) []string {
+	res := []string{}
+	for _, p := range projects {
+		res = append(res, p.ShortCode().String())
+	}
+	return res
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serviceaccount_test
+
+import (
+	"context"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gofrs/uuid"
+)
+
+//inMemRepo is the in memory repository (only visible inside this package)
+type inMemRepo struct {
+	m map[uuid.UUID][]event.Event
+}
+
+//NewInMemRepo create a new in memory repository
+func NewInMemRepo() *inMemRepo {
+	var m = map[uuid.UUID][]event.Event{}
+	return &inMemRepo{
+		m: m,
+	}
+}
+
+//Save a service account
+func (r *inMemRepo) Save(ctx context.Context, e *serviceaccount.Aggregate) (valueobject.Identifier, error) {
+	r.m[e.ID().UUID()] = append(r.m[e.ID().UUID()], e.Events()...)
+	return e.ID(), nil
+}
+
+//Load a service account
+func (r *inMemRepo) Load(ctx context.Context, id valueobject.Identifier) (*serviceaccount.Aggregate, error) {
+	if r.m[id.UUID()] == nil {
+		return nil, serviceaccount.ErrServiceAccountNotFound
+	}
+	return serviceaccount.NewAggregateFromEvents(r.m[id.UUID()]), nil
+}
+
+func (r *inMemRepo) GetServiceAccountIds(ctx context.Context, returnDeletedServiceAccounts bool) ([]valueobject.Identifier, error) {
+	var ids []valueobject.Identifier
+	for k := range r.m {
+		id, _ := valueobject.IdentifierFromBytes([]byte(k.String()))
+		a, _ := r.Load(ctx, id)
+		if !returnDeletedServiceAccounts && !a.DeletedAt().Time().IsZero() {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serviceaccount
+
+import (
+	"context"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+//Reader interface
+type Reader interface {
+	Load(ctx context.Context, id valueobject.Identifier) (*serviceaccount.Aggregate, error)
+	GetServiceAccountIds(ctx context.Context, returnDeletedServiceAccounts bool) ([]valueobject.Identifier, error)
+}
+
+//Writer interface
+type Writer interface {
+	Save(ctx context.Context, e *serviceaccount.Aggregate) (valueobject.Identifier, error)
+}
+
+//Repository interface which should be implemented by repositories.
+type Repository interface {
+	Reader
+	Writer
+}
+
+//UseCase interface which should be implemented by services.
+type UseCase interface {
+	GetServiceAccount(ctx context.Context, id valueobject.Identifier) (*serviceaccount.Aggregate, error)
+	ListServiceAccounts(ctx context.Context, returnDeletedServiceAccounts bool) ([]serviceaccount.Aggregate, error)
+	CreateServiceAccount(ctx context.Context, name valueobject.LongName, description valueobject.Description) (valueobject.Identifier, error)
+	DeleteServiceAccount(ctx context.Context, id valueobject.Identifier) (*serviceaccount.Aggregate, error)
+	IssueAPIKey(ctx context.Context, id valueobject.Identifier, scopes []serviceaccount.Scope, expiresAt valueobject.Timestamp) (string, *serviceaccount.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id valueobject.Identifier, keyID valueobject.Identifier) (*serviceaccount.Aggregate, error)
+	Authenticate(ctx context.Context, key string) (*serviceaccount.Aggregate, *serviceaccount.APIKey, error)
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serviceaccount
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// KeyPrefix is the prefix of every api key issued by the service.
+// It allows to tell api keys apart from JWTs.
+const KeyPrefix = "dsp."
+
+// keyUsageResolution is the minimum time between two recorded usages of the same api key.
+// It keeps the event stream of a busy service account from growing with every request.
+const keyUsageResolution = time.Minute
+
+// Service interface which contains the repository.
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new service account use case.
+func NewService(r Repository) *Service {
+	return &Service{
+		repo: r,
+	}
+}
+
+// CreateServiceAccount creates a new service account without any api keys.
+// The principal found in the context is recorded as the creator of the service account.
+func (s *Service) CreateServiceAccount(ctx context.Context, name valueobject.LongName, description valueobject.Description) (valueobject.Identifier, error) {
+
+	// generate new uuid
+	id, _ := valueobject.NewIdentifier()
+
+	// create service account aggregate
+	agg := serviceaccount.NewAggregate(id, name, description, principal.TypedActorFromContext(ctx))
+
+	// save event to event store
+	if _, err := s.repo.Save(ctx, agg); err != nil {
+		return valueobject.Identifier{}, err
+	}
+
+	return id, nil
+}
+
+// DeleteServiceAccount deletes the service account corresponding to the provided uuid.
+func (s *Service) DeleteServiceAccount(ctx context.Context, id valueobject.Identifier) (*serviceaccount.Aggregate, error) {
+
+	a, err := s.repo.Load(ctx, id)
+	if err != nil {
+		return &serviceaccount.Aggregate{}, err
+	}
+
+	if err := a.DeleteServiceAccount(principal.TypedActorFromContext(ctx)); err != nil {
+		return &serviceaccount.Aggregate{}, err
+	}
+
+	if _, err := s.repo.Save(ctx, a); err != nil {
+		return &serviceaccount.Aggregate{}, err
+	}
+
+	return a, nil
+}
+
+// GetServiceAccount gets the service account with the corresponding uuid.
+func (s *Service) GetServiceAccount(ctx context.Context, id valueobject.Identifier) (*serviceaccount.Aggregate, error) {
+
+	a, err := s.repo.Load(ctx, id)
+	if err != nil {
+		return &serviceaccount.Aggregate{}, err
+	}
+
+	return a, nil
+}
+
+// ListServiceAccounts lists all the active service accounts found in the event store.
+// returnDeletedServiceAccounts can be used to also return service accounts that have been marked as deleted.
+func (s *Service) ListServiceAccounts(ctx context.Context, returnDeletedServiceAccounts bool) ([]serviceaccount.Aggregate, error) {
+
+	var serviceAccounts []serviceaccount.Aggregate
+
+	ids, err := s.repo.GetServiceAccountIds(ctx, returnDeletedServiceAccounts)
+	if err != nil {
+		return []serviceaccount.Aggregate{}, err
+	}
+
+	for _, id := range ids {
+		a, err := s.GetServiceAccount(ctx, id)
+		if err != nil {
+			return []serviceaccount.Aggregate{}, err
+		}
+
+		serviceAccounts = append(serviceAccounts, *a)
+	}
+
+	return serviceAccounts, nil
+}
+
+// IssueAPIKey issues a new api key for the service account with the provided scopes.
+// The returned plain text key is only available at this point, only its hash is stored.
+func (s *Service) IssueAPIKey(ctx context.Context, id valueobject.Identifier, scopes []serviceaccount.Scope, expiresAt valueobject.Timestamp) (string, *serviceaccount.APIKey, error) {
+
+	a, err := s.repo.Load(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	keyID, err := valueobject.NewIdentifier()
+	if err != nil {
+		return "", nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := a.IssueKey(keyID, hashSecret(secret), scopes, expiresAt, principal.TypedActorFromContext(ctx)); err != nil {
+		return "", nil, err
+	}
+
+	if _, err := s.repo.Save(ctx, a); err != nil {
+		return "", nil, err
+	}
+
+	k, err := a.Key(keyID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return KeyPrefix + id.String() + "." + keyID.String() + "." + secret, &k, nil
+}
+
+// RevokeAPIKey revokes the api key with the provided key id.
+func (s *Service) RevokeAPIKey(ctx context.Context, id valueobject.Identifier, keyID valueobject.Identifier) (*serviceaccount.Aggregate, error) {
+
+	a, err := s.repo.Load(ctx, id)
+	if err != nil {
+		return &serviceaccount.Aggregate{}, err
+	}
+
+	if err := a.RevokeKey(keyID, principal.TypedActorFromContext(ctx)); err != nil {
+		return &serviceaccount.Aggregate{}, err
+	}
+
+	if _, err := s.repo.Save(ctx, a); err != nil {
+		return &serviceaccount.Aggregate{}, err
+	}
+
+	return a, nil
+}
+
+// Authenticate checks the provided plain text api key and returns the service account and the key it belongs to.
+// Successful authentications are recorded as the last usage of the key.
+func (s *Service) Authenticate(ctx context.Context, key string) (*serviceaccount.Aggregate, *serviceaccount.APIKey, error) {
+
+	id, keyID, secret, err := parseKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a, err := s.repo.Load(ctx, id)
+	if err != nil || a.ID() != id {
+		return nil, nil, serviceaccount.ErrInvalidAPIKey
+	}
+
+	if !a.DeletedAt().Time().IsZero() {
+		return nil, nil, serviceaccount.ErrServiceAccountHasBeenDeleted
+	}
+
+	k, err := a.Key(keyID)
+	if err != nil {
+		return nil, nil, serviceaccount.ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(k.Hash()), []byte(hashSecret(secret))) != 1 {
+		return nil, nil, serviceaccount.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if err := k.Validate(now); err != nil {
+		return nil, nil, err
+	}
+
+	// record the usage, failing to do so must not prevent the authentication
+	if now.Sub(k.LastUsedAt().Time()) >= keyUsageResolution {
+		if err := a.RecordKeyUsage(keyID); err == nil {
+			if _, err := s.repo.Save(ctx, a); err != nil {
+				log.Printf("failed to record usage of api key %s: %v", keyID, err)
+			}
+		}
+	}
+
+	return a, &k, nil
+}
+
+// parseKey splits the plain text api key into the service account id, the key id and the secret.
+func parseKey(key string) (valueobject.Identifier, valueobject.Identifier, string, error) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return valueobject.Identifier{}, valueobject.Identifier{}, "", serviceaccount.ErrInvalidAPIKey
+	}
+
+	parts := strings.Split(strings.TrimPrefix(key, KeyPrefix), ".")
+	if len(parts) != 3 || parts[2] == "" {
+		return valueobject.Identifier{}, valueobject.Identifier{}, "", serviceaccount.ErrInvalidAPIKey
+	}
+
+	id, err := valueobject.IdentifierFromBytes([]byte(parts[0]))
+	if err != nil {
+		return valueobject.Identifier{}, valueobject.Identifier{}, "", serviceaccount.ErrInvalidAPIKey
+	}
+
+	keyID, err := valueobject.IdentifierFromBytes([]byte(parts[1]))
+	if err != nil {
+		return valueobject.Identifier{}, valueobject.Identifier{}, "", serviceaccount.ErrInvalidAPIKey
+	}
+
+	return id, keyID, parts[2], nil
+}
+
+// newSecret returns 256 bits of randomness encoded as URL safe base64.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecret returns the hex encoded SHA-256 hash of the secret.
+// A fast hash is sufficient here, since the secret is random and not chosen by a user.
+func hashSecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serviceaccount_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	serviceAccountEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/serviceaccount"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_CreateServiceAccount(t *testing.T) {
+	service := serviceaccount.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createServiceAccount(ctx, t, service)
+
+	a, err := service.GetServiceAccount(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, "ingest pipeline", a.Name().String())
+
+	list, err := service.ListServiceAccounts(ctx, false)
+	assert.Nil(t, err)
+	assert.Len(t, list, 1)
+}
+
+func TestService_IssueAPIKey_Authenticate(t *testing.T) {
+	service := serviceaccount.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createServiceAccount(ctx, t, service)
+
+	key, k, err := service.IssueAPIKey(ctx, id, []serviceAccountEntity.Scope{serviceAccountEntity.ScopeProjectsRead}, valueobject.Timestamp{})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(key, serviceaccount.KeyPrefix))
+
+	// the plain text key must never be stored
+	assert.NotContains(t, k.Hash(), key[strings.LastIndex(key, ".")+1:])
+
+	a, authKey, err := service.Authenticate(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, id, a.ID())
+	assert.Equal(t, k.ID(), authKey.ID())
+	assert.True(t, authKey.HasScope(serviceAccountEntity.ScopeProjectsRead))
+
+	// the usage has been recorded
+	stored, _ := service.GetServiceAccount(ctx, id)
+	storedKey, _ := stored.Key(k.ID())
+	assert.False(t, storedKey.LastUsedAt().Time().IsZero())
+}
+
+func TestService_Authenticate_InvalidKeys(t *testing.T) {
+	service := serviceaccount.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createServiceAccount(ctx, t, service)
+	key, _, _ := service.IssueAPIKey(ctx, id, []serviceAccountEntity.Scope{serviceAccountEntity.ScopeProjectsRead}, valueobject.Timestamp{})
+
+	for _, invalid := range []string{"", "not-a-key", serviceaccount.KeyPrefix + "a.b.c", key + "x", key[:len(key)-1]} {
+		_, _, err := service.Authenticate(ctx, invalid)
+		assert.Equal(t, serviceAccountEntity.ErrInvalidAPIKey, err, invalid)
+	}
+}
+
+func TestService_RevokeAPIKey(t *testing.T) {
+	service := serviceaccount.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createServiceAccount(ctx, t, service)
+	key, k, _ := service.IssueAPIKey(ctx, id, []serviceAccountEntity.Scope{serviceAccountEntity.ScopeProjectsRead}, valueobject.Timestamp{})
+
+	_, err := service.RevokeAPIKey(ctx, id, k.ID())
+	assert.Nil(t, err)
+
+	_, _, err = service.Authenticate(ctx, key)
+	assert.Equal(t, serviceAccountEntity.ErrAPIKeyHasBeenRevoked, err)
+}
+
+func TestService_DeleteServiceAccount(t *testing.T) {
+	service := serviceaccount.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createServiceAccount(ctx, t, service)
+	key, _, _ := service.IssueAPIKey(ctx, id, []serviceAccountEntity.Scope{serviceAccountEntity.ScopeProjectsRead}, valueobject.Timestamp{})
+
+	_, err := service.DeleteServiceAccount(ctx, id)
+	assert.Nil(t, err)
+
+	_, _, err = service.Authenticate(ctx, key)
+	assert.Equal(t, serviceAccountEntity.ErrServiceAccountHasBeenDeleted, err)
+
+	list, _ := service.ListServiceAccounts(ctx, false)
+	assert.Len(t, list, 0)
+}
+
+func createServiceAccount(ctx context.Context, t *testing.T, service *serviceaccount.Service) valueobject.Identifier {
+	name, _ := valueobject.NewLongName("ingest pipeline")
+	desc, _ := valueobject.NewDescription("reads project data during ingest")
+
+	id, err := service.CreateServiceAccount(ctx, name, desc)
+	assert.Nil(t, err)
+
+	return id
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gofrs/uuid"
+)
+
+// headers sent along with every payload.
+const (
+	// HeaderDelivery contains the id of the delivery, it stays the same across retries and can be used to detect duplicates.
+	HeaderDelivery = "X-DSP-Delivery"
+	// HeaderEvent contains the type of the event, e.g. project.created.
+	HeaderEvent = "X-DSP-Event"
+	// HeaderTimestamp contains the unix time in seconds at which the payload was signed.
+	HeaderTimestamp = "X-DSP-Timestamp"
+	// HeaderSignature contains the signature of the payload, see Sign.
+	HeaderSignature = "X-DSP-Signature"
+)
+
+// maxResponseBodySize is the number of bytes read from the response of a webhook before the connection is closed.
+const maxResponseBodySize = 64 << 10
+
+// payload is the body posted to a webhook.
+// Data is the project event, serialized as it is recorded in the event store.
+// Its actors are personal data, which the repository encrypts as long as the payload is kept in the outbox.
+type payload struct {
+	ID        valueobject.Identifier `json:"id"`
+	WebhookID valueobject.Identifier `json:"webhookId"`
+	Type      webhook.EventType      `json:"type"`
+	Event     string                 `json:"event"`
+	EventID   string                 `json:"eventId"`
+	Data      event.Event            `json:"data"`
+}
+
+// Sign returns the signature of the body sent at the provided unix time, formatted as `sha256=<hex encoded HMAC>`.
+// The HMAC-SHA256 is computed with the secret of the webhook over the timestamp, a dot and the body.
+// Receivers should compute the signature themselves and compare it in constant time to the one sent in the X-DSP-Signature header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Start schedules deliveries for every project event recorded after the last checkpoint of the outbox
+// and attempts the due deliveries every PollInterval, until the context is done.
+// The project events are read from the source, the outbox itself is stored in the event store
+// so that no event is lost if the service is restarted.
+func (s *Service) Start(ctx context.Context, source Source) error {
+
+	checkpoint, err := s.repo.LoadCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = source.WatchProjectsSince(ctx, checkpoint, func(id string, ev event.Event) {
+		if err := s.Schedule(ctx, id, ev); err != nil {
+			log.Printf("failed to schedule webhook deliveries for event %s: %v", id, err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := s.DeliverDue(ctx, now); err != nil {
+					log.Printf("failed to deliver webhook payloads: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Schedule adds a delivery to the outbox for every active webhook notified about the project event with the provided id,
+// and records the id as the checkpoint of the outbox afterwards.
+// The id of a delivery is derived from the webhook and the event, so scheduling the same event twice has no effect.
+func (s *Service) Schedule(ctx context.Context, eventID string, ev event.Event) error {
+
+	eventType, ok := webhook.EventTypeOf(ev)
+	if !ok {
+		return nil
+	}
+
+	webhooks, err := s.ListWebhooks(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range webhooks {
+		if !w.Subscribes(eventType) {
+			continue
+		}
+
+		id, _ := valueobject.IdentifierFromBytes([]byte(uuid.NewV5(w.ID().UUID(), eventID).String()))
+
+		if _, err := s.repo.LoadDelivery(ctx, id); err == nil {
+			continue
+		}
+
+		body, err := json.Marshal(payload{
+			ID:        id,
+			WebhookID: w.ID(),
+			Type:      eventType,
+			Event:     strings.TrimPrefix(fmt.Sprintf("%T", ev), "*event."),
+			EventID:   eventID,
+			Data:      ev,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.repo.SaveDelivery(ctx, webhook.NewDelivery(id, w.ID(), eventType, eventID, body)); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.SaveCheckpoint(ctx, eventID)
+}
+
+// DeliverDue attempts every delivery of the outbox which is due at the provided time.
+// Failed attempts are retried with an exponential backoff, until MaxAttempts is reached and the delivery is dead-lettered.
+func (s *Service) DeliverDue(ctx context.Context, now time.Time) error {
+
+	deliveries, err := s.deliveries(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		d := &deliveries[i]
+		if !d.IsDue(now) {
+			continue
+		}
+
+		if err := s.deliver(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliver attempts the delivery once and records the outcome.
+func (s *Service) deliver(ctx context.Context, d *webhook.Delivery) error {
+
+	w, err := s.repo.Load(ctx, d.WebhookID())
+	if err != nil {
+		return err
+	}
+
+	if !w.DeletedAt().Time().IsZero() {
+		d.DeadLetter(webhook.ErrWebhookHasBeenDeleted.Error())
+		_, err := s.repo.SaveDelivery(ctx, d)
+		return err
+	}
+
+	statusCode, err := s.post(ctx, w, d)
+	switch {
+	case err == nil:
+		d.RecordSuccess(statusCode)
+	case d.Attempts()+1 >= s.MaxAttempts:
+		d.RecordFailure(statusCode, err.Error(), time.Time{})
+		d.DeadLetter(fmt.Sprintf("gave up after %d attempts: %v", d.Attempts(), err))
+	default:
+		d.RecordFailure(statusCode, err.Error(), time.Now().Add(s.backoff(d.Attempts()+1)))
+	}
+
+	_, err = s.repo.SaveDelivery(ctx, d)
+	return err
+}
+
+// post sends the signed payload of the delivery to the webhook.
+// An error is returned if the webhook could not be reached or did not respond with a 2xx status code.
+func (s *Service) post(ctx context.Context, w *webhook.Aggregate, d *webhook.Delivery) (int, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL(), bytes.NewReader(d.Payload()))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, d.ID().String())
+	req.Header.Set(HeaderEvent, string(d.EventType()))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret(), timestamp, d.Payload()))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain the response so that the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the attempt following the provided number of failed attempts.
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.MaxDelay {
+			return s.MaxDelay
+		}
+	}
+	if delay > s.MaxDelay {
+		return s.MaxDelay
+	}
+	return delay
+}
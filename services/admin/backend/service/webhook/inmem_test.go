@@ -0,0 +1,143 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/gofrs/uuid"
+)
+
+//inMemRepo is the in memory repository (only visible inside this package)
+type inMemRepo struct {
+	mu         sync.Mutex
+	webhooks   map[uuid.UUID][]event.Event
+	deliveries map[uuid.UUID][]event.Event
+	checkpoint string
+}
+
+//NewInMemRepo create a new in memory repository
+func NewInMemRepo() *inMemRepo {
+	return &inMemRepo{
+		webhooks:   map[uuid.UUID][]event.Event{},
+		deliveries: map[uuid.UUID][]event.Event{},
+	}
+}
+
+//Save a webhook
+func (r *inMemRepo) Save(ctx context.Context, e *webhook.Aggregate) (valueobject.Identifier, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks[e.ID().UUID()] = append(r.webhooks[e.ID().UUID()], e.Events()...)
+	return e.ID(), nil
+}
+
+//Load a webhook
+func (r *inMemRepo) Load(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.webhooks[id.UUID()] == nil {
+		return nil, webhook.ErrWebhookNotFound
+	}
+	return webhook.NewAggregateFromEvents(r.webhooks[id.UUID()]), nil
+}
+
+func (r *inMemRepo) GetWebhookIds(ctx context.Context, returnDeletedWebhooks bool) ([]valueobject.Identifier, error) {
+	var ids []valueobject.Identifier
+	r.mu.Lock()
+	keys := make([]uuid.UUID, 0, len(r.webhooks))
+	for k := range r.webhooks {
+		keys = append(keys, k)
+	}
+	r.mu.Unlock()
+	for _, k := range keys {
+		id, _ := valueobject.IdentifierFromBytes([]byte(k.String()))
+		a, _ := r.Load(ctx, id)
+		if !returnDeletedWebhooks && !a.DeletedAt().Time().IsZero() {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+//SaveDelivery saves a delivery
+func (r *inMemRepo) SaveDelivery(ctx context.Context, d *webhook.Delivery) (valueobject.Identifier, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[d.ID().UUID()] = append(r.deliveries[d.ID().UUID()], d.Events()...)
+	return d.ID(), nil
+}
+
+//LoadDelivery loads a delivery
+func (r *inMemRepo) LoadDelivery(ctx context.Context, id valueobject.Identifier) (*webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deliveries[id.UUID()] == nil {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+	return webhook.NewDeliveryFromEvents(r.deliveries[id.UUID()]), nil
+}
+
+func (r *inMemRepo) GetDeliveryIds(ctx context.Context) ([]valueobject.Identifier, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []valueobject.Identifier
+	for k := range r.deliveries {
+		id, _ := valueobject.IdentifierFromBytes([]byte(k.String()))
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *inMemRepo) LoadCheckpoint(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.checkpoint, nil
+}
+
+func (r *inMemRepo) SaveCheckpoint(ctx context.Context, lastEventID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkpoint = lastEventID
+	return nil
+}
+
+//inMemSource replays the recorded project events, ids are the positions of the events starting with "1"
+type inMemSource struct {
+	events []event.Event
+	since  string
+}
+
+func (s *inMemSource) WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error {
+	s.since = lastEventID
+	for i, ev := range s.events {
+		id := strconv.Itoa(i + 1)
+		if lastEventID != "" {
+			if last, _ := strconv.Atoi(lastEventID); i+1 <= last {
+				continue
+			}
+		}
+		handle(id, ev)
+	}
+	return nil
+}
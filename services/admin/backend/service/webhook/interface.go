@@ -0,0 +1,62 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+//Reader interface
+type Reader interface {
+	Load(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error)
+	GetWebhookIds(ctx context.Context, returnDeletedWebhooks bool) ([]valueobject.Identifier, error)
+	LoadDelivery(ctx context.Context, id valueobject.Identifier) (*webhook.Delivery, error)
+	GetDeliveryIds(ctx context.Context) ([]valueobject.Identifier, error)
+	LoadCheckpoint(ctx context.Context) (string, error)
+}
+
+//Writer interface
+type Writer interface {
+	Save(ctx context.Context, e *webhook.Aggregate) (valueobject.Identifier, error)
+	SaveDelivery(ctx context.Context, d *webhook.Delivery) (valueobject.Identifier, error)
+	SaveCheckpoint(ctx context.Context, lastEventID string) error
+}
+
+//Repository interface which should be implemented by repositories.
+type Repository interface {
+	Reader
+	Writer
+}
+
+//Source interface which should be implemented by the services the project events are read from.
+type Source interface {
+	WatchProjectsSince(ctx context.Context, lastEventID string, handle func(id string, ev event.Event)) error
+}
+
+//UseCase interface which should be implemented by services.
+type UseCase interface {
+	GetWebhook(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error)
+	ListWebhooks(ctx context.Context, returnDeletedWebhooks bool) ([]webhook.Aggregate, error)
+	CreateWebhook(ctx context.Context, url string, eventTypes []webhook.EventType, description valueobject.Description) (valueobject.Identifier, error)
+	DeleteWebhook(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error)
+	ListDeliveries(ctx context.Context, webhookID valueobject.Identifier, status webhook.DeliveryStatus) ([]webhook.Delivery, error)
+	Redeliver(ctx context.Context, webhookID valueobject.Identifier, deliveryID valueobject.Identifier) (*webhook.Delivery, error)
+}
@@ -0,0 +1,244 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/principal"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+)
+
+// default settings of the delivery of the payloads, see the fields of Service.
+const (
+	defaultMaxAttempts  = 8
+	defaultBaseDelay    = 10 * time.Second
+	defaultMaxDelay     = time.Hour
+	defaultPollInterval = 5 * time.Second
+	defaultTimeout      = 10 * time.Second
+)
+
+// Service interface which contains the repository.
+// The exported fields control the delivery of the payloads and may be changed before Start is called.
+type Service struct {
+	repo Repository
+
+	// Client posts the payloads to the webhooks.
+	Client *http.Client
+	// MaxAttempts is the number of failed attempts after which a delivery is dead-lettered.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, it doubles with every further attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts.
+	MaxDelay time.Duration
+	// PollInterval is the time between two checks of the outbox for due deliveries.
+	PollInterval time.Duration
+}
+
+// NewService creates a new webhook use case.
+func NewService(r Repository) *Service {
+	return &Service{
+		repo:         r,
+		Client:       &http.Client{Timeout: defaultTimeout},
+		MaxAttempts:  defaultMaxAttempts,
+		BaseDelay:    defaultBaseDelay,
+		MaxDelay:     defaultMaxDelay,
+		PollInterval: defaultPollInterval,
+	}
+}
+
+// CreateWebhook creates a new webhook with a generated secret, which is used to sign the payloads posted to the url.
+// The principal found in the context is recorded as the creator of the webhook.
+func (s *Service) CreateWebhook(ctx context.Context, url string, eventTypes []webhook.EventType, description valueobject.Description) (valueobject.Identifier, error) {
+
+	if err := webhook.ValidateURL(url); err != nil {
+		return valueobject.Identifier{}, err
+	}
+
+	if len(eventTypes) == 0 {
+		return valueobject.Identifier{}, webhook.ErrNoEventTypesProvided
+	}
+
+	// generate new uuid
+	id, _ := valueobject.NewIdentifier()
+
+	secret, err := newSecret()
+	if err != nil {
+		return valueobject.Identifier{}, err
+	}
+
+	// create webhook aggregate
+	agg := webhook.NewAggregate(id, url, secret, eventTypes, description, principal.TypedActorFromContext(ctx))
+
+	// save event to event store
+	if _, err := s.repo.Save(ctx, agg); err != nil {
+		return valueobject.Identifier{}, err
+	}
+
+	return id, nil
+}
+
+// DeleteWebhook deletes the webhook corresponding to the provided uuid.
+// Pending deliveries to the webhook are dead-lettered when they are attempted next.
+func (s *Service) DeleteWebhook(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error) {
+
+	a, err := s.repo.Load(ctx, id)
+	if err != nil {
+		return &webhook.Aggregate{}, err
+	}
+
+	if err := a.DeleteWebhook(principal.TypedActorFromContext(ctx)); err != nil {
+		return &webhook.Aggregate{}, err
+	}
+
+	if _, err := s.repo.Save(ctx, a); err != nil {
+		return &webhook.Aggregate{}, err
+	}
+
+	return a, nil
+}
+
+// GetWebhook gets the webhook with the corresponding uuid.
+func (s *Service) GetWebhook(ctx context.Context, id valueobject.Identifier) (*webhook.Aggregate, error) {
+
+	a, err := s.repo.Load(ctx, id)
+	if err != nil {
+		return &webhook.Aggregate{}, err
+	}
+
+	return a, nil
+}
+
+// ListWebhooks lists all the active webhooks found in the event store.
+// returnDeletedWebhooks can be used to also return webhooks that have been marked as deleted.
+func (s *Service) ListWebhooks(ctx context.Context, returnDeletedWebhooks bool) ([]webhook.Aggregate, error) {
+
+	var webhooks []webhook.Aggregate
+
+	ids, err := s.repo.GetWebhookIds(ctx, returnDeletedWebhooks)
+	if err != nil {
+		return []webhook.Aggregate{}, err
+	}
+
+	for _, id := range ids {
+		a, err := s.GetWebhook(ctx, id)
+		if err != nil {
+			return []webhook.Aggregate{}, err
+		}
+
+		webhooks = append(webhooks, *a)
+	}
+
+	return webhooks, nil
+}
+
+// ListDeliveries lists the deliveries to the webhook with the provided uuid, oldest first.
+// If no webhook id is provided, the deliveries to all webhooks are listed.
+// If a status is provided, only the deliveries with this status are listed.
+func (s *Service) ListDeliveries(ctx context.Context, webhookID valueobject.Identifier, status webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+
+	if webhookID != (valueobject.Identifier{}) {
+		if _, err := s.repo.Load(ctx, webhookID); err != nil {
+			return []webhook.Delivery{}, err
+		}
+	}
+
+	deliveries, err := s.deliveries(ctx)
+	if err != nil {
+		return []webhook.Delivery{}, err
+	}
+
+	var filtered []webhook.Delivery
+	for _, d := range deliveries {
+		if webhookID != (valueobject.Identifier{}) && d.WebhookID() != webhookID {
+			continue
+		}
+		if status != "" && d.Status() != status {
+			continue
+		}
+		filtered = append(filtered, d)
+	}
+
+	return filtered, nil
+}
+
+// Redeliver requests a delivery to the webhook to be attempted again, e.g. after it has been dead-lettered.
+// The delivery is attempted the next time the outbox is checked for due deliveries.
+func (s *Service) Redeliver(ctx context.Context, webhookID valueobject.Identifier, deliveryID valueobject.Identifier) (*webhook.Delivery, error) {
+
+	if _, err := s.repo.Load(ctx, webhookID); err != nil {
+		return &webhook.Delivery{}, err
+	}
+
+	d, err := s.repo.LoadDelivery(ctx, deliveryID)
+	if err != nil {
+		return &webhook.Delivery{}, err
+	}
+
+	if d.WebhookID() != webhookID {
+		return &webhook.Delivery{}, webhook.ErrDeliveryNotFound
+	}
+
+	if err := d.Redeliver(principal.TypedActorFromContext(ctx)); err != nil {
+		return &webhook.Delivery{}, err
+	}
+
+	if _, err := s.repo.SaveDelivery(ctx, d); err != nil {
+		return &webhook.Delivery{}, err
+	}
+
+	return d, nil
+}
+
+// deliveries loads all deliveries found in the event store, oldest first.
+func (s *Service) deliveries(ctx context.Context) ([]webhook.Delivery, error) {
+
+	ids, err := s.repo.GetDeliveryIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []webhook.Delivery
+	for _, id := range ids {
+		d, err := s.repo.LoadDelivery(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].ScheduledAt().Time().Before(deliveries[j].ScheduledAt().Time())
+	})
+
+	return deliveries, nil
+}
+
+// newSecret returns 256 bits of randomness encoded as URL safe base64.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	webhookEntity "github.com/dasch-swiss/dasch-service-platform/services/admin/backend/entity/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/event"
+	"github.com/dasch-swiss/dasch-service-platform/services/admin/backend/service/webhook"
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+// receiver is a local stand-in for the server behind a webhook.
+// It responds with the queued status codes, 204 once the queue is empty.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		status := http.StatusNoContent
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return r
+}
+
+func (r *receiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func TestService_CreateWebhook(t *testing.T) {
+	service := webhook.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createWebhook(ctx, t, service, "https://example.com/hook", webhookEntity.EventProjectCreated)
+
+	w, err := service.GetWebhook(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/hook", w.URL())
+	assert.Len(t, w.Secret(), 43)
+
+	_, err = service.CreateWebhook(ctx, "example.com/hook", []webhookEntity.EventType{webhookEntity.EventProjectCreated}, valueobject.Description{})
+	assert.Equal(t, webhookEntity.ErrInvalidURL, err)
+
+	_, err = service.CreateWebhook(ctx, "https://example.com/hook", nil, valueobject.Description{})
+	assert.Equal(t, webhookEntity.ErrNoEventTypesProvided, err)
+
+	_, err = service.DeleteWebhook(ctx, id)
+	assert.Nil(t, err)
+
+	list, err := service.ListWebhooks(ctx, false)
+	assert.Nil(t, err)
+	assert.Empty(t, list)
+
+	list, err = service.ListWebhooks(ctx, true)
+	assert.Nil(t, err)
+	assert.Len(t, list, 1)
+}
+
+func TestService_Schedule_DeliverDue(t *testing.T) {
+	rcv := newReceiver()
+	defer rcv.Close()
+
+	service := webhook.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createWebhook(ctx, t, service, rcv.URL, webhookEntity.EventProjectCreated)
+	other := createWebhook(ctx, t, service, rcv.URL, webhookEntity.EventProjectDeleted)
+
+	projectID, _ := valueobject.NewIdentifier()
+	ev := &event.ProjectCreated{ID: projectID}
+
+	assert.Nil(t, service.Schedule(ctx, "1", ev))
+	// scheduling the same event again must not duplicate the delivery
+	assert.Nil(t, service.Schedule(ctx, "1", ev))
+
+	deliveries, err := service.ListDeliveries(ctx, id, webhookEntity.DeliveryPending)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 1)
+
+	deliveries, err = service.ListDeliveries(ctx, other, "")
+	assert.Nil(t, err)
+	assert.Empty(t, deliveries)
+
+	assert.Nil(t, service.DeliverDue(ctx, time.Now()))
+	assert.Equal(t, 1, rcv.received())
+
+	req, body := rcv.requests[0], rcv.bodies[0]
+	assert.Equal(t, "project.created", req.Header.Get(webhook.HeaderEvent))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	// the signature must be verifiable with the secret of the webhook
+	w, _ := service.GetWebhook(ctx, id)
+	timestamp, err := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	assert.Nil(t, err)
+	assert.Equal(t, webhook.Sign(w.Secret(), timestamp, body), req.Header.Get(webhook.HeaderSignature))
+	assert.NotEqual(t, webhook.Sign("another secret", timestamp, body), req.Header.Get(webhook.HeaderSignature))
+
+	var payload struct {
+		ID      string          `json:"id"`
+		Type    string          `json:"type"`
+		Event   string          `json:"event"`
+		EventID string          `json:"eventId"`
+		Data    json.RawMessage `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, req.Header.Get(webhook.HeaderDelivery), payload.ID)
+	assert.Equal(t, "project.created", payload.Type)
+	assert.Equal(t, "ProjectCreated", payload.Event)
+	assert.Equal(t, "1", payload.EventID)
+	assert.Contains(t, string(payload.Data), projectID.String())
+
+	deliveries, err = service.ListDeliveries(ctx, id, "")
+	assert.Nil(t, err)
+	assert.Equal(t, webhookEntity.DeliveryDelivered, deliveries[0].Status())
+	assert.Equal(t, 204, deliveries[0].LastStatusCode())
+
+	// delivered payloads are not sent again
+	assert.Nil(t, service.DeliverDue(ctx, time.Now().Add(time.Hour)))
+	assert.Equal(t, 1, rcv.received())
+}
+
+func TestService_DeliverDue_RetryAndDeadLetter(t *testing.T) {
+	rcv := newReceiver(http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable)
+	defer rcv.Close()
+
+	service := webhook.NewService(NewInMemRepo())
+	service.MaxAttempts = 3
+	service.BaseDelay = time.Minute
+	service.MaxDelay = 90 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createWebhook(ctx, t, service, rcv.URL, webhookEntity.EventProjectDeleted)
+	assert.Nil(t, service.Schedule(ctx, "1", &event.ProjectDeleted{}))
+
+	start := time.Now()
+	assert.Nil(t, service.DeliverDue(ctx, start))
+	deliveries, _ := service.ListDeliveries(ctx, id, "")
+	d := deliveries[0]
+	assert.Equal(t, webhookEntity.DeliveryPending, d.Status())
+	assert.Equal(t, 1, d.Attempts())
+	assert.Equal(t, 500, d.LastStatusCode())
+	assert.Equal(t, "unexpected status code 500", d.LastError())
+	assert.InDelta(t, start.Add(time.Minute).Unix(), d.NextAttemptAt().Unix(), 2)
+
+	// the delivery is not attempted before the backoff has passed
+	assert.Nil(t, service.DeliverDue(ctx, start.Add(30*time.Second)))
+	assert.Equal(t, 1, rcv.received())
+
+	// the second delay is capped by MaxDelay
+	assert.Nil(t, service.DeliverDue(ctx, start.Add(2*time.Minute)))
+	deliveries, _ = service.ListDeliveries(ctx, id, "")
+	assert.Equal(t, 2, deliveries[0].Attempts())
+	assert.InDelta(t, start.Add(90*time.Second).Unix(), deliveries[0].NextAttemptAt().Unix(), 2)
+
+	assert.Nil(t, service.DeliverDue(ctx, start.Add(time.Hour)))
+	assert.Equal(t, 3, rcv.received())
+
+	deadLetters, err := service.ListDeliveries(ctx, valueobject.Identifier{}, webhookEntity.DeliveryDeadLettered)
+	assert.Nil(t, err)
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, "gave up after 3 attempts: unexpected status code 503", deadLetters[0].LastError())
+
+	// dead letters are only attempted again on request
+	assert.Nil(t, service.DeliverDue(ctx, start.Add(2*time.Hour)))
+	assert.Equal(t, 3, rcv.received())
+
+	d2, err := service.Redeliver(ctx, id, deadLetters[0].ID())
+	assert.Nil(t, err)
+	assert.Equal(t, webhookEntity.DeliveryPending, d2.Status())
+
+	_, err = service.Redeliver(ctx, id, deadLetters[0].ID())
+	assert.Equal(t, webhookEntity.ErrDeliveryIsPending, err)
+
+	assert.Nil(t, service.DeliverDue(ctx, time.Now().Add(time.Second)))
+	assert.Equal(t, 4, rcv.received())
+
+	deliveries, _ = service.ListDeliveries(ctx, id, webhookEntity.DeliveryDelivered)
+	assert.Len(t, deliveries, 1)
+}
+
+func TestService_Redeliver_UnknownDelivery(t *testing.T) {
+	service := webhook.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createWebhook(ctx, t, service, "https://example.com/hook", webhookEntity.EventProjectCreated)
+	other := createWebhook(ctx, t, service, "https://example.com/other", webhookEntity.EventProjectCreated)
+	assert.Nil(t, service.Schedule(ctx, "1", &event.ProjectCreated{}))
+
+	deliveries, _ := service.ListDeliveries(ctx, other, "")
+	_, err := service.Redeliver(ctx, id, deliveries[0].ID())
+	assert.Equal(t, webhookEntity.ErrDeliveryNotFound, err)
+
+	unknown, _ := valueobject.NewIdentifier()
+	_, err = service.Redeliver(ctx, unknown, deliveries[0].ID())
+	assert.Equal(t, webhookEntity.ErrWebhookNotFound, err)
+}
+
+func TestService_DeliverDue_DeletedWebhook(t *testing.T) {
+	rcv := newReceiver()
+	defer rcv.Close()
+
+	service := webhook.NewService(NewInMemRepo())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	id := createWebhook(ctx, t, service, rcv.URL, webhookEntity.EventProjectCreated)
+	assert.Nil(t, service.Schedule(ctx, "1", &event.ProjectCreated{}))
+	_, _ = service.DeleteWebhook(ctx, id)
+
+	assert.Nil(t, service.DeliverDue(ctx, time.Now()))
+	assert.Equal(t, 0, rcv.received())
+
+	deliveries, _ := service.ListDeliveries(ctx, id, webhookEntity.DeliveryDeadLettered)
+	assert.Len(t, deliveries, 1)
+}
+
+func TestService_Start(t *testing.T) {
+	rcv := newReceiver()
+	defer rcv.Close()
+
+	repo := NewInMemRepo()
+	service := webhook.NewService(repo)
+	service.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+	defer cancel()
+
+	createWebhook(ctx, t, service, rcv.URL, webhookEntity.EventProjectCreated, webhookEntity.EventProjectChanged)
+	assert.Nil(t, repo.SaveCheckpoint(ctx, "1"))
+
+	// the event before the checkpoint has already been scheduled before a restart
+	source := &inMemSource{events: []event.Event{&event.ProjectCreated{}, &event.ProjectLongNameChanged{}, &event.ProjectDeleted{}}}
+	assert.Nil(t, service.Start(ctx, source))
+	assert.Equal(t, "1", source.since)
+
+	checkpoint, _ := repo.LoadCheckpoint(ctx)
+	assert.Equal(t, "3", checkpoint)
+
+	assert.Eventually(t, func() bool { return rcv.received() == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "project.changed", rcv.requests[0].Header.Get(webhook.HeaderEvent))
+}
+
+func createWebhook(ctx context.Context, t *testing.T, service *webhook.Service, url string, eventTypes ...webhookEntity.EventType) valueobject.Identifier {
+	description, _ := valueobject.NewDescription("notifies the archive")
+	id, err := service.CreateWebhook(ctx, url, eventTypes, description)
+	assert.Nil(t, err)
+	return id
+}
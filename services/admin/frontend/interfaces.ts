@@ -21,12 +21,27 @@ export interface Project {
     shortName: string;
     longName: string;
     description: string;
-    createdAt: string;
-    createdBy: string;
-    changedAt: string;
-    changedBy: string;
-    deletedAt: string;
-    deletedBy: string;
+    keywords: string[];
+    createdAt: string | null;
+    createdBy: Actor | null;
+    changedAt: string | null;
+    changedBy: Actor | null;
+    deletedAt: string | null;
+    deletedBy: Actor | null;
+}
+
+export interface Actor {
+    id: string;
+    type: 'user' | 'serviceAccount' | null;
+}
+
+export interface ProjectEvent {
+    type: 'created' | 'changed' | 'deleted';
+    event: string;
+    projectId: string;
+    at: string | null;
+    by: Actor | null;
+    project?: Project;
 }
 
 export interface User {
@@ -15,7 +15,7 @@
  *
  */
 import { writable } from 'svelte/store'
-import type { Project, User} from './interfaces';
+import type { Project, ProjectEvent, User} from './interfaces';
 
 export const projectsList = writable([] as Project[]);
 export const currentProject = writable({} as Project);
@@ -27,7 +27,7 @@ const baseUrl = `${protocol}//${window.location.hostname}${port}/`;
 
 export async function getProjects(jwt: string, returnDeletedProjects?: boolean): Promise<void> {
 
-  const response = await fetch(`${baseUrl}v1/projects`, {
+  const response = await fetch(`${baseUrl}v2/projects?includeDeleted=${!!returnDeletedProjects}`, {
     headers: {'Authorization': 'Bearer ' + jwt}
   });
   
@@ -38,7 +38,7 @@ export async function getProjects(jwt: string, returnDeletedProjects?: boolean):
 
 export async function getProject(jwt: string, uuid: string): Promise<void> {
 
-  const response = await fetch(`${baseUrl}v1/projects/${uuid}`, {
+  const response = await fetch(`${baseUrl}v2/projects/${uuid}`, {
     headers: {'Authorization': 'Bearer ' + jwt}
   });
   
@@ -56,7 +56,7 @@ export async function createProject(jwt: string, sc: string, sn: string, ln: str
     description: desc
   }
 
-  const response = await fetch(`${baseUrl}v1/projects`, {
+  const response = await fetch(`${baseUrl}v2/projects`, {
     method: 'POST',
     headers: {'Authorization': 'Bearer ' + jwt},
     body: JSON.stringify(p)
@@ -76,7 +76,7 @@ export async function editProject(jwt: string, uuid: string, sc: string, sn: str
     description: desc
   }
 
-  const response = await fetch(`${baseUrl}v1/projects/${uuid}`, {
+  const response = await fetch(`${baseUrl}v2/projects/${uuid}`, {
     method: 'PUT',
     headers: {'Authorization': 'Bearer ' + jwt},
     body: JSON.stringify(p)
@@ -89,7 +89,7 @@ export async function editProject(jwt: string, uuid: string, sc: string, sn: str
 
 export async function deleteProject(jwt: string, uuid: string): Promise<void> {
 
-  const response = await fetch(`${baseUrl}v1/projects/${uuid}`, {
+  const response = await fetch(`${baseUrl}v2/projects/${uuid}`, {
     method: 'DELETE',
     headers: {'Authorization': 'Bearer ' + jwt}
   });
@@ -99,6 +99,95 @@ export async function deleteProject(jwt: string, uuid: string): Promise<void> {
   });
 }
 
+/**
+ * Keeps the project list and the current project in sync with the changes made in other tabs or by other users,
+ * by listening to the stream of project events. Returns a function to stop listening.
+ *
+ * EventSource cannot send the Authorization header, so the stream is read with fetch.
+ * After the connection is lost, listening is resumed after the last event received.
+ */
+export function watchProjects(jwt: string, returnDeletedProjects?: boolean): () => void {
+  const controller = new AbortController();
+  let lastEventId = '';
+
+  async function listen(): Promise<void> {
+    const headers: Record<string, string> = {'Authorization': 'Bearer ' + jwt};
+    if (lastEventId) {
+      headers['Last-Event-ID'] = lastEventId;
+    }
+
+    const response = await fetch(`${baseUrl}v1/events/projects`, {headers, signal: controller.signal});
+    if (!response.ok || !response.body) {
+      throw new Error(`watching the projects failed with status ${response.status}`);
+    }
+
+    const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+    let buffer = '';
+    while (true) {
+      const {value, done} = await reader.read();
+      if (done) {
+        return;
+      }
+
+      // events are separated by a blank line, lines starting with a colon are comments
+      buffer += value;
+      let end: number;
+      while ((end = buffer.indexOf('\n\n')) >= 0) {
+        const message = buffer.slice(0, end);
+        buffer = buffer.slice(end + 2);
+
+        let data = '';
+        for (const line of message.split('\n')) {
+          if (line.startsWith('id: ')) {
+            lastEventId = line.slice(4);
+          } else if (line.startsWith('data: ')) {
+            data += line.slice(6);
+          }
+        }
+        if (data) {
+          applyProjectEvent(JSON.parse(data), !!returnDeletedProjects);
+        }
+      }
+    }
+  }
+
+  (async () => {
+    while (!controller.signal.aborted) {
+      try {
+        await listen();
+      } catch (err) {
+        if (controller.signal.aborted) {
+          return;
+        }
+        console.log(err);
+      }
+      await new Promise(resolve => setTimeout(resolve, 3000));
+    }
+  })();
+
+  return () => controller.abort();
+}
+
+function applyProjectEvent(ev: ProjectEvent, returnDeletedProjects: boolean): void {
+  if (!ev.project) {
+    return;
+  }
+  const project = ev.project;
+
+  projectsList.update(projects => {
+    if (ev.type === 'deleted' && !returnDeletedProjects) {
+      return projects.filter(p => p.id !== project.id);
+    }
+    const i = projects.findIndex(p => p.id === project.id);
+    if (i === -1) {
+      return [...projects, project];
+    }
+    return [...projects.slice(0, i), project, ...projects.slice(i + 1)];
+  });
+
+  currentProject.update(current => current.id === project.id ? project : current);
+}
+
 export function getUser(user: User) {
   console.log(user);
   currentUser.set(user);
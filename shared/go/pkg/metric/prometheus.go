@@ -48,18 +48,19 @@ func NewPrometheusService() (*service, error) {
 		Buckets:   prometheus.DefBuckets,
 	}, []string{"handler", "method", "code"})
 
-	s := &service{
-		pHistogram:           cli,
-		httpRequestHistogram: http,
-	}
-	err := prometheus.Register(s.pHistogram)
-	if err != nil && err.Error() != "duplicate metrics collector registration attempted" {
+	cli, err := register(cli)
+	if err != nil {
 		return nil, err
 	}
-	err = prometheus.Register(s.httpRequestHistogram)
-	if err != nil && err.Error() != "duplicate metrics collector registration attempted" {
+	http, err = register(http)
+	if err != nil {
 		return nil, err
 	}
+
+	s := &service{
+		pHistogram:           cli,
+		httpRequestHistogram: http,
+	}
 	return s, nil
 }
 
@@ -67,3 +68,17 @@ func NewPrometheusService() (*service, error) {
 func (s *service) SaveHTTP(h *HTTP) {
 	s.httpRequestHistogram.WithLabelValues(h.Handler, h.Method, h.StatusCode).Observe(h.Duration)
 }
+
+//register registers the histogram, the identical histogram registered by another service is reused
+func register(h *prometheus.HistogramVec) (*prometheus.HistogramVec, error) {
+	err := prometheus.Register(h)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		if existing, ok := are.ExistingCollector.(*prometheus.HistogramVec); ok {
+			return existing, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,10 +18,15 @@ import (
 	"github.com/urfave/negroni"
 )
 
+// defaultTimeout is the time allowed for reading a request and writing its response.
+const defaultTimeout = 1 * time.Second
+
 // NewAPISPAServer returns a new server instance.
 //
 // By default it will serve:
-//  `./public/index.html`
+//
+//	`./public/index.html`
+//
 // for the single page application.
 func NewAPISPAServer(port string) *APISPAServer {
 	r := mux.NewRouter()
@@ -32,7 +38,8 @@ func NewAPISPAServer(port string) *APISPAServer {
 		port:   port,
 		Router: *r,
 		// Negroni: *n,
-		spa: defaultSPAHandler,
+		spa:     defaultSPAHandler,
+		timeout: defaultTimeout,
 	}
 }
 
@@ -41,36 +48,50 @@ APISPAServer is a representation of a basic server that serves a
 single page application (SPA) and an application programming interface (API).
 
 The server is created by calling e.g.:
- s := NewAPISPAServer("8080")
+
+	s := NewAPISPAServer("8080")
 
 After which, API routes can be registered on the server's router:
- r := &server.Router
- r.HandleFunc("/api/v1/status", getStatus).Methods("GET")
+
+	r := &server.Router
+	r.HandleFunc("/api/v1/status", getStatus).Methods("GET")
 
 By default, the SPA is served from:
- `./public/index.html`
+
+	`./public/index.html`
 
 The SPA directory can be changed by calling:
- server.SetSPA("public/service-xy")
+
+	server.SetSPA("public/service-xy")
+
 This will then serve:
- `./public/service-xy/index.html`
+
+	`./public/service-xy/index.html`
+
+A sitemap of the SPA's pages is served at `/sitemap.xml` after calling:
+
+	server.SetSitemap(sm)
 
 Finally, the server can be started:
- log.Fatal(server.ListenAndServe())
+
+	log.Fatal(server.ListenAndServe())
 */
 type APISPAServer struct {
-	port   string
-	Router mux.Router
-	spa    spaHandler
-	srv    http.Server
-	stop   chan bool
+	port    string
+	Router  mux.Router
+	spa     spaHandler
+	sitemap *Sitemap
+	timeout time.Duration
+	srv     http.Server
+	stop    chan bool
 }
 
 /*
 Set the path to the single page application.
 
 The path passed should point to the directory where `index.html` lies, though without tailing slash:
- srv.SetSPA("public/admin")
+
+	srv.SetSPA("public/admin")
 */
 func (server *APISPAServer) SetSPA(path string) {
 	server.spa = spaHandler{
@@ -79,9 +100,56 @@ func (server *APISPAServer) SetSPA(path string) {
 	}
 }
 
+// Set the sitemap served at `/sitemap.xml`.
+func (server *APISPAServer) SetSitemap(sitemap *Sitemap) {
+	server.sitemap = sitemap
+}
+
+/*
+Set the time allowed for reading a request and writing its response, which is one second by default.
+
+Long-lived responses, such as streams of server-sent events, should not disable the timeout for all requests,
+but extend the deadline of their own connection before every write:
+
+	server.ExtendDeadline(r, 30*time.Second)
+
+The request headers must always be received within one second.
+*/
+func (server *APISPAServer) SetTimeout(timeout time.Duration) {
+	server.timeout = timeout
+}
+
+// connContextKey is the context key of the connection a request has been received on.
+type connContextKey struct{}
+
+/*
+ExtendDeadline allows the response to the request to be written until the timeout from now, regardless of the timeout of the server.
+Reading from the connection is no longer limited, as the server would otherwise cancel the request once the read timeout has passed.
+
+It is meant for long-lived responses such as streams of server-sent events, which call it before every write,
+so that a client which stops reading is still disconnected. Requests which have not been received by the server,
+e.g. in tests using httptest, are left unchanged.
+*/
+func ExtendDeadline(r *http.Request, timeout time.Duration) error {
+	c, ok := r.Context().Value(connContextKey{}).(net.Conn)
+	if !ok {
+		return nil
+	}
+	if err := c.SetReadDeadline(time.Time{}); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(time.Now().Add(timeout))
+}
+
 // Create an http.Server from the APISPAServer. is called from ListenAndServe().
 // The `log` flag specifies if negroni should log
 func (server *APISPAServer) prepare(log bool) http.Server {
+	// apply sitemap handler
+	if server.sitemap != nil {
+		server.Router.Handle("/sitemap.xml", server.sitemap).Methods("GET")
+		server.Router.Handle("/sitemap-{n:[0-9]+}.xml", server.sitemap).Methods("GET")
+	}
+
 	// apply SPA handler
 	server.Router.PathPrefix("/").Handler(server.spa)
 
@@ -97,10 +165,15 @@ func (server *APISPAServer) prepare(log bool) http.Server {
 	n.UseHandler(&server.Router)
 
 	srv := &http.Server{
-		Handler:      n,
-		Addr:         ":" + server.port,
-		WriteTimeout: 1 * time.Second,
-		ReadTimeout:  1 * time.Second,
+		Handler:           n,
+		Addr:              ":" + server.port,
+		WriteTimeout:      server.timeout,
+		ReadTimeout:       server.timeout,
+		ReadHeaderTimeout: defaultTimeout,
+		// the connection is made available to the handlers, so that they can extend its deadline
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, connContextKey{}, c)
+		},
 	}
 	return *srv
 }
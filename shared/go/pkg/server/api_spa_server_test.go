@@ -179,3 +179,40 @@ func TestServer(t *testing.T) {
 		assert.NotNil(t, res)
 	})
 }
+
+func TestServerTimeout(t *testing.T) {
+	s := NewAPISPAServer("8080")
+	srv := s.prepare(false)
+	assert.Equal(t, time.Second, srv.ReadTimeout)
+	assert.Equal(t, time.Second, srv.WriteTimeout)
+
+	assert.Equal(t, time.Second, srv.ReadHeaderTimeout)
+}
+
+func TestExtendDeadline(t *testing.T) {
+	s := NewAPISPAServer("8080")
+	s.Router.HandleFunc("/stream", func(rw http.ResponseWriter, r *http.Request) {
+		rw.(http.Flusher).Flush()
+		for i := 0; i < 3; i++ {
+			assert.Nil(t, ExtendDeadline(r, time.Second))
+			time.Sleep(600 * time.Millisecond)
+			io.WriteString(rw, "data\n")
+			rw.(http.Flusher).Flush()
+		}
+	})
+	srv := s.prepare(false)
+	ts := httptest.NewUnstartedServer(srv.Handler)
+	ts.Config.ReadTimeout = srv.ReadTimeout
+	ts.Config.WriteTimeout = srv.WriteTimeout
+	ts.Config.ConnContext = srv.ConnContext
+	ts.Start()
+	defer ts.Close()
+
+	// the stream outlives the timeout of the server
+	res, err := http.Get(ts.URL + "/stream")
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "data\ndata\ndata\n", string(b))
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/snabb/sitemap"
+)
+
+// MaxSitemapURLs is the maximum number of URLs of a single sitemap allowed by the protocol, see https://www.sitemaps.org/protocol.html.
+const MaxSitemapURLs = 50000
+
+/*
+Sitemap lists the pages of the single page application for search engines.
+
+The pages are added and removed as the underlying data changes, e.g.:
+ sm := NewSitemap("https://admin.dasch.swiss")
+ sm.Set("/projects/1234", changedAt)
+
+It is served as `/sitemap.xml` once it is passed to the server:
+ server.SetSitemap(sm)
+
+If there are more pages than a single sitemap may contain, `/sitemap.xml` is a sitemap index
+referencing the sitemaps `/sitemap-1.xml`, `/sitemap-2.xml` and so on.
+The documents are only generated again after the pages changed, not on every request.
+*/
+type Sitemap struct {
+	// MaxURLs is the maximum number of URLs per sitemap, defaults to MaxSitemapURLs.
+	MaxURLs int
+
+	baseURL   string
+	mu        sync.Mutex
+	pages     map[string]time.Time
+	documents map[string][]byte
+}
+
+// NewSitemap creates an empty sitemap, whose URLs are the paths of the pages appended to the base URL.
+func NewSitemap(baseURL string) *Sitemap {
+	return &Sitemap{
+		MaxURLs: MaxSitemapURLs,
+		baseURL: baseURL,
+		pages:   map[string]time.Time{},
+	}
+}
+
+// Set adds the page with the path or updates the time it was last modified.
+func (s *Sitemap) Set(path string, lastMod time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages[path] = lastMod
+	s.documents = nil
+}
+
+// Remove removes the page with the path.
+func (s *Sitemap) Remove(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pages[path]; ok {
+		delete(s.pages, path)
+		s.documents = nil
+	}
+}
+
+// ServeHTTP serves the sitemap or sitemap index at `/sitemap.xml` and the sitemaps of an index.
+func (s *Sitemap) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if s.documents == nil {
+		s.documents = s.generate()
+	}
+	doc, ok := s.documents[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	if _, err := w.Write(doc); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// generate returns the documents of the sitemap by path, the pages are listed in lexical order.
+func (s *Sitemap) generate() map[string][]byte {
+	paths := make([]string, 0, len(s.pages))
+	for p := range s.pages {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	max := s.MaxURLs
+	if max <= 0 || max > MaxSitemapURLs {
+		max = MaxSitemapURLs
+	}
+
+	docs := map[string][]byte{}
+	if len(paths) <= max {
+		docs["/sitemap.xml"] = s.urlset(paths)
+		return docs
+	}
+
+	index := sitemap.NewSitemapIndex()
+	for i := 0; i*max < len(paths); i++ {
+		chunk := paths[i*max:]
+		if len(chunk) > max {
+			chunk = chunk[:max]
+		}
+
+		path := "/sitemap-" + strconv.Itoa(i+1) + ".xml"
+		docs[path] = s.urlset(chunk)
+		index.Add(&sitemap.URL{Loc: s.baseURL + path, LastMod: s.latest(chunk)})
+	}
+	docs["/sitemap.xml"] = encode(index)
+
+	return docs
+}
+
+// urlset returns the sitemap listing the pages.
+func (s *Sitemap) urlset(paths []string) []byte {
+	sm := sitemap.New()
+	for _, p := range paths {
+		lastMod := s.pages[p]
+		u := &sitemap.URL{Loc: s.baseURL + p}
+		if !lastMod.IsZero() {
+			u.LastMod = &lastMod
+		}
+		sm.Add(u)
+	}
+	return encode(sm)
+}
+
+// latest returns the time the most recently modified of the pages was modified, or nil if it is unknown.
+func (s *Sitemap) latest(paths []string) *time.Time {
+	var res *time.Time
+	for _, p := range paths {
+		if lastMod := s.pages[p]; !lastMod.IsZero() && (res == nil || lastMod.After(*res)) {
+			res = &lastMod
+		}
+	}
+	return res
+}
+
+// encode writes the sitemap or sitemap index into a buffer.
+func encode(doc io.WriterTo) []byte {
+	var b bytes.Buffer
+	if _, err := doc.WriteTo(&b); err != nil {
+		log.Println(err.Error())
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type urlset struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+}
+
+type sitemapindex struct {
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+}
+
+func getSitemap(t *testing.T, s *APISPAServer, path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	srv := s.prepare(false)
+	srv.Handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSitemap(t *testing.T) {
+	first := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	second := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	sm := NewSitemap("https://example.com")
+	sm.Set("/projects/b", second)
+	sm.Set("/projects/a", first)
+	sm.Set("/projects/c", first)
+	sm.Remove("/projects/c")
+
+	s := NewAPISPAServer("8080")
+	s.SetSPA("testfiles")
+	s.SetSitemap(sm)
+
+	rr := getSitemap(t, s, "/sitemap.xml")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", rr.Header().Get("Content-Type"))
+
+	var res urlset
+	assert.Nil(t, xml.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Len(t, res.URLs, 2)
+	assert.Equal(t, "https://example.com/projects/a", res.URLs[0].Loc)
+	assert.Equal(t, "2021-06-01T12:00:00Z", res.URLs[0].LastMod)
+	assert.Equal(t, "https://example.com/projects/b", res.URLs[1].Loc)
+	assert.Equal(t, "2021-07-01T12:00:00Z", res.URLs[1].LastMod)
+
+	// changes are picked up on the next request
+	sm.Set("/projects/a", second)
+	rr = getSitemap(t, s, "/sitemap.xml")
+	res = urlset{}
+	assert.Nil(t, xml.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Equal(t, "2021-07-01T12:00:00Z", res.URLs[0].LastMod)
+
+	// there is no index for a single sitemap
+	rr = getSitemap(t, s, "/sitemap-1.xml")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestSitemap_Index(t *testing.T) {
+	first := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	second := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	sm := NewSitemap("https://example.com")
+	sm.MaxURLs = 2
+	sm.Set("/projects/a", first)
+	sm.Set("/projects/b", second)
+	sm.Set("/projects/c", first)
+
+	s := NewAPISPAServer("8080")
+	s.SetSPA("testfiles")
+	s.SetSitemap(sm)
+
+	rr := getSitemap(t, s, "/sitemap.xml")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var index sitemapindex
+	assert.Nil(t, xml.Unmarshal(rr.Body.Bytes(), &index))
+	assert.Len(t, index.Sitemaps, 2)
+	assert.Equal(t, "https://example.com/sitemap-1.xml", index.Sitemaps[0].Loc)
+	assert.Equal(t, "2021-07-01T12:00:00Z", index.Sitemaps[0].LastMod)
+	assert.Equal(t, "https://example.com/sitemap-2.xml", index.Sitemaps[1].Loc)
+	assert.Equal(t, "2021-06-01T12:00:00Z", index.Sitemaps[1].LastMod)
+
+	var res urlset
+	rr = getSitemap(t, s, "/sitemap-2.xml")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, xml.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Len(t, res.URLs, 1)
+	assert.Equal(t, "https://example.com/projects/c", res.URLs[0].Loc)
+
+	rr = getSitemap(t, s, "/sitemap-3.xml")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
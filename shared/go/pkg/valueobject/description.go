@@ -17,7 +17,6 @@
 package valueobject
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -27,8 +26,11 @@ type Description struct {
 
 // NewDescription creates a new valid description object.
 func NewDescription(value string) (Description, error) {
-	if len(value) > 300 || strings.TrimSpace(value) == "" {
-		return Description{}, fmt.Errorf("invalid description, must be within 300 characters and non-empty")
+	if strings.TrimSpace(value) == "" {
+		return Description{}, &ConstraintError{Constraint: ConstraintRequired, Message: "invalid description, must be within 300 characters and non-empty"}
+	}
+	if len(value) > 300 {
+		return Description{}, &ConstraintError{Constraint: ConstraintMaxLength, Limit: 300, Message: "invalid description, must be within 300 characters and non-empty"}
 	}
 
 	return Description{value: value}, nil
@@ -17,7 +17,6 @@
 package valueobject
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -27,8 +26,11 @@ type LongName struct {
 
 // NewLongName creates a new valid long name object.
 func NewLongName(value string) (LongName, error) {
-	if len(value) > 50 || strings.TrimSpace(value) == "" {
-		return LongName{}, fmt.Errorf("invalid long name, must be within 50 characters and non-empty")
+	if strings.TrimSpace(value) == "" {
+		return LongName{}, &ConstraintError{Constraint: ConstraintRequired, Message: "invalid long name, must be within 50 characters and non-empty"}
+	}
+	if len(value) > 50 {
+		return LongName{}, &ConstraintError{Constraint: ConstraintMaxLength, Limit: 50, Message: "invalid long name, must be within 50 characters and non-empty"}
 	}
 
 	return LongName{value: value}, nil
@@ -17,8 +17,8 @@
 package valueobject
 
 import (
-	"fmt"
 	"strconv"
+	"strings"
 )
 
 type ShortCode struct {
@@ -27,9 +27,11 @@ type ShortCode struct {
 
 // NewShortCode creates a new valid short code object.
 func NewShortCode(value string) (ShortCode, error) {
-	_, err := strconv.ParseUint(value, 16, 64)
-	if err != nil {
-		return ShortCode{}, fmt.Errorf("invalid short code, must be two hexadecimal digits and non-empty")
+	if strings.TrimSpace(value) == "" {
+		return ShortCode{}, &ConstraintError{Constraint: ConstraintRequired, Message: "invalid short code, must be two hexadecimal digits and non-empty"}
+	}
+	if _, err := strconv.ParseUint(value, 16, 64); err != nil {
+		return ShortCode{}, &ConstraintError{Constraint: ConstraintHexadecimal, Message: "invalid short code, must be two hexadecimal digits and non-empty"}
 	}
 
 	return ShortCode{value: value}, nil
@@ -17,7 +17,6 @@
 package valueobject
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -27,8 +26,11 @@ type ShortName struct {
 
 // NewShortName creates a new valid short name object.
 func NewShortName(value string) (ShortName, error) {
-	if len(value) > 15 || strings.TrimSpace(value) == "" {
-		return ShortName{}, fmt.Errorf("invalid short name, must be within 15 characters and non-empty")
+	if strings.TrimSpace(value) == "" {
+		return ShortName{}, &ConstraintError{Constraint: ConstraintRequired, Message: "invalid short name, must be within 15 characters and non-empty"}
+	}
+	if len(value) > 15 {
+		return ShortName{}, &ConstraintError{Constraint: ConstraintMaxLength, Limit: 15, Message: "invalid short name, must be within 15 characters and non-empty"}
 	}
 
 	return ShortName{value: value}, nil
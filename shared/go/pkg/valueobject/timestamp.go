@@ -37,6 +37,11 @@ func NewTimestampFromUnix(sec int64) Timestamp{
 	return Timestamp{value: time.Unix(sec, 0)}
 }
 
+//NewTimestampFromTime creates a new timestamp value object for the supplied time, keeping its fractions of a second.
+func NewTimestampFromTime(t time.Time) Timestamp {
+	return Timestamp{value: t}
+}
+
 //Time returns the Time of the value.
 func (v Timestamp) Time() time.Time {
 	return v.value
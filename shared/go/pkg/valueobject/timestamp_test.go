@@ -20,6 +20,7 @@ import (
 	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestNewTimestamp(t *testing.T) {
@@ -28,7 +29,10 @@ func TestNewTimestamp(t *testing.T) {
 }
 
 func TestNewTimestampFromTime(t *testing.T) {
-	// TODO: add test
+	expected := time.Date(2016, 3, 1, 12, 30, 15, 123456789, time.UTC)
+	ts := valueobject.NewTimestampFromTime(expected)
+	assert.Equal(t, expected, ts.Time())
+	assert.Equal(t, expected.Unix(), ts.Unix())
 }
 
 func TestTimestamp_Unix(t *testing.T) {
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package valueobject
+
+import (
+	"strings"
+)
+
+// Constraint is a rule the value of a value object must satisfy.
+type Constraint string
+
+const (
+	// ConstraintRequired requires a value which is not blank.
+	ConstraintRequired Constraint = "required"
+	// ConstraintMaxLength limits the number of characters of a value.
+	ConstraintMaxLength Constraint = "maxLength"
+	// ConstraintHexadecimal requires a hexadecimal number.
+	ConstraintHexadecimal Constraint = "hexadecimal"
+)
+
+// ConstraintError is returned by the constructors of value objects if the provided value violates a constraint.
+type ConstraintError struct {
+	Constraint Constraint
+	// Limit is the maximum length of a value violating ConstraintMaxLength, zero otherwise.
+	Limit   int
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ConstraintError) Error() string {
+	return e.Message
+}
+
+// FieldError is a constraint violated by the value of a field.
+type FieldError struct {
+	Field      string
+	Constraint Constraint
+	Limit      int
+	Message    string
+}
+
+// ValidationError lists every field whose value violates a constraint, in the order they were validated.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	var messages []string
+	for _, f := range e.Fields {
+		messages = append(messages, f.Field+": "+f.Message)
+	}
+	return "invalid fields: " + strings.Join(messages, "; ")
+}
+
+// Validation creates several value objects and collects the errors of all invalid values, so that they can be reported at once.
+// The value objects returned for invalid values are empty and must not be used if Err returns an error.
+//
+//	v := valueobject.Validation{}
+//	sc := v.ShortCode("shortCode", input.ShortCode)
+//	sn := v.ShortName("shortName", input.ShortName)
+//	if err := v.Err(); err != nil {
+//		return err
+//	}
+type Validation struct {
+	fields []FieldError
+}
+
+// ShortCode creates the short code of the field.
+func (v *Validation) ShortCode(field string, value string) ShortCode {
+	res, err := NewShortCode(value)
+	v.Add(field, err)
+	return res
+}
+
+// ShortName creates the short name of the field.
+func (v *Validation) ShortName(field string, value string) ShortName {
+	res, err := NewShortName(value)
+	v.Add(field, err)
+	return res
+}
+
+// LongName creates the long name of the field.
+func (v *Validation) LongName(field string, value string) LongName {
+	res, err := NewLongName(value)
+	v.Add(field, err)
+	return res
+}
+
+// Description creates the description of the field.
+func (v *Validation) Description(field string, value string) Description {
+	res, err := NewDescription(value)
+	v.Add(field, err)
+	return res
+}
+
+// Add records the error of the field, nil errors are ignored.
+// Errors other than ConstraintError are recorded without constraint.
+func (v *Validation) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+
+	f := FieldError{Field: field, Message: err.Error()}
+	if c, ok := err.(*ConstraintError); ok {
+		f.Constraint = c.Constraint
+		f.Limit = c.Limit
+	}
+	v.fields = append(v.fields, f)
+}
+
+// Err returns a ValidationError listing all invalid fields, or nil if all values are valid.
+func (v *Validation) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: append([]FieldError{}, v.fields...)}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2021 DaSCH - Data and Service Center for the Humanities.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package valueobject_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dasch-swiss/dasch-service-platform/shared/go/pkg/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation_Valid(t *testing.T) {
+	v := valueobject.Validation{}
+	sc := v.ShortCode("shortCode", "00FF")
+	sn := v.ShortName("shortName", "short")
+	ln := v.LongName("longName", "long name")
+	desc := v.Description("description", "description")
+
+	assert.Nil(t, v.Err())
+	assert.Equal(t, "00FF", sc.String())
+	assert.Equal(t, "short", sn.String())
+	assert.Equal(t, "long name", ln.String())
+	assert.Equal(t, "description", desc.String())
+}
+
+func TestValidation_CollectsAllErrors(t *testing.T) {
+	v := valueobject.Validation{}
+	v.ShortCode("shortCode", "xyz")
+	v.ShortName("shortName", strings.Repeat("a", 16))
+	v.LongName("longName", "valid")
+	v.Description("description", " ")
+
+	err := v.Err()
+	assert.NotNil(t, err)
+
+	var verr *valueobject.ValidationError
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, []valueobject.FieldError{
+		{Field: "shortCode", Constraint: valueobject.ConstraintHexadecimal, Message: "invalid short code, must be two hexadecimal digits and non-empty"},
+		{Field: "shortName", Constraint: valueobject.ConstraintMaxLength, Limit: 15, Message: "invalid short name, must be within 15 characters and non-empty"},
+		{Field: "description", Constraint: valueobject.ConstraintRequired, Message: "invalid description, must be within 300 characters and non-empty"},
+	}, verr.Fields)
+	assert.Contains(t, err.Error(), "shortName: invalid short name")
+}
+
+func TestValidation_Add(t *testing.T) {
+	v := valueobject.Validation{}
+	v.Add("expiresAt", nil)
+	assert.Nil(t, v.Err())
+
+	v.Add("expiresAt", errors.New("invalid date"))
+	assert.Equal(t, []valueobject.FieldError{{Field: "expiresAt", Message: "invalid date"}}, v.Err().(*valueobject.ValidationError).Fields)
+}